@@ -0,0 +1,260 @@
+// Package agentscheduler is the stable entry point for embedding the agent
+// scheduler in other services. It wraps the parser, scheduler, and formatter
+// packages under pkg/ so callers can go from CSV input to a rendered schedule
+// without depending on their individual APIs.
+package agentscheduler
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/karthikrao-23/agentscheduler/pkg/formatter"
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+	"github.com/karthikrao-23/agentscheduler/pkg/parser"
+	"github.com/karthikrao-23/agentscheduler/pkg/scheduler"
+)
+
+// CallData is a single parsed row of call volume input.
+type CallData = models.CallData
+
+// ScheduleResult is the hourly agent schedule produced by Schedule.
+type ScheduleResult = models.Schedule
+
+// Scenario is a named set of options for a what-if run.
+type Scenario = models.Scenario
+
+// ScenarioResult is a scenario together with its schedule and summary.
+type ScenarioResult = models.ScenarioResult
+
+// Replanner regenerates a schedule as its input changes, redoing only what
+// the change touches.
+type Replanner = scheduler.Replanner
+
+// Scheduler generates schedules under one set of options.
+type Scheduler = scheduler.Scheduler
+
+// ScheduleOption configures Schedule.
+type ScheduleOption = scheduler.Option
+
+// FormatOption configures Format, Write, and FormatAll.
+type FormatOption = formatter.Option
+
+// ParseOption configures Parse.
+type ParseOption = parser.Option
+
+// WithLenient skips invalid rows instead of failing the whole parse.
+func WithLenient(lenient bool) ParseOption {
+	return parser.WithLenient(lenient)
+}
+
+// Parse reads call volume CSV data from r.
+func Parse(ctx context.Context, r io.Reader, opts ...ParseOption) ([]CallData, error) {
+	return parser.ParseContext(ctx, r, opts...)
+}
+
+// WithUtilization sets the utilization multiplier (between 0 and 1).
+func WithUtilization(utilization float64) ScheduleOption {
+	return scheduler.WithUtilization(utilization)
+}
+
+// WithMaxOccupancy caps the share of their time agents spend on calls,
+// adding agents where the staffing model would keep them busier.
+func WithMaxOccupancy(maxOccupancy float64) ScheduleOption {
+	return scheduler.WithMaxOccupancy(maxOccupancy)
+}
+
+// WithPeakedness staffs every slot for calls arriving factor times faster
+// than its average, for the busiest part of the slot.
+func WithPeakedness(factor float64) ScheduleOption {
+	return scheduler.WithPeakedness(factor)
+}
+
+// WithArrivalCurves staffs each customer with a curve for the busiest part
+// of every slot by it.
+func WithArrivalCurves(curves models.ArrivalCurves) ScheduleOption {
+	return scheduler.WithArrivalCurves(curves)
+}
+
+// WithSmoothing limits each customer's agents to change by at most maxDelta
+// between slots up to window slots apart, topping slots up to ramp into and
+// out of peaks.
+func WithSmoothing(window, maxDelta int) ScheduleOption {
+	return scheduler.WithSmoothing(window, maxDelta)
+}
+
+// WithFTE keeps each requirement's fractional agents, before rounding up, as
+// full-time equivalents; render them with FormatFTE.
+func WithFTE(fte bool) ScheduleOption {
+	return scheduler.WithFTE(fte)
+}
+
+// Hooks are called with the schedule's allocation slot by slot; see
+// WithHooks.
+type Hooks = scheduler.Hooks
+
+// WithHooks calls hooks for every slot of the schedule, in order, once its
+// allocation is final.
+func WithHooks(hooks Hooks) ScheduleOption {
+	return scheduler.WithHooks(hooks)
+}
+
+// FormatMetadata heads the rendered schedule with its metadata; pass it to
+// Format, Write, or FormatAll.
+func FormatMetadata() FormatOption {
+	return formatter.WithMetadata()
+}
+
+// FormatFTE renders agents as full-time equivalents with precision decimal
+// places, for schedules built WithFTE; pass it to Format, Write, or FormatAll.
+func FormatFTE(precision int) FormatOption {
+	return formatter.WithFTE(precision)
+}
+
+// WithCapacity caps the agents allocated in every hour (0 = unlimited).
+func WithCapacity(capacityPerHour int) ScheduleOption {
+	return scheduler.WithCapacity(capacityPerHour)
+}
+
+// WithCapacityProfile sets a separate capacity for each hour of the day.
+func WithCapacityProfile(capacityByHour []int) ScheduleOption {
+	return scheduler.WithCapacityProfile(capacityByHour)
+}
+
+// WithInterval sets the length of the schedule's slots, e.g. 15 or 30
+// minutes. Defaults to an hour.
+func WithInterval(interval time.Duration) ScheduleOption {
+	return scheduler.WithInterval(interval)
+}
+
+// WithMultiDay schedules each date in the input separately instead of
+// folding every row into one day.
+func WithMultiDay(multiDay bool) ScheduleOption {
+	return scheduler.WithMultiDay(multiDay)
+}
+
+// WithUTC keys the schedule by UTC time rather than each customer's local
+// time of day, so customers working at the same moment share a slot and
+// its capacity.
+func WithUTC(utc bool) ScheduleOption {
+	return scheduler.WithUTC(utc)
+}
+
+// WithWeeklyPattern repeats each row over the week it starts in, Monday to
+// Sunday, scaling its calls by the customer's multiplier for each weekday.
+func WithWeeklyPattern(pattern models.WeeklyPattern) ScheduleOption {
+	return scheduler.WithWeeklyPattern(pattern)
+}
+
+// WithCalendar scales each slot's calls by the holidays and blackout windows
+// covering it, on a schedule laid out by date.
+func WithCalendar(cal models.Calendar) ScheduleOption {
+	return scheduler.WithCalendar(cal)
+}
+
+// WithSeasonality scales each slot's calls by the customer's multipliers for
+// the month and ISO week it falls in.
+func WithSeasonality(seasonality models.Seasonality) ScheduleOption {
+	return scheduler.WithSeasonality(seasonality)
+}
+
+// WithProportionalAllocation shares out hours that are short of capacity pro
+// rata to each customer's demand, instead of in strict priority order.
+func WithProportionalAllocation() ScheduleOption {
+	return scheduler.WithAllocator(scheduler.ProportionalAllocator{})
+}
+
+// WithOptimalAllocation shares out hours that are short of capacity so as to
+// meet the most demand weighted by priority, with weights as
+// scheduler.OptimalAllocator describes (nil for the defaults). With
+// WithAgentPools, given before or after, it also routes by skill.
+func WithOptimalAllocation(weights map[models.Priority]float64) ScheduleOption {
+	return scheduler.WithAllocator(scheduler.OptimalAllocator{Weights: weights})
+}
+
+// WithReservedCapacity holds a percentage of every hour's capacity for each
+// priority tier, which its demand is met from before any other tier's.
+func WithReservedCapacity(percentByPriority map[models.Priority]float64) ScheduleOption {
+	return scheduler.WithReservedCapacity(percentByPriority)
+}
+
+// WithAgentPools staffs each hour from pools of agents with skills, routing
+// each customer's calls only to pools that serve its skill. It cannot be
+// combined with WithProportionalAllocation.
+func WithAgentPools(pools []models.AgentPool) ScheduleOption {
+	return scheduler.WithAgentPools(pools)
+}
+
+// WithErlangC staffs each hour to answer targetPercent of calls within
+// thresholdSeconds, using the Erlang C model, instead of to raw workload.
+func WithErlangC(targetPercent float64, thresholdSeconds int) ScheduleOption {
+	return scheduler.WithErlangC(targetPercent, thresholdSeconds)
+}
+
+// WithPoisson staffs each hour for the calls in progress at once at the
+// given percentile, assuming Poisson arrivals, instead of to raw workload.
+func WithPoisson(percentile float64) ScheduleOption {
+	return scheduler.WithPoisson(percentile)
+}
+
+// WithWorkers expands rows and allocates hours on up to n goroutines. The
+// schedule is the same for any n.
+func WithWorkers(n int) ScheduleOption {
+	return scheduler.WithWorkers(n)
+}
+
+// Schedule converts call volume data into hourly agent requirements. Without
+// options it assumes full utilization and unlimited capacity.
+func Schedule(ctx context.Context, data []CallData, opts ...ScheduleOption) (*ScheduleResult, error) {
+	return scheduler.GenerateScheduleContext(ctx, data, opts...)
+}
+
+// New returns a Scheduler for opts, checking them once rather than on every
+// Schedule call.
+func New(opts ...ScheduleOption) (*Scheduler, error) {
+	return scheduler.New(opts...)
+}
+
+// NewReplanner returns a Replanner for schedules under opts, for inputs
+// revised and scheduled again through the day.
+func NewReplanner(opts ...ScheduleOption) (*Replanner, error) {
+	return scheduler.NewReplanner(opts...)
+}
+
+// Reallocate allocates a saved schedule's demand again under opts, such as
+// a new capacity, without the input it was generated from.
+func Reallocate(ctx context.Context, saved *ScheduleResult, opts ...ScheduleOption) (*ScheduleResult, error) {
+	return scheduler.Reallocate(ctx, saved, opts...)
+}
+
+// Save writes schedule to w as canonical JSON, for Load to read back.
+func Save(w io.Writer, schedule *ScheduleResult) error {
+	return schedule.Save(w)
+}
+
+// Load reads a schedule written by Save.
+func Load(r io.Reader) (*ScheduleResult, error) {
+	return models.Load(r)
+}
+
+// RunScenario schedules data under scenario and summarizes the result, for
+// comparing what-if runs.
+func RunScenario(ctx context.Context, data []CallData, scenario Scenario, opts ...ScheduleOption) (ScenarioResult, error) {
+	return scheduler.RunScenario(ctx, data, scenario, opts...)
+}
+
+// Format renders a schedule as "text", "json", or "csv".
+func Format(ctx context.Context, format string, schedule *ScheduleResult, opts ...FormatOption) (string, error) {
+	return formatter.Format(ctx, format, schedule, opts...)
+}
+
+// Write is like Format but writes the rendered schedule to w.
+func Write(ctx context.Context, w io.Writer, format string, schedule *ScheduleResult, opts ...FormatOption) error {
+	return formatter.Write(ctx, w, format, schedule, opts...)
+}
+
+// FormatAll renders a schedule in several formats at once, concurrently;
+// the i-th output is the schedule in formats[i].
+func FormatAll(ctx context.Context, formats []string, schedule *ScheduleResult, opts ...FormatOption) ([]string, error) {
+	return formatter.FormatAll(ctx, formats, schedule, opts...)
+}
@@ -0,0 +1,58 @@
+package agentscheduler_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/karthikrao-23/agentscheduler"
+	customerrors "github.com/karthikrao-23/agentscheduler/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseScheduleFormat(t *testing.T) {
+	ctx := context.Background()
+	input := "#CustomerName, Duration, StartTimeUTC, EndTimeUTC, Calls, Priority\nAcme, 3600, 9AM, 11AM, 20, 1\n"
+
+	data, err := agentscheduler.Parse(ctx, strings.NewReader(input))
+	require.NoError(t, err)
+	require.Len(t, data, 1)
+
+	schedule, err := agentscheduler.Schedule(ctx, data)
+	require.NoError(t, err)
+	assert.Len(t, schedule.HourlyRequirements[9], 1)
+	assert.Equal(t, 10, schedule.HourlyRequirements[9][0].AgentsNeeded)
+
+	out, err := agentscheduler.Format(ctx, "text", schedule)
+	require.NoError(t, err)
+	assert.Contains(t, out, "09:00 : total=10 ; [UTC: total=10, Acme=10]")
+}
+
+func TestParse_Lenient(t *testing.T) {
+	input := "Acme, 3600, 9AM, 11AM, 20, 1\nBroken, x, 9AM, 11AM, 20, 1\n"
+
+	_, err := agentscheduler.Parse(context.Background(), strings.NewReader(input))
+	assert.Error(t, err)
+
+	data, err := agentscheduler.Parse(context.Background(), strings.NewReader(input), agentscheduler.WithLenient(true))
+	require.NoError(t, err)
+	assert.Len(t, data, 1)
+}
+
+func TestNew(t *testing.T) {
+	_, err := agentscheduler.New(agentscheduler.WithUtilization(0))
+	var violation *customerrors.ConstraintViolationError
+	assert.ErrorAs(t, err, &violation)
+
+	s, err := agentscheduler.New(agentscheduler.WithCapacity(6))
+	require.NoError(t, err)
+	data, err := agentscheduler.Parse(context.Background(), strings.NewReader("Acme, 3600, 9AM, 11AM, 20, 1\n"))
+	require.NoError(t, err)
+	for range 2 {
+		schedule, err := s.Schedule(context.Background(), data)
+		require.NoError(t, err)
+		assert.Equal(t, 6, schedule.AgentsForHour(9))
+	}
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log/slog"
+	"time"
+
+	"github.com/karthikrao-23/agentscheduler/pkg/eventbus"
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+)
+
+// busFlags are the event-bus publishing flags shared by the long-running
+// serve and kafka commands.
+type busFlags struct {
+	mqttURL     *string
+	mqttTopic   *string
+	mqttQoS     *uint
+	mqttRetain  *bool
+	natsURL     *string
+	natsSubject *string
+}
+
+func addBusFlags(fs *flag.FlagSet) busFlags {
+	return busFlags{
+		mqttURL:     fs.String("mqtt-url", "", "MQTT broker to publish every schedule to as NDJSON, one line per slot (e.g., tcp://localhost:1883)"),
+		mqttTopic:   fs.String("mqtt-topic", "agent-scheduler/schedule", "MQTT topic to publish schedules to"),
+		mqttQoS:     fs.Uint("mqtt-qos", 1, "MQTT quality of service: 0, 1, or 2"),
+		mqttRetain:  fs.Bool("mqtt-retain", true, "Retain the latest schedule on the MQTT broker for new subscribers"),
+		natsURL:     fs.String("nats-url", "", "NATS server to publish every schedule to as NDJSON, one line per slot (e.g., nats://localhost:4222)"),
+		natsSubject: fs.String("nats-subject", "agent-scheduler.schedule", "NATS subject to publish schedules to"),
+	}
+}
+
+// dial connects to the configured buses. The caller closes the publishers.
+func (f busFlags) dial() ([]eventbus.Publisher, error) {
+	var publishers []eventbus.Publisher
+	if *f.mqttURL != "" {
+		p, err := eventbus.DialMQTT(eventbus.MQTTConfig{
+			BrokerURL: *f.mqttURL,
+			Topic:     *f.mqttTopic,
+			QoS:       byte(min(*f.mqttQoS, 255)),
+			Retain:    *f.mqttRetain,
+		})
+		if err != nil {
+			return nil, err
+		}
+		publishers = append(publishers, p)
+	}
+	if *f.natsURL != "" {
+		p, err := eventbus.DialNATS(*f.natsURL, *f.natsSubject)
+		if err != nil {
+			closeAll(publishers)
+			return nil, err
+		}
+		publishers = append(publishers, p)
+	}
+	return publishers, nil
+}
+
+// publishToBus returns a function that publishes a schedule to every
+// publisher, logging failures so they never hold up scheduling.
+func publishToBus(publishers []eventbus.Publisher) func(context.Context, *models.Schedule) {
+	return func(ctx context.Context, schedule *models.Schedule) {
+		ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+		for _, p := range publishers {
+			if err := eventbus.Publish(ctx, p, schedule); err != nil {
+				slog.Warn("publishing schedule to event bus", slog.Any("error", err))
+			}
+		}
+	}
+}
+
+func closeAll(publishers []eventbus.Publisher) {
+	for _, p := range publishers {
+		_ = p.Close()
+	}
+}
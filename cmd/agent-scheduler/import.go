@@ -0,0 +1,103 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/karthikrao-23/agentscheduler/pkg/blob"
+	"github.com/karthikrao-23/agentscheduler/pkg/importer"
+	"github.com/karthikrao-23/agentscheduler/pkg/parser"
+)
+
+// importCommand implements "agent-scheduler import", which builds input CSV
+// from a ticketing system's interaction history. It returns the process exit
+// code.
+func importCommand(ctx context.Context, args []string) int {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	source := fs.String("source", "", "System to import from: servicenow|zendesk|salesforce (required)")
+	url := fs.String("url", "", "Instance or account URL, e.g. https://acme.service-now.com (required)")
+	table := fs.String("table", "", "Table or object to read (default: incident for ServiceNow, Case for Salesforce)")
+	query := fs.String("query", "", "Extra filter: a ServiceNow encoded query, e.g. contact_type=chat, or a SOQL condition, e.g. \"Origin = 'Chat'\"")
+	customerField := fs.String("customer-field", "", "Salesforce field naming the customer (default: Owner.Name)")
+	lookback := fs.Duration("lookback", importer.DefaultLookback, "Period to average interaction volumes over")
+	handleTime := fs.Int("handle-time", importer.DefaultHandleTimeSeconds, "Average handle time per interaction, in seconds")
+	timezone := fs.String("timezone", "UTC", "IANA time zone to count hours of day in")
+	output := fs.String("output", "", "File or az://, wasbs://, or sftp:// URI to write the input CSV to (default: stdout)")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *source == "" || *url == "" {
+		fmt.Fprintln(os.Stderr, "Error: -source and -url are required")
+		return 2
+	}
+	loc, err := time.LoadLocation(*timezone)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: invalid -timezone: %v\n", err)
+		return 2
+	}
+
+	var src importer.Source
+	switch *source {
+	case "servicenow":
+		src = &importer.ServiceNow{
+			InstanceURL: *url,
+			Username:    os.Getenv("SERVICENOW_USERNAME"),
+			Password:    os.Getenv("SERVICENOW_PASSWORD"),
+			Table:       cmp.Or(*table, "incident"),
+			Query:       *query,
+		}
+	case "zendesk":
+		src = &importer.Zendesk{
+			URL:      *url,
+			Email:    os.Getenv("ZENDESK_EMAIL"),
+			APIToken: os.Getenv("ZENDESK_API_TOKEN"),
+		}
+	case "salesforce":
+		src = &importer.Salesforce{
+			InstanceURL:   *url,
+			AccessToken:   os.Getenv("SALESFORCE_ACCESS_TOKEN"),
+			ClientID:      os.Getenv("SALESFORCE_CLIENT_ID"),
+			ClientSecret:  os.Getenv("SALESFORCE_CLIENT_SECRET"),
+			Object:        *table,
+			CustomerField: *customerField,
+			Where:         *query,
+		}
+	default:
+		fmt.Fprintf(os.Stderr, "Error: source must be one of: servicenow, zendesk, salesforce (got: %s)\n", *source)
+		return 2
+	}
+
+	data, err := importer.Import(ctx, src, importer.Config{
+		Lookback:          *lookback,
+		HandleTimeSeconds: *handleTime,
+		Location:          loc,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	var w io.WriteCloser = os.Stdout
+	if *output != "" {
+		if w, err = blob.Create(ctx, *output); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+	}
+	err = parser.WriteCSV(w, data)
+	if *output != "" {
+		err = errors.Join(err, w.Close())
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	fmt.Fprintf(os.Stderr, "Imported %d rows from %s\n", len(data), *source)
+	return 0
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/karthikrao-23/agentscheduler/pkg/kafka"
+	"github.com/karthikrao-23/agentscheduler/pkg/scheduler"
+
+	kafkago "github.com/segmentio/kafka-go"
+)
+
+// kafkaCommand implements "agent-scheduler kafka", which consumes call-data
+// records from a topic and republishes the schedule as they change. It
+// returns the process exit code.
+func kafkaCommand(ctx context.Context, args []string) int {
+	fs := flag.NewFlagSet("kafka", flag.ContinueOnError)
+	brokers := fs.String("brokers", "", "Comma-separated Kafka brokers (required)")
+	topic := fs.String("topic", "", "Topic to consume call-data records from (required)")
+	group := fs.String("group", "agent-scheduler", "Consumer group ID")
+	outputTopic := fs.String("output-topic", "", "Topic to publish schedules to (required)")
+	encoding := fs.String("encoding", "json", "Record encoding: json|avro")
+	avroSchema := fs.String("avro-schema", "", "Avro schema file for -encoding avro (default: built-in CallData schema)")
+	window := fs.Duration("window", 0, "How long a record counts towards the schedule (0 = until replaced)")
+	interval := fs.Duration("interval", time.Minute, "How often to regenerate the schedule when input changed")
+	utilization := fs.Float64("utilization", 1.0, "Utilization multiplier (between 0 and 1)")
+	capacity := fs.Int("capacity", 0, "Maximum agent capacity per hour (0 = unlimited)")
+	bus := addBusFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *brokers == "" || *topic == "" || *outputTopic == "" {
+		fmt.Fprintln(os.Stderr, "Error: -brokers, -topic, and -output-topic are required")
+		return 2
+	}
+
+	decode := kafka.DecodeJSON
+	switch *encoding {
+	case "json":
+	case "avro":
+		schema := kafka.CallDataAvroSchema
+		if *avroSchema != "" {
+			b, err := os.ReadFile(*avroSchema)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				return 1
+			}
+			schema = string(b)
+		}
+		var err error
+		if decode, err = kafka.NewAvroDecoder(schema); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+	default:
+		fmt.Fprintf(os.Stderr, "Error: encoding must be one of: json, avro (got: %s)\n", *encoding)
+		return 2
+	}
+
+	reader := kafkago.NewReader(kafkago.ReaderConfig{
+		Brokers: kafka.ParseBrokers(*brokers),
+		Topic:   *topic,
+		GroupID: *group,
+	})
+	defer reader.Close()
+	writer := &kafkago.Writer{
+		Addr:     kafkago.TCP(kafka.ParseBrokers(*brokers)...),
+		Topic:    *outputTopic,
+		Balancer: &kafkago.Hash{},
+	}
+	defer writer.Close()
+
+	publishers, err := bus.dial()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	defer closeAll(publishers)
+
+	consumer := kafka.NewConsumer(reader, writer,
+		kafka.WithDecoder(decode),
+		kafka.WithWindow(*window),
+		kafka.WithInterval(*interval),
+		kafka.WithScheduleOptions(scheduler.WithUtilization(*utilization), scheduler.WithCapacity(*capacity)),
+		kafka.WithOnPublish(publishToBus(publishers)),
+	)
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	fmt.Fprintf(os.Stderr, "Consuming %s, publishing schedules to %s\n", *topic, *outputTopic)
+	if err := consumer.Run(ctx); err != nil && !errors.Is(err, context.Canceled) {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
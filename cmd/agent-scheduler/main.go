@@ -0,0 +1,1011 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"runtime"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/karthikrao-23/agentscheduler/pkg/alerting"
+	"github.com/karthikrao-23/agentscheduler/pkg/blob"
+	"github.com/karthikrao-23/agentscheduler/pkg/formatter"
+	"github.com/karthikrao-23/agentscheduler/pkg/metrics"
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+	"github.com/karthikrao-23/agentscheduler/pkg/parser"
+	"github.com/karthikrao-23/agentscheduler/pkg/scheduler"
+	"github.com/karthikrao-23/agentscheduler/pkg/secrets"
+	"github.com/karthikrao-23/agentscheduler/pkg/shifts"
+	"github.com/karthikrao-23/agentscheduler/pkg/store"
+	"github.com/karthikrao-23/agentscheduler/pkg/tracing"
+	"github.com/karthikrao-23/agentscheduler/pkg/twilio"
+	"github.com/karthikrao-23/agentscheduler/pkg/webhook"
+	"github.com/karthikrao-23/agentscheduler/pkg/wfm"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+func main() {
+	// Credentials in the environment may be Vault or Key Vault references
+	resolver := secrets.NewResolver()
+	if err := resolver.ResolveEnv(context.Background()); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "runs":
+			os.Exit(runsCommand(context.Background(), os.Args[2:]))
+		case "kafka":
+			os.Exit(kafkaCommand(context.Background(), os.Args[2:]))
+		case "serve":
+			os.Exit(serveCommand(context.Background(), os.Args[2:]))
+		case "import":
+			os.Exit(importCommand(context.Background(), os.Args[2:]))
+		case "roster":
+			os.Exit(rosterCommand(context.Background(), os.Args[2:]))
+		case "plan":
+			os.Exit(planCommand(context.Background(), os.Args[2:]))
+		case "apply":
+			os.Exit(applyCommand(context.Background(), os.Args[2:]))
+		}
+	}
+
+	// Define flags
+	input := flag.String("input", "", "Input CSV file or az://, wasbs://, or sftp:// URI (required)")
+	outputPath := flag.String("output", "", "File or az://, wasbs://, or sftp:// URI to write the schedule to (default: stdout)")
+	format := flag.String("format", "text", "Output format: text|json|csv, or several comma-separated, each written to -output with the format's extension")
+	lenient := flag.Bool("lenient", false, "Skip invalid input rows instead of failing")
+	skipUnchanged := flag.Bool("skip-unchanged", false, "Skip the run when the input and options match the last run that wrote -output, tracked in <output>.state.json")
+	utilization := flag.Float64("utilization", 1.0, "Utilization multiplier (between 0 and 1)")
+	maxOccupancy := flag.Float64("max-occupancy", 0, "Cap on the share of their time agents spend on calls, e.g. 0.9 (0 = no cap)")
+	fte := flag.Bool("fte", false, "Output fractional agents (full-time equivalents) instead of rounding each customer up every slot")
+	precision := flag.Int("precision", 2, "Decimal places agents are written with under -fte")
+	withMetadata := flag.Bool("metadata", false, "Head every output with the schedule's metadata: when it was generated, the run ID, input hash, options, and tool version")
+	outputTZ := flag.String("output-tz", "", "IANA timezone to report every slot in, e.g. UTC or America/New_York, instead of each customer's local time")
+	capacity := flag.Int("capacity", 0, "Maximum agent capacity per hour (0 = unlimited)")
+	interval := flag.Int("interval", 60, "Minutes in each schedule slot: 15, 30, or 60")
+	multiDay := flag.Bool("multi-day", false, "Schedule each date in the input separately, from the first to the last, instead of folding every row into one day")
+	utc := flag.Bool("utc", false, "Key slots by UTC time rather than each customer's local hour, so customers in different timezones share capacity at the same moment")
+	smoothingMaxDelta := flag.Int("smoothing-max-delta", 0, "Most each customer's agents may change within -smoothing-window slots (0 = no smoothing)")
+	smoothingWindow := flag.Int("smoothing-window", 1, "Slots over which -smoothing-max-delta limits a customer's change in agents")
+	peakedness := flag.Float64("peakedness", 1, "Staff every slot for calls arriving this many times faster than its average, e.g. 1.2 (at least 1)")
+	arrivalCurves := flag.String("arrival-curves", "", "CSV file or URI of per-customer intra-slot arrival curves; each customer is staffed for the busiest part of every slot by its curve")
+	seasonality := flag.String("seasonality", "", "CSV file or URI of monthly and ISO-weekly volume multipliers, per customer or for all, applied by each slot's date")
+	calendar := flag.String("calendar", "", "CSV file or URI of holidays and blackout windows that scale or zero demand on their dates; needs -multi-day or -weekly-pattern")
+	weeklyPattern := flag.String("weekly-pattern", "", "CSV file or URI of per-customer Monday-Sunday volume multipliers; each input row is repeated over its week with them")
+	allocation := flag.String("allocation", "priority", "How short hours are shared out: priority (higher priorities first), proportional (pro rata to demand), or optimal (most weighted demand met)")
+	priorityWeights := flag.String("priority-weights", "", "Value per agent of each priority tier under -allocation optimal, as priority=weight pairs (e.g., 1=10,2=3); tiers left out weigh 1/priority")
+	reserve := flag.String("reserve", "", "Percent of each hour's capacity held for a priority tier, as priority=percent pairs (e.g., 1=60,2=20)")
+	poolsPath := flag.String("pools", "", "CSV file or URI of agent pools (name, skills, headcount, and optionally hours, location, hourly cost, tier) to staff every hour from, routing each customer's calls by its Skill column; replaces -capacity")
+	staffingModel := flag.String("staffing-model", "workload", "How agents are derived from call volume: workload (calls x duration), erlang-c (staff to -sla-target), or poisson (staff to -poisson-percentile)")
+	slaTarget := flag.Float64("sla-target", 80, "Percentage of calls to answer within -sla-threshold, for -staffing-model erlang-c")
+	slaThreshold := flag.Int("sla-threshold", 20, "Seconds within which -sla-target percent of calls are answered, for -staffing-model erlang-c")
+	poissonPercentile := flag.Float64("poisson-percentile", 95, "Percentile of calls in progress at once to staff for, for -staffing-model poisson")
+	metricsAddr := flag.String("metrics-addr", "", "Address to expose Prometheus metrics (e.g., :9090)")
+	pushGateway := flag.String("push-url", "", "Pushgateway URL to push metrics to (e.g., http://localhost:9091)")
+	remoteWriteURL := flag.String("remote-write-url", "", "Prometheus remote-write endpoint to push metrics to (e.g., https://mimir/api/v1/push)")
+	remoteWriteHeaders := flag.String("remote-write-headers", "", "Extra remote-write request headers as name=value pairs (e.g., X-Scope-OrgID=staffing)")
+	pushJob := flag.String("push-job", metrics.DefaultJobName, "Job label for pushed metrics (Pushgateway or remote-write)")
+	pushGrouping := flag.String("push-grouping", "", "Labels for pushed metrics as name=value pairs (e.g., instance=host1,site=nyc)")
+	pushRetries := flag.Int("push-retries", 3, "Number of retries after a failed metrics push")
+	pushTimeout := flag.Duration("push-timeout", 10*time.Second, "Timeout for each metrics push attempt")
+	wait := flag.Bool("wait", false, "Keep process running after completion to allow for metric scraping")
+	runtimeMetrics := flag.Bool("metrics-runtime", false, "Register Go runtime and process collectors (memory, GC, goroutines, CPU)")
+	perCustomer := flag.Bool("metrics-per-customer", false, "Export demand/allocation/unmet metrics labeled by customer")
+	metricsReset := flag.String("metrics-reset", "run", "Reset policy for run-labeled counters: run (keep only the latest run) or never (keep every run)")
+	alertUnmetPercent := flag.Float64("alert-unmet-percent", 0, "Fire an alert when unmet agents exceed this percentage of demand (0 = disabled)")
+	alertHighPriorityUnmetPercent := flag.Float64("alert-high-priority-unmet-percent", 0, "Fire an alert when unmet priority-1 agents exceed this percentage of priority-1 demand (0 = disabled)")
+	alertExitCode := flag.Int("alert-exit-code", 0, "Exit with this code when any alert fires (0 = keep normal exit code)")
+	pager := flag.String("pager", "", "Open an incident when the priority-1 unmet alert fires: pagerduty (key from PAGERDUTY_ROUTING_KEY) or opsgenie (key from OPSGENIE_API_KEY)")
+	otlpEndpoint := flag.String("otlp-endpoint", "", "OTLP/HTTP endpoint to export traces to (e.g., localhost:4318)")
+	otlpInsecure := flag.Bool("otlp-insecure", false, "Disable TLS when exporting traces")
+	traceDebug := flag.Bool("trace-debug", false, "Emit per-customer child spans during schedule generation")
+	storePath := flag.String("store", "", "SQLite database to record every run's schedule in (e.g., schedules.db); read back with \"agent-scheduler runs\"")
+	saveSchedulePath := flag.String("save-schedule", "", "Write this run's schedule as JSON to this file or URI, to diff a later run against with -diff")
+	diffPath := flag.String("diff", "", "Schedule JSON file or URI saved by a previous run's -save-schedule; prints how this run differs from it")
+	fromSchedule := flag.String("from-schedule", "", "Schedule JSON file or URI saved by a previous run's -save-schedule to allocate again under this run's capacity and allocation flags, instead of reading -input")
+	webhookURL := flag.String("webhook-url", "", "URL to POST a JSON summary to when a run completes")
+	hookHeaders := webhook.Headers{}
+	flag.Var(hookHeaders, "webhook-headers", "Extra webhook request header as Name: value, repeated for each header (e.g., -webhook-headers 'Authorization: Bearer token')")
+	webhookShortfallPercent := flag.Float64("webhook-shortfall-percent", 0, "Report the run as a shortfall (warning severity) when unmet agents exceed this percentage of demand (0 = any unmet demand)")
+	webhookScheduleURL := flag.String("webhook-schedule-url", "", "Link to the published schedule to include in the webhook payload")
+	webhookAttach := flag.Bool("webhook-attach-schedule", false, "Embed the full schedule in the webhook payload")
+	grafanaURL := flag.String("grafana-url", "", "Grafana URL to post a run annotation to (token from GRAFANA_TOKEN)")
+	grafanaDashboard := flag.String("grafana-dashboard-uid", "", "Dashboard UID to scope the annotation to (default: organization-wide)")
+	grafanaPanel := flag.Int64("grafana-panel-id", 0, "Panel ID to scope the annotation to (requires -grafana-dashboard-uid)")
+	grafanaTags := flag.String("grafana-tags", "agent-scheduler", "Comma-separated tags to add to the annotation")
+	twilioExport := flag.String("twilio-export", "", "Write a Twilio TaskRouter/Flex staffing plan as JSON to this file")
+	twilioWorkspace := flag.String("twilio-workspace", "", "TaskRouter workspace SID to record in the staffing plan")
+	twilioChannel := flag.String("twilio-channel", twilio.DefaultTaskChannel, "TaskRouter task channel the staffing plan applies to (e.g., voice, chat)")
+	twilioSyncService := flag.String("twilio-sync-service", "", "Twilio Sync service SID to publish the staffing plan to; credentials are read from TWILIO_ACCOUNT_SID and TWILIO_AUTH_TOKEN")
+	twilioSyncDocument := flag.String("twilio-sync-document", "staffing-plan", "Twilio Sync document the staffing plan is published as")
+	wfmExport := flag.String("wfm-export", "", "Write the staffing requirements in a WFM suite's import format to this file")
+	wfmFormat := flag.String("wfm-format", string(wfm.Verint), "WFM import format for -wfm-export: verint|nice|calabrio")
+	wfmDate := flag.String("wfm-date", "", "Date (YYYY-MM-DD) to place the exported requirements on (default: today)")
+	shiftsOutput := flag.String("shifts-output", "", "Write the shifts that staff the schedule, as CSV of how many agents start when, to this file or URI")
+	shiftLength := flag.Duration("shift-length", 8*time.Hour, "Length of every shift for -shifts-output")
+	shiftStarts := flag.String("shift-starts", "", "Comma-separated times of day shifts may start for -shifts-output, e.g. 06:00,14:00,22:00 (default: any slot)")
+	shiftBreaks := flag.String("shift-breaks", "", "Comma-separated breaks taken off the phones in every shift for -shifts-output, as length/every or just length for one mid-shift, e.g. 15m/2h,30m")
+	rosterInput := flag.String("roster", "", "Roster CSV file or URI of the agents to assign to the schedule, or to its shifts with -shifts-output")
+	assignmentsOutput := flag.String("assignments-output", "", "Write the roster's agents assigned to the schedule, and the coverage gaps it leaves, as CSV to this file or URI (requires -roster)")
+	rosterDate := flag.String("roster-date", "", "Date (YYYY-MM-DD) to place a generic day on when assigning the roster (default: today)")
+	workers := flag.Int("workers", runtime.GOMAXPROCS(0), "Goroutines to parse and schedule the input on, each (1 = no parallelism)")
+	customerLimit := flag.Int("metrics-customer-limit", 50, "Maximum distinct customer labels when -metrics-per-customer is set; the rest are grouped as \"_other\"")
+
+	// Parse command-line flags
+	flag.Parse()
+
+	policy, err := metrics.ParseResetPolicy(*metricsReset)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	metrics.SetResetPolicy(policy)
+
+	grouping, err := metrics.ParseGrouping(*pushGrouping)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	headers, err := metrics.ParseGrouping(*remoteWriteHeaders)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	for _, h := range []map[string]string{headers, hookHeaders} {
+		if err := resolveValues(context.Background(), resolver, h); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if *runtimeMetrics {
+		if err := metrics.RegisterRuntimeCollectors(); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if *perCustomer {
+		metrics.EnablePerCustomerMetrics(*customerLimit)
+	}
+
+	// Start metrics server if address provided
+	if *metricsAddr != "" {
+		go func() {
+			http.Handle("/metrics", metrics.InstrumentHandler("/metrics", promhttp.HandlerFor(metrics.Registry, promhttp.HandlerOpts{
+				// OpenMetrics is required to expose exemplars
+				EnableOpenMetrics: true,
+			})))
+			fmt.Printf("Metrics server listening on %s/metrics\n", *metricsAddr)
+			if err := http.ListenAndServe(*metricsAddr, nil); err != nil {
+				fmt.Printf("Metrics server error: %v\n", err)
+			}
+		}()
+	}
+
+	// Validate required input flag
+	if *input == "" && *fromSchedule == "" {
+		fmt.Println("Error: -input flag is required")
+		fmt.Println("\nUsage:")
+		flag.PrintDefaults()
+		os.Exit(1)
+	}
+	if *input != "" && *fromSchedule != "" {
+		fmt.Println("Error: -from-schedule cannot be combined with -input")
+		os.Exit(1)
+	}
+	source := *input
+	if *fromSchedule != "" {
+		source = *fromSchedule
+	}
+
+	// Validate format enum
+	validFormats := map[string]bool{"text": true, "json": true, "csv": true}
+	formats := splitList(*format)
+	if len(formats) == 0 {
+		formats = []string{*format}
+	}
+	for _, f := range formats {
+		if !validFormats[f] {
+			fmt.Printf("Error: format must be one of: text, json, csv (got: %s)\n", f)
+			os.Exit(1)
+		}
+	}
+	if len(formats) > 1 && *outputPath == "" {
+		fmt.Println("Error: -output is required with several formats")
+		os.Exit(1)
+	}
+	if *skipUnchanged && *outputPath == "" {
+		fmt.Println("Error: -output is required with -skip-unchanged")
+		os.Exit(1)
+	}
+	if *assignmentsOutput != "" && *rosterInput == "" {
+		fmt.Println("Error: -roster is required with -assignments-output")
+		os.Exit(1)
+	}
+
+	// Validate utilization range
+	if *utilization <= 0 || *utilization > 1 {
+		fmt.Println("Error: utilization must be greater than 0 and at most 1")
+		os.Exit(1)
+	}
+	if *maxOccupancy < 0 || *maxOccupancy > 1 {
+		fmt.Println("Error: max-occupancy must be greater than 0 and at most 1, or 0 for no cap")
+		os.Exit(1)
+	}
+
+	if *precision < 0 {
+		fmt.Println("Error: precision must not be negative")
+		os.Exit(1)
+	}
+	var formatOpts []formatter.Option
+	if *fte {
+		formatOpts = append(formatOpts, formatter.WithFTE(*precision))
+	}
+	if *outputTZ != "" {
+		loc, err := models.LoadLocation(*outputTZ)
+		if err != nil {
+			fmt.Printf("Error: output-tz: %v\n", err)
+			os.Exit(1)
+		}
+		formatOpts = append(formatOpts, formatter.WithLocation(loc))
+	}
+	if *withMetadata {
+		formatOpts = append(formatOpts, formatter.WithMetadata())
+	}
+
+	if *interval != 15 && *interval != 30 && *interval != 60 {
+		fmt.Printf("Error: interval must be one of: 15, 30, 60 (got: %d)\n", *interval)
+		os.Exit(1)
+	}
+	scheduleOpts := []scheduler.Option{
+		scheduler.WithInterval(time.Duration(*interval) * time.Minute),
+		scheduler.WithMultiDay(*multiDay),
+		scheduler.WithUTC(*utc),
+		scheduler.WithMaxOccupancy(*maxOccupancy),
+		scheduler.WithFTE(*fte),
+	}
+	if *smoothingMaxDelta < 0 || *smoothingWindow < 1 {
+		fmt.Println("Error: smoothing-max-delta must not be negative and smoothing-window must be at least 1")
+		os.Exit(1)
+	}
+	if *smoothingMaxDelta > 0 {
+		scheduleOpts = append(scheduleOpts, scheduler.WithSmoothing(*smoothingWindow, *smoothingMaxDelta))
+	}
+	switch *staffingModel {
+	case "workload":
+	case "erlang-c":
+		scheduleOpts = append(scheduleOpts, scheduler.WithErlangC(*slaTarget, *slaThreshold))
+	case "poisson":
+		scheduleOpts = append(scheduleOpts, scheduler.WithPoisson(*poissonPercentile))
+	default:
+		fmt.Printf("Error: staffing model must be one of: workload, erlang-c, poisson (got: %s)\n", *staffingModel)
+		os.Exit(1)
+	}
+	if *peakedness < 1 {
+		fmt.Println("Error: peakedness must be at least 1")
+		os.Exit(1)
+	}
+	scheduleOpts = append(scheduleOpts, scheduler.WithPeakedness(*peakedness))
+	ctx := context.Background()
+	if *arrivalCurves != "" {
+		curves, err := loadArrivalCurves(ctx, *arrivalCurves)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		scheduleOpts = append(scheduleOpts, scheduler.WithArrivalCurves(curves))
+	}
+	if *weeklyPattern != "" {
+		pattern, err := loadWeeklyPattern(ctx, *weeklyPattern)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		scheduleOpts = append(scheduleOpts, scheduler.WithWeeklyPattern(pattern))
+	}
+	if *calendar != "" {
+		cal, err := loadCalendar(ctx, *calendar)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		scheduleOpts = append(scheduleOpts, scheduler.WithCalendar(cal))
+	}
+	if *seasonality != "" {
+		multipliers, err := loadSeasonality(ctx, *seasonality)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		scheduleOpts = append(scheduleOpts, scheduler.WithSeasonality(multipliers))
+	}
+	var weights map[models.Priority]float64
+	if *priorityWeights != "" {
+		if *allocation != "optimal" {
+			fmt.Println("Error: -priority-weights requires -allocation optimal")
+			os.Exit(1)
+		}
+		weights, err = parsePriorityValues("priority-weights", *priorityWeights)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	switch *allocation {
+	case "priority", "optimal":
+	case "proportional":
+		if *poolsPath != "" {
+			fmt.Println("Error: -allocation proportional cannot be combined with -pools")
+			os.Exit(1)
+		}
+		scheduleOpts = append(scheduleOpts, scheduler.WithAllocator(scheduler.ProportionalAllocator{}))
+	default:
+		fmt.Printf("Error: allocation must be one of: priority, proportional, optimal (got: %s)\n", *allocation)
+		os.Exit(1)
+	}
+	if *reserve != "" {
+		reserved, err := parsePriorityValues("reserve", *reserve)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		scheduleOpts = append(scheduleOpts, scheduler.WithReservedCapacity(reserved))
+	}
+	if *poolsPath != "" {
+		pools, err := loadAgentPools(ctx, *poolsPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		scheduleOpts = append(scheduleOpts, scheduler.WithAgentPools(pools))
+	}
+	if *allocation == "optimal" {
+		scheduleOpts = append(scheduleOpts, scheduler.WithAllocator(scheduler.OptimalAllocator{Weights: weights}))
+	}
+
+	// Skip the whole run, side effects included, when it would only rewrite
+	// the output it wrote last time
+	var state runState
+	if *skipUnchanged {
+		state, err = newRunState(ctx, source, runOptions{
+			Formats:      formats,
+			Utilization:  *utilization,
+			MaxOccupancy: *maxOccupancy,
+			Peakedness:   *peakedness,
+			Capacity:     *capacity,
+			Lenient:      *lenient,
+			Interval:     *interval,
+			MultiDay:     *multiDay,
+			UTC:          *utc,
+			Staffing:     staffingOptions(*staffingModel, *slaTarget, *slaThreshold, *poissonPercentile),
+			Allocation:   *allocation,
+			Reserve:      *reserve,
+			Weights:      *priorityWeights,
+			Smoothing:    smoothingOptions(*smoothingWindow, *smoothingMaxDelta),
+			FTE:          fteOptions(*fte, *precision),
+			OutputTZ:     *outputTZ,
+			Metadata:     *withMetadata,
+		}, map[string]string{"weekly-pattern": *weeklyPattern, "calendar": *calendar, "seasonality": *seasonality, "arrival-curves": *arrivalCurves, "pools": *poolsPath})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if state.unchanged(ctx, *outputPath) {
+			fmt.Fprintf(os.Stderr, "Input and options unchanged since the last run; keeping %s\n", *outputPath)
+			return
+		}
+	}
+
+	// Set up tracing if an exporter endpoint was provided
+	shutdownTracing := func(context.Context) error { return nil }
+	if *otlpEndpoint != "" {
+		shutdown, err := tracing.Setup(ctx, *otlpEndpoint, *otlpInsecure)
+		if err != nil {
+			fmt.Printf("Error setting up tracing: %v\n", err)
+			os.Exit(1)
+		}
+		shutdownTracing = shutdown
+		tracing.SetDebug(*traceDebug)
+	}
+
+	pushCfg := metrics.PushConfig{
+		URL:      *pushGateway,
+		Job:      *pushJob,
+		Grouping: grouping,
+		Retries:  *pushRetries,
+		Backoff:  500 * time.Millisecond,
+		Timeout:  *pushTimeout,
+	}
+	remoteWriteCfg := metrics.RemoteWriteConfig{
+		URL:     *remoteWriteURL,
+		Job:     *pushJob,
+		Labels:  grouping,
+		Headers: headers,
+		Retries: *pushRetries,
+		Backoff: 500 * time.Millisecond,
+		Timeout: *pushTimeout,
+	}
+
+	var schedule *models.Schedule
+	var outputs []string
+	if *fromSchedule != "" {
+		schedule, outputs, err = reallocate(ctx, *fromSchedule, formats, formatOpts, *capacity, *workers, scheduleOpts...)
+	} else {
+		schedule, outputs, err = run(ctx, *input, formats, formatOpts, *utilization, *capacity, *lenient, *workers, scheduleOpts...)
+	}
+	metrics.RecordRunResult(err)
+	// Flush spans before any exit path
+	if shutdownErr := shutdownTracing(ctx); shutdownErr != nil {
+		fmt.Fprintf(os.Stderr, "Error flushing traces: %v\n", shutdownErr)
+	}
+	if err != nil {
+		fmt.Printf("Error %v\n", err)
+		// Push the failure so freshness alerts see it
+		pushMetrics(ctx, pushCfg, remoteWriteCfg)
+		os.Exit(1)
+	}
+	switch {
+	case *outputPath == "":
+		fmt.Print(outputs[0])
+	case len(outputs) == 1:
+		if err := writeOutput(ctx, *outputPath, outputs[0]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing output: %v\n", err)
+			os.Exit(1)
+		}
+	default:
+		for i, f := range formats {
+			if err := writeOutput(ctx, *outputPath+formatExtensions[f], outputs[i]); err != nil {
+				fmt.Fprintf(os.Stderr, "Error writing %s output: %v\n", f, err)
+				os.Exit(1)
+			}
+		}
+	}
+	if *skipUnchanged {
+		state.RunID = schedule.Metadata.RunID
+		state.GeneratedAt = schedule.Metadata.GeneratedAt
+		if err := state.save(ctx, *outputPath); err != nil {
+			fmt.Fprintf(os.Stderr, "Error saving run state: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if *storePath != "" {
+		if err := saveSchedule(ctx, *storePath, schedule); err != nil {
+			fmt.Fprintf(os.Stderr, "Error storing schedule: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if *saveSchedulePath != "" {
+		if err := writeSchedule(ctx, *saveSchedulePath, schedule); err != nil {
+			fmt.Fprintf(os.Stderr, "Error saving schedule: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if *diffPath != "" {
+		previous, err := loadSchedule(ctx, *diffPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading previous schedule: %v\n", err)
+			os.Exit(1)
+		}
+		diff := previous.Diff(schedule)
+		if previous.Metadata != nil {
+			fmt.Printf("\nChanges from run %s:\n", previous.Metadata.RunID)
+		} else {
+			fmt.Println("\nChanges from previous schedule:")
+		}
+		fmt.Print(formatter.FormatDiff(diff))
+		if !diff.Empty() {
+			fmt.Println("\nBy customer:")
+			fmt.Print(formatter.FormatCustomerDeltas(diff))
+		}
+	}
+
+	if *webhookURL != "" {
+		err := webhook.Send(ctx, webhook.Config{
+			URL:              *webhookURL,
+			Headers:          hookHeaders,
+			ShortfallPercent: *webhookShortfallPercent,
+			ScheduleURL:      *webhookScheduleURL,
+			AttachSchedule:   *webhookAttach,
+			Retries:          3,
+			Backoff:          500 * time.Millisecond,
+			Timeout:          10 * time.Second,
+		}, schedule)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error sending webhook: %v\n", err)
+		}
+	}
+
+	if *grafanaURL != "" {
+		err := metrics.Annotate(ctx, metrics.AnnotationConfig{
+			URL:          *grafanaURL,
+			Token:        os.Getenv("GRAFANA_TOKEN"),
+			DashboardUID: *grafanaDashboard,
+			PanelID:      *grafanaPanel,
+			Tags:         splitList(*grafanaTags),
+			Retries:      3,
+			Backoff:      500 * time.Millisecond,
+			Timeout:      10 * time.Second,
+		}, runAnnotation(schedule))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error posting Grafana annotation: %v\n", err)
+		}
+	}
+
+	if *twilioExport != "" || *twilioSyncService != "" {
+		opts := []twilio.Option{twilio.WithWorkspace(*twilioWorkspace), twilio.WithTaskChannel(*twilioChannel)}
+		if err := exportTwilio(ctx, schedule, opts, *twilioExport, *twilioSyncService, *twilioSyncDocument); err != nil {
+			fmt.Fprintf(os.Stderr, "Error exporting to Twilio: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if *wfmExport != "" {
+		if err := exportWFM(ctx, schedule, *wfmExport, *wfmFormat, *wfmDate); err != nil {
+			fmt.Fprintf(os.Stderr, "Error exporting WFM requirements: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	var plan *shifts.Plan
+	if *shiftsOutput != "" {
+		if plan, err = exportShifts(ctx, schedule, *shiftsOutput, *shiftLength, *shiftStarts, *shiftBreaks); err != nil {
+			fmt.Fprintf(os.Stderr, "Error exporting shifts: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if *rosterInput != "" {
+		if err := assignRoster(ctx, schedule, plan, *rosterInput, *assignmentsOutput, *rosterDate); err != nil {
+			fmt.Fprintf(os.Stderr, "Error assigning roster: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	// Evaluate built-in alerts before metrics are pushed or scraped
+	alerts := alerting.Evaluate(schedule, alerting.Thresholds{
+		UnmetPercent:             *alertUnmetPercent,
+		HighPriorityUnmetPercent: *alertHighPriorityUnmetPercent,
+	})
+	logger := slog.New(slog.NewJSONHandler(os.Stderr, nil))
+	alertFiring := alerting.Report(logger, alerts)
+	if *pager != "" {
+		if err := pageHighPriority(ctx, *pager, schedule, alerts); err != nil {
+			fmt.Fprintf(os.Stderr, "Error paging: %v\n", err)
+		}
+	}
+
+	// Handle metrics pushing or waiting
+	pushMetrics(ctx, pushCfg, remoteWriteCfg)
+
+	if *wait && *metricsAddr != "" {
+		fmt.Println("\nProcess kept alive for metric scraping. Press Ctrl+C to exit.")
+		// Wait for interrupt signal
+		c := make(chan os.Signal, 1)
+		signal.Notify(c, os.Interrupt, syscall.SIGTERM)
+		<-c
+		fmt.Println("\nExiting...")
+	}
+
+	if alertFiring && *alertExitCode != 0 {
+		os.Exit(*alertExitCode)
+	}
+}
+
+// formatExtensions are appended to -output when writing several formats.
+var formatExtensions = map[string]string{"text": ".txt", "json": ".json", "csv": ".csv"}
+
+// writeOutput writes the rendered schedule to the file or blob at uri.
+func writeOutput(ctx context.Context, uri, output string) error {
+	w, err := blob.Create(ctx, uri)
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(w, output); err != nil {
+		w.Close()
+		return err
+	}
+	return w.Close()
+}
+
+// saveSchedule records schedule in the SQLite store at path.
+func saveSchedule(ctx context.Context, path string, schedule *models.Schedule) error {
+	s, err := store.OpenSQLite(ctx, path)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+	return s.Save(ctx, schedule)
+}
+
+// writeSchedule writes schedule as JSON to the file or blob at uri.
+func writeSchedule(ctx context.Context, uri string, schedule *models.Schedule) error {
+	w, err := blob.Create(ctx, uri)
+	if err != nil {
+		return err
+	}
+	if err := schedule.Save(w); err != nil {
+		w.Close()
+		return err
+	}
+	return w.Close()
+}
+
+// loadSchedule reads a schedule written by writeSchedule from uri.
+func loadSchedule(ctx context.Context, uri string) (*models.Schedule, error) {
+	r, err := blob.Open(ctx, uri)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return models.Load(r)
+}
+
+// runAnnotation summarizes a run for a Grafana annotation: its totals and
+// the hours it fell short in.
+func runAnnotation(schedule *models.Schedule) metrics.Annotation {
+	summary := schedule.Summary()
+	text := fmt.Sprintf("Agent schedule generated: demand=%d, allocated=%d, unmet=%d", summary.TotalDemand, summary.TotalAgents, summary.TotalUnmet)
+	if hours := schedule.HoursWithShortfall(); len(hours) > 0 {
+		slots := make([]string, len(hours))
+		for i, h := range hours {
+			slots[i] = schedule.SlotAt(h).String()
+		}
+		text += ", unmet hours: " + strings.Join(slots, " ")
+	}
+
+	a := metrics.Annotation{Time: time.Now(), Text: text}
+	if md := schedule.Metadata; md != nil {
+		a.Time = md.GeneratedAt
+		a.Text += " (run " + md.RunID + ")"
+	}
+	if summary.TotalUnmet > 0 {
+		a.Tags = []string{"shortfall"}
+	}
+	return a
+}
+
+// resolveValues replaces secret references among values, e.g. header
+// values, with the secrets they refer to.
+func resolveValues(ctx context.Context, resolver *secrets.Resolver, values map[string]string) error {
+	for name, value := range values {
+		secret, err := resolver.Resolve(ctx, value)
+		if err != nil {
+			return fmt.Errorf("resolving %s: %w", name, err)
+		}
+		values[name] = secret
+	}
+	return nil
+}
+
+// splitList splits a comma-separated list, dropping empty items.
+func splitList(s string) []string {
+	var items []string
+	for item := range strings.SplitSeq(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// exportTwilio writes the staffing plan for schedule to path and/or
+// publishes it to the Sync document in service, whichever are set.
+func exportTwilio(ctx context.Context, schedule *models.Schedule, opts []twilio.Option, path, service, document string) error {
+	if path != "" {
+		f, err := blob.Create(ctx, path)
+		if err != nil {
+			return err
+		}
+		if err := twilio.Write(f, schedule, opts...); err != nil {
+			f.Close()
+			return err
+		}
+		if err := f.Close(); err != nil {
+			return err
+		}
+	}
+	if service == "" {
+		return nil
+	}
+	accountSID, authToken := os.Getenv("TWILIO_ACCOUNT_SID"), os.Getenv("TWILIO_AUTH_TOKEN")
+	if accountSID == "" || authToken == "" {
+		return errors.New("TWILIO_ACCOUNT_SID and TWILIO_AUTH_TOKEN must be set to publish to Twilio Sync")
+	}
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	client := &twilio.Client{AccountSID: accountSID, AuthToken: authToken}
+	return client.Publish(ctx, service, document, twilio.NewStaffingPlan(schedule, opts...))
+}
+
+// exportWFM writes schedule to path in the named WFM import format, placing
+// it on dateStr, or today when empty.
+func exportWFM(ctx context.Context, schedule *models.Schedule, path, formatName, dateStr string) error {
+	format, err := wfm.ParseFormat(formatName)
+	if err != nil {
+		return err
+	}
+	date := time.Now()
+	if dateStr != "" {
+		if date, err = time.Parse(time.DateOnly, dateStr); err != nil {
+			return fmt.Errorf("invalid -wfm-date: %w", err)
+		}
+	}
+
+	f, err := blob.Create(ctx, path)
+	if err != nil {
+		return err
+	}
+	if err := wfm.Write(f, format, schedule, date); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// exportShifts writes the shifts of length, starting at the times of day in
+// starts and with the breaks in breaks, that staff schedule to path, and
+// returns them.
+func exportShifts(ctx context.Context, schedule *models.Schedule, path string, length time.Duration, starts, breaks string) (*shifts.Plan, error) {
+	cfg := shifts.Config{Length: length}
+	if starts != "" {
+		for s := range strings.SplitSeq(starts, ",") {
+			t, err := time.Parse("15:04", strings.TrimSpace(s))
+			if err != nil {
+				return nil, fmt.Errorf("invalid -shift-starts time %q: must be HH:MM", s)
+			}
+			cfg.Starts = append(cfg.Starts, time.Duration(t.Hour())*time.Hour+time.Duration(t.Minute())*time.Minute)
+		}
+	}
+	if breaks != "" {
+		for s := range strings.SplitSeq(breaks, ",") {
+			lengthStr, everyStr, repeats := strings.Cut(strings.TrimSpace(s), "/")
+			var b shifts.Break
+			var err error
+			if b.Length, err = time.ParseDuration(lengthStr); err != nil {
+				return nil, fmt.Errorf("invalid -shift-breaks break %q: %w", s, err)
+			}
+			if repeats {
+				if b.Every, err = time.ParseDuration(everyStr); err != nil {
+					return nil, fmt.Errorf("invalid -shift-breaks break %q: %w", s, err)
+				}
+			}
+			cfg.Breaks = append(cfg.Breaks, b)
+		}
+	}
+	plan, err := shifts.Generate(schedule, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := blob.Create(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	if err := shifts.Write(f, plan); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return plan, f.Close()
+}
+
+// pageHighPriority opens an incident with the named service if the
+// priority-1 unmet alert fired.
+func pageHighPriority(ctx context.Context, service string, schedule *models.Schedule, alerts []alerting.Alert) error {
+	var (
+		p      alerting.Pager
+		keyEnv string
+	)
+	switch service {
+	case "pagerduty":
+		keyEnv = "PAGERDUTY_ROUTING_KEY"
+		p = &alerting.PagerDuty{RoutingKey: os.Getenv(keyEnv)}
+	case "opsgenie":
+		keyEnv = "OPSGENIE_API_KEY"
+		p = &alerting.Opsgenie{APIKey: os.Getenv(keyEnv)}
+	default:
+		return fmt.Errorf("unknown pager %q: must be pagerduty or opsgenie", service)
+	}
+	if os.Getenv(keyEnv) == "" {
+		return fmt.Errorf("%s must be set to page with %s", keyEnv, service)
+	}
+
+	var page []alerting.Alert
+	for _, a := range alerts {
+		if a.Name == alerting.AlertHighPriorityUnmetPercent {
+			page = append(page, a)
+		}
+	}
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	return alerting.Page(ctx, p, schedule, page)
+}
+
+// pushMetrics sends the Registry to the Pushgateway and/or remote-write
+// endpoint, whichever are configured.
+func pushMetrics(ctx context.Context, pushCfg metrics.PushConfig, remoteWriteCfg metrics.RemoteWriteConfig) {
+	if pushCfg.URL != "" {
+		if err := metrics.Push(ctx, pushCfg); err != nil {
+			fmt.Fprintf(os.Stderr, "Error pushing to Pushgateway: %v\n", err)
+		} else {
+			fmt.Println("\nMetrics successfully pushed to Pushgateway")
+		}
+	}
+	if remoteWriteCfg.URL != "" {
+		if err := metrics.RemoteWrite(ctx, remoteWriteCfg); err != nil {
+			fmt.Fprintf(os.Stderr, "Error sending remote write: %v\n", err)
+		} else {
+			fmt.Println("\nMetrics successfully sent via remote write")
+		}
+	}
+}
+
+// loadWeeklyPattern reads the weekday multipliers at uri.
+func loadWeeklyPattern(ctx context.Context, uri string) (models.WeeklyPattern, error) {
+	in, err := blob.Open(ctx, uri)
+	if err != nil {
+		return nil, fmt.Errorf("opening weekly pattern: %w", err)
+	}
+	defer in.Close()
+	pattern, err := parser.ParseWeeklyPattern(in)
+	if err != nil {
+		return nil, fmt.Errorf("parsing weekly pattern: %w", err)
+	}
+	return pattern, nil
+}
+
+// loadCalendar reads the holidays and blackout windows at uri.
+func loadCalendar(ctx context.Context, uri string) (models.Calendar, error) {
+	in, err := blob.Open(ctx, uri)
+	if err != nil {
+		return nil, fmt.Errorf("opening calendar: %w", err)
+	}
+	defer in.Close()
+	cal, err := parser.ParseCalendar(in)
+	if err != nil {
+		return nil, fmt.Errorf("parsing calendar: %w", err)
+	}
+	return cal, nil
+}
+
+// loadSeasonality reads the seasonal multipliers at uri.
+func loadSeasonality(ctx context.Context, uri string) (models.Seasonality, error) {
+	in, err := blob.Open(ctx, uri)
+	if err != nil {
+		return nil, fmt.Errorf("opening seasonality: %w", err)
+	}
+	defer in.Close()
+	seasonality, err := parser.ParseSeasonality(in)
+	if err != nil {
+		return nil, fmt.Errorf("parsing seasonality: %w", err)
+	}
+	return seasonality, nil
+}
+
+// loadArrivalCurves reads the intra-slot arrival curves at uri.
+func loadArrivalCurves(ctx context.Context, uri string) (models.ArrivalCurves, error) {
+	in, err := blob.Open(ctx, uri)
+	if err != nil {
+		return nil, fmt.Errorf("opening arrival curves: %w", err)
+	}
+	defer in.Close()
+	curves, err := parser.ParseArrivalCurves(in)
+	if err != nil {
+		return nil, fmt.Errorf("parsing arrival curves: %w", err)
+	}
+	return curves, nil
+}
+
+// parsePriorityValues parses the priority=value pairs of the flag called
+// name, such as -reserve's percents.
+func parsePriorityValues(name, s string) (map[models.Priority]float64, error) {
+	pairs, err := metrics.ParseGrouping(s)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	values := make(map[models.Priority]float64, len(pairs))
+	for key, value := range pairs {
+		priority, err := models.ParsePriority(key)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		v, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s: invalid value %q for priority %s", name, value, key)
+		}
+		values[priority] = v
+	}
+	return values, nil
+}
+
+// loadAgentPools reads the agent pools at uri.
+func loadAgentPools(ctx context.Context, uri string) ([]models.AgentPool, error) {
+	in, err := blob.Open(ctx, uri)
+	if err != nil {
+		return nil, fmt.Errorf("opening agent pools: %w", err)
+	}
+	defer in.Close()
+	pools, err := parser.ParseAgentPools(in)
+	if err != nil {
+		return nil, fmt.Errorf("parsing agent pools: %w", err)
+	}
+	return pools, nil
+}
+
+// run parses the input file, generates the schedule, and renders it in each
+// of formats, all under a single root span so the phases show up as one
+// trace. The phases overlap: batches of parsed rows are expanded on up to workers goroutines
+// while later rows are still being read and parsed on as many again. Each
+// customer's demand is aggregated per hour as it is read, so memory does not
+// grow with the number of rows. opts are applied after the flag-derived
+// options, e.g. to pick the interval or staffing model.
+func run(ctx context.Context, input string, formats []string, formatOpts []formatter.Option, utilization float64, capacity int, lenient bool, workers int, opts ...scheduler.Option) (*models.Schedule, []string, error) {
+	ctx, span := tracing.Tracer("github.com/karthikrao-23/agentscheduler/cmd/agent-scheduler").Start(ctx, "agent-scheduler.run")
+	defer span.End()
+
+	// Open input file or blob
+	file, err := blob.Open(ctx, input)
+	if err != nil {
+		return nil, nil, fmt.Errorf("opening file: %w", err)
+	}
+	defer file.Close()
+
+	builder, err := scheduler.NewBuilder(ctx, append([]scheduler.Option{
+		scheduler.WithUtilization(utilization),
+		scheduler.WithCapacity(capacity),
+		scheduler.WithWorkers(workers),
+	}, opts...)...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("generating schedule: %w", err)
+	}
+	err = parser.Stream(ctx, file, builder.Add, parser.WithLenient(lenient), parser.WithWorkers(workers))
+	schedule, buildErr := builder.Build()
+	if err != nil {
+		// Build was only needed to end the run
+		return nil, nil, fmt.Errorf("parsing file: %w", err)
+	}
+	if buildErr != nil {
+		return nil, nil, fmt.Errorf("generating schedule: %w", buildErr)
+	}
+
+	// Output based on format
+	outputs, err := formatter.FormatAll(ctx, formats, schedule, formatOpts...)
+	return schedule, outputs, err
+}
+
+// reallocate loads the schedule saved at path, allocates its demand again
+// under capacity and opts, and renders it in each of formats, as run does
+// for an input file.
+func reallocate(ctx context.Context, path string, formats []string, formatOpts []formatter.Option, capacity int, workers int, opts ...scheduler.Option) (*models.Schedule, []string, error) {
+	ctx, span := tracing.Tracer("github.com/karthikrao-23/agentscheduler/cmd/agent-scheduler").Start(ctx, "agent-scheduler.reallocate")
+	defer span.End()
+
+	saved, err := loadSchedule(ctx, path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("loading schedule: %w", err)
+	}
+	schedule, err := scheduler.Reallocate(ctx, saved, append([]scheduler.Option{
+		scheduler.WithCapacity(capacity),
+		scheduler.WithWorkers(workers),
+	}, opts...)...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("generating schedule: %w", err)
+	}
+	outputs, err := formatter.FormatAll(ctx, formats, schedule, formatOpts...)
+	return schedule, outputs, err
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+
+	customerrors "github.com/karthikrao-23/agentscheduler/pkg/errors"
+	"github.com/karthikrao-23/agentscheduler/pkg/formatter"
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+	"github.com/karthikrao-23/agentscheduler/pkg/store"
+)
+
+// planCommand implements "agent-scheduler plan", which generates a schedule,
+// records it in the store without publishing it, and prints how it differs
+// from the published schedule. It returns the process exit code.
+func planCommand(ctx context.Context, args []string) int {
+	fs := flag.NewFlagSet("plan", flag.ContinueOnError)
+	storePath := fs.String("store", "", "SQLite database holding the published schedule (required)")
+	input := fs.String("input", "", "Input CSV file or az://, wasbs://, or sftp:// URI (required)")
+	lenient := fs.Bool("lenient", false, "Skip invalid input rows instead of failing")
+	utilization := fs.Float64("utilization", 1.0, "Utilization multiplier (between 0 and 1)")
+	capacity := fs.Int("capacity", 0, "Maximum agent capacity per hour (0 = unlimited)")
+	workers := fs.Int("workers", runtime.GOMAXPROCS(0), "Goroutines to parse and schedule the input on, each (1 = no parallelism)")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *storePath == "" || *input == "" {
+		fmt.Fprintln(os.Stderr, "Error: -store and -input are required")
+		return 2
+	}
+
+	s, err := store.OpenSQLite(ctx, *storePath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	defer s.Close()
+
+	planned, _, err := run(ctx, *input, []string{"text"}, nil, *utilization, *capacity, *lenient, *workers)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	published, err := s.Published(ctx)
+	if err != nil && !errors.Is(err, customerrors.ErrScheduleNotFound) {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	if err := s.Save(ctx, planned); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	if published == nil {
+		fmt.Println("Nothing is published yet; applying publishes the whole schedule.")
+		published = &models.Schedule{}
+	} else {
+		fmt.Printf("Changes from published run %s:\n", published.Metadata.RunID)
+	}
+	fmt.Print(formatter.FormatDiff(published.Diff(planned)))
+	fmt.Printf("\nPlanned run %s. To publish it:\n  agent-scheduler apply -store %s -token %s %s\n",
+		planned.Metadata.RunID, *storePath, approvalToken(published, planned.Metadata.RunID), planned.Metadata.RunID)
+	return 0
+}
+
+// applyCommand implements "agent-scheduler apply", which publishes a planned
+// run. With -token, it refuses to publish if the published schedule changed
+// since the plan was made. It returns the process exit code.
+func applyCommand(ctx context.Context, args []string) int {
+	fs := flag.NewFlagSet("apply", flag.ContinueOnError)
+	storePath := fs.String("store", "", "SQLite database holding the published schedule (required)")
+	token := fs.String("token", "", "Approval token printed by plan; rejects the apply if the published schedule has changed since")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *storePath == "" || fs.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "Usage: agent-scheduler apply -store schedules.db [-token token] <run-id>")
+		return 2
+	}
+	runID := fs.Arg(0)
+
+	s, err := store.OpenSQLite(ctx, *storePath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	defer s.Close()
+
+	if *token != "" {
+		published, err := s.Published(ctx)
+		if errors.Is(err, customerrors.ErrScheduleNotFound) {
+			published = &models.Schedule{}
+		} else if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		if approvalToken(published, runID) != *token {
+			fmt.Fprintln(os.Stderr, "Error: approval token does not match; the published schedule has changed since the plan, so re-run plan")
+			return 1
+		}
+	}
+	if err := s.Publish(ctx, runID); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	fmt.Printf("Published run %s\n", runID)
+	return 0
+}
+
+// approvalToken ties a plan to the schedule that was published when it was
+// made, so applying a stale plan can be detected.
+func approvalToken(published *models.Schedule, runID string) string {
+	var publishedID string
+	if published.Metadata != nil {
+		publishedID = published.Metadata.RunID
+	}
+	sum := sha256.Sum256([]byte(publishedID + "\n" + runID))
+	return hex.EncodeToString(sum[:8])
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/karthikrao-23/agentscheduler/pkg/blob"
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+	"github.com/karthikrao-23/agentscheduler/pkg/roster"
+	"github.com/karthikrao-23/agentscheduler/pkg/shifts"
+)
+
+// rosterCommand implements "agent-scheduler roster", which loads agents from
+// a roster CSV or Workday report, validates them, and writes normalized
+// roster CSV for roster assignment. It returns the process exit code.
+func rosterCommand(ctx context.Context, args []string) int {
+	fs := flag.NewFlagSet("roster", flag.ContinueOnError)
+	source := fs.String("source", "csv", "System to load agents from: csv|workday")
+	input := fs.String("input", "", "Roster CSV file or az://, wasbs://, or sftp:// URI for -source csv (required)")
+	url := fs.String("url", "", "Workday report (RaaS) URL for -source workday (required)")
+	output := fs.String("output", "", "File or az://, wasbs://, or sftp:// URI to write the roster CSV to (default: stdout)")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	var agents []models.Agent
+	var err error
+	switch *source {
+	case "csv":
+		if *input == "" {
+			fmt.Fprintln(os.Stderr, "Error: -input is required for -source csv")
+			return 2
+		}
+		var r io.ReadCloser
+		if r, err = blob.Open(ctx, *input); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		agents, err = roster.ParseCSV(r)
+		r.Close()
+	case "workday":
+		if *url == "" {
+			fmt.Fprintln(os.Stderr, "Error: -url is required for -source workday")
+			return 2
+		}
+		agents, err = (&roster.Workday{
+			ReportURL: *url,
+			Username:  os.Getenv("WORKDAY_USERNAME"),
+			Password:  os.Getenv("WORKDAY_PASSWORD"),
+		}).Agents(ctx)
+	default:
+		fmt.Fprintf(os.Stderr, "Error: source must be one of: csv, workday (got: %s)\n", *source)
+		return 2
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	var w io.WriteCloser = os.Stdout
+	if *output != "" {
+		if w, err = blob.Create(ctx, *output); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+	}
+	err = roster.WriteCSV(w, agents)
+	if *output != "" {
+		err = errors.Join(err, w.Close())
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	fmt.Fprintf(os.Stderr, "Loaded %d agents from %s\n", len(agents), *source)
+	return 0
+}
+
+// assignRoster assigns the agents in the roster at uri to schedule, or to
+// plan's shifts when there are any, placing a generic day on dateStr, or
+// today when empty. It writes the assignments to path, if set, and reports
+// any coverage gaps on stderr.
+func assignRoster(ctx context.Context, schedule *models.Schedule, plan *shifts.Plan, uri, path, dateStr string) error {
+	date := time.Now()
+	if dateStr != "" {
+		var err error
+		if date, err = time.Parse(time.DateOnly, dateStr); err != nil {
+			return fmt.Errorf("invalid -roster-date: %w", err)
+		}
+	}
+	r, err := blob.Open(ctx, uri)
+	if err != nil {
+		return err
+	}
+	agents, err := roster.ParseCSV(r)
+	r.Close()
+	if err != nil {
+		return err
+	}
+
+	var coverage *roster.Coverage
+	if plan != nil {
+		coverage = roster.AssignShifts(plan, agents, date)
+	} else {
+		coverage = roster.Assign(schedule, agents, date)
+	}
+	if path != "" {
+		w, err := blob.Create(ctx, path)
+		if err != nil {
+			return err
+		}
+		if err := roster.WriteCoverage(w, coverage); err != nil {
+			w.Close()
+			return err
+		}
+		if err := w.Close(); err != nil {
+			return err
+		}
+	}
+	if short := coverage.Short(); short > 0 {
+		fmt.Fprintf(os.Stderr, "Roster of %d agents leaves %d gaps, short %d agents in all\n", len(agents), len(coverage.Gaps), short)
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/karthikrao-23/agentscheduler/pkg/formatter"
+	"github.com/karthikrao-23/agentscheduler/pkg/store"
+)
+
+const runsUsage = `Usage:
+  agent-scheduler runs list -store schedules.db [-limit 20]
+  agent-scheduler runs show -store schedules.db [-format text|json|csv] <run-id>
+`
+
+// runsCommand implements "agent-scheduler runs", which reads back schedules
+// recorded with -store. It returns the process exit code.
+func runsCommand(ctx context.Context, args []string) int {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, runsUsage)
+		return 2
+	}
+
+	fs := flag.NewFlagSet("runs "+args[0], flag.ContinueOnError)
+	storePath := fs.String("store", "", "SQLite database written by -store (required)")
+	limit := fs.Int("limit", 20, "Maximum runs to list (0 = all)")
+	format := fs.String("format", "text", "Output format: text|json|csv")
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	}
+	if *storePath == "" {
+		fmt.Fprintln(os.Stderr, "Error: -store flag is required")
+		return 2
+	}
+
+	s, err := store.OpenSQLite(ctx, *storePath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	defer s.Close()
+
+	switch args[0] {
+	case "list":
+		runs, err := s.List(ctx, *limit)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "RUN ID\tGENERATED AT\tINPUT HASH\tDEMAND\tALLOCATED\tUNMET")
+		for _, r := range runs {
+			hash := r.InputHash
+			if len(hash) > 12 {
+				hash = hash[:12]
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%d\t%d\n", r.RunID, r.GeneratedAt.Format(time.RFC3339),
+				hash, r.TotalDemand, r.TotalAgents, r.TotalUnmet)
+		}
+		w.Flush()
+		return 0
+	case "show":
+		if fs.NArg() != 1 {
+			fmt.Fprint(os.Stderr, runsUsage)
+			return 2
+		}
+		schedule, err := s.Get(ctx, fs.Arg(0))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		if err := formatter.Write(ctx, os.Stdout, *format, schedule); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		return 0
+	default:
+		fmt.Fprint(os.Stderr, runsUsage)
+		return 2
+	}
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/karthikrao-23/agentscheduler/pkg/blob"
+	"github.com/karthikrao-23/agentscheduler/pkg/cache"
+	"github.com/karthikrao-23/agentscheduler/pkg/metrics"
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+	"github.com/karthikrao-23/agentscheduler/pkg/scheduler"
+	"github.com/karthikrao-23/agentscheduler/pkg/secrets"
+	"github.com/karthikrao-23/agentscheduler/pkg/server"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/redis/go-redis/v9"
+)
+
+// serveCommand implements "agent-scheduler serve", which exposes the
+// scheduler over HTTP. It returns the process exit code.
+func serveCommand(ctx context.Context, args []string) int {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	addr := fs.String("addr", ":8080", "Address to listen on")
+	utilization := fs.Float64("utilization", 1.0, "Default utilization multiplier (between 0 and 1)")
+	capacity := fs.Int("capacity", 0, "Default maximum agent capacity per hour (0 = unlimited)")
+	redisURL := fs.String("redis-url", "", "Redis URL to cache schedules in by input and options (e.g., redis://localhost:6379/0)")
+	cacheTTL := fs.Duration("cache-ttl", 5*time.Minute, "How long cached schedules are kept (0 = until evicted)")
+	profilesPath := fs.String("profiles", "", "JSON file of named run profiles to enable POST /trigger with (token from TRIGGER_TOKEN)")
+	tenantsPath := fs.String("tenants", "", "JSON file of tenants, each with its own API key, defaults, and profiles, to serve in isolation")
+	bus := addBusFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *tenantsPath != "" && (*profilesPath != "" || *bus.mqttURL != "" || *bus.natsURL != "") {
+		// A shared profile set or bus topic would mix the tenants' schedules
+		fmt.Fprintln(os.Stderr, "Error: -tenants cannot be combined with -profiles, -mqtt-url, or -nats-url")
+		return 2
+	}
+	metricsToken := os.Getenv("METRICS_TOKEN")
+	if *tenantsPath != "" && metricsToken == "" {
+		// The process-wide metrics describe whichever tenant ran last
+		fmt.Fprintln(os.Stderr, "Error: -tenants requires METRICS_TOKEN to authenticate /metrics")
+		return 2
+	}
+
+	opts := []server.Option{
+		server.WithScheduleOptions(scheduler.WithUtilization(*utilization), scheduler.WithCapacity(*capacity)),
+	}
+	if *redisURL != "" {
+		redisOpts, err := redis.ParseURL(*redisURL)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: invalid -redis-url: %v\n", err)
+			return 2
+		}
+		client := redis.NewClient(redisOpts)
+		defer client.Close()
+		opts = append(opts, server.WithCache(cache.NewRedis(client, *cacheTTL)))
+	}
+
+	if *profilesPath != "" {
+		token := os.Getenv("TRIGGER_TOKEN")
+		if token == "" {
+			fmt.Fprintln(os.Stderr, "Error: -profiles requires TRIGGER_TOKEN to authenticate /trigger")
+			return 2
+		}
+		f, err := os.Open(*profilesPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		profiles, err := server.LoadProfiles(f)
+		f.Close()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s: %v\n", *profilesPath, err)
+			return 1
+		}
+		opts = append(opts, server.WithTrigger(server.TriggerConfig{
+			Token:    token,
+			Profiles: profiles,
+			Open:     blob.Open,
+			Create:   blob.Create,
+		}))
+	}
+
+	if *tenantsPath != "" {
+		tenants, err := loadTenants(ctx, *tenantsPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s: %v\n", *tenantsPath, err)
+			return 1
+		}
+		opts = append(opts,
+			server.WithTenants(tenants...),
+			server.WithTrigger(server.TriggerConfig{Open: blob.Open, Create: blob.Create}),
+		)
+	}
+
+	publishers, err := bus.dial()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	defer closeAll(publishers)
+	if len(publishers) > 0 {
+		publish := publishToBus(publishers)
+		opts = append(opts, server.WithOnPublish(func(schedule *models.Schedule) {
+			publish(ctx, schedule)
+		}))
+	}
+
+	srv := server.New(opts...)
+	metricsHandler := metrics.InstrumentHandler("/metrics", promhttp.HandlerFor(metrics.Registry, promhttp.HandlerOpts{
+		// OpenMetrics is required to expose exemplars
+		EnableOpenMetrics: true,
+	}))
+	if metricsToken != "" {
+		srv.HandleWithToken("GET /metrics", metricsToken, metricsHandler)
+	} else {
+		srv.Handle("GET /metrics", metricsHandler)
+	}
+	httpServer := &http.Server{Addr: *addr, Handler: srv, ReadHeaderTimeout: 10 * time.Second}
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		_ = httpServer.Shutdown(shutdownCtx)
+	}()
+
+	fmt.Fprintf(os.Stderr, "Serving the scheduling API on %s (spec at /openapi.yaml)\n", *addr)
+	if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// loadTenants reads the -tenants file, resolving Vault and Key Vault
+// references in API keys.
+func loadTenants(ctx context.Context, path string) ([]server.Tenant, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	tenants, err := server.LoadTenants(f)
+	if err != nil {
+		return nil, err
+	}
+	resolver := secrets.NewResolver()
+	for i := range tenants {
+		if tenants[i].APIKey, err = resolver.Resolve(ctx, tenants[i].APIKey); err != nil {
+			return nil, fmt.Errorf("tenant %q: %w", tenants[i].Name, err)
+		}
+	}
+	return tenants, nil
+}
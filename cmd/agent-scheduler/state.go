@@ -0,0 +1,177 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/karthikrao-23/agentscheduler/pkg/blob"
+	"github.com/karthikrao-23/agentscheduler/pkg/version"
+)
+
+// runOptions are the flags that change what a batch run writes.
+type runOptions struct {
+	Formats     []string `json:"formats"`
+	Utilization float64  `json:"utilization"`
+	// MaxOccupancy is 0 when occupancy is not capped
+	MaxOccupancy float64 `json:"max_occupancy,omitempty"`
+	Peakedness   float64 `json:"peakedness"`
+	Capacity     int     `json:"capacity"`
+	Lenient      bool    `json:"lenient"`
+	// Interval is the slot length in minutes
+	Interval int  `json:"interval"`
+	MultiDay bool `json:"multi_day"`
+	UTC      bool `json:"utc,omitempty"`
+	// Staffing is the staffing model and its service level, e.g.
+	// "erlang-c 80/20", or its percentile, e.g. "poisson 95"
+	Staffing   string `json:"staffing"`
+	Allocation string `json:"allocation"`
+	Reserve    string `json:"reserve,omitempty"`
+	// Weights is -priority-weights, if given
+	Weights string `json:"priority_weights,omitempty"`
+	// Smoothing is the max delta and window, e.g. "20/2", if any
+	Smoothing string `json:"smoothing,omitempty"`
+	// FTE is the precision agents are written with under -fte, e.g.
+	// "fte/2", if set
+	FTE string `json:"fte,omitempty"`
+	// OutputTZ is -output-tz, if given
+	OutputTZ string `json:"output_tz,omitempty"`
+	// Metadata is -metadata
+	Metadata bool `json:"metadata,omitempty"`
+	// Files holds the SHA-256 of each extra input file, such as
+	// -weekly-pattern or -arrival-curves, by flag name
+	Files map[string]string `json:"files,omitempty"`
+}
+
+// staffingOptions describes the staffing flags for runOptions. The service
+// level only counts under Erlang C and the percentile under Poisson, so
+// changing either alone under another model does not force a rerun.
+func staffingOptions(model string, slaTarget float64, slaThreshold int, percentile float64) string {
+	switch model {
+	case "erlang-c":
+		return fmt.Sprintf("%s %g/%d", model, slaTarget, slaThreshold)
+	case "poisson":
+		return fmt.Sprintf("%s %g", model, percentile)
+	}
+	return model
+}
+
+// fteOptions describes the FTE flags for runOptions. The precision only
+// counts under -fte.
+func fteOptions(fte bool, precision int) string {
+	if !fte {
+		return ""
+	}
+	return fmt.Sprintf("fte/%d", precision)
+}
+
+// smoothingOptions describes the smoothing flags for runOptions. The window
+// only counts when there is a max delta.
+func smoothingOptions(window, maxDelta int) string {
+	if maxDelta == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d/%d", maxDelta, window)
+}
+
+func (o runOptions) equal(other runOptions) bool {
+	return slices.Equal(o.Formats, other.Formats) && o.Utilization == other.Utilization && o.MaxOccupancy == other.MaxOccupancy && o.Peakedness == other.Peakedness &&
+		o.Capacity == other.Capacity && o.Lenient == other.Lenient && o.Interval == other.Interval && o.MultiDay == other.MultiDay && o.UTC == other.UTC &&
+		o.Staffing == other.Staffing && o.Allocation == other.Allocation && o.Reserve == other.Reserve && o.Weights == other.Weights && o.Smoothing == other.Smoothing && o.FTE == other.FTE && o.OutputTZ == other.OutputTZ && o.Metadata == other.Metadata &&
+		maps.Equal(o.Files, other.Files)
+}
+
+// runState is what -skip-unchanged records next to the output: enough to
+// tell whether another run would write the same schedule.
+type runState struct {
+	// InputHash is the SHA-256 of the raw input file
+	InputHash string     `json:"input_hash"`
+	Options   runOptions `json:"options"`
+	// Version is the build that wrote the output; a new build regenerates
+	Version string `json:"version"`
+	// Date is the day the input's times were read on. The parser dates them
+	// today, so a new day can move them across a DST change
+	Date        string    `json:"date"`
+	RunID       string    `json:"run_id,omitempty"`
+	GeneratedAt time.Time `json:"generated_at"`
+}
+
+// statePath returns where the state for output is kept.
+func statePath(output string) string {
+	return output + ".state.json"
+}
+
+// newRunState hashes the input at uri for a run with opts, along with the
+// extra input files, keyed by flag name, that were given.
+func newRunState(ctx context.Context, uri string, opts runOptions, files map[string]string) (runState, error) {
+	inputHash, err := hashFile(ctx, uri)
+	if err != nil {
+		return runState{}, err
+	}
+	for name, file := range files {
+		if file == "" {
+			continue
+		}
+		if opts.Files == nil {
+			opts.Files = make(map[string]string)
+		}
+		if opts.Files[name], err = hashFile(ctx, file); err != nil {
+			return runState{}, err
+		}
+	}
+	return runState{
+		InputHash: inputHash,
+		Options:   opts,
+		Version:   version.String(),
+		Date:      time.Now().Format(time.DateOnly),
+	}, nil
+}
+
+// hashFile returns the hex SHA-256 of the file at uri.
+func hashFile(ctx context.Context, uri string) (string, error) {
+	in, err := blob.Open(ctx, uri)
+	if err != nil {
+		return "", fmt.Errorf("opening file: %w", err)
+	}
+	defer in.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, in); err != nil {
+		return "", fmt.Errorf("hashing %s: %w", uri, err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// unchanged reports whether the state stored for output was written for the
+// same input, options, build, and day as s. A missing or unreadable state
+// counts as changed, so the run goes ahead.
+func (s runState) unchanged(ctx context.Context, output string) bool {
+	in, err := blob.Open(ctx, statePath(output))
+	if err != nil {
+		return false
+	}
+	defer in.Close()
+	var previous runState
+	if err := json.NewDecoder(in).Decode(&previous); err != nil {
+		return false
+	}
+	return previous.InputHash == s.InputHash && previous.Options.equal(s.Options) &&
+		previous.Version == s.Version && previous.Date == s.Date
+}
+
+// save records s next to output, once output has been written.
+func (s runState) save(ctx context.Context, output string) error {
+	w, err := blob.Create(ctx, statePath(output))
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return errors.Join(enc.Encode(s), w.Close())
+}
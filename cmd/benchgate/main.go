@@ -0,0 +1,204 @@
+// Command benchgate compares benchmark results against a stored baseline and
+// fails when any benchmark has regressed past a threshold, so slowdowns and
+// new allocations are caught before a feature merges.
+//
+// Both inputs are `go test -bench -benchmem` output. With -count above 1,
+// each benchmark's median is compared, which keeps one noisy run from
+// failing the gate:
+//
+//	go test -run '^$' -bench . -benchmem -count 3 ./... > bench_output.txt
+//	go run ./cmd/benchgate -baseline testdata/bench_baseline.txt bench_output.txt
+//
+// Time per op depends on the machine, so the baseline should be recorded on
+// the machine the gate runs on (make bench-baseline). Bytes and allocations
+// per op do not, and have their own, tighter threshold.
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+func main() {
+	os.Exit(benchgate(os.Args[1:], os.Stdout))
+}
+
+// metric is a per-op measurement the gate checks, and the flag-set
+// threshold it is held to.
+type metric struct {
+	unit      string
+	threshold *float64
+}
+
+func benchgate(args []string, stdout io.Writer) int {
+	fs := flag.NewFlagSet("benchgate", flag.ContinueOnError)
+	baselinePath := fs.String("baseline", "testdata/bench_baseline.txt", "Benchmark output to compare against")
+	maxSlowdown := fs.Float64("max-slowdown", 20, "Fail when a benchmark's ns/op grows by more than this percentage")
+	maxAllocGrowth := fs.Float64("max-alloc-growth", 10, "Fail when a benchmark's B/op or allocs/op grows by more than this percentage")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: benchgate [flags] [results file, default stdin]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() > 1 {
+		fs.Usage()
+		return 2
+	}
+
+	baseline, err := readResultsFile(*baselinePath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	var current map[string]map[string]float64
+	if fs.NArg() == 0 || fs.Arg(0) == "-" {
+		current, err = readResults(os.Stdin)
+	} else {
+		current, err = readResultsFile(fs.Arg(0))
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	if len(current) == 0 {
+		fmt.Fprintln(os.Stderr, "Error: no benchmark results to check")
+		return 1
+	}
+
+	metrics := []metric{
+		{unit: "ns/op", threshold: maxSlowdown},
+		{unit: "B/op", threshold: maxAllocGrowth},
+		{unit: "allocs/op", threshold: maxAllocGrowth},
+	}
+	names := make([]string, 0, len(current))
+	for name := range current {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	tw := tabwriter.NewWriter(stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "BENCHMARK\tMETRIC\tBASELINE\tCURRENT\tDELTA\t")
+	regressions := 0
+	for _, name := range names {
+		base, ok := baseline[name]
+		if !ok {
+			fmt.Fprintf(tw, "%s\t\t\t\tnew\t\n", name)
+			continue
+		}
+		for _, m := range metrics {
+			before, okBefore := base[m.unit]
+			after, okAfter := current[name][m.unit]
+			if !okBefore || !okAfter {
+				continue
+			}
+			delta := percentChange(before, after)
+			verdict := ""
+			if delta > *m.threshold {
+				verdict = "REGRESSED"
+				regressions++
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%+.1f%%\t%s\n", name, m.unit, formatValue(before), formatValue(after), delta, verdict)
+		}
+	}
+	tw.Flush()
+
+	if regressions > 0 {
+		fmt.Fprintf(stdout, "\n%d measurement(s) regressed past the threshold\n", regressions)
+		return 1
+	}
+	return 0
+}
+
+// readResultsFile reads benchmark output from path.
+func readResultsFile(path string) (map[string]map[string]float64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	results, err := readResults(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return results, nil
+}
+
+// procsSuffix is the -GOMAXPROCS suffix go test adds to benchmark names.
+var procsSuffix = regexp.MustCompile(`-\d+$`)
+
+// readResults parses benchmark output into each benchmark's median value
+// per unit, keyed by name without the GOMAXPROCS suffix. Lines that are not
+// results are skipped.
+func readResults(r io.Reader) (map[string]map[string]float64, error) {
+	runs := make(map[string]map[string][]float64)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		// Benchmark<Name>-N <iterations> <value> <unit> [<value> <unit>]...
+		if len(fields) < 4 || !strings.HasPrefix(fields[0], "Benchmark") || len(fields)%2 != 0 {
+			continue
+		}
+		if _, err := strconv.Atoi(fields[1]); err != nil {
+			continue
+		}
+		name := procsSuffix.ReplaceAllString(fields[0], "")
+		if runs[name] == nil {
+			runs[name] = make(map[string][]float64)
+		}
+		for i := 2; i < len(fields); i += 2 {
+			value, err := strconv.ParseFloat(fields[i], 64)
+			if err != nil {
+				return nil, fmt.Errorf("benchmark %s: invalid value %q", name, fields[i])
+			}
+			runs[name][fields[i+1]] = append(runs[name][fields[i+1]], value)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	results := make(map[string]map[string]float64, len(runs))
+	for name, units := range runs {
+		results[name] = make(map[string]float64, len(units))
+		for unit, values := range units {
+			results[name][unit] = median(values)
+		}
+	}
+	return results, nil
+}
+
+func median(values []float64) float64 {
+	slices.Sort(values)
+	mid := len(values) / 2
+	if len(values)%2 == 0 {
+		return (values[mid-1] + values[mid]) / 2
+	}
+	return values[mid]
+}
+
+// percentChange returns how much after grew over before, in percent. Growth
+// from zero counts 100% for each unit gained.
+func percentChange(before, after float64) float64 {
+	switch {
+	case before == after:
+		return 0
+	case before == 0:
+		return 100 * after
+	default:
+		return 100 * (after - before) / before
+	}
+}
+
+func formatValue(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
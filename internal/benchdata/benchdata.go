@@ -0,0 +1,146 @@
+// Package benchdata generates the synthetic inputs the benchmarks run on:
+// call data shaped like a large multi-timezone forecast, at the sizes in
+// Sizes.
+package benchdata
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"time"
+
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+)
+
+// Sizes are the row counts every benchmark runs at.
+var Sizes = []int{1_000, 100_000, 1_000_000}
+
+// Name labels a sub-benchmark for n rows, e.g. "rows=100k".
+func Name(n int) string {
+	switch {
+	case n >= 1_000_000 && n%1_000_000 == 0:
+		return fmt.Sprintf("rows=%dM", n/1_000_000)
+	case n >= 1_000 && n%1_000 == 0:
+		return fmt.Sprintf("rows=%dk", n/1_000)
+	default:
+		return fmt.Sprintf("rows=%d", n)
+	}
+}
+
+// zones are cycled through in blocks of blockRows rows, each block under its
+// own timezone header in the CSV.
+var zones = []struct {
+	code string
+	name string
+}{
+	{"PT", "America/Los_Angeles"},
+	{"ET", "America/New_York"},
+	{"CT", "America/Chicago"},
+	{"UTC", "UTC"},
+	{"Europe/London", "Europe/London"},
+	{"Asia/Tokyo", "Asia/Tokyo"},
+}
+
+const blockRows = 500
+
+// row is one generated forecast row.
+type row struct {
+	customer   string
+	zone       int
+	duration   int
+	start, end int // minutes after midnight
+	calls      int
+	priority   int
+}
+
+// rows generates n rows deterministically. Customers repeat across rows, as
+// in a forecast listing each customer's windows, with about ten rows each.
+func rows(n int) []row {
+	rng := rand.New(rand.NewPCG(1, uint64(n)))
+	customers := max(n/10, 1)
+	out := make([]row, n)
+	for i := range out {
+		start := (6 + rng.IntN(10)) * 60
+		if rng.IntN(4) == 0 {
+			start += 30
+		}
+		out[i] = row{
+			customer: fmt.Sprintf("Customer %07d", rng.IntN(customers)),
+			zone:     (i / blockRows) % len(zones),
+			duration: 60 + rng.IntN(540),
+			start:    start,
+			end:      start + (1+rng.IntN(7))*60,
+			calls:    rng.IntN(20_000),
+			priority: 1 + rng.IntN(5),
+		}
+	}
+	return out
+}
+
+// CSV returns n rows of parser input, with a timezone header before each
+// block of rows.
+func CSV(n int) []byte {
+	var sb strings.Builder
+	for i, r := range rows(n) {
+		if i%blockRows == 0 {
+			code := zones[r.zone].code
+			fmt.Fprintf(&sb, "#CustomerName, AverageCallDurationSeconds, StartTime%s, EndTime%s, NumberOfCalls, Priority\n", code, code)
+		}
+		fmt.Fprintf(&sb, "%s, %d, %s, %s, %d, %d\n", r.customer, r.duration, clock(r.start), clock(r.end), r.calls, r.priority)
+	}
+	return []byte(sb.String())
+}
+
+// CallData returns the n rows CSV(n) parses to.
+func CallData(n int) []models.CallData {
+	locations := make([]*time.Location, len(zones))
+	for i, z := range zones {
+		loc, err := time.LoadLocation(z.name)
+		if err != nil {
+			panic(err)
+		}
+		locations[i] = loc
+	}
+
+	data := make([]models.CallData, n)
+	for i, r := range rows(n) {
+		loc := locations[r.zone]
+		year, month, day := time.Now().In(loc).Date()
+		data[i] = models.CallData{
+			CustomerName:               r.customer,
+			AverageCallDurationSeconds: r.duration,
+			StartTime:                  time.Date(year, month, day, 0, r.start, 0, 0, loc),
+			EndTime:                    time.Date(year, month, day, 0, r.end, 0, 0, loc),
+			Location:                   models.NewLocation(loc),
+			NumberOfCalls:              r.calls,
+			Priority:                   models.Priority(r.priority),
+		}
+	}
+	return data
+}
+
+// Requirements returns n requirements for a single hour, for benchmarking an
+// allocator on its own, along with half their total demand as a capacity
+// that leaves the allocator work to do.
+func Requirements(n int) ([]models.CustomerRequirement, int) {
+	reqs := make([]models.CustomerRequirement, n)
+	demand := 0
+	for i, r := range rows(n) {
+		reqs[i] = models.CustomerRequirement{
+			Name:         r.customer,
+			AgentsNeeded: 1 + r.calls%20,
+			Priority:     models.Priority(r.priority),
+		}
+		demand += reqs[i].AgentsNeeded
+	}
+	return reqs, demand / 2
+}
+
+// clock formats minutes after midnight in the parser's "3PM"/"3:04PM" form.
+func clock(minutes int) string {
+	t := time.Date(0, 1, 1, 0, minutes, 0, 0, time.UTC)
+	if t.Minute() == 0 {
+		return t.Format("3PM")
+	}
+	return t.Format("3:04PM")
+}
@@ -0,0 +1,26 @@
+package benchdata_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/karthikrao-23/agentscheduler/internal/benchdata"
+	"github.com/karthikrao-23/agentscheduler/pkg/parser"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCSV(t *testing.T) {
+	// The parser and scheduler benchmarks must run on the same rows
+	got, err := parser.Parse(bytes.NewReader(benchdata.CSV(2_000)))
+	require.NoError(t, err)
+	assert.Equal(t, benchdata.CallData(2_000), got)
+}
+
+func TestName(t *testing.T) {
+	assert.Equal(t, "rows=1k", benchdata.Name(1_000))
+	assert.Equal(t, "rows=100k", benchdata.Name(100_000))
+	assert.Equal(t, "rows=1M", benchdata.Name(1_000_000))
+	assert.Equal(t, "rows=1500", benchdata.Name(1_500))
+}
@@ -0,0 +1,87 @@
+// Package alerting evaluates simple built-in thresholds against a generated
+// schedule, giving deployments without a Prometheus rule stack basic alerting.
+package alerting
+
+import (
+	"log/slog"
+
+	"github.com/karthikrao-23/agentscheduler/pkg/metrics"
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+)
+
+// Alert names, also used as the alert label of scheduler_alert_firing.
+const (
+	AlertUnmetPercent             = "unmet_percent"
+	AlertHighPriorityUnmetPercent = "high_priority_unmet_percent"
+)
+
+// Thresholds configures when alerts fire. A zero threshold disables that alert.
+type Thresholds struct {
+	// UnmetPercent fires when unmet agents exceed this percentage of total demand
+	UnmetPercent float64
+	// HighPriorityUnmetPercent fires when unmet priority-1 agents exceed this
+	// percentage of priority-1 demand
+	HighPriorityUnmetPercent float64
+}
+
+// Alert describes a threshold evaluation.
+type Alert struct {
+	Name      string
+	Value     float64
+	Threshold float64
+	Firing    bool
+}
+
+// Evaluate checks every enabled threshold against the schedule.
+func Evaluate(schedule *models.Schedule, t Thresholds) []Alert {
+	highAllocated, highUnmet := schedule.PriorityTotals(1)
+
+	var alerts []Alert
+	if t.UnmetPercent > 0 {
+		alerts = append(alerts, newAlert(AlertUnmetPercent, unmetPercent(schedule.TotalUnmet(), schedule.TotalDemand()), t.UnmetPercent))
+	}
+	if t.HighPriorityUnmetPercent > 0 {
+		alerts = append(alerts, newAlert(AlertHighPriorityUnmetPercent, unmetPercent(highUnmet, highAllocated+highUnmet), t.HighPriorityUnmetPercent))
+	}
+	return alerts
+}
+
+// Report sets scheduler_alert_firing for each alert and logs a structured
+// warning for those that fire. It returns true if any alert fired.
+func Report(logger *slog.Logger, alerts []Alert) bool {
+	metrics.AlertFiring.Reset()
+
+	anyFiring := false
+	for _, a := range alerts {
+		value := 0.0
+		if a.Firing {
+			value = 1
+			anyFiring = true
+			logger.Warn("alert threshold breached",
+				slog.String("alert", a.Name),
+				slog.Float64("value", a.Value),
+				slog.Float64("threshold", a.Threshold),
+				slog.String("run_id", metrics.CurrentRunID()),
+			)
+		}
+		metrics.AlertFiring.WithLabelValues(a.Name).Set(value)
+	}
+	return anyFiring
+}
+
+func newAlert(name string, value, threshold float64) Alert {
+	return Alert{
+		Name:      name,
+		Value:     value,
+		Threshold: threshold,
+		Firing:    value > threshold,
+	}
+}
+
+// unmetPercent returns unmet as a percentage of demanded (0 with no demand).
+func unmetPercent(unmet, demanded int) float64 {
+	if demanded == 0 {
+		return 0
+	}
+	return float64(unmet) / float64(demanded) * 100
+}
@@ -0,0 +1,87 @@
+package alerting_test
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+
+	"github.com/karthikrao-23/agentscheduler/pkg/alerting"
+	"github.com/karthikrao-23/agentscheduler/pkg/metrics"
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvaluate(t *testing.T) {
+	// 10 allocated (8 priority 1), 5 unmet (2 priority 1)
+	schedule := &models.Schedule{
+		HourlyRequirements: func() [][]models.CustomerRequirement {
+			reqs := make([][]models.CustomerRequirement, 24)
+			reqs[10] = []models.CustomerRequirement{
+				{Name: "VIP", AgentsNeeded: 8, Priority: 1},
+				{Name: "Basic", AgentsNeeded: 2, Priority: 2},
+			}
+			return reqs
+		}(),
+		UnmetDemands: []models.UnmetDemand{
+			{
+				Hour: 10, TotalDemand: 15, AllocatedAgents: 10, UnmetAgents: 5,
+				ImpactedClients: []models.ImpactedClient{
+					{Name: "VIP", RequestedAgents: 10, AllocatedAgents: 8, UnmetAgents: 2, Priority: 1},
+					{Name: "Basic", RequestedAgents: 5, AllocatedAgents: 2, UnmetAgents: 3, Priority: 2},
+				},
+			},
+		},
+	}
+
+	tests := map[string]struct {
+		thresholds alerting.Thresholds
+		expected   []alerting.Alert
+	}{
+		"Disabled": {
+			thresholds: alerting.Thresholds{},
+			expected:   nil,
+		},
+		"UnmetPercent_Firing": {
+			thresholds: alerting.Thresholds{UnmetPercent: 5},
+			expected: []alerting.Alert{
+				{Name: alerting.AlertUnmetPercent, Value: 100.0 / 3, Threshold: 5, Firing: true},
+			},
+		},
+		"HighPriority_NotFiring": {
+			thresholds: alerting.Thresholds{HighPriorityUnmetPercent: 25},
+			expected: []alerting.Alert{
+				{Name: alerting.AlertHighPriorityUnmetPercent, Value: 20, Threshold: 25, Firing: false},
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			alerts := alerting.Evaluate(schedule, tt.thresholds)
+			assert.Equal(t, len(tt.expected), len(alerts))
+			for i := range tt.expected {
+				assert.Equal(t, tt.expected[i].Name, alerts[i].Name)
+				assert.InDelta(t, tt.expected[i].Value, alerts[i].Value, 0.001)
+				assert.Equal(t, tt.expected[i].Firing, alerts[i].Firing)
+			}
+		})
+	}
+}
+
+func TestReport(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+
+	firing := alerting.Report(logger, []alerting.Alert{
+		{Name: alerting.AlertUnmetPercent, Value: 10, Threshold: 5, Firing: true},
+		{Name: alerting.AlertHighPriorityUnmetPercent, Value: 0, Threshold: 1, Firing: false},
+	})
+
+	assert.True(t, firing)
+	assert.Equal(t, 1.0, testutil.ToFloat64(metrics.AlertFiring.WithLabelValues(alerting.AlertUnmetPercent)))
+	assert.Equal(t, 0.0, testutil.ToFloat64(metrics.AlertFiring.WithLabelValues(alerting.AlertHighPriorityUnmetPercent)))
+	assert.Contains(t, buf.String(), `"alert":"unmet_percent"`)
+	assert.NotContains(t, buf.String(), `"alert":"high_priority_unmet_percent"`)
+}
@@ -0,0 +1,206 @@
+package alerting
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+)
+
+// Default endpoints of the supported incident services.
+const (
+	DefaultPagerDutyURL = "https://events.pagerduty.com/v2/enqueue"
+	DefaultOpsgenieURL  = "https://api.opsgenie.com/v2/alerts"
+)
+
+// Incident is a page raised for a firing alert.
+type Incident struct {
+	// Summary is a one-line description of the breach
+	Summary string
+	// DedupKey identifies the incident so repeated pages for the same alert
+	// collapse into one, however many runs find the shortfall before it is
+	// resolved
+	DedupKey string
+	// RunID is the run that raised the page, reported in its details
+	RunID string
+	Alert Alert
+	// Impacted lists the priority-1 clients that did not get all the agents
+	// they needed, one per affected hour
+	Impacted []ImpactedClient
+}
+
+// ImpactedClient is a priority-1 client's shortfall in one hour.
+type ImpactedClient struct {
+	Slot            string `json:"slot"`
+	Name            string `json:"name"`
+	Location        string `json:"location,omitempty"`
+	RequestedAgents int    `json:"requested_agents"`
+	AllocatedAgents int    `json:"allocated_agents"`
+	UnmetAgents     int    `json:"unmet_agents"`
+}
+
+// NewIncident describes alert against schedule, including the impacted
+// priority-1 clients.
+func NewIncident(schedule *models.Schedule, alert Alert) Incident {
+	inc := Incident{
+		Summary: fmt.Sprintf("Agent schedule shortfall: %s at %.1f%% (threshold %.1f%%)", alert.Name, alert.Value, alert.Threshold),
+		Alert:   alert,
+	}
+	if md := schedule.Metadata; md != nil {
+		inc.RunID = md.RunID
+	}
+	inc.DedupKey = "agent-scheduler/" + alert.Name
+	for _, u := range schedule.UnmetDemands {
+		for _, c := range u.ImpactedClients {
+			if c.Priority != models.PriorityCritical {
+				continue
+			}
+			inc.Impacted = append(inc.Impacted, ImpactedClient{
+				Slot:            schedule.SlotAt(u.Hour).String(),
+				Name:            c.Name,
+				Location:        c.Location.Name(),
+				RequestedAgents: c.RequestedAgents,
+				AllocatedAgents: c.AllocatedAgents,
+				UnmetAgents:     c.UnmetAgents,
+			})
+		}
+	}
+	return inc
+}
+
+// Details renders the impacted clients as one line each, for alert bodies.
+func (i Incident) Details() string {
+	var b strings.Builder
+	for _, c := range i.Impacted {
+		fmt.Fprintf(&b, "%s %s: requested=%d, allocated=%d, unmet=%d\n", c.Slot, c.Name, c.RequestedAgents, c.AllocatedAgents, c.UnmetAgents)
+	}
+	return b.String()
+}
+
+// Pager opens incidents with an on-call service.
+type Pager interface {
+	Open(ctx context.Context, incident Incident) error
+}
+
+// Page opens an incident on pager for every firing alert. An alert that
+// cannot be paged does not stop the others; the errors are joined.
+func Page(ctx context.Context, pager Pager, schedule *models.Schedule, alerts []Alert) error {
+	var errs []error
+	for _, a := range alerts {
+		if !a.Firing {
+			continue
+		}
+		if err := pager.Open(ctx, NewIncident(schedule, a)); err != nil {
+			errs = append(errs, fmt.Errorf("paging %s: %w", a.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// PagerDuty opens incidents through the PagerDuty Events API v2.
+type PagerDuty struct {
+	// RoutingKey is the integration key of the PagerDuty service
+	RoutingKey string
+	// URL overrides DefaultPagerDutyURL
+	URL string
+	// Client sends the requests; defaults to http.DefaultClient
+	Client *http.Client
+}
+
+// Open triggers a critical PagerDuty event for incident.
+func (p *PagerDuty) Open(ctx context.Context, incident Incident) error {
+	body := map[string]any{
+		"routing_key":  p.RoutingKey,
+		"event_action": "trigger",
+		"dedup_key":    incident.DedupKey,
+		"payload": map[string]any{
+			"summary":  incident.Summary,
+			"source":   "agent-scheduler",
+			"severity": "critical",
+			"custom_details": map[string]any{
+				"run_id":           incident.RunID,
+				"alert":            incident.Alert.Name,
+				"value":            incident.Alert.Value,
+				"threshold":        incident.Alert.Threshold,
+				"impacted_clients": incident.Impacted,
+			},
+		},
+	}
+	return postJSON(ctx, p.Client, orDefault(p.URL, DefaultPagerDutyURL), nil, body)
+}
+
+// Opsgenie opens incidents through the Opsgenie Alert API.
+type Opsgenie struct {
+	// APIKey is the key of an Opsgenie API integration
+	APIKey string
+	// URL overrides DefaultOpsgenieURL, e.g. for the EU instance
+	URL string
+	// Client sends the requests; defaults to http.DefaultClient
+	Client *http.Client
+}
+
+// Open creates a P1 Opsgenie alert for incident.
+func (o *Opsgenie) Open(ctx context.Context, incident Incident) error {
+	message := incident.Summary
+	// Opsgenie truncates longer messages
+	if len(message) > 130 {
+		message = message[:130]
+	}
+	body := map[string]any{
+		"message":     message,
+		"alias":       incident.DedupKey,
+		"description": incident.Details(),
+		"priority":    "P1",
+		"source":      "agent-scheduler",
+		"details": map[string]string{
+			"run_id":    incident.RunID,
+			"alert":     incident.Alert.Name,
+			"value":     fmt.Sprintf("%.2f", incident.Alert.Value),
+			"threshold": fmt.Sprintf("%.2f", incident.Alert.Threshold),
+		},
+	}
+	return postJSON(ctx, o.Client, orDefault(o.URL, DefaultOpsgenieURL), map[string]string{"Authorization": "GenieKey " + o.APIKey}, body)
+}
+
+func postJSON(ctx context.Context, client *http.Client, url string, headers map[string]string, body any) error {
+	b, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	// Drain so the connection can be reused
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
+
+func orDefault(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
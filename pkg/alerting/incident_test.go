@@ -0,0 +1,141 @@
+package alerting_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/karthikrao-23/agentscheduler/pkg/alerting"
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func shortfallSchedule() *models.Schedule {
+	return &models.Schedule{
+		HourlyRequirements: make([][]models.CustomerRequirement, 24),
+		UnmetDemands: []models.UnmetDemand{{
+			Hour: 10, TotalDemand: 15, AllocatedAgents: 10, UnmetAgents: 5,
+			ImpactedClients: []models.ImpactedClient{
+				{Name: "VIP", RequestedAgents: 10, AllocatedAgents: 8, UnmetAgents: 2, Priority: models.PriorityCritical},
+				{Name: "Basic", RequestedAgents: 5, AllocatedAgents: 2, UnmetAgents: 3, Priority: models.PriorityHigh},
+			},
+		}},
+		Metadata: &models.Metadata{RunID: "run-1"},
+	}
+}
+
+var firing = alerting.Alert{Name: alerting.AlertHighPriorityUnmetPercent, Value: 20, Threshold: 10, Firing: true}
+
+func TestNewIncident(t *testing.T) {
+	inc := alerting.NewIncident(shortfallSchedule(), firing)
+
+	assert.Equal(t, "agent-scheduler/high_priority_unmet_percent", inc.DedupKey)
+	assert.Equal(t, "run-1", inc.RunID)
+	assert.Contains(t, inc.Summary, "20.0%")
+	assert.Equal(t, []alerting.ImpactedClient{
+		{Slot: "10:00", Name: "VIP", RequestedAgents: 10, AllocatedAgents: 8, UnmetAgents: 2},
+	}, inc.Impacted, "only priority-1 clients are included")
+	assert.Equal(t, "10:00 VIP: requested=10, allocated=8, unmet=2\n", inc.Details())
+}
+
+type recordingPager struct {
+	opened []alerting.Incident
+	// fail makes opening these alerts fail
+	fail map[string]bool
+}
+
+func (p *recordingPager) Open(_ context.Context, inc alerting.Incident) error {
+	if p.fail[inc.Alert.Name] {
+		return errors.New("service unavailable")
+	}
+	p.opened = append(p.opened, inc)
+	return nil
+}
+
+func TestPage(t *testing.T) {
+	var p recordingPager
+	err := alerting.Page(context.Background(), &p, shortfallSchedule(), []alerting.Alert{
+		firing,
+		{Name: alerting.AlertUnmetPercent, Value: 1, Threshold: 5},
+	})
+	require.NoError(t, err)
+	require.Len(t, p.opened, 1)
+	assert.Equal(t, alerting.AlertHighPriorityUnmetPercent, p.opened[0].Alert.Name)
+}
+
+func TestPage_KeepsGoing(t *testing.T) {
+	p := recordingPager{fail: map[string]bool{alerting.AlertUnmetPercent: true}}
+	err := alerting.Page(context.Background(), &p, shortfallSchedule(), []alerting.Alert{
+		{Name: alerting.AlertUnmetPercent, Value: 33, Threshold: 5, Firing: true},
+		firing,
+	})
+	assert.ErrorContains(t, err, "paging unmet_percent: service unavailable")
+	require.Len(t, p.opened, 1, "a failed page does not stop the next")
+	assert.Equal(t, alerting.AlertHighPriorityUnmetPercent, p.opened[0].Alert.Name)
+}
+
+func TestNewIncident_DedupAcrossRuns(t *testing.T) {
+	later := shortfallSchedule()
+	later.Metadata.RunID = "run-2"
+	first, second := alerting.NewIncident(shortfallSchedule(), firing), alerting.NewIncident(later, firing)
+	assert.Equal(t, first.DedupKey, second.DedupKey, "an ongoing shortfall stays one incident")
+	assert.Equal(t, "run-2", second.RunID)
+}
+
+func TestPagerDutyOpen(t *testing.T) {
+	var body map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	pd := &alerting.PagerDuty{RoutingKey: "key", URL: srv.URL}
+	require.NoError(t, pd.Open(context.Background(), alerting.NewIncident(shortfallSchedule(), firing)))
+
+	assert.Equal(t, "key", body["routing_key"])
+	assert.Equal(t, "trigger", body["event_action"])
+	assert.Equal(t, "agent-scheduler/high_priority_unmet_percent", body["dedup_key"])
+	payload := body["payload"].(map[string]any)
+	assert.Equal(t, "critical", payload["severity"])
+	details := payload["custom_details"].(map[string]any)
+	assert.Len(t, details["impacted_clients"], 1)
+}
+
+func TestOpsgenieOpen(t *testing.T) {
+	tests := map[string]struct {
+		status  int
+		wantErr bool
+	}{
+		"Accepted":     {status: http.StatusAccepted},
+		"Unauthorized": {status: http.StatusUnauthorized, wantErr: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var body map[string]any
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "GenieKey key", r.Header.Get("Authorization"))
+				assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+				w.WriteHeader(tt.status)
+			}))
+			defer srv.Close()
+
+			og := &alerting.Opsgenie{APIKey: "key", URL: srv.URL}
+			err := og.Open(context.Background(), alerting.NewIncident(shortfallSchedule(), firing))
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "P1", body["priority"])
+			assert.Equal(t, "agent-scheduler/high_priority_unmet_percent", body["alias"])
+			assert.Contains(t, body["description"], "VIP: requested=10")
+		})
+	}
+}
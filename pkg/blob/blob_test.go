@@ -0,0 +1,71 @@
+package blob_test
+
+import (
+	"context"
+	"io"
+	"path/filepath"
+	"testing"
+
+	"github.com/karthikrao-23/agentscheduler/pkg/blob"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAzureURI(t *testing.T) {
+	tests := map[string]struct {
+		uri     string
+		want    blob.AzureLocation
+		isAzure bool
+		wantErr bool
+	}{
+		"Az": {
+			uri:     "az://acct/schedules/2026/03/out.csv",
+			want:    blob.AzureLocation{ServiceURL: "https://acct.blob.core.windows.net/", Container: "schedules", Blob: "2026/03/out.csv"},
+			isAzure: true,
+		},
+		"Wasbs": {
+			uri:     "wasbs://schedules@acct.blob.core.usgovcloudapi.net/in/data.csv",
+			want:    blob.AzureLocation{ServiceURL: "https://acct.blob.core.usgovcloudapi.net/", Container: "schedules", Blob: "in/data.csv"},
+			isAzure: true,
+		},
+		"MissingBlob":      {uri: "az://acct/schedules", isAzure: true, wantErr: true},
+		"MissingContainer": {uri: "wasbs://acct.blob.core.windows.net/data.csv", isAzure: true, wantErr: true},
+		"LocalPath":        {uri: "testdata/data.csv"},
+		"FileURI":          {uri: "file:///tmp/data.csv"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, isAzure, err := blob.ParseAzureURI(tt.uri)
+			assert.Equal(t, tt.isAzure, isAzure)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestLocalFiles(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "out.csv")
+
+	w, err := blob.Create(ctx, "file://"+path)
+	require.NoError(t, err)
+	_, err = io.WriteString(w, "hello\n")
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	r, err := blob.Open(ctx, path)
+	require.NoError(t, err)
+	defer r.Close()
+	b, err := io.ReadAll(r)
+	require.NoError(t, err)
+	assert.Equal(t, "hello\n", string(b))
+
+	_, err = blob.Open(ctx, "az://acct/container")
+	assert.ErrorContains(t, err, "invalid Azure blob URI")
+}
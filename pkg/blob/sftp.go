@@ -0,0 +1,192 @@
+package blob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// SFTPLocation is a file on an SFTP server.
+type SFTPLocation struct {
+	// Addr is the server's host:port
+	Addr string
+	User string
+	Path string
+}
+
+// ParseSFTPURI parses an sftp://user@host[:port]/path URI. Paths are
+// relative to the login directory unless they start with "//", e.g.
+// sftp://partner@drop.example.com//srv/outbound/forecast.csv. It reports
+// false for URIs of any other scheme.
+func ParseSFTPURI(uri string) (SFTPLocation, bool, error) {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "sftp" {
+		return SFTPLocation{}, false, nil
+	}
+	loc := SFTPLocation{Addr: u.Host, User: u.User.Username(), Path: u.Path}
+	if u.Port() == "" {
+		loc.Addr = net.JoinHostPort(u.Hostname(), "22")
+	}
+	// The first slash separates the host from a relative path
+	if len(loc.Path) > 0 {
+		loc.Path = loc.Path[1:]
+	}
+	if u.Hostname() == "" || loc.User == "" || loc.Path == "" {
+		return SFTPLocation{}, true, fmt.Errorf("invalid SFTP URI %q: want sftp://user@host[:port]/path", uri)
+	}
+	return loc, true, nil
+}
+
+// sftpConn is an SFTP session and the SSH connection it runs over.
+type sftpConn struct {
+	*sftp.Client
+	ssh *ssh.Client
+}
+
+func (c *sftpConn) Close() error {
+	return errors.Join(c.Client.Close(), c.ssh.Close())
+}
+
+// dialSFTP connects with key-based auth. The private key is read from
+// SFTP_PRIVATE_KEY_FILE (default ~/.ssh/id_ed25519, then ~/.ssh/id_rsa),
+// decrypted with SFTP_PRIVATE_KEY_PASSPHRASE if set, and the server's host
+// key must be listed in SFTP_KNOWN_HOSTS (default ~/.ssh/known_hosts).
+func dialSFTP(ctx context.Context, loc SFTPLocation) (*sftpConn, error) {
+	signer, err := sftpSigner()
+	if err != nil {
+		return nil, err
+	}
+	knownHosts := os.Getenv("SFTP_KNOWN_HOSTS")
+	if knownHosts == "" {
+		home, _ := os.UserHomeDir()
+		knownHosts = filepath.Join(home, ".ssh", "known_hosts")
+	}
+	hostKeyCallback, err := knownhosts.New(knownHosts)
+	if err != nil {
+		return nil, fmt.Errorf("sftp: reading known hosts: %w", err)
+	}
+	config := &ssh.ClientConfig{
+		User:            loc.User,
+		Auth:            []ssh.AuthMethod{ssh.PublicKeys(signer)},
+		HostKeyCallback: hostKeyCallback,
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", loc.Addr)
+	if err != nil {
+		return nil, fmt.Errorf("sftp: %w", err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+	c, chans, reqs, err := ssh.NewClientConn(conn, loc.Addr, config)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("sftp: connecting to %s: %w", loc.Addr, err)
+	}
+	sshClient := ssh.NewClient(c, chans, reqs)
+	client, err := sftp.NewClient(sshClient)
+	if err != nil {
+		sshClient.Close()
+		return nil, fmt.Errorf("sftp: starting session on %s: %w", loc.Addr, err)
+	}
+	return &sftpConn{Client: client, ssh: sshClient}, nil
+}
+
+func sftpSigner() (ssh.Signer, error) {
+	paths := []string{os.Getenv("SFTP_PRIVATE_KEY_FILE")}
+	if paths[0] == "" {
+		home, _ := os.UserHomeDir()
+		paths = []string{filepath.Join(home, ".ssh", "id_ed25519"), filepath.Join(home, ".ssh", "id_rsa")}
+	}
+	for _, path := range paths {
+		pem, err := os.ReadFile(path)
+		if errors.Is(err, os.ErrNotExist) && len(paths) > 1 {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("sftp: reading private key: %w", err)
+		}
+		var signer ssh.Signer
+		if passphrase := os.Getenv("SFTP_PRIVATE_KEY_PASSPHRASE"); passphrase != "" {
+			signer, err = ssh.ParsePrivateKeyWithPassphrase(pem, []byte(passphrase))
+		} else {
+			signer, err = ssh.ParsePrivateKey(pem)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("sftp: parsing private key %s: %w", path, err)
+		}
+		return signer, nil
+	}
+	return nil, fmt.Errorf("sftp: no private key found; set SFTP_PRIVATE_KEY_FILE")
+}
+
+func openSFTP(ctx context.Context, loc SFTPLocation) (io.ReadCloser, error) {
+	conn, err := dialSFTP(ctx, loc)
+	if err != nil {
+		return nil, err
+	}
+	f, err := conn.Open(loc.Path)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("sftp: opening %s: %w", loc.Path, err)
+	}
+	return &sftpReader{File: f, conn: conn}, nil
+}
+
+type sftpReader struct {
+	*sftp.File
+	conn *sftpConn
+}
+
+func (r *sftpReader) Close() error {
+	return errors.Join(r.File.Close(), r.conn.Close())
+}
+
+// createSFTP writes to a ".part" file that is renamed into place on Close,
+// so partners polling a drop folder never pick up a partial file.
+func createSFTP(ctx context.Context, loc SFTPLocation) (io.WriteCloser, error) {
+	conn, err := dialSFTP(ctx, loc)
+	if err != nil {
+		return nil, err
+	}
+	f, err := conn.Create(loc.Path + ".part")
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("sftp: creating %s: %w", loc.Path, err)
+	}
+	return &sftpWriter{File: f, conn: conn, path: loc.Path}, nil
+}
+
+type sftpWriter struct {
+	*sftp.File
+	conn *sftpConn
+	path string
+}
+
+func (w *sftpWriter) Close() error {
+	defer w.conn.Close()
+	if err := w.File.Close(); err != nil {
+		return fmt.Errorf("sftp: writing %s: %w", w.path, err)
+	}
+	part := w.path + ".part"
+	// posix-rename replaces an existing file; plain SFTP rename does not
+	err := w.conn.PosixRename(part, w.path)
+	if err != nil {
+		_ = w.conn.Remove(w.path)
+		err = w.conn.Rename(part, w.path)
+	}
+	if err != nil {
+		return fmt.Errorf("sftp: renaming %s into place: %w", w.path, err)
+	}
+	return nil
+}
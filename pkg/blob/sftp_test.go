@@ -0,0 +1,165 @@
+package blob_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/karthikrao-23/agentscheduler/pkg/blob"
+
+	"github.com/pkg/sftp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+func TestParseSFTPURI(t *testing.T) {
+	tests := map[string]struct {
+		uri     string
+		want    blob.SFTPLocation
+		isSFTP  bool
+		wantErr bool
+	}{
+		"Relative": {
+			uri:    "sftp://partner@drop.example.com/outbound/forecast.csv",
+			want:   blob.SFTPLocation{Addr: "drop.example.com:22", User: "partner", Path: "outbound/forecast.csv"},
+			isSFTP: true,
+		},
+		"AbsoluteWithPort": {
+			uri:    "sftp://partner@drop.example.com:2222//srv/inbound/schedule.csv",
+			want:   blob.SFTPLocation{Addr: "drop.example.com:2222", User: "partner", Path: "/srv/inbound/schedule.csv"},
+			isSFTP: true,
+		},
+		"MissingUser": {uri: "sftp://drop.example.com/forecast.csv", isSFTP: true, wantErr: true},
+		"MissingPath": {uri: "sftp://partner@drop.example.com", isSFTP: true, wantErr: true},
+		"Azure":       {uri: "az://acct/schedules/out.csv"},
+		"LocalPath":   {uri: "testdata/data.csv"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, isSFTP, err := blob.ParseSFTPURI(tt.uri)
+			assert.Equal(t, tt.isSFTP, isSFTP)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+// startSFTPServer serves dir over SFTP to clients holding the key it writes
+// to SFTP_PRIVATE_KEY_FILE, and trusts its host key via SFTP_KNOWN_HOSTS.
+func startSFTPServer(t *testing.T, dir string) string {
+	t.Helper()
+	keys := t.TempDir()
+
+	_, hostPriv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	hostSigner, err := ssh.NewSignerFromKey(hostPriv)
+	require.NoError(t, err)
+	_, clientPriv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	clientPEM, err := ssh.MarshalPrivateKey(clientPriv, "")
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(keys, "id_ed25519"), pem.EncodeToMemory(clientPEM), 0o600))
+	clientSigner, err := ssh.NewSignerFromKey(clientPriv)
+	require.NoError(t, err)
+
+	config := &ssh.ServerConfig{
+		PublicKeyCallback: func(conn ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			if conn.User() == "partner" && bytes.Equal(key.Marshal(), clientSigner.PublicKey().Marshal()) {
+				return nil, nil
+			}
+			return nil, assert.AnError
+		},
+	}
+	config.AddHostKey(hostSigner)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go serveSFTP(conn, config, dir)
+		}
+	}()
+
+	line := knownhosts.Line([]string{knownhosts.Normalize(ln.Addr().String())}, hostSigner.PublicKey())
+	require.NoError(t, os.WriteFile(filepath.Join(keys, "known_hosts"), []byte(line+"\n"), 0o600))
+	t.Setenv("SFTP_PRIVATE_KEY_FILE", filepath.Join(keys, "id_ed25519"))
+	t.Setenv("SFTP_KNOWN_HOSTS", filepath.Join(keys, "known_hosts"))
+	return ln.Addr().String()
+}
+
+func serveSFTP(conn net.Conn, config *ssh.ServerConfig, dir string) {
+	_, chans, reqs, err := ssh.NewServerConn(conn, config)
+	if err != nil {
+		return
+	}
+	go ssh.DiscardRequests(reqs)
+	for newChan := range chans {
+		channel, requests, err := newChan.Accept()
+		if err != nil {
+			continue
+		}
+		go func() {
+			for req := range requests {
+				req.Reply(req.Type == "subsystem" && string(req.Payload[4:]) == "sftp", nil)
+			}
+		}()
+		server, err := sftp.NewServer(channel, sftp.WithServerWorkingDirectory(dir))
+		if err != nil {
+			channel.Close()
+			continue
+		}
+		_ = server.Serve()
+		server.Close()
+	}
+}
+
+func TestSFTP(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "schedule.csv"), []byte("stale\n"), 0o600))
+	addr := startSFTPServer(t, dir)
+	uri := "sftp://partner@" + addr + "/schedule.csv"
+
+	w, err := blob.Create(ctx, uri)
+	require.NoError(t, err)
+	_, err = io.WriteString(w, "hello\n")
+	require.NoError(t, err)
+	_, err = os.Stat(filepath.Join(dir, "schedule.csv.part"))
+	require.NoError(t, err, "written to a .part file first")
+	require.NoError(t, w.Close())
+
+	b, err := os.ReadFile(filepath.Join(dir, "schedule.csv"))
+	require.NoError(t, err)
+	assert.Equal(t, "hello\n", string(b), "replaces the existing file on close")
+	_, err = os.Stat(filepath.Join(dir, "schedule.csv.part"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	r, err := blob.Open(ctx, uri)
+	require.NoError(t, err)
+	b, err = io.ReadAll(r)
+	require.NoError(t, err)
+	require.NoError(t, r.Close())
+	assert.Equal(t, "hello\n", string(b))
+
+	_, err = blob.Open(ctx, "sftp://intruder@"+addr+"/schedule.csv")
+	assert.Error(t, err, "unknown users are rejected")
+}
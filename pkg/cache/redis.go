@@ -0,0 +1,68 @@
+// Package cache provides schedule caches for serve mode, keyed by
+// scheduler.CacheKey.
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"time"
+
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// DefaultPrefix namespaces the keys Redis stores schedules under.
+const DefaultPrefix = "agentscheduler:schedule:"
+
+// Redis caches schedules in Redis as their JSON form, expiring them after a
+// TTL so forecasts that are re-run with fresh data do not linger.
+type Redis struct {
+	client redis.UniversalClient
+	ttl    time.Duration
+	prefix string
+}
+
+// NewRedis returns a cache storing schedules in client for ttl (0 = no
+// expiry) under DefaultPrefix.
+func NewRedis(client redis.UniversalClient, ttl time.Duration) *Redis {
+	return &Redis{client: client, ttl: ttl, prefix: DefaultPrefix}
+}
+
+// WithPrefix returns a copy of the cache that stores keys under prefix, e.g.
+// to share one Redis between deployments.
+func (r *Redis) WithPrefix(prefix string) *Redis {
+	c := *r
+	c.prefix = prefix
+	return &c
+}
+
+// Get returns the schedule cached under key, or nil if there is none.
+func (r *Redis) Get(ctx context.Context, key string) (*models.Schedule, error) {
+	b, err := r.client.Get(ctx, r.prefix+key).Bytes()
+	if stderrors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("redis get: %w", err)
+	}
+	var schedule models.Schedule
+	if err := json.Unmarshal(b, &schedule); err != nil {
+		return nil, fmt.Errorf("decoding cached schedule: %w", err)
+	}
+	return &schedule, nil
+}
+
+// Set caches schedule under key.
+func (r *Redis) Set(ctx context.Context, key string, schedule *models.Schedule) error {
+	b, err := json.Marshal(schedule)
+	if err != nil {
+		return fmt.Errorf("encoding schedule: %w", err)
+	}
+	if err := r.client.Set(ctx, r.prefix+key, b, r.ttl).Err(); err != nil {
+		return fmt.Errorf("redis set: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package cache_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/karthikrao-23/agentscheduler/pkg/cache"
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedis(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer client.Close()
+	c := cache.NewRedis(client, time.Minute)
+	ctx := context.Background()
+
+	got, err := c.Get(ctx, "key")
+	require.NoError(t, err)
+	assert.Nil(t, got, "a miss is not an error")
+
+	reqs := make([][]models.CustomerRequirement, 24)
+	reqs[9] = []models.CustomerRequirement{{Name: "Acme", AgentsNeeded: 5, Priority: models.PriorityCritical, Location: models.NewLocation(time.UTC)}}
+	schedule := &models.Schedule{HourlyRequirements: reqs, Metadata: &models.Metadata{RunID: "run-1"}}
+	require.NoError(t, c.Set(ctx, "key", schedule))
+
+	assert.True(t, mr.Exists(cache.DefaultPrefix+"key"))
+	assert.Equal(t, time.Minute, mr.TTL(cache.DefaultPrefix+"key"))
+
+	got, err = c.Get(ctx, "key")
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.True(t, schedule.Equal(got))
+	assert.Equal(t, "run-1", got.Metadata.RunID)
+
+	// Entries expire after the TTL
+	mr.FastForward(time.Minute)
+	got, err = c.Get(ctx, "key")
+	require.NoError(t, err)
+	assert.Nil(t, got)
+
+	prefixed := c.WithPrefix("tenant:")
+	require.NoError(t, prefixed.Set(ctx, "key", schedule))
+	assert.True(t, mr.Exists("tenant:key"))
+}
+
+func TestRedisCorruptEntry(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer client.Close()
+	require.NoError(t, mr.Set(cache.DefaultPrefix+"key", "not json"))
+
+	_, err := cache.NewRedis(client, 0).Get(context.Background(), "key")
+	assert.ErrorContains(t, err, "decoding cached schedule")
+}
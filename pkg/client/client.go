@@ -0,0 +1,170 @@
+// Package client is a typed Go client for the serve mode HTTP API described
+// by server.OpenAPI, so services integrate against the same types the server
+// uses instead of hand-rolling requests.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+	"github.com/karthikrao-23/agentscheduler/pkg/server"
+)
+
+// ScheduleRequest is the JSON body of CreateSchedule.
+type ScheduleRequest = server.ScheduleRequest
+
+// CSVOptions are the scheduling options of CreateScheduleCSV. Zero values
+// leave the server's defaults in place.
+type CSVOptions struct {
+	Utilization float64
+	Capacity    int
+	Lenient     bool
+}
+
+// APIError is returned for a response with a non-2xx status.
+type APIError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("agent scheduler API: %d %s: %s", e.StatusCode, http.StatusText(e.StatusCode), e.Message)
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithHTTPClient sets the HTTP client requests are sent with. Defaults to
+// http.DefaultClient.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = hc
+	}
+}
+
+// WithHeader adds a header to every request, e.g. an API key.
+func WithHeader(name, value string) Option {
+	return func(c *Client) {
+		c.headers.Set(name, value)
+	}
+}
+
+// Client calls a serve mode API.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	headers    http.Header
+}
+
+// New returns a Client for the server at baseURL, e.g.
+// "http://scheduler:8080".
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		httpClient: http.DefaultClient,
+		headers:    make(http.Header),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// CreateSchedule generates and publishes a schedule for req.
+func (c *Client) CreateSchedule(ctx context.Context, req ScheduleRequest) (*models.Schedule, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("encoding request: %w", err)
+	}
+	var schedule models.Schedule
+	if err := c.do(ctx, http.MethodPost, "/v1/schedules", nil, "application/json", bytes.NewReader(body), &schedule); err != nil {
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+// CreateScheduleCSV generates and publishes a schedule for CSV input in the
+// command-line format.
+func (c *Client) CreateScheduleCSV(ctx context.Context, csv io.Reader, opts CSVOptions) (*models.Schedule, error) {
+	query := url.Values{}
+	if opts.Utilization != 0 {
+		query.Set("utilization", strconv.FormatFloat(opts.Utilization, 'g', -1, 64))
+	}
+	if opts.Capacity != 0 {
+		query.Set("capacity", strconv.Itoa(opts.Capacity))
+	}
+	if opts.Lenient {
+		query.Set("lenient", "true")
+	}
+	var schedule models.Schedule
+	if err := c.do(ctx, http.MethodPost, "/v1/schedules", query, "text/csv", csv, &schedule); err != nil {
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+// LatestSchedule returns the most recently published schedule.
+func (c *Client) LatestSchedule(ctx context.Context) (*models.Schedule, error) {
+	var schedule models.Schedule
+	if err := c.do(ctx, http.MethodGet, "/v1/schedules/latest", nil, "", nil, &schedule); err != nil {
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+// Health returns nil if the server is up.
+func (c *Client) Health(ctx context.Context) error {
+	return c.do(ctx, http.MethodGet, "/healthz", nil, "", nil, nil)
+}
+
+// do sends a request and decodes a JSON response into out, if non-nil.
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, contentType string, body io.Reader, out any) error {
+	endpoint := c.baseURL + path
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, body)
+	if err != nil {
+		return err
+	}
+	for name, values := range c.headers {
+		req.Header[name] = values
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		apiErr := &APIError{StatusCode: resp.StatusCode}
+		var e server.Error
+		b, _ := io.ReadAll(resp.Body)
+		if json.Unmarshal(b, &e) == nil && e.Error != "" {
+			apiErr.Message = e.Error
+		} else {
+			apiErr.Message = strings.TrimSpace(string(b))
+		}
+		return apiErr
+	}
+	if out == nil {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decoding response: %w", err)
+	}
+	return nil
+}
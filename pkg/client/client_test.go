@@ -0,0 +1,66 @@
+package client_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/karthikrao-23/agentscheduler/pkg/client"
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+	"github.com/karthikrao-23/agentscheduler/pkg/server"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient(t *testing.T) {
+	srv := httptest.NewServer(server.New())
+	defer srv.Close()
+	c := client.New(srv.URL + "/")
+	ctx := context.Background()
+
+	require.NoError(t, c.Health(ctx))
+
+	_, err := c.LatestSchedule(ctx)
+	var apiErr *client.APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+	assert.Equal(t, "no schedule has been published yet", apiErr.Message)
+
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	cd, err := models.NewCallData("Acme", start, start.Add(2*time.Hour), 240, 300, models.WithPriority(models.PriorityCritical))
+	require.NoError(t, err)
+	schedule, err := c.CreateSchedule(ctx, client.ScheduleRequest{CallData: []models.CallData{cd}, Capacity: 5})
+	require.NoError(t, err)
+	assert.Equal(t, 10, schedule.TotalAgents())
+	assert.Equal(t, 10, schedule.TotalUnmet())
+
+	schedule, err = c.CreateScheduleCSV(ctx, strings.NewReader(`#CustomerName, AverageCallDurationSeconds, StartTimeUTC, EndTimeUTC, NumberOfCalls, Priority
+Acme, 300, 9AM, 11AM, 240, 1
+`), client.CSVOptions{Capacity: 8})
+	require.NoError(t, err)
+	assert.Equal(t, 16, schedule.TotalAgents())
+
+	latest, err := c.LatestSchedule(ctx)
+	require.NoError(t, err)
+	assert.True(t, latest.Equal(schedule))
+
+	_, err = c.CreateScheduleCSV(ctx, strings.NewReader("Acme, x\n"), client.CSVOptions{})
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+}
+
+func TestWithHeader(t *testing.T) {
+	var got string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Get("X-API-Key")
+	}))
+	defer srv.Close()
+
+	c := client.New(srv.URL, client.WithHeader("X-API-Key", "secret"), client.WithHTTPClient(srv.Client()))
+	require.NoError(t, c.Health(context.Background()))
+	assert.Equal(t, "secret", got)
+}
@@ -0,0 +1,94 @@
+package errors
+
+import "fmt"
+
+// ParseError wraps a specific error with context about where it occurred.
+type ParseError struct {
+	Line   int
+	Record []string
+	Err    error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("parse error at line %d: %v (record: %v)", e.Line, e.Err, e.Record)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// Define specific error types for better error handling
+var (
+	ErrInvalidFieldCount    = fmt.Errorf("invalid field count")
+	ErrInvalidDuration      = fmt.Errorf("invalid duration")
+	ErrInvalidStartTime     = fmt.Errorf("invalid start time")
+	ErrInvalidEndTime       = fmt.Errorf("invalid end time")
+	ErrInvalidDate          = fmt.Errorf("invalid date")
+	ErrInvalidNumberOfCalls = fmt.Errorf("invalid number of calls")
+	ErrInvalidPriority      = fmt.Errorf("invalid priority")
+	ErrEmptyRecord          = fmt.Errorf("empty record")
+	ErrMissingLocation      = fmt.Errorf("missing location")
+	ErrInvalidWindow        = fmt.Errorf("invalid call window")
+	ErrInvalidSLA           = fmt.Errorf("invalid SLA")
+	ErrInvalidConcurrency   = fmt.Errorf("invalid concurrency")
+	ErrInvalidCost          = fmt.Errorf("invalid cost")
+	ErrInvalidMultiplier    = fmt.Errorf("invalid multiplier")
+	ErrInvalidHeadcount     = fmt.Errorf("invalid headcount")
+	ErrInvalidMinAgents     = fmt.Errorf("invalid minimum agents")
+	ErrInvalidAgentLimits   = fmt.Errorf("invalid agent limits")
+	ErrInvalidHours         = fmt.Errorf("invalid hours of operation")
+	ErrInvalidTier          = fmt.Errorf("invalid tier")
+)
+
+// Scheduler errors, returned by schedule generation when the input or
+// options make a meaningful schedule impossible.
+var (
+	ErrEmptyInput            = fmt.Errorf("empty input")
+	ErrInvalidUtilization    = fmt.Errorf("invalid utilization")
+	ErrInfeasibleConstraints = fmt.Errorf("infeasible constraints")
+)
+
+// ErrUnsupportedSchemaVersion is returned when loading a persisted schedule
+// written by a newer version of the scheduler.
+var ErrUnsupportedSchemaVersion = fmt.Errorf("unsupported schedule schema version")
+
+// ErrScheduleNotFound is returned when a stored schedule is looked up by a
+// run ID that was never saved.
+var ErrScheduleNotFound = fmt.Errorf("schedule not found")
+
+// ErrIncompatibleSchedules is returned when combining schedules whose time
+// slots do not line up.
+var ErrIncompatibleSchedules = fmt.Errorf("incompatible schedules")
+
+// RowError reports a row of call data the scheduler could not schedule,
+// by its position in the input, counting from 0.
+type RowError struct {
+	Row      int
+	Customer string
+	Err      error
+}
+
+func (e *RowError) Error() string {
+	return fmt.Sprintf("row %d (%s): %v", e.Row, e.Customer, e.Err)
+}
+
+func (e *RowError) Unwrap() error {
+	return e.Err
+}
+
+// ConstraintViolationError reports which scheduling constraint was violated
+// and by what value.
+type ConstraintViolationError struct {
+	Constraint string
+	Value      any
+	Detail     string
+	Err        error
+}
+
+func (e *ConstraintViolationError) Error() string {
+	return fmt.Sprintf("%v: %s=%v: %s", e.Err, e.Constraint, e.Value, e.Detail)
+}
+
+func (e *ConstraintViolationError) Unwrap() error {
+	return e.Err
+}
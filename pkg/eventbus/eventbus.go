@@ -0,0 +1,113 @@
+// Package eventbus publishes schedules to an MQTT topic or NATS subject, so
+// wallboards and downstream automations receive each new schedule as it is
+// generated instead of polling for it.
+//
+// Each schedule is published as one message of newline-delimited JSON with
+// a Slot line per time slot.
+package eventbus
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"sort"
+
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+)
+
+// Publisher sends encoded schedules to a message bus.
+type Publisher interface {
+	Publish(ctx context.Context, payload []byte) error
+	Close() error
+}
+
+// Slot is one line of the published NDJSON: the staffing for one time slot
+// of a run.
+type Slot struct {
+	RunID string `json:"run_id,omitempty"`
+	Hour  int    `json:"hour"`
+	// Slot is encoded as in the schedule's JSON
+	Slot        models.TimeSlot `json:"slot"`
+	TotalAgents int             `json:"total_agents"`
+	UnmetAgents int             `json:"unmet_agents"`
+	Customers   []Customer      `json:"customers"`
+}
+
+// Customer is the staffing for one customer in a slot.
+type Customer struct {
+	Name        string `json:"name"`
+	Location    string `json:"location,omitempty"`
+	Agents      int    `json:"agents"`
+	UnmetAgents int    `json:"unmet_agents,omitempty"`
+}
+
+// Slots returns the schedule's slots in order.
+func Slots(schedule *models.Schedule) []Slot {
+	var runID string
+	if schedule.Metadata != nil {
+		runID = schedule.Metadata.RunID
+	}
+	unmet := make(map[int]models.UnmetDemand, len(schedule.UnmetDemands))
+	for _, u := range schedule.UnmetDemands {
+		unmet[u.Hour] = u
+	}
+
+	slots := make([]Slot, schedule.NumSlots())
+	for h := range slots {
+		s := Slot{RunID: runID, Hour: h, Slot: schedule.SlotAt(h), Customers: []Customer{}}
+
+		index := make(map[[2]string]int)
+		customer := func(name string, loc models.Location) *Customer {
+			k := [2]string{name, loc.Name()}
+			i, ok := index[k]
+			if !ok {
+				i = len(s.Customers)
+				index[k] = i
+				s.Customers = append(s.Customers, Customer{Name: name, Location: loc.Name()})
+			}
+			return &s.Customers[i]
+		}
+		if h < len(schedule.HourlyRequirements) {
+			for _, req := range schedule.HourlyRequirements[h] {
+				customer(req.Name, req.Location).Agents += req.AgentsNeeded
+				s.TotalAgents += req.AgentsNeeded
+			}
+		}
+		if u, ok := unmet[h]; ok {
+			s.UnmetAgents = u.UnmetAgents
+			for _, client := range u.ImpactedClients {
+				customer(client.Name, client.Location).UnmetAgents += client.UnmetAgents
+			}
+		}
+		sort.Slice(s.Customers, func(i, j int) bool {
+			a, b := s.Customers[i], s.Customers[j]
+			if a.Name != b.Name {
+				return a.Name < b.Name
+			}
+			return a.Location < b.Location
+		})
+		slots[h] = s
+	}
+	return slots
+}
+
+// WriteNDJSON writes the schedule's slots to w, one JSON object per line.
+func WriteNDJSON(w io.Writer, schedule *models.Schedule) error {
+	enc := json.NewEncoder(w)
+	for _, slot := range Slots(schedule) {
+		if err := enc.Encode(slot); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Publish encodes schedule as NDJSON and sends it with p.
+func Publish(ctx context.Context, p Publisher, schedule *models.Schedule) error {
+	var buf bytes.Buffer
+	if err := WriteNDJSON(&buf, schedule); err != nil {
+		return err
+	}
+	return p.Publish(ctx, buf.Bytes())
+}
@@ -0,0 +1,76 @@
+package eventbus_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/karthikrao-23/agentscheduler/pkg/eventbus"
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+	"github.com/karthikrao-23/agentscheduler/pkg/scheduler"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newSchedule(t *testing.T) *models.Schedule {
+	t.Helper()
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	acme, err := models.NewCallData("Acme", start, start.Add(time.Hour), 8, 3600)
+	require.NoError(t, err)
+	globex, err := models.NewCallData("Globex", start, start.Add(2*time.Hour), 2, 3600)
+	require.NoError(t, err)
+	schedule, err := scheduler.GenerateScheduleContext(context.Background(), []models.CallData{acme, globex}, scheduler.WithCapacity(6))
+	require.NoError(t, err)
+	return schedule
+}
+
+func TestSlots(t *testing.T) {
+	schedule := newSchedule(t)
+	slots := eventbus.Slots(schedule)
+	require.Len(t, slots, schedule.NumSlots())
+
+	nine := slots[9]
+	assert.Equal(t, schedule.Metadata.RunID, nine.RunID)
+	assert.Equal(t, 6, nine.TotalAgents)
+	assert.Equal(t, 3, nine.UnmetAgents, "8 agents for Acme and 1 for Globex against 6")
+	require.Len(t, nine.Customers, 2)
+	assert.Equal(t, "Acme", nine.Customers[0].Name)
+	assert.Equal(t, nine.TotalAgents, nine.Customers[0].Agents+nine.Customers[1].Agents)
+	assert.Equal(t, nine.UnmetAgents, nine.Customers[0].UnmetAgents+nine.Customers[1].UnmetAgents)
+
+	assert.Empty(t, slots[0].Customers)
+	assert.NotNil(t, slots[0].Customers, "empty slots encode an empty list")
+}
+
+type recorder struct {
+	payloads [][]byte
+}
+
+func (r *recorder) Publish(_ context.Context, payload []byte) error {
+	r.payloads = append(r.payloads, payload)
+	return nil
+}
+
+func (r *recorder) Close() error { return nil }
+
+func TestPublish(t *testing.T) {
+	schedule := newSchedule(t)
+	var r recorder
+	require.NoError(t, eventbus.Publish(context.Background(), &r, schedule))
+	require.Len(t, r.payloads, 1, "one message per schedule")
+
+	var lines int
+	scanner := bufio.NewScanner(bytes.NewReader(r.payloads[0]))
+	for scanner.Scan() {
+		var slot eventbus.Slot
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &slot))
+		assert.Equal(t, lines, slot.Hour)
+		assert.True(t, schedule.SlotAt(lines).Equal(slot.Slot))
+		lines++
+	}
+	assert.Equal(t, schedule.NumSlots(), lines)
+}
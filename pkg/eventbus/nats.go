@@ -0,0 +1,46 @@
+package eventbus
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nats-io/nats.go"
+)
+
+// NATS publishes to a subject on a NATS server.
+type NATS struct {
+	conn    *nats.Conn
+	subject string
+}
+
+// DialNATS connects to the NATS server at url, e.g. nats://localhost:4222.
+// Credentials in the URL are used to log in.
+func DialNATS(url, subject string) (*NATS, error) {
+	conn, err := nats.Connect(url, nats.Name(DefaultClientID))
+	if err != nil {
+		return nil, fmt.Errorf("nats: connecting: %w", err)
+	}
+	return &NATS{conn: conn, subject: subject}, nil
+}
+
+// Publish implements Publisher. It waits until the server has received the
+// message.
+func (n *NATS) Publish(ctx context.Context, payload []byte) error {
+	if err := n.conn.Publish(n.subject, payload); err != nil {
+		return fmt.Errorf("nats: publishing to %s: %w", n.subject, err)
+	}
+	flush := n.conn.Flush
+	if _, ok := ctx.Deadline(); ok {
+		// FlushWithContext rejects contexts without a deadline
+		flush = func() error { return n.conn.FlushWithContext(ctx) }
+	}
+	if err := flush(); err != nil {
+		return fmt.Errorf("nats: publishing to %s: %w", n.subject, err)
+	}
+	return nil
+}
+
+// Close drains pending messages and closes the connection.
+func (n *NATS) Close() error {
+	return n.conn.Drain()
+}
@@ -0,0 +1,61 @@
+package formatter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+)
+
+// FormatDiff returns a line per changed customer and slot, e.g.
+//
+//	09:00 Acme (America/New_York): agents 3 -> 5 (+2), unmet 2 -> 0 (-2)
+//
+// or "No changes." when the diff is empty.
+func FormatDiff(diff models.ScheduleDiff) string {
+	if diff.Empty() {
+		return "No changes.\n"
+	}
+	var sb strings.Builder
+	for _, c := range diff.Changes {
+		fmt.Fprintf(&sb, "%s %s", c.Slot, c.Customer)
+		if !c.Location.IsZero() {
+			fmt.Fprintf(&sb, " (%s)", c.Location)
+		}
+		fmt.Fprintf(&sb, ": agents %d -> %d (%+d)", c.AgentsBefore, c.AgentsAfter, c.AgentsDelta())
+		if c.UnmetDelta() != 0 {
+			fmt.Fprintf(&sb, ", unmet %d -> %d (%+d)", c.UnmetBefore, c.UnmetAfter, c.UnmetDelta())
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// FormatCustomerDeltas returns a line per changed customer, summed over
+// every slot, e.g.
+//
+//	Acme (America/New_York): agents +6 over 3 slots, unmet -2
+//
+// or "No changes." when the diff is empty.
+func FormatCustomerDeltas(diff models.ScheduleDiff) string {
+	if diff.Empty() {
+		return "No changes.\n"
+	}
+	var sb strings.Builder
+	for _, d := range diff.ByCustomer() {
+		sb.WriteString(d.Customer)
+		if !d.Location.IsZero() {
+			fmt.Fprintf(&sb, " (%s)", d.Location)
+		}
+		slots := "slots"
+		if d.Slots == 1 {
+			slots = "slot"
+		}
+		fmt.Fprintf(&sb, ": agents %+d over %d %s", d.Agents, d.Slots, slots)
+		if d.Unmet != 0 {
+			fmt.Fprintf(&sb, ", unmet %+d", d.Unmet)
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
@@ -0,0 +1,666 @@
+package formatter
+
+import (
+	"cmp"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"math"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+	"github.com/karthikrao-23/agentscheduler/pkg/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/sync/errgroup"
+)
+
+// Option configures how a schedule is rendered.
+type Option func(*options)
+
+type options struct {
+	// fte renders agents as full-time equivalents, to precision decimals
+	fte       bool
+	precision int
+	// location is the timezone every slot is reported in, if set
+	location models.Location
+	// metadata heads the output with the schedule's metadata
+	metadata bool
+}
+
+// WithFTE renders agents as full-time equivalents with precision decimal
+// places, e.g. 2.35, from each requirement's FTE (see
+// scheduler.WithFTE), so totals are not inflated by rounding every
+// customer up. JSON output keeps its whole-agent fields and adds fte ones.
+// Unmet demand is still in whole agents.
+func WithFTE(precision int) Option {
+	return func(o *options) {
+		o.fte = true
+		o.precision = precision
+	}
+}
+
+// WithLocation reports every slot in loc rather than in each customer's
+// local time, moving requirements to the slot they fall in there so slots
+// total the agents working at the same moment (see Schedule.InLocation). A
+// generic day is converted with today's offsets.
+func WithLocation(loc models.Location) Option {
+	return func(o *options) {
+		o.location = loc
+	}
+}
+
+// WithMetadata heads the output with the schedule's Metadata, so a report
+// records when and from what it was generated: text and CSV start with a
+// "# name: value" line for each field and JSON becomes an object with the
+// metadata and the hours. A schedule without metadata gets none.
+func WithMetadata() Option {
+	return func(o *options) {
+		o.metadata = true
+	}
+}
+
+// appendMetadata appends md as "# name: value" lines, with its options in
+// name order, or nothing for nil md.
+func appendMetadata(buf []byte, md *models.Metadata) []byte {
+	if md == nil {
+		return buf
+	}
+	line := func(name, value string) {
+		if value != "" {
+			buf = fmt.Appendf(buf, "# %s: %s\n", name, value)
+		}
+	}
+	line("generated_at", md.GeneratedAt.Format(time.RFC3339))
+	line("run_id", md.RunID)
+	line("input_hash", md.InputHash)
+	line("tool_version", md.ToolVersion)
+	for _, name := range slices.Sorted(maps.Keys(md.Options)) {
+		line("option."+name, md.Options[name])
+	}
+	return buf
+}
+
+// appendAgents appends agents, or with WithFTE, fte.
+func (o options) appendAgents(buf []byte, agents int, fte float64) []byte {
+	if o.fte {
+		return strconv.AppendFloat(buf, fte, 'f', o.precision, 64)
+	}
+	return strconv.AppendInt(buf, int64(agents), 10)
+}
+
+// round rounds fte to the precision WithFTE renders it with.
+func (o options) round(fte float64) float64 {
+	scale := math.Pow10(o.precision)
+	return math.Round(fte*scale) / scale
+}
+
+// ScheduleData holds prepared schedule data used by all formatters
+type ScheduleData struct {
+	Hours       []HourlyData
+	UnmetByHour map[int]*models.UnmetDemand
+
+	schedule *models.Schedule
+	opts     options
+	// grouped and unmet hold each hour for the text and CSV formats
+	grouped []groupedHour
+	unmet   []*models.UnmetDemand
+}
+
+// HourlyData groups requirements by location for an hour
+type HourlyData struct {
+	Hour int             `json:"hour"`
+	Slot models.TimeSlot `json:"-"`
+	// Date and Weekday are set only for a schedule laid out by date
+	Date    string `json:"date,omitempty"`
+	Weekday string `json:"weekday,omitempty"`
+	Total   int    `json:"total"`
+	// FTE is Total as full-time equivalents, with WithFTE
+	FTE          float64                   `json:"fte,omitempty"`
+	LocationData map[string]*LocationGroup `json:"locations,omitempty"`
+	UnmetDemand  *UnmetDemandInfo          `json:"unmet_demand,omitempty"`
+}
+
+// UnmetDemandInfo represents unmet demand for a specific hour
+type UnmetDemandInfo struct {
+	TotalDemand     int                     `json:"total_demand"`
+	AllocatedAgents int                     `json:"allocated_agents"`
+	UnmetAgents     int                     `json:"unmet_agents"`
+	ImpactedClients []models.ImpactedClient `json:"impacted_clients"`
+}
+
+// LocationGroup holds customer data for a location
+type LocationGroup struct {
+	Total     int            `json:"total"`
+	Customers map[string]int `json:"customers"`
+	// FTE and CustomerFTE are Total and Customers as full-time
+	// equivalents, with WithFTE
+	FTE         float64            `json:"fte,omitempty"`
+	CustomerFTE map[string]float64 `json:"customer_fte,omitempty"`
+}
+
+// prepareScheduleData extracts and organizes schedule data for formatting
+// in each of formats, so that rendering several formats prepares it once
+func prepareScheduleData(schedule *models.Schedule, opts []Option, formats ...string) *ScheduleData {
+	data := &ScheduleData{}
+	for _, opt := range opts {
+		opt(&data.opts)
+	}
+	if !data.opts.location.IsZero() {
+		schedule = schedule.InLocation(data.opts.location, time.Now())
+	}
+	data.schedule = schedule
+	data.unmet = unmetByHour(schedule, schedule.NumSlots())
+	if slices.Contains(formats, "text") || slices.Contains(formats, "csv") {
+		data.grouped = groupHours(schedule)
+	}
+	if !slices.Contains(formats, "json") {
+		return data
+	}
+
+	data.Hours = make([]HourlyData, schedule.NumSlots())
+	data.UnmetByHour = make(map[int]*models.UnmetDemand)
+	forEachHour(schedule, len(data.Hours), func(h int) {
+		data.Hours[h] = processHour(schedule, h, data.opts)
+	})
+	for h := range data.Hours {
+		// Add unmet demand info if exists
+		if u := data.unmet[h]; u != nil {
+			data.UnmetByHour[h] = u
+			data.Hours[h].UnmetDemand = &UnmetDemandInfo{
+				TotalDemand:     u.TotalDemand,
+				AllocatedAgents: u.AllocatedAgents,
+				UnmetAgents:     u.UnmetAgents,
+				ImpactedClients: u.ImpactedClients,
+			}
+		}
+	}
+	return data
+}
+
+// parallelRequirements is the schedule size, in requirements, from which
+// hours are grouped concurrently; smaller schedules are quicker on one
+// goroutine.
+const parallelRequirements = 4096
+
+// forEachHour calls fn for each of the first n hours, concurrently once the
+// schedule has parallelRequirements requirements.
+func forEachHour(schedule *models.Schedule, n int, fn func(h int)) {
+	if requirementCount(schedule) < parallelRequirements {
+		for h := range n {
+			fn(h)
+		}
+		return
+	}
+
+	var g errgroup.Group
+	g.SetLimit(runtime.GOMAXPROCS(0))
+	for h := range n {
+		g.Go(func() error {
+			fn(h)
+			return nil
+		})
+	}
+	_ = g.Wait()
+}
+
+// requirementCount returns how many requirements the schedule holds across
+// all hours.
+func requirementCount(schedule *models.Schedule) int {
+	n := 0
+	for _, reqs := range schedule.HourlyRequirements {
+		n += len(reqs)
+	}
+	return n
+}
+
+// Format renders the schedule in the named format (text, json, or csv),
+// recording a tracing span as a child of ctx.
+func Format(ctx context.Context, format string, schedule *models.Schedule, opts ...Option) (string, error) {
+	var sb strings.Builder
+	if err := Write(ctx, &sb, format, schedule, opts...); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
+
+// Write is like Format but writes the rendered schedule to w; text and CSV
+// are written an hour at a time rather than built in memory first. Nothing
+// is written if ctx is already cancelled.
+func Write(ctx context.Context, w io.Writer, format string, schedule *models.Schedule, opts ...Option) error {
+	_, span := tracing.Tracer("github.com/karthikrao-23/agentscheduler/pkg/formatter").Start(ctx, "formatter.Format",
+		trace.WithAttributes(attribute.String("formatter.format", format)))
+	defer span.End()
+
+	write, err := writerFor(format)
+	if err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return write(w, prepareScheduleData(schedule, opts, format))
+}
+
+// FormatAll renders the schedule in each of formats concurrently, from data
+// prepared once for all of them; the i-th output is the schedule in
+// formats[i]. It records a tracing span as a child of ctx.
+func FormatAll(ctx context.Context, formats []string, schedule *models.Schedule, opts ...Option) ([]string, error) {
+	_, span := tracing.Tracer("github.com/karthikrao-23/agentscheduler/pkg/formatter").Start(ctx, "formatter.FormatAll",
+		trace.WithAttributes(attribute.StringSlice("formatter.formats", formats)))
+	defer span.End()
+
+	writes := make([]func(io.Writer, *ScheduleData) error, len(formats))
+	for i, format := range formats {
+		write, err := writerFor(format)
+		if err != nil {
+			return nil, err
+		}
+		writes[i] = write
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	data := prepareScheduleData(schedule, opts, formats...)
+	outputs := make([]string, len(formats))
+	var g errgroup.Group
+	for i, write := range writes {
+		g.Go(func() error {
+			var sb strings.Builder
+			if err := write(&sb, data); err != nil {
+				return err
+			}
+			outputs[i] = sb.String()
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	return outputs, nil
+}
+
+// writerFor returns the function that writes format.
+func writerFor(format string) (func(io.Writer, *ScheduleData) error, error) {
+	switch format {
+	case "text":
+		return writeText, nil
+	case "json":
+		return writeJSON, nil
+	case "csv":
+		return writeCSV, nil
+	default:
+		return nil, fmt.Errorf("unknown output format: %s", format)
+	}
+}
+
+// FormatText returns the text representation of the schedule
+func FormatText(schedule *models.Schedule) string {
+	var sb strings.Builder
+	_ = writeText(&sb, prepareScheduleData(schedule, nil, "text"))
+	return sb.String()
+}
+
+// FormatJSON returns the JSON representation of the schedule
+func FormatJSON(schedule *models.Schedule) string {
+	var sb strings.Builder
+	_ = writeJSON(&sb, prepareScheduleData(schedule, nil, "json"))
+	return sb.String()
+}
+
+// FormatCSV returns the CSV representation of the schedule
+func FormatCSV(schedule *models.Schedule) string {
+	var sb strings.Builder
+	_ = writeCSV(&sb, prepareScheduleData(schedule, nil, "csv"))
+	return sb.String()
+}
+
+// writeText writes the text format a line at a time, reusing one buffer. A
+// schedule laid out by date gets a heading line, e.g. "Monday 2026-03-02",
+// before each day's slots.
+func writeText(w io.Writer, data *ScheduleData) error {
+	var buf []byte
+	if data.opts.metadata {
+		if _, err := w.Write(appendMetadata(buf, data.schedule.Metadata)); err != nil {
+			return err
+		}
+	}
+	day := ""
+	for h, hour := range data.grouped {
+		buf = buf[:0]
+		slot := data.schedule.SlotAt(h)
+		if slot.HasDate() {
+			if heading := slot.Location.In(slot.Start).Format("Monday 2006-01-02"); heading != day {
+				if day != "" {
+					buf = append(buf, '\n')
+				}
+				day = heading
+				buf = append(buf, heading...)
+				buf = append(buf, '\n')
+			}
+		}
+		buf = append(buf, slot.String()...)
+		buf = append(buf, " : total="...)
+		buf = data.opts.appendAgents(buf, hour.total, hour.fte)
+		if hour.total == 0 {
+			buf = append(buf, " ; none"...)
+		} else {
+			buf = append(buf, " ; ["...)
+			for i, c := range hour.customers {
+				if i == 0 || c.location != hour.customers[i-1].location {
+					if i > 0 {
+						buf = append(buf, ", "...)
+					}
+					buf = append(buf, c.location...)
+					buf = append(buf, ": total="...)
+					total, fte := hour.locationTotal(i)
+					buf = data.opts.appendAgents(buf, total, fte)
+				}
+				buf = append(buf, ", "...)
+				buf = append(buf, c.name...)
+				buf = append(buf, '=')
+				buf = data.opts.appendAgents(buf, c.agents, c.fte)
+			}
+			buf = append(buf, ']')
+		}
+		buf = append(buf, '\n')
+
+		// Add unmet demand warning if exists
+		if u := data.unmet[h]; u != nil {
+			buf = fmt.Appendf(buf, "  ⚠️  CAPACITY WARNING: Demand=%d, Allocated=%d, Unmet=%d\n",
+				u.TotalDemand, u.AllocatedAgents, u.UnmetAgents)
+			buf = append(buf, "  Impacted clients:\n"...)
+			for _, client := range u.ImpactedClients {
+				buf = fmt.Appendf(buf, "    • %s [Priority %d", client.Name, client.Priority)
+				if client.Skill != "" {
+					buf = fmt.Appendf(buf, ", skill %s", client.Skill)
+				}
+				if client.BelowMinimum() {
+					buf = fmt.Appendf(buf, ", below minimum %d", client.MinAgents)
+				}
+				if client.Capped() {
+					buf = fmt.Appendf(buf, ", capped at %d", client.CeilingAgents)
+				}
+				buf = fmt.Appendf(buf, "]: Requested=%d, Allocated=%d, Unmet=%d\n",
+					client.RequestedAgents, client.AllocatedAgents, client.UnmetAgents)
+			}
+		}
+
+		if _, err := w.Write(buf); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeJSON writes the JSON format, which keeps the shape of HourlyData.
+func writeJSON(w io.Writer, data *ScheduleData) error {
+	var v any = data.Hours
+	if data.opts.metadata {
+		v = struct {
+			Metadata *models.Metadata `json:"metadata,omitempty"`
+			Hours    []HourlyData     `json:"hours"`
+		}{data.schedule.Metadata, data.Hours}
+	}
+	jsonBytes, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(jsonBytes)
+	return err
+}
+
+// writeCSV writes the CSV format a row at a time.
+func writeCSV(w io.Writer, data *ScheduleData) error {
+	if data.opts.metadata {
+		if _, err := w.Write(appendMetadata(nil, data.schedule.Metadata)); err != nil {
+			return err
+		}
+	}
+	writer := csv.NewWriter(w)
+
+	// Write header
+	writer.Write([]string{
+		"Hour", "Total Agents", "Locations", "Customer Details",
+		"Capacity Warning", "Total Demand", "Allocated", "Unmet", "Impacted Clients",
+	})
+
+	row := make([]string, 9)
+	var buf []byte
+	for h, hour := range data.grouped {
+		buf = writeHourToCSV(writer, row, buf, data.schedule.SlotAt(h), hour, data.unmet[h], data.opts)
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// writeHourToCSV writes a single hour's data to CSV, building its fields in
+// row and buf, which it returns for the next hour to reuse.
+func writeHourToCSV(writer *csv.Writer, row []string, buf []byte, slot models.TimeSlot, hour groupedHour, unmet *models.UnmetDemand, opts options) []byte {
+	clear(row)
+	row[0] = slot.String()
+
+	if hour.total == 0 {
+		// Empty hour
+		row[1], row[4] = "0", "No"
+		if opts.fte {
+			row[1] = string(opts.appendAgents(buf[:0], 0, 0))
+		}
+		writer.Write(row)
+		return buf
+	}
+	row[1] = string(opts.appendAgents(buf[:0], hour.total, hour.fte))
+
+	// Build location list
+	buf = buf[:0]
+	for i, c := range hour.customers {
+		if i > 0 && c.location == hour.customers[i-1].location {
+			continue
+		}
+		if i > 0 {
+			buf = append(buf, "; "...)
+		}
+		buf = append(buf, c.location...)
+	}
+	row[2] = string(buf)
+
+	// Build customer details with format: "Customer1(loc1,agents=5); Customer2(loc2,agents=3)"
+	buf = buf[:0]
+	for i, c := range hour.customers {
+		if i > 0 {
+			buf = append(buf, "; "...)
+		}
+		buf = append(buf, c.name...)
+		buf = append(buf, '(')
+		buf = append(buf, c.location...)
+		buf = append(buf, ",agents="...)
+		buf = opts.appendAgents(buf, c.agents, c.fte)
+		buf = append(buf, ')')
+	}
+	row[3] = string(buf)
+
+	if unmet == nil {
+		row[4] = "No"
+		writer.Write(row)
+		return buf
+	}
+
+	// Build impacted clients string
+	buf = buf[:0]
+	for i, client := range unmet.ImpactedClients {
+		if i > 0 {
+			buf = append(buf, "; "...)
+		}
+		buf = fmt.Appendf(buf, "%s(priority=%d,", client.Name, client.Priority)
+		if client.Skill != "" {
+			buf = fmt.Appendf(buf, "skill=%s,", client.Skill)
+		}
+		if client.BelowMinimum() {
+			buf = fmt.Appendf(buf, "below_minimum=%d,", client.MinAgents)
+		}
+		if client.Capped() {
+			buf = fmt.Appendf(buf, "ceiling=%d,", client.CeilingAgents)
+		}
+		buf = fmt.Appendf(buf, "requested=%d,allocated=%d,unmet=%d)",
+			client.RequestedAgents, client.AllocatedAgents, client.UnmetAgents)
+	}
+	row[4] = "Yes"
+	row[5] = strconv.Itoa(unmet.TotalDemand)
+	row[6] = strconv.Itoa(unmet.AllocatedAgents)
+	row[7] = strconv.Itoa(unmet.UnmetAgents)
+	row[8] = string(buf)
+	writer.Write(row)
+	return buf
+}
+
+// customerAgents is the agents one customer needs at a location in an hour.
+type customerAgents struct {
+	location string
+	name     string
+	agents   int
+	fte      float64
+}
+
+// groupedHour is an hour's requirements summed per location and customer,
+// sorted by location and then customer, as the text and CSV formats list
+// them.
+type groupedHour struct {
+	customers []customerAgents
+	total     int
+	fte       float64
+}
+
+// locationTotal returns the agents, and full-time equivalents, needed at the
+// location of customers[i], which must be the first customer listed there.
+func (g groupedHour) locationTotal(i int) (int, float64) {
+	total, fte := 0, 0.0
+	for _, c := range g.customers[i:] {
+		if c.location != g.customers[i].location {
+			break
+		}
+		total += c.agents
+		fte += c.fte
+	}
+	return total, fte
+}
+
+// groupHours groups each hour's requirements for the text and CSV formats.
+// Every hour shares one backing array rather than building maps per hour
+// and location, so large schedules allocate little beyond their output.
+func groupHours(schedule *models.Schedule) []groupedHour {
+	hours := make([]groupedHour, schedule.NumSlots())
+	customers := make([]customerAgents, requirementCount(schedule))
+	offset := 0
+	for h := range hours {
+		if h < len(schedule.HourlyRequirements) {
+			n := len(schedule.HourlyRequirements[h])
+			hours[h].customers = customers[offset : offset+n : offset+n]
+			offset += n
+		}
+	}
+
+	forEachHour(schedule, len(hours), func(h int) {
+		cs := hours[h].customers
+		if len(cs) == 0 {
+			return
+		}
+		for i, req := range schedule.HourlyRequirements[h] {
+			cs[i] = customerAgents{location: req.Location.String(), name: req.Name, agents: req.AgentsNeeded, fte: req.AgentsFTE()}
+			hours[h].total += req.AgentsNeeded
+			hours[h].fte += req.AgentsFTE()
+		}
+		slices.SortFunc(cs, func(a, b customerAgents) int {
+			return cmp.Or(strings.Compare(a.location, b.location), strings.Compare(a.name, b.name))
+		})
+		// A customer can have several requirements in an hour, e.g. at
+		// different priorities
+		n := 0
+		for _, c := range cs {
+			if n > 0 && cs[n-1].location == c.location && cs[n-1].name == c.name {
+				cs[n-1].agents += c.agents
+				cs[n-1].fte += c.fte
+				continue
+			}
+			cs[n] = c
+			n++
+		}
+		hours[h].customers = cs[:n]
+	})
+	return hours
+}
+
+// unmetByHour indexes the schedule's unmet demand by hour.
+func unmetByHour(schedule *models.Schedule, hours int) []*models.UnmetDemand {
+	unmet := make([]*models.UnmetDemand, hours)
+	for i := range schedule.UnmetDemands {
+		if h := schedule.UnmetDemands[i].Hour; h >= 0 && h < hours {
+			unmet[h] = &schedule.UnmetDemands[i]
+		}
+	}
+	return unmet
+}
+
+// processHour groups requirements by location for a given hour
+func processHour(schedule *models.Schedule, hour int, opts options) HourlyData {
+	data := HourlyData{
+		Hour:         hour,
+		Slot:         schedule.SlotAt(hour),
+		LocationData: make(map[string]*LocationGroup),
+	}
+	if data.Slot.HasDate() {
+		local := data.Slot.Location.In(data.Slot.Start)
+		data.Date = local.Format(time.DateOnly)
+		data.Weekday = local.Weekday().String()
+	}
+
+	if hour >= len(schedule.HourlyRequirements) {
+		return data
+	}
+
+	for _, req := range schedule.HourlyRequirements[hour] {
+		locName := req.Location.String()
+
+		group, exists := data.LocationData[locName]
+		if !exists {
+			group = &LocationGroup{Customers: make(map[string]int)}
+			data.LocationData[locName] = group
+		}
+
+		// A customer can have several requirements in an hour, e.g. at
+		// different priorities
+		group.Customers[req.Name] += req.AgentsNeeded
+		group.Total += req.AgentsNeeded
+		data.Total += req.AgentsNeeded
+		if opts.fte {
+			if group.CustomerFTE == nil {
+				group.CustomerFTE = make(map[string]float64)
+			}
+			group.CustomerFTE[req.Name] += req.AgentsFTE()
+			group.FTE += req.AgentsFTE()
+			data.FTE += req.AgentsFTE()
+		}
+	}
+
+	// Rounded once summed, so totals are not off by the rounding of each
+	// customer
+	if opts.fte {
+		data.FTE = opts.round(data.FTE)
+		for _, group := range data.LocationData {
+			group.FTE = opts.round(group.FTE)
+			for name, fte := range group.CustomerFTE {
+				group.CustomerFTE[name] = opts.round(fte)
+			}
+		}
+	}
+	return data
+}
@@ -0,0 +1,531 @@
+package formatter_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/karthikrao-23/agentscheduler/internal/benchdata"
+	"github.com/karthikrao-23/agentscheduler/pkg/formatter"
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+	"github.com/karthikrao-23/agentscheduler/pkg/scheduler"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatText(t *testing.T) {
+	tests := map[string]struct {
+		schedule *models.Schedule
+		contains []string
+	}{
+		"EmptySchedule": {
+			schedule: &models.Schedule{
+				HourlyRequirements: make([][]models.CustomerRequirement, 24),
+			},
+			contains: []string{
+				"00:00 : total=0 ; none",
+				"12:00 : total=0 ; none",
+				"23:00 : total=0 ; none",
+			},
+		},
+		"SimpleSchedule": {
+			schedule: &models.Schedule{
+				HourlyRequirements: func() [][]models.CustomerRequirement {
+					reqs := make([][]models.CustomerRequirement, 24)
+					reqs[10] = []models.CustomerRequirement{
+						{Name: "Cust1", AgentsNeeded: 5, Location: models.NewLocation(time.UTC)},
+					}
+					return reqs
+				}(),
+			},
+			contains: []string{
+				"10:00 : total=5 ; [UTC: total=5, Cust1=5]",
+			},
+		},
+		"WithUnmetDemand": {
+			schedule: &models.Schedule{
+				HourlyRequirements: func() [][]models.CustomerRequirement {
+					reqs := make([][]models.CustomerRequirement, 24)
+					reqs[10] = []models.CustomerRequirement{
+						{Name: "Cust1", AgentsNeeded: 5, Location: models.NewLocation(time.UTC)},
+					}
+					return reqs
+				}(),
+				UnmetDemands: []models.UnmetDemand{
+					{
+						Hour:            10,
+						TotalDemand:     10,
+						AllocatedAgents: 5,
+						UnmetAgents:     5,
+						ImpactedClients: []models.ImpactedClient{
+							{Name: "Cust2", RequestedAgents: 5, AllocatedAgents: 0, UnmetAgents: 5, Priority: 2},
+						},
+					},
+				},
+			},
+			contains: []string{
+				"10:00 : total=5 ; [UTC: total=5, Cust1=5]",
+				"⚠️  CAPACITY WARNING: Demand=10, Allocated=5, Unmet=5",
+				"Impacted clients:",
+				"• Cust2 [Priority 2]: Requested=5, Allocated=0, Unmet=5",
+			},
+		},
+		"BelowMinimum": {
+			schedule: &models.Schedule{
+				HourlyRequirements: make([][]models.CustomerRequirement, 24),
+				UnmetDemands: []models.UnmetDemand{
+					{
+						Hour:        10,
+						TotalDemand: 5,
+						UnmetAgents: 4,
+						ImpactedClients: []models.ImpactedClient{
+							{Name: "Cust2", RequestedAgents: 5, AllocatedAgents: 1, UnmetAgents: 4, Priority: 2, MinAgents: 3, Skill: "billing"},
+						},
+					},
+				},
+			},
+			contains: []string{
+				"• Cust2 [Priority 2, skill billing, below minimum 3]: Requested=5, Allocated=1, Unmet=4",
+			},
+		},
+		"Capped": {
+			schedule: &models.Schedule{
+				HourlyRequirements: make([][]models.CustomerRequirement, 24),
+				UnmetDemands: []models.UnmetDemand{
+					{
+						Hour:            10,
+						TotalDemand:     12,
+						AllocatedAgents: 8,
+						UnmetAgents:     4,
+						ImpactedClients: []models.ImpactedClient{
+							{Name: "Cust2", RequestedAgents: 12, AllocatedAgents: 8, UnmetAgents: 4, Priority: 2, CeilingAgents: 8},
+						},
+					},
+				},
+			},
+			contains: []string{
+				"• Cust2 [Priority 2, capped at 8]: Requested=12, Allocated=8, Unmet=4",
+			},
+		},
+		"UnsetLocation": {
+			schedule: &models.Schedule{
+				HourlyRequirements: func() [][]models.CustomerRequirement {
+					reqs := make([][]models.CustomerRequirement, 24)
+					reqs[10] = []models.CustomerRequirement{
+						{Name: "Cust1", AgentsNeeded: 5},
+					}
+					return reqs
+				}(),
+			},
+			contains: []string{
+				"10:00 : total=5 ; [unknown: total=5, Cust1=5]",
+			},
+		},
+		"CustomerAtTwoPriorities": {
+			schedule: &models.Schedule{
+				HourlyRequirements: func() [][]models.CustomerRequirement {
+					reqs := make([][]models.CustomerRequirement, 24)
+					reqs[10] = []models.CustomerRequirement{
+						{Name: "Cust1", AgentsNeeded: 5, Priority: 1, Location: models.NewLocation(time.UTC)},
+						{Name: "Cust1", AgentsNeeded: 2, Priority: 3, Location: models.NewLocation(time.UTC)},
+					}
+					return reqs
+				}(),
+			},
+			contains: []string{
+				"10:00 : total=7 ; [UTC: total=7, Cust1=7]",
+			},
+		},
+		"SeveralLocations": {
+			schedule: &models.Schedule{
+				HourlyRequirements: func() [][]models.CustomerRequirement {
+					tokyo, _ := models.LoadLocation("Asia/Tokyo")
+					reqs := make([][]models.CustomerRequirement, 24)
+					reqs[10] = []models.CustomerRequirement{
+						{Name: "Zeta", AgentsNeeded: 1, Location: models.NewLocation(time.UTC)},
+						{Name: "Beta", AgentsNeeded: 2, Location: tokyo},
+						{Name: "Alpha", AgentsNeeded: 3, Location: models.NewLocation(time.UTC)},
+						{Name: "Alpha", AgentsNeeded: 4, Location: tokyo},
+					}
+					return reqs
+				}(),
+			},
+			contains: []string{
+				"10:00 : total=10 ; [Asia/Tokyo: total=6, Alpha=4, Beta=2, UTC: total=4, Alpha=3, Zeta=1]",
+			},
+		},
+		"ByDate": {
+			schedule: &models.Schedule{
+				HourlyRequirements: make([][]models.CustomerRequirement, 48),
+				Slots:              append(models.DaySlots(2026, 3, 1, time.Hour), models.DaySlots(2026, 3, 2, time.Hour)...),
+			},
+			contains: []string{
+				"Sunday 2026-03-01\n2026-03-01 00:00 : total=0 ; none",
+				"2026-03-01 23:00 : total=0 ; none\n\nMonday 2026-03-02\n2026-03-02 00:00 : total=0 ; none",
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			output := formatter.FormatText(tt.schedule)
+			for _, s := range tt.contains {
+				assert.Contains(t, output, s)
+			}
+		})
+	}
+}
+
+func TestFormatJSON(t *testing.T) {
+	tests := map[string]struct {
+		schedule *models.Schedule
+		contains []string
+	}{
+		"EmptySchedule": {
+			schedule: &models.Schedule{
+				HourlyRequirements: make([][]models.CustomerRequirement, 24),
+			},
+			contains: []string{
+				`"hour": 0`,
+				`"total": 0`,
+			},
+		},
+		"SimpleSchedule": {
+			schedule: &models.Schedule{
+				HourlyRequirements: func() [][]models.CustomerRequirement {
+					reqs := make([][]models.CustomerRequirement, 24)
+					reqs[10] = []models.CustomerRequirement{
+						{Name: "Cust1", AgentsNeeded: 5, Location: models.NewLocation(time.UTC)},
+					}
+					return reqs
+				}(),
+			},
+			contains: []string{
+				`"hour": 10`,
+				`"total": 5`,
+				`"UTC"`,
+				`"Cust1": 5`,
+			},
+		},
+		"ImpactedClientLocation": {
+			schedule: &models.Schedule{
+				HourlyRequirements: make([][]models.CustomerRequirement, 24),
+				UnmetDemands: []models.UnmetDemand{
+					{
+						Hour:        10,
+						TotalDemand: 5,
+						UnmetAgents: 5,
+						ImpactedClients: []models.ImpactedClient{
+							{Name: "Cust2", RequestedAgents: 5, UnmetAgents: 5, Priority: 2, Location: models.NewLocation(time.UTC)},
+						},
+					},
+				},
+			},
+			contains: []string{
+				`"Location": "UTC"`,
+			},
+		},
+		"ByDate": {
+			schedule: &models.Schedule{
+				HourlyRequirements: make([][]models.CustomerRequirement, 24),
+				Slots:              models.DaySlots(2026, 3, 2, time.Hour),
+			},
+			contains: []string{
+				`"date": "2026-03-02"`,
+				`"weekday": "Monday"`,
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			output := formatter.FormatJSON(tt.schedule)
+			for _, s := range tt.contains {
+				assert.Contains(t, output, s)
+			}
+		})
+	}
+}
+
+func TestFormatCSV(t *testing.T) {
+	tests := map[string]struct {
+		schedule *models.Schedule
+		contains []string
+	}{
+		"EmptySchedule": {
+			schedule: &models.Schedule{
+				HourlyRequirements: make([][]models.CustomerRequirement, 24),
+			},
+			contains: []string{
+				"Hour,Total Agents,Locations,Customer Details,Capacity Warning,Total Demand,Allocated,Unmet,Impacted Clients",
+				"00:00,0,,,No,,,,",
+			},
+		},
+		"SimpleSchedule": {
+			schedule: &models.Schedule{
+				HourlyRequirements: func() [][]models.CustomerRequirement {
+					reqs := make([][]models.CustomerRequirement, 24)
+					reqs[10] = []models.CustomerRequirement{
+						{Name: "Cust1", AgentsNeeded: 5, Location: models.NewLocation(time.UTC)},
+					}
+					return reqs
+				}(),
+			},
+			contains: []string{
+				"10:00,5,UTC,\"Cust1(UTC,agents=5)\",No,,,,",
+			},
+		},
+		"WithUnmetDemand": {
+			schedule: &models.Schedule{
+				HourlyRequirements: func() [][]models.CustomerRequirement {
+					reqs := make([][]models.CustomerRequirement, 24)
+					reqs[10] = []models.CustomerRequirement{
+						{Name: "Cust1", AgentsNeeded: 5, Location: models.NewLocation(time.UTC)},
+					}
+					return reqs
+				}(),
+				UnmetDemands: []models.UnmetDemand{
+					{
+						Hour:            10,
+						TotalDemand:     10,
+						AllocatedAgents: 5,
+						UnmetAgents:     5,
+						ImpactedClients: []models.ImpactedClient{
+							{Name: "Cust2", RequestedAgents: 5, AllocatedAgents: 0, UnmetAgents: 5, Priority: 2},
+						},
+					},
+				},
+			},
+			contains: []string{
+				"10:00,5,UTC,\"Cust1(UTC,agents=5)\",Yes,10,5,5,\"Cust2(priority=2,requested=5,allocated=0,unmet=5)\"",
+			},
+		},
+		"SeveralLocations": {
+			schedule: &models.Schedule{
+				HourlyRequirements: func() [][]models.CustomerRequirement {
+					tokyo, _ := models.LoadLocation("Asia/Tokyo")
+					reqs := make([][]models.CustomerRequirement, 24)
+					reqs[10] = []models.CustomerRequirement{
+						{Name: "Zeta", AgentsNeeded: 1, Location: models.NewLocation(time.UTC)},
+						{Name: "Alpha", AgentsNeeded: 3, Location: tokyo},
+						{Name: "Zeta", AgentsNeeded: 2, Location: models.NewLocation(time.UTC)},
+					}
+					return reqs
+				}(),
+			},
+			contains: []string{
+				"10:00,6,Asia/Tokyo; UTC,\"Alpha(Asia/Tokyo,agents=3); Zeta(UTC,agents=3)\",No,,,,",
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			output := formatter.FormatCSV(tt.schedule)
+			lines := strings.Split(output, "\n")
+
+			// Check header
+			assert.Equal(t, "Hour,Total Agents,Locations,Customer Details,Capacity Warning,Total Demand,Allocated,Unmet,Impacted Clients", lines[0])
+
+			for _, s := range tt.contains {
+				assert.Contains(t, output, s)
+			}
+		})
+	}
+}
+
+func TestWrite(t *testing.T) {
+	schedule := &models.Schedule{
+		HourlyRequirements: make([][]models.CustomerRequirement, 24),
+	}
+
+	var buf bytes.Buffer
+	err := formatter.Write(context.Background(), &buf, "text", schedule)
+	assert.NoError(t, err)
+	assert.Equal(t, formatter.FormatText(schedule), buf.String())
+
+	err = formatter.Write(context.Background(), &buf, "xml", schedule)
+	assert.EqualError(t, err, "unknown output format: xml")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	buf.Reset()
+	err = formatter.Write(ctx, &buf, "csv", schedule)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Zero(t, buf.Len(), "nothing is written once the context is cancelled")
+}
+
+func TestFormatAll(t *testing.T) {
+	schedule := &models.Schedule{
+		HourlyRequirements: func() [][]models.CustomerRequirement {
+			reqs := make([][]models.CustomerRequirement, 24)
+			reqs[10] = []models.CustomerRequirement{
+				{Name: "Cust1", AgentsNeeded: 5, Location: models.NewLocation(time.UTC)},
+			}
+			return reqs
+		}(),
+		UnmetDemands: []models.UnmetDemand{{Hour: 10, TotalDemand: 8, AllocatedAgents: 5, UnmetAgents: 3}},
+	}
+
+	outputs, err := formatter.FormatAll(context.Background(), []string{"csv", "text", "json"}, schedule)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{formatter.FormatCSV(schedule), formatter.FormatText(schedule), formatter.FormatJSON(schedule)}, outputs)
+
+	_, err = formatter.FormatAll(context.Background(), []string{"text", "xml"}, schedule)
+	assert.EqualError(t, err, "unknown output format: xml")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = formatter.FormatAll(ctx, []string{"text"}, schedule)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestFormat_FTE(t *testing.T) {
+	utc := models.NewLocation(time.UTC)
+	schedule := &models.Schedule{
+		HourlyRequirements: func() [][]models.CustomerRequirement {
+			reqs := make([][]models.CustomerRequirement, 24)
+			reqs[10] = []models.CustomerRequirement{
+				{Name: "Cust1", AgentsNeeded: 2, FTE: 1.26, Location: utc},
+				{Name: "Cust2", AgentsNeeded: 1, FTE: 0.333, Location: utc},
+				{Name: "Cust2", AgentsNeeded: 1, FTE: 0.333, Location: utc},
+			}
+			return reqs
+		}(),
+	}
+	tests := map[string]struct {
+		format   string
+		contains []string
+	}{
+		"Text": {
+			format: "text",
+			contains: []string{
+				"00:00 : total=0.0 ; none",
+				"10:00 : total=1.9 ; [UTC: total=1.9, Cust1=1.3, Cust2=0.7]",
+			},
+		},
+		"CSV": {
+			format: "csv",
+			contains: []string{
+				"00:00,0.0,,,No,,,,",
+				"10:00,1.9,UTC,\"Cust1(UTC,agents=1.3); Cust2(UTC,agents=0.7)\",No,,,,",
+			},
+		},
+		"JSON": {
+			format: "json",
+			contains: []string{
+				`"total": 4,`,
+				`"fte": 1.9,`,
+				`"Cust2": 0.7`,
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			output, err := formatter.Format(context.Background(), tt.format, schedule, formatter.WithFTE(1))
+			assert.NoError(t, err)
+			for _, s := range tt.contains {
+				assert.Contains(t, output, s)
+			}
+		})
+	}
+}
+
+func TestFormat_Location(t *testing.T) {
+	utc := models.NewLocation(time.UTC)
+	tokyo, err := models.LoadLocation("Asia/Tokyo")
+	require.NoError(t, err)
+	// 7PM in Tokyo, which keeps no DST, is 10AM UTC
+	schedule := &models.Schedule{HourlyRequirements: make([][]models.CustomerRequirement, 24)}
+	schedule.HourlyRequirements[10] = []models.CustomerRequirement{{Name: "Cust1", AgentsNeeded: 2, Location: utc}}
+	schedule.HourlyRequirements[19] = []models.CustomerRequirement{{Name: "Cust2", AgentsNeeded: 3, Location: tokyo}}
+
+	output, err := formatter.Format(context.Background(), "text", schedule, formatter.WithLocation(utc))
+	require.NoError(t, err)
+	assert.Contains(t, output, "10:00 : total=5 ; [Asia/Tokyo: total=3, Cust2=3, UTC: total=2, Cust1=2]")
+	assert.Contains(t, output, "19:00 : total=0 ; none")
+}
+
+func TestFormat_Metadata(t *testing.T) {
+	schedule := &models.Schedule{
+		HourlyRequirements: make([][]models.CustomerRequirement, 24),
+		Metadata: &models.Metadata{
+			GeneratedAt: time.Date(2026, 3, 2, 8, 0, 0, 0, time.UTC),
+			RunID:       "run-1",
+			InputHash:   "abc123",
+			Options:     map[string]string{"utilization": "0.85", "capacity": "50"},
+			ToolVersion: "v1.2.3",
+		},
+	}
+	schedule.HourlyRequirements[9] = []models.CustomerRequirement{{Name: "Acme", AgentsNeeded: 2, Location: models.NewLocation(time.UTC)}}
+	header := "# generated_at: 2026-03-02T08:00:00Z\n# run_id: run-1\n# input_hash: abc123\n# tool_version: v1.2.3\n# option.capacity: 50\n# option.utilization: 0.85\n"
+
+	for _, format := range []string{"text", "csv"} {
+		t.Run(format, func(t *testing.T) {
+			output, err := formatter.Format(context.Background(), format, schedule, formatter.WithMetadata())
+			require.NoError(t, err)
+			assert.True(t, strings.HasPrefix(output, header), output)
+
+			output, err = formatter.Format(context.Background(), format, schedule)
+			require.NoError(t, err)
+			assert.NotContains(t, output, "# ")
+		})
+	}
+
+	t.Run("json", func(t *testing.T) {
+		output, err := formatter.Format(context.Background(), "json", schedule, formatter.WithMetadata())
+		require.NoError(t, err)
+		var got struct {
+			Metadata models.Metadata        `json:"metadata"`
+			Hours    []formatter.HourlyData `json:"hours"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(output), &got))
+		assert.Equal(t, *schedule.Metadata, got.Metadata)
+		assert.Len(t, got.Hours, 24)
+		assert.Equal(t, 2, got.Hours[9].Total)
+	})
+}
+
+func TestFormatDiff(t *testing.T) {
+	before := &models.Schedule{HourlyRequirements: make([][]models.CustomerRequirement, 24)}
+	before.HourlyRequirements[9] = []models.CustomerRequirement{
+		{Name: "Acme", AgentsNeeded: 3, Location: models.NewLocation(time.UTC)},
+	}
+	before.UnmetDemands = []models.UnmetDemand{{Hour: 9, ImpactedClients: []models.ImpactedClient{
+		{Name: "Acme", UnmetAgents: 2, Location: models.NewLocation(time.UTC)},
+	}}}
+	after := &models.Schedule{HourlyRequirements: make([][]models.CustomerRequirement, 24)}
+	after.HourlyRequirements[9] = []models.CustomerRequirement{
+		{Name: "Acme", AgentsNeeded: 5, Location: models.NewLocation(time.UTC)},
+	}
+	after.HourlyRequirements[10] = []models.CustomerRequirement{{Name: "Globex", AgentsNeeded: 1}}
+
+	assert.Equal(t, "09:00 Acme (UTC): agents 3 -> 5 (+2), unmet 2 -> 0 (-2)\n10:00 Globex: agents 0 -> 1 (+1)\n",
+		formatter.FormatDiff(before.Diff(after)))
+	assert.Equal(t, "No changes.\n", formatter.FormatDiff(after.Diff(after)))
+	assert.Equal(t, "Acme (UTC): agents +2 over 1 slot, unmet -2\nGlobex: agents +1 over 1 slot\n",
+		formatter.FormatCustomerDeltas(before.Diff(after)))
+}
+
+func BenchmarkWrite(b *testing.B) {
+	for _, n := range benchdata.Sizes {
+		schedule, err := scheduler.GenerateSchedule(benchdata.CallData(n), scheduler.WithCapacity(n/10))
+		require.NoError(b, err)
+		for _, format := range []string{"text", "json", "csv"} {
+			b.Run(format+"/"+benchdata.Name(n), func(b *testing.B) {
+				b.ReportAllocs()
+				for b.Loop() {
+					if err := formatter.Write(context.Background(), io.Discard, format, schedule); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,176 @@
+// Package ics renders a site's staffing as an iCalendar (RFC 5545) feed, so
+// supervisors can subscribe to the schedule in their calendar client.
+package ics
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+)
+
+// RefreshInterval is how often subscribed calendar clients are asked to
+// re-fetch the feed.
+const RefreshInterval = 15 * time.Minute
+
+const timestampLayout = "20060102T150405Z"
+
+// Write writes the staffing for site as a calendar with one event per slot
+// that has agents or unmet demand there. Sites are matched against each
+// requirement's location as the text output names it, so customers without
+// a location are under "unknown".
+//
+// Slots of a generic day are placed on the day the schedule was generated,
+// in the site's time zone when site is an IANA name. Times are written in
+// UTC so clients need no time zone definitions.
+func Write(w io.Writer, schedule *models.Schedule, site string) error {
+	loc := time.UTC
+	if l, err := models.LoadLocation(site); err == nil && !l.IsZero() {
+		loc = l.Time()
+	}
+	generated := time.Now().UTC()
+	if schedule.Metadata != nil && !schedule.Metadata.GeneratedAt.IsZero() {
+		generated = schedule.Metadata.GeneratedAt.UTC()
+	}
+	day := generated.In(loc)
+
+	unmet := make(map[int][]models.ImpactedClient)
+	for _, u := range schedule.UnmetDemands {
+		for _, client := range u.ImpactedClients {
+			if client.Location.String() == site && client.UnmetAgents > 0 {
+				unmet[u.Hour] = append(unmet[u.Hour], client)
+			}
+		}
+	}
+
+	cw := &contentWriter{w: w}
+	cw.line("BEGIN:VCALENDAR")
+	cw.line("VERSION:2.0")
+	cw.line("PRODID:-//agent-scheduler//staffing//EN")
+	cw.line("CALSCALE:GREGORIAN")
+	cw.line("METHOD:PUBLISH")
+	cw.line("X-WR-CALNAME:" + escape("Staffing: "+site))
+	cw.line("REFRESH-INTERVAL;VALUE=DURATION:" + duration(RefreshInterval))
+	cw.line("X-PUBLISHED-TTL:" + duration(RefreshInterval))
+
+	for h := range schedule.NumSlots() {
+		customers := make(map[string]int)
+		var total int
+		if h < len(schedule.HourlyRequirements) {
+			for _, req := range schedule.HourlyRequirements[h] {
+				if req.Location.String() == site {
+					customers[req.Name] += req.AgentsNeeded
+					total += req.AgentsNeeded
+				}
+			}
+		}
+		if total == 0 && len(unmet[h]) == 0 {
+			continue
+		}
+
+		slot := schedule.SlotAt(h)
+		start := slotStart(slot, day, loc)
+		var unmetTotal int
+		for _, client := range unmet[h] {
+			unmetTotal += client.UnmetAgents
+		}
+		summary := fmt.Sprintf("%d agents", total)
+		if unmetTotal > 0 {
+			summary += fmt.Sprintf(" (%d unmet)", unmetTotal)
+		}
+
+		cw.line("BEGIN:VEVENT")
+		cw.line(fmt.Sprintf("UID:%s-%s@agent-scheduler", start.Format(timestampLayout), uidSafe(site)))
+		cw.line("DTSTAMP:" + generated.Format(timestampLayout))
+		cw.line("DTSTART:" + start.Format(timestampLayout))
+		cw.line("DTEND:" + start.Add(slot.Duration).Format(timestampLayout))
+		cw.line("SUMMARY:" + escape(summary))
+		cw.line("DESCRIPTION:" + escape(description(customers, unmet[h])))
+		cw.line("TRANSP:TRANSPARENT")
+		cw.line("END:VEVENT")
+	}
+	cw.line("END:VCALENDAR")
+	return cw.err
+}
+
+// slotStart returns the instant slot starts at the site.
+func slotStart(slot models.TimeSlot, day time.Time, loc *time.Location) time.Time {
+	if !slot.Location.IsZero() && slot.HasDate() {
+		return slot.Start.UTC()
+	}
+	// Wall-clock slot: its clock time at the site, or in its own location if
+	// it has one, on its own date if it has one
+	if !slot.Location.IsZero() {
+		loc = slot.Location.Time()
+	}
+	year, month, date := day.Date()
+	if slot.HasDate() {
+		year, month, date = slot.Start.Date()
+	}
+	return time.Date(year, month, date, slot.Start.Hour(), slot.Start.Minute(), 0, 0, loc).UTC()
+}
+
+func description(customers map[string]int, unmet []models.ImpactedClient) string {
+	names := make([]string, 0, len(customers))
+	for name := range customers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	lines := make([]string, 0, len(names)+len(unmet))
+	for _, name := range names {
+		lines = append(lines, fmt.Sprintf("%s: %d", name, customers[name]))
+	}
+	for _, client := range unmet {
+		lines = append(lines, fmt.Sprintf("Unmet %s [Priority %d]: %d", client.Name, client.Priority, client.UnmetAgents))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func duration(d time.Duration) string {
+	return fmt.Sprintf("PT%dM", int(d.Minutes()))
+}
+
+// escape escapes a TEXT value.
+func escape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`).Replace(s)
+}
+
+func uidSafe(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '/' || r == ' ' || r == '@' {
+			return '-'
+		}
+		return r
+	}, s)
+}
+
+// contentWriter writes CRLF-terminated content lines folded at 75 octets,
+// keeping the first error.
+type contentWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (cw *contentWriter) line(s string) {
+	if cw.err != nil {
+		return
+	}
+	var sb strings.Builder
+	// Continuation lines start with a space, which counts towards the limit
+	for limit := 75; len(s) > limit; limit = 74 {
+		// Fold on a UTF-8 boundary
+		n := limit
+		for n > 0 && s[n]&0xC0 == 0x80 {
+			n--
+		}
+		sb.WriteString(s[:n])
+		sb.WriteString("\r\n ")
+		s = s[n:]
+	}
+	sb.WriteString(s)
+	sb.WriteString("\r\n")
+	_, cw.err = io.WriteString(cw.w, sb.String())
+}
@@ -0,0 +1,51 @@
+package ics_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/karthikrao-23/agentscheduler/pkg/ics"
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+	"github.com/karthikrao-23/agentscheduler/pkg/scheduler"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWrite(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	acme, err := models.NewCallData("Acme", time.Date(0, 1, 1, 9, 0, 0, 0, ny), time.Date(0, 1, 1, 11, 0, 0, 0, ny), 8, 3600)
+	require.NoError(t, err)
+	acme.Location = models.NewLocation(ny)
+	globex, err := models.NewCallData("Globex, Inc", time.Date(0, 1, 1, 9, 0, 0, 0, ny), time.Date(0, 1, 1, 10, 0, 0, 0, ny), 4, 3600)
+	require.NoError(t, err)
+	globex.Location = models.NewLocation(ny)
+	schedule, err := scheduler.GenerateScheduleContext(context.Background(), []models.CallData{acme, globex}, scheduler.WithCapacity(6))
+	require.NoError(t, err)
+	schedule.Metadata.GeneratedAt = time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+
+	var buf bytes.Buffer
+	require.NoError(t, ics.Write(&buf, schedule, "America/New_York"))
+	out := buf.String()
+
+	assert.True(t, strings.HasPrefix(out, "BEGIN:VCALENDAR\r\n"))
+	assert.True(t, strings.HasSuffix(out, "END:VCALENDAR\r\n"))
+	assert.Equal(t, 2, strings.Count(out, "BEGIN:VEVENT"), "09:00 and 10:00")
+	// 09:00 in New York on the day the schedule was generated
+	assert.Contains(t, out, "DTSTART:20260302T140000Z\r\nDTEND:20260302T150000Z\r\n")
+	assert.Contains(t, out, "SUMMARY:6 agents (2 unmet)\r\n")
+	assert.Contains(t, out, `DESCRIPTION:Acme: `)
+	assert.Contains(t, out, `Globex\, Inc`, "text values are escaped")
+	assert.Contains(t, out, "UID:20260302T140000Z-America-New_York@agent-scheduler\r\n")
+	for line := range strings.SplitSeq(out, "\r\n") {
+		assert.LessOrEqual(t, len(line), 75, "lines are folded")
+	}
+
+	buf.Reset()
+	require.NoError(t, ics.Write(&buf, schedule, "Europe/London"))
+	assert.NotContains(t, buf.String(), "BEGIN:VEVENT", "other sites have no events")
+}
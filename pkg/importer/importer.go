@@ -0,0 +1,146 @@
+// Package importer builds call data from the systems of record of non-voice
+// channels, such as ticketing tools, so they can be scheduled without
+// building the CSV input by hand.
+//
+// Importers fetch the interactions created over a lookback period and turn
+// them into an expected volume per customer and hour of day: the average
+// number created in that hour across the days looked back over.
+package importer
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+)
+
+// Default import settings.
+const (
+	DefaultLookback          = 7 * 24 * time.Hour
+	DefaultHandleTimeSeconds = 600
+)
+
+// Interaction is one ticket, case, or other contact from a source system.
+type Interaction struct {
+	// Customer is what the interaction is scheduled under, e.g. the
+	// assignment group or team that handles it
+	Customer string
+	Created  time.Time
+	Priority models.Priority
+}
+
+// Source fetches the interactions created since a point in time.
+type Source interface {
+	Interactions(ctx context.Context, since time.Time) ([]Interaction, error)
+}
+
+// Config configures how interactions become call data.
+type Config struct {
+	// Lookback is how far back interactions are averaged over, in whole
+	// days. Defaults to DefaultLookback.
+	Lookback time.Duration
+	// HandleTimeSeconds is the average time an agent spends per
+	// interaction. Defaults to DefaultHandleTimeSeconds.
+	HandleTimeSeconds int
+	// Location is the time zone hours of day are counted in. Defaults to UTC.
+	Location *time.Location
+	// Date is the day the call data is placed on. Defaults to today.
+	Date time.Time
+}
+
+// Import fetches the interactions of the configured lookback period from src
+// and converts them with FromInteractions.
+func Import(ctx context.Context, src Source, cfg Config) ([]models.CallData, error) {
+	cfg = cfg.withDefaults()
+	interactions, err := src.Interactions(ctx, cfg.Date.Add(-cfg.Lookback))
+	if err != nil {
+		return nil, err
+	}
+	return FromInteractions(interactions, cfg)
+}
+
+// FromInteractions returns one call data row per customer and hour of day in
+// which interactions were created, with the average number created per day
+// in that hour, rounded up. Each customer is given its most common priority.
+// Rows are ordered by customer, then hour.
+func FromInteractions(interactions []Interaction, cfg Config) ([]models.CallData, error) {
+	cfg = cfg.withDefaults()
+	days := max(int(math.Ceil(cfg.Lookback.Hours()/24)), 1)
+
+	type key struct {
+		customer string
+		hour     int
+	}
+	counts := make(map[key]int)
+	priorities := make(map[string]map[models.Priority]int)
+	for _, in := range interactions {
+		if in.Customer == "" {
+			continue
+		}
+		counts[key{in.Customer, in.Created.In(cfg.Location).Hour()}]++
+		if priorities[in.Customer] == nil {
+			priorities[in.Customer] = make(map[models.Priority]int)
+		}
+		priority := in.Priority
+		if !priority.Valid() {
+			priority = models.DefaultPriority
+		}
+		priorities[in.Customer][priority]++
+	}
+
+	keys := make([]key, 0, len(counts))
+	for k := range counts {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].customer != keys[j].customer {
+			return keys[i].customer < keys[j].customer
+		}
+		return keys[i].hour < keys[j].hour
+	})
+
+	y, m, d := cfg.Date.In(cfg.Location).Date()
+	data := make([]models.CallData, 0, len(keys))
+	for _, k := range keys {
+		start := time.Date(y, m, d, k.hour, 0, 0, 0, cfg.Location)
+		calls := (counts[k] + days - 1) / days
+		cd, err := models.NewCallData(k.customer, start, start.Add(time.Hour), calls, cfg.HandleTimeSeconds,
+			models.WithPriority(mostCommon(priorities[k.customer])))
+		if err != nil {
+			return nil, fmt.Errorf("customer %q at %02d:00: %w", k.customer, k.hour, err)
+		}
+		data = append(data, cd)
+	}
+	return data, nil
+}
+
+func (c Config) withDefaults() Config {
+	if c.Lookback <= 0 {
+		c.Lookback = DefaultLookback
+	}
+	if c.HandleTimeSeconds == 0 {
+		c.HandleTimeSeconds = DefaultHandleTimeSeconds
+	}
+	if c.Location == nil {
+		c.Location = time.UTC
+	}
+	if c.Date.IsZero() {
+		c.Date = time.Now()
+	}
+	return c
+}
+
+// mostCommon returns the priority seen most often, preferring the more
+// urgent one on a tie.
+func mostCommon(counts map[models.Priority]int) models.Priority {
+	best, bestCount := models.DefaultPriority, 0
+	for p, n := range counts {
+		if n > bestCount || (n == bestCount && p < best) {
+			best, bestCount = p, n
+		}
+	}
+	return best
+}
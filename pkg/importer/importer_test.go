@@ -0,0 +1,90 @@
+package importer_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/karthikrao-23/agentscheduler/pkg/importer"
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var day = time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+
+type fakeSource struct {
+	interactions []importer.Interaction
+	since        time.Time
+}
+
+func (s *fakeSource) Interactions(_ context.Context, since time.Time) ([]importer.Interaction, error) {
+	s.since = since
+	return s.interactions, nil
+}
+
+func TestFromInteractions(t *testing.T) {
+	var interactions []importer.Interaction
+	// Service Desk: 5 tickets at 09:xx on each of two days, mostly high priority
+	for d := range 2 {
+		for i := range 5 {
+			priority := models.PriorityHigh
+			if i == 0 {
+				priority = models.PriorityLow
+			}
+			interactions = append(interactions, importer.Interaction{
+				Customer: "Service Desk",
+				Created:  day.AddDate(0, 0, -d-1).Add(9*time.Hour + time.Duration(i)*time.Minute),
+				Priority: priority,
+			})
+		}
+	}
+	interactions = append(interactions,
+		importer.Interaction{Customer: "Billing", Created: day.Add(-10 * time.Hour), Priority: models.PriorityCritical},
+		importer.Interaction{Customer: "", Created: day.Add(-10 * time.Hour)},
+	)
+
+	data, err := importer.FromInteractions(interactions, importer.Config{Lookback: 48 * time.Hour, HandleTimeSeconds: 300, Date: day})
+	require.NoError(t, err)
+	require.Len(t, data, 2)
+
+	billing := data[0]
+	assert.Equal(t, "Billing", billing.CustomerName)
+	assert.Equal(t, day.Add(14*time.Hour), billing.StartTime)
+	assert.Equal(t, day.Add(15*time.Hour), billing.EndTime)
+	assert.Equal(t, 1, billing.NumberOfCalls, "averages round up")
+	assert.Equal(t, models.PriorityCritical, billing.Priority)
+
+	desk := data[1]
+	assert.Equal(t, "Service Desk", desk.CustomerName)
+	assert.Equal(t, 9, desk.StartTime.Hour())
+	assert.Equal(t, 5, desk.NumberOfCalls, "10 tickets over 2 days")
+	assert.Equal(t, 300, desk.AverageCallDurationSeconds)
+	assert.Equal(t, models.PriorityHigh, desk.Priority, "the most common priority wins")
+	assert.Equal(t, "UTC", desk.Location.Name())
+}
+
+func TestFromInteractionsLocation(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	data, err := importer.FromInteractions([]importer.Interaction{
+		{Customer: "Chat", Created: day.Add(14 * time.Hour), Priority: models.PriorityNormal},
+	}, importer.Config{Location: ny, Date: day.Add(12 * time.Hour)})
+	require.NoError(t, err)
+	require.Len(t, data, 1)
+	assert.Equal(t, 9, data[0].StartTime.Hour(), "hours are counted in the configured location")
+	assert.Equal(t, "America/New_York", data[0].Location.Name())
+	assert.Equal(t, importer.DefaultHandleTimeSeconds, data[0].AverageCallDurationSeconds)
+}
+
+func TestImport(t *testing.T) {
+	src := &fakeSource{interactions: []importer.Interaction{
+		{Customer: "Chat", Created: day.Add(-time.Hour), Priority: models.PriorityNormal},
+	}}
+	data, err := importer.Import(context.Background(), src, importer.Config{Date: day})
+	require.NoError(t, err)
+	assert.Len(t, data, 1)
+	assert.Equal(t, day.Add(-importer.DefaultLookback), src.since)
+}
@@ -0,0 +1,166 @@
+package importer
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+)
+
+// DefaultSalesforceAPIVersion is the REST API version queried unless
+// Salesforce.APIVersion is set.
+const DefaultSalesforceAPIVersion = "v61.0"
+
+// salesforceTimeLayout is how the REST API formats datetime fields.
+const salesforceTimeLayout = "2006-01-02T15:04:05.000-0700"
+
+// Salesforce reads cases, or another object with a CreatedDate, from
+// Salesforce Service Cloud with a SOQL query, scheduling each under the
+// value of a field such as the owning queue.
+type Salesforce struct {
+	// InstanceURL is the org's My Domain, e.g. https://acme.my.salesforce.com
+	InstanceURL string
+	// AccessToken authenticates directly. Without one, ClientID and
+	// ClientSecret obtain a token with the OAuth 2.0 client credentials
+	// flow of a connected app.
+	AccessToken  string
+	ClientID     string
+	ClientSecret string
+	// Object is the object to read; defaults to "Case"
+	Object string
+	// CustomerField is the field, possibly a relationship path, that names
+	// the customer; defaults to "Owner.Name", the owning queue or user
+	CustomerField string
+	// Where is an extra SOQL condition, e.g. "Origin = 'Chat'"
+	Where string
+	// APIVersion defaults to DefaultSalesforceAPIVersion
+	APIVersion string
+	// Client sends the requests; defaults to http.DefaultClient
+	Client *http.Client
+}
+
+// Interactions implements Source. Salesforce priorities map by name, so the
+// default High, Medium, and Low become high, normal, and low; unknown or
+// empty priorities are normal. Records without a customer are skipped.
+func (s *Salesforce) Interactions(ctx context.Context, since time.Time) ([]Interaction, error) {
+	token := s.AccessToken
+	if token == "" {
+		var err error
+		if token, err = s.clientCredentialsToken(ctx); err != nil {
+			return nil, err
+		}
+	}
+	authorize := func(req *http.Request) {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	object := cmp.Or(s.Object, "Case")
+	field := cmp.Or(s.CustomerField, "Owner.Name")
+	soql := fmt.Sprintf("SELECT CreatedDate, Priority, %s FROM %s WHERE CreatedDate >= %s",
+		field, object, since.UTC().Format(time.RFC3339))
+	if s.Where != "" {
+		soql += " AND (" + s.Where + ")"
+	}
+	next := fmt.Sprintf("/services/data/%s/query?q=%s", cmp.Or(s.APIVersion, DefaultSalesforceAPIVersion), url.QueryEscape(soql))
+
+	var interactions []Interaction
+	for {
+		var page struct {
+			Records        []map[string]any `json:"records"`
+			Done           bool             `json:"done"`
+			NextRecordsURL string           `json:"nextRecordsUrl"`
+		}
+		if err := getJSON(ctx, s.Client, strings.TrimSuffix(s.InstanceURL, "/")+next, authorize, &page); err != nil {
+			return nil, fmt.Errorf("salesforce: %w", err)
+		}
+		for _, r := range page.Records {
+			customer, _ := lookupField(r, field).(string)
+			if customer == "" {
+				continue
+			}
+			createdDate, _ := r["CreatedDate"].(string)
+			created, err := time.Parse(salesforceTimeLayout, createdDate)
+			if err != nil {
+				return nil, fmt.Errorf("salesforce: invalid CreatedDate %q: %w", createdDate, err)
+			}
+			priority, _ := r["Priority"].(string)
+			interactions = append(interactions, Interaction{
+				Customer: customer,
+				Created:  created.UTC(),
+				Priority: salesforcePriority(priority),
+			})
+		}
+		if page.Done || page.NextRecordsURL == "" {
+			return interactions, nil
+		}
+		next = page.NextRecordsURL
+	}
+}
+
+// clientCredentialsToken obtains an access token for the connected app.
+func (s *Salesforce) clientCredentialsToken(ctx context.Context) (string, error) {
+	form := url.Values{
+		"grant_type":    {"client_credentials"},
+		"client_id":     {s.ClientID},
+		"client_secret": {s.ClientSecret},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		strings.TrimSuffix(s.InstanceURL, "/")+"/services/oauth2/token", strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("salesforce: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("salesforce: requesting token: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return "", fmt.Errorf("salesforce: requesting token: unexpected status %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	var body struct {
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("salesforce: decoding token: %w", err)
+	}
+	return body.AccessToken, nil
+}
+
+// lookupField follows a dotted relationship path such as "Owner.Name"
+// through a query record.
+func lookupField(record map[string]any, path string) any {
+	var v any = record
+	for name := range strings.SplitSeq(path, ".") {
+		m, ok := v.(map[string]any)
+		if !ok {
+			return nil
+		}
+		v = m[name]
+	}
+	return v
+}
+
+func salesforcePriority(p string) models.Priority {
+	if strings.EqualFold(p, "medium") {
+		return models.PriorityNormal
+	}
+	priority, err := models.ParsePriority(p)
+	if err != nil {
+		return models.DefaultPriority
+	}
+	return priority
+}
@@ -0,0 +1,65 @@
+package importer_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/karthikrao-23/agentscheduler/pkg/importer"
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSalesforceInteractions(t *testing.T) {
+	var queries []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/services/oauth2/token":
+			require.NoError(t, r.ParseForm())
+			assert.Equal(t, "client_credentials", r.PostForm.Get("grant_type"))
+			assert.Equal(t, "app", r.PostForm.Get("client_id"))
+			fmt.Fprint(w, `{"access_token":"tok","token_type":"Bearer"}`)
+		case "/services/data/v61.0/query":
+			assert.Equal(t, "Bearer tok", r.Header.Get("Authorization"))
+			queries = append(queries, r.URL.Query().Get("q"))
+			fmt.Fprint(w, `{"done":false,"nextRecordsUrl":"/services/data/v61.0/query/01g-2000","records":[
+				{"CreatedDate":"2026-03-01T09:15:00.000+0000","Priority":"High","Owner":{"Name":"Tier 1"}},
+				{"CreatedDate":"2026-03-01T10:00:00.000-0500","Priority":"Medium","Owner":{"Name":"Tier 2"}}
+			]}`)
+		case "/services/data/v61.0/query/01g-2000":
+			fmt.Fprint(w, `{"done":true,"records":[
+				{"CreatedDate":"2026-03-02T09:45:00.000+0000","Priority":null,"Owner":{"Name":"Tier 1"}},
+				{"CreatedDate":"2026-03-02T09:45:00.000+0000","Priority":"Low","Owner":null}
+			]}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	sf := &importer.Salesforce{InstanceURL: srv.URL, ClientID: "app", ClientSecret: "secret", Where: "Origin = 'Chat'"}
+	got, err := sf.Interactions(context.Background(), time.Date(2026, 2, 23, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+
+	assert.Equal(t, []importer.Interaction{
+		{Customer: "Tier 1", Created: time.Date(2026, 3, 1, 9, 15, 0, 0, time.UTC), Priority: models.PriorityHigh},
+		{Customer: "Tier 2", Created: time.Date(2026, 3, 1, 15, 0, 0, 0, time.UTC), Priority: models.PriorityNormal},
+		{Customer: "Tier 1", Created: time.Date(2026, 3, 2, 9, 45, 0, 0, time.UTC), Priority: models.DefaultPriority},
+	}, got, "records without an owner are skipped")
+	assert.Equal(t, []string{"SELECT CreatedDate, Priority, Owner.Name FROM Case WHERE CreatedDate >= 2026-02-23T00:00:00Z AND (Origin = 'Chat')"}, queries)
+}
+
+func TestSalesforceTokenError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error":"invalid_client"}`, http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	_, err := (&importer.Salesforce{InstanceURL: srv.URL}).Interactions(context.Background(), time.Now())
+	assert.ErrorContains(t, err, "salesforce: requesting token: unexpected status 400")
+}
@@ -0,0 +1,124 @@
+package importer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+)
+
+// serviceNowPageSize is the number of records fetched per Table API request.
+const serviceNowPageSize = 1000
+
+// ServiceNow reads records such as incidents through the ServiceNow Table
+// API, scheduling each under its assignment group.
+type ServiceNow struct {
+	// InstanceURL is the instance, e.g. https://acme.service-now.com
+	InstanceURL string
+	Username    string
+	Password    string
+	// Table is the table to read; defaults to "incident"
+	Table string
+	// Query is an extra encoded query to filter by, e.g. "contact_type=chat"
+	Query string
+	// Client sends the requests; defaults to http.DefaultClient
+	Client *http.Client
+}
+
+// serviceNowField is a field as returned with sysparm_display_value=all.
+type serviceNowField struct {
+	Value        string `json:"value"`
+	DisplayValue string `json:"display_value"`
+}
+
+type serviceNowRecord struct {
+	Created         serviceNowField `json:"sys_created_on"`
+	Priority        serviceNowField `json:"priority"`
+	AssignmentGroup serviceNowField `json:"assignment_group"`
+}
+
+// Interactions implements Source. ServiceNow priorities 1 (critical) to 5
+// (planning) map to the same scheduling priorities.
+func (s *ServiceNow) Interactions(ctx context.Context, since time.Time) ([]Interaction, error) {
+	table := s.Table
+	if table == "" {
+		table = "incident"
+	}
+	query := fmt.Sprintf("sys_created_on>=javascript:gs.dateGenerate('%s','%s')",
+		since.UTC().Format(time.DateOnly), since.UTC().Format(time.TimeOnly))
+	if s.Query != "" {
+		query += "^" + s.Query
+	}
+
+	var interactions []Interaction
+	for offset := 0; ; offset += serviceNowPageSize {
+		params := url.Values{
+			"sysparm_query":         {query},
+			"sysparm_fields":        {"sys_created_on,priority,assignment_group"},
+			"sysparm_display_value": {"all"},
+			"sysparm_limit":         {strconv.Itoa(serviceNowPageSize)},
+			"sysparm_offset":        {strconv.Itoa(offset)},
+		}
+		endpoint := fmt.Sprintf("%s/api/now/table/%s?%s", strings.TrimSuffix(s.InstanceURL, "/"), url.PathEscape(table), params.Encode())
+
+		var page struct {
+			Result []serviceNowRecord `json:"result"`
+		}
+		if err := getJSON(ctx, s.Client, endpoint, func(req *http.Request) {
+			req.SetBasicAuth(s.Username, s.Password)
+		}, &page); err != nil {
+			return nil, fmt.Errorf("servicenow: %w", err)
+		}
+
+		for _, r := range page.Result {
+			// Values are in UTC; display values are in the user's time zone
+			created, err := time.Parse(time.DateTime, r.Created.Value)
+			if err != nil {
+				return nil, fmt.Errorf("servicenow: invalid sys_created_on %q: %w", r.Created.Value, err)
+			}
+			priority, _ := strconv.Atoi(r.Priority.Value)
+			interactions = append(interactions, Interaction{
+				Customer: r.AssignmentGroup.DisplayValue,
+				Created:  created,
+				Priority: models.Priority(priority),
+			})
+		}
+		if len(page.Result) < serviceNowPageSize {
+			return interactions, nil
+		}
+	}
+}
+
+// getJSON sends an authorized GET request and decodes the JSON response.
+func getJSON(ctx context.Context, client *http.Client, endpoint string, authorize func(*http.Request), out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	authorize(req)
+
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("unexpected status %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decoding response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package importer_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/karthikrao-23/agentscheduler/pkg/importer"
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServiceNowInteractions(t *testing.T) {
+	var queries []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, _ := r.BasicAuth()
+		assert.Equal(t, "admin", user)
+		assert.Equal(t, "secret", pass)
+		assert.Equal(t, "/api/now/table/incident", r.URL.Path)
+		assert.Equal(t, "all", r.URL.Query().Get("sysparm_display_value"))
+		queries = append(queries, r.URL.Query().Get("sysparm_query"))
+
+		fmt.Fprint(w, `{"result":[
+			{"sys_created_on":{"value":"2026-03-01 09:15:00","display_value":"03/01/2026 01:15:00"},
+			 "priority":{"value":"1","display_value":"1 - Critical"},
+			 "assignment_group":{"value":"abc123","display_value":"Service Desk"}}
+		]}`)
+	}))
+	defer srv.Close()
+
+	sn := &importer.ServiceNow{InstanceURL: srv.URL, Username: "admin", Password: "secret", Query: "contact_type=chat"}
+	got, err := sn.Interactions(context.Background(), time.Date(2026, 2, 23, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+
+	assert.Equal(t, []importer.Interaction{
+		{Customer: "Service Desk", Created: time.Date(2026, 3, 1, 9, 15, 0, 0, time.UTC), Priority: models.PriorityCritical},
+	}, got)
+	assert.Equal(t, []string{"sys_created_on>=javascript:gs.dateGenerate('2026-02-23','00:00:00')^contact_type=chat"}, queries)
+}
+
+func TestServiceNowError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "bad credentials", http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	_, err := (&importer.ServiceNow{InstanceURL: srv.URL}).Interactions(context.Background(), time.Now())
+	require.Error(t, err)
+	assert.True(t, strings.HasPrefix(err.Error(), "servicenow: unexpected status 401"), err.Error())
+}
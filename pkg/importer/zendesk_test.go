@@ -0,0 +1,53 @@
+package importer_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/karthikrao-23/agentscheduler/pkg/importer"
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestZendeskInteractions(t *testing.T) {
+	since := time.Date(2026, 2, 23, 0, 0, 0, 0, time.UTC)
+	var srvURL string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, _ := r.BasicAuth()
+		assert.Equal(t, "ops@example.com/token", user)
+		assert.Equal(t, "tok", pass)
+
+		switch {
+		case r.URL.Path == "/api/v2/groups.json":
+			fmt.Fprint(w, `{"groups":[{"id":1,"name":"Support"},{"id":2,"name":"Billing"}],"next_page":null}`)
+		case r.URL.Path == "/api/v2/incremental/tickets/cursor.json" && r.URL.Query().Get("cursor") == "":
+			assert.Equal(t, fmt.Sprint(since.Unix()), r.URL.Query().Get("start_time"))
+			fmt.Fprintf(w, `{"tickets":[
+				{"created_at":"2026-03-01T09:00:00Z","priority":"urgent","group_id":1},
+				{"created_at":"2026-02-01T09:00:00Z","priority":"high","group_id":1},
+				{"created_at":"2026-03-01T10:00:00Z","priority":null,"group_id":null}
+			],"after_url":"%s/api/v2/incremental/tickets/cursor.json?cursor=next","end_of_stream":false}`, srvURL)
+		case r.URL.Query().Get("cursor") == "next":
+			fmt.Fprint(w, `{"tickets":[{"created_at":"2026-03-01T11:00:00Z","priority":"low","group_id":2}],"after_url":null,"end_of_stream":true}`)
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+		}
+	}))
+	defer srv.Close()
+	srvURL = srv.URL
+
+	zd := &importer.Zendesk{URL: srv.URL + "/", Email: "ops@example.com", APIToken: "tok"}
+	got, err := zd.Interactions(context.Background(), since)
+	require.NoError(t, err)
+
+	assert.Equal(t, []importer.Interaction{
+		{Customer: "Support", Created: time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC), Priority: models.PriorityCritical},
+		{Customer: "Billing", Created: time.Date(2026, 3, 1, 11, 0, 0, 0, time.UTC), Priority: models.PriorityLow},
+	}, got)
+}
@@ -0,0 +1,267 @@
+// Package kafka runs the scheduler continuously from call-data records
+// streamed over Kafka, publishing a fresh schedule as the input changes.
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+	"github.com/karthikrao-23/agentscheduler/pkg/scheduler"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// Reader is the part of *kafka.Reader the consumer uses.
+type Reader interface {
+	FetchMessage(ctx context.Context) (kafka.Message, error)
+	CommitMessages(ctx context.Context, msgs ...kafka.Message) error
+}
+
+// Writer is the part of *kafka.Writer used to publish schedules.
+type Writer interface {
+	WriteMessages(ctx context.Context, msgs ...kafka.Message) error
+}
+
+// Option configures a Consumer.
+type Option func(*config)
+
+type config struct {
+	decode          Decoder
+	window          time.Duration
+	interval        time.Duration
+	scheduleOptions []scheduler.Option
+	logger          *slog.Logger
+	onPublish       func(context.Context, *models.Schedule)
+}
+
+// WithDecoder sets how message values are decoded. Defaults to DecodeJSON.
+func WithDecoder(decode Decoder) Option {
+	return func(c *config) {
+		c.decode = decode
+	}
+}
+
+// WithWindow sets how long a record counts towards the schedule after it was
+// produced. Defaults to 0, which keeps records until they are replaced.
+func WithWindow(window time.Duration) Option {
+	return func(c *config) {
+		c.window = window
+	}
+}
+
+// WithInterval sets how often the schedule is regenerated when the input has
+// changed. Defaults to one minute.
+func WithInterval(interval time.Duration) Option {
+	return func(c *config) {
+		c.interval = interval
+	}
+}
+
+// WithScheduleOptions sets the options every schedule is generated with.
+func WithScheduleOptions(opts ...scheduler.Option) Option {
+	return func(c *config) {
+		c.scheduleOptions = opts
+	}
+}
+
+// WithOnPublish calls fn with every schedule once it has been written to the
+// output topic, e.g. to forward it to other systems.
+func WithOnPublish(fn func(context.Context, *models.Schedule)) Option {
+	return func(c *config) {
+		c.onPublish = fn
+	}
+}
+
+// WithLogger sets the logger for skipped records and failed runs. Defaults
+// to slog.Default().
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *config) {
+		c.logger = logger
+	}
+}
+
+// Consumer reads call-data records from a topic into a Window and, every
+// interval in which the window changed, regenerates the schedule and writes
+// it as JSON to the output topic, keyed by run ID. Offsets are committed
+// only once the schedule covering their records has been written, so a
+// restart replays whatever was not yet published.
+type Consumer struct {
+	reader Reader
+	writer Writer
+	window *Window
+	cfg    config
+
+	mu sync.Mutex
+	// pending is the last fetched message per partition not yet committed
+	pending map[partition]kafka.Message
+}
+
+type partition struct {
+	topic string
+	id    int
+}
+
+// NewConsumer returns a consumer reading from r and publishing to w.
+func NewConsumer(r Reader, w Writer, opts ...Option) *Consumer {
+	cfg := config{decode: DecodeJSON, interval: time.Minute, logger: slog.Default()}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return &Consumer{reader: r, writer: w, window: NewWindow(cfg.window), cfg: cfg, pending: make(map[partition]kafka.Message)}
+}
+
+// Run consumes until ctx is cancelled or reading or committing fails.
+// Records that cannot be decoded or fail validation are logged, committed,
+// and skipped so they cannot block the partition.
+func (c *Consumer) Run(ctx context.Context) error {
+	consumeCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	errs := make(chan error, 1)
+	go func() {
+		errs <- c.consume(consumeCtx)
+	}()
+
+	ticker := time.NewTicker(c.cfg.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case err := <-errs:
+			if err != nil {
+				return err
+			}
+			return ctx.Err()
+		case now := <-ticker.C:
+			if err := c.publish(ctx, now); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// consume reads messages into the window until ctx is done or reading fails,
+// leaving their offsets for publish to commit.
+func (c *Consumer) consume(ctx context.Context) error {
+	for {
+		msg, err := c.reader.FetchMessage(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("reading from kafka: %w", err)
+		}
+		if cd, err := c.decode(msg.Value); err != nil {
+			c.cfg.logger.Warn("skipping call-data record",
+				"topic", msg.Topic, "partition", msg.Partition, "offset", msg.Offset, "error", err)
+		} else {
+			at := msg.Time
+			if at.IsZero() {
+				at = time.Now()
+			}
+			c.window.Add(string(msg.Key), cd, at)
+		}
+		c.mu.Lock()
+		c.pending[partition{msg.Topic, msg.Partition}] = msg
+		c.mu.Unlock()
+	}
+}
+
+// decode decodes and validates a message value.
+func (c *Consumer) decode(value []byte) (models.CallData, error) {
+	cd, err := c.cfg.decode(value)
+	if err != nil {
+		return models.CallData{}, err
+	}
+	if err := cd.Validate(); err != nil {
+		return models.CallData{}, err
+	}
+	return cd, nil
+}
+
+// publish regenerates and writes the schedule if the window changed, an
+// empty one once every record has expired, then commits the offsets of the
+// records it covered. It returns an error only if committing fails.
+func (c *Consumer) publish(ctx context.Context, now time.Time) error {
+	// Take the offsets before the snapshot, so that every record they cover
+	// is in it
+	c.mu.Lock()
+	pending := c.pending
+	c.pending = make(map[partition]kafka.Message)
+	c.mu.Unlock()
+
+	if data, changed := c.window.Snapshot(now); changed && !c.write(ctx, data) {
+		// Retry on the next tick, committing nothing until then
+		c.window.markChanged()
+		c.mu.Lock()
+		for p, msg := range pending {
+			if _, ok := c.pending[p]; !ok {
+				c.pending[p] = msg
+			}
+		}
+		c.mu.Unlock()
+		return nil
+	}
+
+	if len(pending) == 0 {
+		return nil
+	}
+	msgs := make([]kafka.Message, 0, len(pending))
+	for _, msg := range pending {
+		msgs = append(msgs, msg)
+	}
+	if err := c.reader.CommitMessages(ctx, msgs...); err != nil {
+		if ctx.Err() != nil {
+			return nil
+		}
+		return fmt.Errorf("committing offset: %w", err)
+	}
+	return nil
+}
+
+// write generates the schedule for data and writes it to the output topic.
+// It reports false if writing failed and should be retried. A schedule that
+// cannot be generated is logged and counts as written, since retrying the
+// same input would fail again.
+func (c *Consumer) write(ctx context.Context, data []models.CallData) bool {
+	var schedule *models.Schedule
+	var err error
+	if len(data) == 0 {
+		schedule, err = scheduler.EmptySchedule(ctx, c.cfg.scheduleOptions...)
+	} else {
+		schedule, err = scheduler.GenerateScheduleContext(ctx, data, c.cfg.scheduleOptions...)
+	}
+	if err != nil {
+		c.cfg.logger.Error("generating schedule", "customers", len(data), "error", err)
+		return true
+	}
+	value, err := json.Marshal(schedule)
+	if err != nil {
+		c.cfg.logger.Error("encoding schedule", "error", err)
+		return true
+	}
+	msg := kafka.Message{Key: []byte(schedule.Metadata.RunID), Value: value}
+	if err := c.writer.WriteMessages(ctx, msg); err != nil {
+		c.cfg.logger.Error("publishing schedule", "run_id", schedule.Metadata.RunID, "error", err)
+		return false
+	}
+	if c.cfg.onPublish != nil {
+		c.cfg.onPublish(ctx, schedule)
+	}
+	return true
+}
+
+// ParseBrokers splits a comma-separated broker list.
+func ParseBrokers(s string) []string {
+	var brokers []string
+	for _, b := range strings.Split(s, ",") {
+		if b = strings.TrimSpace(b); b != "" {
+			brokers = append(brokers, b)
+		}
+	}
+	return brokers
+}
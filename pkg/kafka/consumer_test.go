@@ -0,0 +1,184 @@
+package kafka_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/karthikrao-23/agentscheduler/pkg/kafka"
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+	"github.com/karthikrao-23/agentscheduler/pkg/scheduler"
+
+	kafkago "github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeReader serves queued messages, then blocks until ctx is done.
+type fakeReader struct {
+	msgs chan kafkago.Message
+	mu   sync.Mutex
+	// committed is the highest committed offset, or -1
+	committed int64
+}
+
+func newFakeReader(msgs ...kafkago.Message) *fakeReader {
+	r := &fakeReader{msgs: make(chan kafkago.Message, len(msgs)), committed: -1}
+	for _, msg := range msgs {
+		r.msgs <- msg
+	}
+	return r
+}
+
+func (r *fakeReader) offset() int64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.committed
+}
+
+func (r *fakeReader) FetchMessage(ctx context.Context) (kafkago.Message, error) {
+	select {
+	case msg := <-r.msgs:
+		return msg, nil
+	case <-ctx.Done():
+		return kafkago.Message{}, ctx.Err()
+	}
+}
+
+func (r *fakeReader) CommitMessages(_ context.Context, msgs ...kafkago.Message) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, msg := range msgs {
+		r.committed = max(r.committed, msg.Offset)
+	}
+	return nil
+}
+
+type fakeWriter struct {
+	published chan kafkago.Message
+	// fail makes writes fail while set
+	fail atomic.Bool
+}
+
+func (w *fakeWriter) WriteMessages(_ context.Context, msgs ...kafkago.Message) error {
+	if w.fail.Load() {
+		return errors.New("broker unreachable")
+	}
+	for _, msg := range msgs {
+		w.published <- msg
+	}
+	return nil
+}
+
+func TestConsumer(t *testing.T) {
+	reader := newFakeReader(
+		kafkago.Message{Key: []byte("acme"), Value: []byte(record), Time: time.Now(), Offset: 0},
+		kafkago.Message{Key: []byte("bad"), Value: []byte(`not json`), Time: time.Now(), Offset: 1},
+		kafkago.Message{Key: []byte("invalid"), Value: []byte(`{"customer_name":"NoLocation"}`), Time: time.Now(), Offset: 2},
+	)
+	writer := &fakeWriter{published: make(chan kafkago.Message, 10)}
+
+	c := kafka.NewConsumer(reader, writer,
+		kafka.WithInterval(10*time.Millisecond),
+		kafka.WithScheduleOptions(scheduler.WithCapacity(10)),
+		kafka.WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+	)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- c.Run(ctx) }()
+
+	var msg kafkago.Message
+	select {
+	case msg = <-writer.published:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no schedule published")
+	}
+	var schedule models.Schedule
+	require.NoError(t, json.Unmarshal(msg.Value, &schedule))
+	assert.Equal(t, schedule.Metadata.RunID, string(msg.Key))
+	assert.Equal(t, 8*13, schedule.TotalDemand(), "150 calls of 5 minutes an hour for 8 hours")
+	assert.Equal(t, "10", schedule.Metadata.Options["capacity"])
+
+	// Unchanged input is not republished
+	select {
+	case <-writer.published:
+		t.Fatal("schedule republished without new input")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled)
+	assert.Equal(t, int64(2), reader.offset(), "skipped records are committed too")
+}
+
+func TestConsumer_CommitsOnlyPublished(t *testing.T) {
+	reader := newFakeReader(kafkago.Message{Key: []byte("acme"), Value: []byte(record), Time: time.Now(), Offset: 7})
+	writer := &fakeWriter{published: make(chan kafkago.Message, 10)}
+	writer.fail.Store(true)
+
+	c := kafka.NewConsumer(reader, writer,
+		kafka.WithInterval(10*time.Millisecond),
+		kafka.WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+	)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = c.Run(ctx) }()
+
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, int64(-1), reader.offset(), "nothing is committed while publishing fails")
+
+	writer.fail.Store(false)
+	select {
+	case <-writer.published:
+	case <-time.After(5 * time.Second):
+		t.Fatal("failed publish not retried")
+	}
+	assert.Eventually(t, func() bool { return reader.offset() == 7 }, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestConsumer_WindowDrained(t *testing.T) {
+	reader := newFakeReader(kafkago.Message{Key: []byte("acme"), Value: []byte(record), Time: time.Now()})
+	writer := &fakeWriter{published: make(chan kafkago.Message, 10)}
+
+	c := kafka.NewConsumer(reader, writer,
+		kafka.WithWindow(100*time.Millisecond),
+		kafka.WithInterval(10*time.Millisecond),
+		kafka.WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+	)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = c.Run(ctx) }()
+
+	var demand []int
+	for range 2 {
+		select {
+		case msg := <-writer.published:
+			var schedule models.Schedule
+			require.NoError(t, json.Unmarshal(msg.Value, &schedule))
+			demand = append(demand, schedule.TotalDemand())
+		case <-time.After(5 * time.Second):
+			t.Fatal("no schedule published")
+		}
+	}
+	assert.Equal(t, []int{8 * 13, 0}, demand, "an empty schedule replaces the expired demand")
+}
+
+type failingReader struct{}
+
+func (failingReader) FetchMessage(context.Context) (kafkago.Message, error) {
+	return kafkago.Message{}, errors.New("broker unreachable")
+}
+
+func (failingReader) CommitMessages(context.Context, ...kafkago.Message) error { return nil }
+
+func TestConsumer_ReadError(t *testing.T) {
+	c := kafka.NewConsumer(failingReader{}, &fakeWriter{})
+	err := c.Run(context.Background())
+	assert.ErrorContains(t, err, "broker unreachable")
+}
@@ -0,0 +1,74 @@
+package kafka
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+
+	"github.com/linkedin/goavro/v2"
+)
+
+// Decoder turns a message value into call data.
+type Decoder func(value []byte) (models.CallData, error)
+
+// CallDataAvroSchema is the default Avro schema for call-data records. Its
+// fields match CallData's JSON form; times are RFC3339 strings and location
+// is an IANA name.
+const CallDataAvroSchema = `{
+	"type": "record",
+	"name": "CallData",
+	"namespace": "agentscheduler",
+	"fields": [
+		{"name": "customer_name", "type": "string"},
+		{"name": "average_call_duration_seconds", "type": "int"},
+		{"name": "start_time", "type": "string"},
+		{"name": "end_time", "type": "string"},
+		{"name": "location", "type": "string"},
+		{"name": "number_of_calls", "type": "int"},
+		{"name": "priority", "type": "int"},
+		{"name": "sla_target_percent", "type": "double", "default": 0},
+		{"name": "sla_threshold_seconds", "type": "int", "default": 0},
+		{"name": "skill", "type": "string", "default": ""},
+		{"name": "concurrency", "type": "int", "default": 0},
+		{"name": "hourly_cost", "type": "double", "default": 0},
+		{"name": "min_agents", "type": "int", "default": 0},
+		{"name": "floor_agents", "type": "int", "default": 0},
+		{"name": "ceiling_agents", "type": "int", "default": 0}
+	]
+}`
+
+// DecodeJSON decodes a call-data record in CallData's JSON form.
+func DecodeJSON(value []byte) (models.CallData, error) {
+	var cd models.CallData
+	if err := json.Unmarshal(value, &cd); err != nil {
+		return models.CallData{}, fmt.Errorf("decoding JSON record: %w", err)
+	}
+	return cd, nil
+}
+
+// NewAvroDecoder returns a Decoder for binary Avro records written with
+// schema, which must use the field names of CallDataAvroSchema. Values in the
+// Confluent wire format (a zero magic byte and a 4-byte schema ID ahead of
+// the record) are accepted too.
+func NewAvroDecoder(schema string) (Decoder, error) {
+	codec, err := goavro.NewCodec(schema)
+	if err != nil {
+		return nil, fmt.Errorf("parsing Avro schema: %w", err)
+	}
+	return func(value []byte) (models.CallData, error) {
+		if len(value) > 5 && value[0] == 0 {
+			value = value[5:]
+		}
+		native, _, err := codec.NativeFromBinary(value)
+		if err != nil {
+			return models.CallData{}, fmt.Errorf("decoding Avro record: %w", err)
+		}
+		// The textual form of a record is its JSON form
+		textual, err := codec.TextualFromNative(nil, native)
+		if err != nil {
+			return models.CallData{}, fmt.Errorf("decoding Avro record: %w", err)
+		}
+		return DecodeJSON(textual)
+	}, nil
+}
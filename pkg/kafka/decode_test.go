@@ -0,0 +1,60 @@
+package kafka_test
+
+import (
+	"testing"
+
+	"github.com/karthikrao-23/agentscheduler/pkg/kafka"
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+
+	"github.com/linkedin/goavro/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const record = `{"customer_name":"Acme","average_call_duration_seconds":300,` +
+	`"start_time":"2026-03-02T09:00:00-05:00","end_time":"2026-03-02T17:00:00-05:00",` +
+	`"location":"America/New_York","number_of_calls":1200,"priority":2,` +
+	`"sla_target_percent":80,"sla_threshold_seconds":20,"skill":"","concurrency":0,"hourly_cost":0}`
+
+func TestDecodeJSON(t *testing.T) {
+	cd, err := kafka.DecodeJSON([]byte(record))
+	require.NoError(t, err)
+	assert.Equal(t, "Acme", cd.CustomerName)
+	assert.Equal(t, "America/New_York", cd.Location.Name())
+	assert.Equal(t, 9, cd.StartTime.Hour())
+	assert.Equal(t, models.PriorityHigh, cd.Priority)
+	assert.Equal(t, 80.0, cd.SLATargetPercent)
+
+	_, err = kafka.DecodeJSON([]byte(`{"customer_name":`))
+	assert.Error(t, err)
+}
+
+func TestAvroDecoder(t *testing.T) {
+	codec, err := goavro.NewCodec(kafka.CallDataAvroSchema)
+	require.NoError(t, err)
+	native, _, err := codec.NativeFromTextual([]byte(record))
+	require.NoError(t, err)
+	binary, err := codec.BinaryFromNative(nil, native)
+	require.NoError(t, err)
+
+	decode, err := kafka.NewAvroDecoder(kafka.CallDataAvroSchema)
+	require.NoError(t, err)
+
+	tests := map[string][]byte{
+		"Plain":     binary,
+		"Confluent": append([]byte{0, 0, 0, 0, 42}, binary...),
+	}
+	for name, value := range tests {
+		t.Run(name, func(t *testing.T) {
+			cd, err := decode(value)
+			require.NoError(t, err)
+			assert.Equal(t, "Acme", cd.CustomerName)
+			assert.Equal(t, 1200, cd.NumberOfCalls)
+			assert.Equal(t, "America/New_York", cd.Location.Name())
+			assert.NoError(t, cd.Validate())
+		})
+	}
+
+	_, err = kafka.NewAvroDecoder(`{"type":"nope"}`)
+	assert.Error(t, err)
+}
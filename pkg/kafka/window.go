@@ -0,0 +1,85 @@
+package kafka
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+)
+
+// Window aggregates streamed call data for scheduling. It keeps the latest
+// record per key, so an updated forecast for a customer replaces the previous
+// one, and drops records that have not been refreshed within the window.
+// It is safe for concurrent use.
+type Window struct {
+	mu      sync.Mutex
+	size    time.Duration
+	records map[string]windowRecord
+	changed bool
+}
+
+type windowRecord struct {
+	data models.CallData
+	at   time.Time
+}
+
+// NewWindow returns a window that keeps records for size after they were
+// produced. A size of 0 keeps records until they are replaced.
+func NewWindow(size time.Duration) *Window {
+	return &Window{size: size, records: make(map[string]windowRecord)}
+}
+
+// Add records cd under key, replacing any earlier record with that key. An
+// empty key falls back to the customer name and location.
+func (w *Window) Add(key string, cd models.CallData, at time.Time) {
+	if key == "" {
+		key = cd.CustomerName + "/" + cd.Location.Name()
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if prev, ok := w.records[key]; ok && prev.at.After(at) {
+		// Out-of-order delivery; keep the newer record
+		return
+	}
+	w.records[key] = windowRecord{data: cd, at: at}
+	w.changed = true
+}
+
+// Snapshot evicts records older than the window as of now and returns the
+// rest, ordered by key. changed reports whether the contents differ from the
+// previous snapshot.
+func (w *Window) Snapshot(now time.Time) (data []models.CallData, changed bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.size > 0 {
+		for key, r := range w.records {
+			if now.Sub(r.at) > w.size {
+				delete(w.records, key)
+				w.changed = true
+			}
+		}
+	}
+
+	keys := make([]string, 0, len(w.records))
+	for key := range w.records {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	data = make([]models.CallData, len(keys))
+	for i, key := range keys {
+		data[i] = w.records[key].data
+	}
+
+	changed = w.changed
+	w.changed = false
+	return data, changed
+}
+
+// markChanged forces the next Snapshot to report a change, so a failed
+// publish is retried.
+func (w *Window) markChanged() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.changed = true
+}
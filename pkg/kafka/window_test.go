@@ -0,0 +1,48 @@
+package kafka_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/karthikrao-23/agentscheduler/pkg/kafka"
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWindow(t *testing.T) {
+	t0 := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	w := kafka.NewWindow(time.Hour)
+
+	w.Add("acme", models.CallData{CustomerName: "Acme", NumberOfCalls: 10}, t0)
+	w.Add("globex", models.CallData{CustomerName: "Globex", NumberOfCalls: 5}, t0)
+	w.Add("acme", models.CallData{CustomerName: "Acme", NumberOfCalls: 20}, t0.Add(30*time.Minute))
+	// An older record arriving late does not replace the newer one
+	w.Add("acme", models.CallData{CustomerName: "Acme", NumberOfCalls: 1}, t0.Add(time.Minute))
+
+	data, changed := w.Snapshot(t0.Add(45 * time.Minute))
+	assert.True(t, changed)
+	assert.Equal(t, []models.CallData{
+		{CustomerName: "Acme", NumberOfCalls: 20},
+		{CustomerName: "Globex", NumberOfCalls: 5},
+	}, data)
+
+	_, changed = w.Snapshot(t0.Add(50 * time.Minute))
+	assert.False(t, changed, "nothing new since the last snapshot")
+
+	// Globex expires; the refreshed Acme record does not
+	data, changed = w.Snapshot(t0.Add(61 * time.Minute))
+	assert.True(t, changed)
+	assert.Equal(t, []models.CallData{{CustomerName: "Acme", NumberOfCalls: 20}}, data)
+}
+
+func TestWindow_DefaultKey(t *testing.T) {
+	w := kafka.NewWindow(0)
+	ny := models.NewLocation(time.UTC)
+	w.Add("", models.CallData{CustomerName: "Acme", Location: ny, NumberOfCalls: 1}, time.Now())
+	w.Add("", models.CallData{CustomerName: "Acme", Location: ny, NumberOfCalls: 2}, time.Now())
+
+	data, _ := w.Snapshot(time.Now().Add(24 * time.Hour))
+	assert.Len(t, data, 1, "records without a key are keyed by customer and location")
+	assert.Equal(t, 2, data[0].NumberOfCalls)
+}
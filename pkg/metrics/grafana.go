@@ -0,0 +1,93 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// AnnotationConfig describes how to post annotations to Grafana.
+type AnnotationConfig struct {
+	// URL of Grafana (e.g., https://grafana.example.com)
+	URL string
+	// Token is a service account token, sent as a bearer token
+	Token string
+	// DashboardUID and PanelID scope the annotation to one dashboard or
+	// panel; empty posts an organization-wide annotation
+	DashboardUID string
+	PanelID      int64
+	// Tags are added to every annotation, alongside the annotation's own
+	Tags []string
+	// Retries, Backoff, and Timeout behave as in PushConfig
+	Retries int
+	Backoff time.Duration
+	Timeout time.Duration
+	// Client is the HTTP client to use; defaults to http.DefaultClient
+	Client *http.Client
+}
+
+// Annotation is an event marked on Grafana dashboards.
+type Annotation struct {
+	Time time.Time
+	Text string
+	Tags []string
+}
+
+// Annotate posts a through Grafana's annotations HTTP API, retrying server
+// errors with exponential backoff.
+func Annotate(ctx context.Context, cfg AnnotationConfig, a Annotation) error {
+	body, err := json.Marshal(struct {
+		DashboardUID string   `json:"dashboardUID,omitempty"`
+		PanelID      int64    `json:"panelId,omitempty"`
+		Time         int64    `json:"time"`
+		Tags         []string `json:"tags"`
+		Text         string   `json:"text"`
+	}{
+		DashboardUID: cfg.DashboardUID,
+		PanelID:      cfg.PanelID,
+		Time:         a.Time.UnixMilli(),
+		Tags:         append(append([]string{}, cfg.Tags...), a.Tags...),
+		Text:         a.Text,
+	})
+	if err != nil {
+		return fmt.Errorf("error encoding annotation: %w", err)
+	}
+
+	client := cfg.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	endpoint := strings.TrimSuffix(cfg.URL, "/") + "/api/annotations"
+
+	return withRetry(ctx, cfg.Retries, cfg.Backoff, cfg.Timeout, func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+		if err != nil {
+			return &permanentError{err: err}
+		}
+		req.Header.Set("Content-Type", "application/json")
+		if cfg.Token != "" {
+			req.Header.Set("Authorization", "Bearer "+cfg.Token)
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode/100 == 2 {
+			return nil
+		}
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		err = fmt.Errorf("grafana returned %s: %s", resp.Status, bytes.TrimSpace(msg))
+		if resp.StatusCode/100 == 4 && resp.StatusCode != http.StatusTooManyRequests {
+			return &permanentError{err: err}
+		}
+		return err
+	})
+}
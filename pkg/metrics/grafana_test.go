@@ -0,0 +1,64 @@
+package metrics_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/karthikrao-23/agentscheduler/pkg/metrics"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnnotate(t *testing.T) {
+	var calls atomic.Int32
+	var body map[string]any
+	var auth, path string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		auth, path = r.Header.Get("Authorization"), r.URL.Path
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+	}))
+	defer server.Close()
+
+	at := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	err := metrics.Annotate(context.Background(), metrics.AnnotationConfig{
+		URL:          server.URL + "/",
+		Token:        "glsa_token",
+		DashboardUID: "staffing",
+		Tags:         []string{"agent-scheduler"},
+		Retries:      1,
+		Backoff:      time.Millisecond,
+	}, metrics.Annotation{Time: at, Text: "run finished", Tags: []string{"shortfall"}})
+	require.NoError(t, err)
+
+	assert.Equal(t, int32(2), calls.Load(), "server errors are retried")
+	assert.Equal(t, "Bearer glsa_token", auth)
+	assert.Equal(t, "/api/annotations", path)
+	assert.Equal(t, "staffing", body["dashboardUID"])
+	assert.Equal(t, float64(at.UnixMilli()), body["time"])
+	assert.Equal(t, []any{"agent-scheduler", "shortfall"}, body["tags"])
+	assert.Equal(t, "run finished", body["text"])
+	assert.NotContains(t, body, "panelId")
+}
+
+func TestAnnotateClientError(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		http.Error(w, "invalid token", http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	err := metrics.Annotate(context.Background(), metrics.AnnotationConfig{URL: server.URL, Retries: 3}, metrics.Annotation{Time: time.Now()})
+	assert.ErrorContains(t, err, "401 Unauthorized: invalid token")
+	assert.Equal(t, int32(1), calls.Load(), "client errors are not retried")
+}
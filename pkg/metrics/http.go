@@ -0,0 +1,107 @@
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// =============================================================================
+// SERVER METRICS - Per-request instrumentation for HTTP endpoints
+// =============================================================================
+
+// HTTPRequestsTotal counts HTTP requests by handler, method, and status code.
+var HTTPRequestsTotal = factory.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "server",
+	Name:      "http_requests_total",
+	Help:      "Total HTTP requests by handler, method, and status code",
+}, []string{"handler", "method", "code"})
+
+// HTTPRequestDurationSeconds tracks HTTP request latency.
+var HTTPRequestDurationSeconds = factory.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: "server",
+	Name:      "http_request_duration_seconds",
+	Help:      "HTTP request latency by handler, method, and status code",
+	Buckets:   prometheus.DefBuckets,
+}, []string{"handler", "method", "code"})
+
+// HTTPRequestSizeBytes tracks HTTP request payload sizes.
+var HTTPRequestSizeBytes = factory.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: "server",
+	Name:      "http_request_size_bytes",
+	Help:      "HTTP request size by handler and method",
+	Buckets:   prometheus.ExponentialBuckets(256, 4, 8),
+}, []string{"handler", "method"})
+
+// HTTPResponseSizeBytes tracks HTTP response payload sizes.
+var HTTPResponseSizeBytes = factory.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: "server",
+	Name:      "http_response_size_bytes",
+	Help:      "HTTP response size by handler and method",
+	Buckets:   prometheus.ExponentialBuckets(256, 4, 8),
+}, []string{"handler", "method"})
+
+// HTTPRequestsInFlight tracks requests currently being served.
+var HTTPRequestsInFlight = factory.NewGaugeVec(prometheus.GaugeOpts{
+	Namespace: "server",
+	Name:      "http_requests_in_flight",
+	Help:      "HTTP requests currently being served by handler",
+}, []string{"handler"})
+
+// TenantHTTPRequestsTotal counts a multi-tenant server's authenticated
+// requests by tenant, handler, method, and status code.
+var TenantHTTPRequestsTotal = factory.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "server",
+	Name:      "tenant_http_requests_total",
+	Help:      "Total authenticated HTTP requests by tenant, handler, method, and status code",
+}, []string{"tenant", "handler", "method", "code"})
+
+// TenantSchedulesPublishedTotal counts schedules published by each tenant of
+// a multi-tenant server.
+var TenantSchedulesPublishedTotal = factory.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "server",
+	Name:      "tenant_schedules_published_total",
+	Help:      "Total schedules published by tenant",
+}, []string{"tenant"})
+
+// TenantLatestAgents tracks each tenant's latest published schedule.
+var TenantLatestAgents = factory.NewGaugeVec(prometheus.GaugeOpts{
+	Namespace: "server",
+	Name:      "tenant_latest_agents",
+	Help:      "Agents in the tenant's latest published schedule, by kind (allocated or unmet)",
+}, []string{"tenant", "kind"})
+
+// RecordTenantSchedule records a schedule published by tenant. Only totals
+// are labelled with the tenant, so one tenant's customers never appear in
+// series another tenant can be shown.
+func RecordTenantSchedule(tenant string, allocated, unmet int) {
+	TenantSchedulesPublishedTotal.WithLabelValues(tenant).Inc()
+	TenantLatestAgents.WithLabelValues(tenant, "allocated").Set(float64(allocated))
+	TenantLatestAgents.WithLabelValues(tenant, "unmet").Set(float64(unmet))
+}
+
+// InstrumentTenantHandler wraps h so every request is counted under the
+// tenant and handler labels. Tenants are configured, not taken from
+// requests, so their cardinality is bounded.
+func InstrumentTenantHandler(tenant, handler string, h http.Handler) http.Handler {
+	return promhttp.InstrumentHandlerCounter(
+		TenantHTTPRequestsTotal.MustCurryWith(prometheus.Labels{"tenant": tenant, "handler": handler}), h)
+}
+
+// InstrumentHandler wraps h so every request is counted, timed, and sized on
+// the Registry under the given handler label. Use a fixed route name (e.g.
+// "/schedule"), not the raw request path, to keep label cardinality bounded.
+func InstrumentHandler(handler string, h http.Handler) http.Handler {
+	labels := prometheus.Labels{"handler": handler}
+
+	return promhttp.InstrumentHandlerInFlight(HTTPRequestsInFlight.WithLabelValues(handler),
+		promhttp.InstrumentHandlerCounter(HTTPRequestsTotal.MustCurryWith(labels),
+			promhttp.InstrumentHandlerDuration(HTTPRequestDurationSeconds.MustCurryWith(labels),
+				promhttp.InstrumentHandlerRequestSize(HTTPRequestSizeBytes.MustCurryWith(labels),
+					promhttp.InstrumentHandlerResponseSize(HTTPResponseSizeBytes.MustCurryWith(labels), h),
+				),
+			),
+		),
+	)
+}
@@ -0,0 +1,31 @@
+package metrics_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/karthikrao-23/agentscheduler/pkg/metrics"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInstrumentHandler(t *testing.T) {
+	var inFlight float64
+	h := metrics.InstrumentHandler("/test", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		inFlight = testutil.ToFloat64(metrics.HTTPRequestsInFlight.WithLabelValues("/test"))
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte("hello"))
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/test", strings.NewReader("payload"))
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Equal(t, 1.0, inFlight, "request should be in flight while served")
+	assert.Equal(t, 0.0, testutil.ToFloat64(metrics.HTTPRequestsInFlight.WithLabelValues("/test")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(metrics.HTTPRequestsTotal.WithLabelValues("/test", "post", "201")))
+	assert.Equal(t, 1, testutil.CollectAndCount(metrics.HTTPRequestDurationSeconds))
+	assert.Equal(t, 1, testutil.CollectAndCount(metrics.HTTPResponseSizeBytes))
+}
@@ -0,0 +1,576 @@
+// Package metrics provides Prometheus observability metrics for the agent scheduler.
+// It includes Critical and Important metrics for business and operational visibility.
+package metrics
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Registry is the custom prometheus registry for our application
+var Registry = prometheus.NewRegistry()
+
+// factory allows us to register metrics to our custom Registry directly
+var factory = promauto.With(Registry)
+
+// RunIDLabel is the label that ties run-scoped counters to a scheduling run.
+const RunIDLabel = "run_id"
+
+// ResetPolicy controls what happens to run-labeled counters when a new run starts.
+type ResetPolicy string
+
+const (
+	// ResetPerRun drops series from previous runs so dashboards only see the latest schedule.
+	ResetPerRun ResetPolicy = "run"
+	// ResetNever keeps the series of every run for the lifetime of the process.
+	ResetNever ResetPolicy = "never"
+)
+
+// ParseResetPolicy converts a flag value into a ResetPolicy.
+func ParseResetPolicy(s string) (ResetPolicy, error) {
+	switch p := ResetPolicy(s); p {
+	case ResetPerRun, ResetNever:
+		return p, nil
+	default:
+		return "", fmt.Errorf("invalid reset policy %q: must be one of: run, never", s)
+	}
+}
+
+var (
+	runMu        sync.Mutex
+	resetPolicy  = ResetPerRun
+	currentRunID string
+)
+
+// SetResetPolicy sets the policy applied by StartRun.
+func SetResetPolicy(p ResetPolicy) {
+	runMu.Lock()
+	defer runMu.Unlock()
+	resetPolicy = p
+}
+
+// =============================================================================
+// CRITICAL METRICS - Business Impact Visibility
+// =============================================================================
+
+// AgentsUnmetTotal tracks total unmet agent demand across all hours.
+// High values indicate capacity planning issues.
+var AgentsUnmetTotal = factory.NewGauge(prometheus.GaugeOpts{
+	Namespace: "scheduler",
+	Name:      "agents_unmet_total",
+	Help:      "Total number of agents that could not be allocated due to capacity constraints",
+})
+
+// AgentsDemandedTotal tracks total agent demand across all hours.
+var AgentsDemandedTotal = factory.NewGauge(prometheus.GaugeOpts{
+	Namespace: "scheduler",
+	Name:      "agents_demanded_total",
+	Help:      "Total number of agents demanded across all customers and hours",
+})
+
+// AgentsAllocatedTotal tracks total agents successfully allocated.
+var AgentsAllocatedTotal = factory.NewGauge(prometheus.GaugeOpts{
+	Namespace: "scheduler",
+	Name:      "agents_allocated_total",
+	Help:      "Total number of agents successfully allocated",
+})
+
+// RunInfo is set to 1 for the run ID of the most recent scheduling run.
+var RunInfo = factory.NewGaugeVec(prometheus.GaugeOpts{
+	Namespace: "scheduler",
+	Name:      "run_info",
+	Help:      "Always 1; the run_id label identifies the most recent scheduling run",
+}, []string{RunIDLabel})
+
+// Satisfaction outcomes used as the outcome label of RequestsSatisfaction.
+const (
+	OutcomeFull    = "full"
+	OutcomePartial = "partial"
+	OutcomeNone    = "none"
+)
+
+// RequestsSatisfaction counts hourly customer requests by priority and by
+// whether they were fully, partially, or not at all satisfied.
+// Like the other run-labeled counters, it is subject to the reset policy.
+var RequestsSatisfaction = factory.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "scheduler",
+	Name:      "requests_satisfaction_total",
+	Help:      "Count of hourly customer requests by priority and satisfaction outcome (full, partial, none)",
+}, []string{"priority", "outcome", RunIDLabel})
+
+// HoursWithUnmetDemand tracks number of hours where capacity was exceeded.
+var HoursWithUnmetDemand = factory.NewGauge(prometheus.GaugeOpts{
+	Namespace: "scheduler",
+	Name:      "hours_with_unmet_demand",
+	Help:      "Number of hours in the schedule where demand exceeded capacity",
+})
+
+// UnmetDemandByPriority tracks unmet agents by priority level.
+var UnmetDemandByPriority = factory.NewGaugeVec(prometheus.GaugeOpts{
+	Namespace: "scheduler",
+	Name:      "unmet_demand_by_priority",
+	Help:      "Unmet agent demand broken down by priority level",
+}, []string{"priority"})
+
+// DemandFulfillmentPercent tracks the percentage of demanded agents that were allocated.
+var DemandFulfillmentPercent = factory.NewGauge(prometheus.GaugeOpts{
+	Namespace: "scheduler",
+	Name:      "demand_fulfillment_percent",
+	Help:      "Percentage of demanded agents that were allocated across all hours (100 when there is no demand)",
+})
+
+// HighPriorityFulfillmentPercent tracks the percentage of priority-1 demand that was allocated.
+var HighPriorityFulfillmentPercent = factory.NewGauge(prometheus.GaugeOpts{
+	Namespace: "scheduler",
+	Name:      "high_priority_fulfillment_percent",
+	Help:      "Percentage of priority-1 agent demand that was allocated (100 when there is no priority-1 demand)",
+})
+
+// WorstHourFulfillmentPercent tracks fulfillment in the worst-served hour.
+var WorstHourFulfillmentPercent = factory.NewGauge(prometheus.GaugeOpts{
+	Namespace: "scheduler",
+	Name:      "worst_hour_fulfillment_percent",
+	Help:      "Lowest per-hour percentage of demanded agents allocated, over hours with demand",
+})
+
+// PeakHour tracks the local hour of the day with the highest agent demand.
+var PeakHour = factory.NewGauge(prometheus.GaugeOpts{
+	Namespace: "scheduler",
+	Name:      "peak_hour",
+	Help:      "Hour of the day (0-23) with the highest agent demand; -1 when there is no demand",
+})
+
+// PeakHourAgentsDemanded tracks agent demand in the peak hour.
+var PeakHourAgentsDemanded = factory.NewGauge(prometheus.GaugeOpts{
+	Namespace: "scheduler",
+	Name:      "peak_hour_agents_demanded",
+	Help:      "Agents demanded in the peak hour",
+})
+
+// PeakHourAgentsUnmet tracks unmet agent demand in the peak hour.
+var PeakHourAgentsUnmet = factory.NewGauge(prometheus.GaugeOpts{
+	Namespace: "scheduler",
+	Name:      "peak_hour_agents_unmet",
+	Help:      "Agents that could not be allocated in the peak hour",
+})
+
+// HourlyAgentsDemanded tracks agent demand per local hour of the day.
+var HourlyAgentsDemanded = factory.NewGaugeVec(prometheus.GaugeOpts{
+	Namespace: "scheduler",
+	Name:      "hourly_agents_demanded",
+	Help:      "Agents demanded per hour of the day (0-23)",
+}, []string{"hour"})
+
+// HourlyAgentsAllocated tracks agents allocated per local hour of the day.
+var HourlyAgentsAllocated = factory.NewGaugeVec(prometheus.GaugeOpts{
+	Namespace: "scheduler",
+	Name:      "hourly_agents_allocated",
+	Help:      "Agents allocated per hour of the day (0-23)",
+}, []string{"hour"})
+
+// HourlyAgentsUnmet tracks unmet agent demand per local hour of the day.
+var HourlyAgentsUnmet = factory.NewGaugeVec(prometheus.GaugeOpts{
+	Namespace: "scheduler",
+	Name:      "hourly_agents_unmet",
+	Help:      "Agents that could not be allocated per hour of the day (0-23)",
+}, []string{"hour"})
+
+// AlertFiring is 1 for each built-in alert whose threshold is breached.
+var AlertFiring = factory.NewGaugeVec(prometheus.GaugeOpts{
+	Namespace: "scheduler",
+	Name:      "alert_firing",
+	Help:      "1 if the built-in alert threshold is breached for the latest run, 0 otherwise",
+}, []string{"alert"})
+
+// LocationAgentsDemanded tracks agent demand per location (timezone/site).
+var LocationAgentsDemanded = factory.NewGaugeVec(prometheus.GaugeOpts{
+	Namespace: "scheduler",
+	Name:      "location_agents_demanded",
+	Help:      "Agents demanded per location across all hours",
+}, []string{"location"})
+
+// LocationAgentsAllocated tracks agents allocated per location (timezone/site).
+var LocationAgentsAllocated = factory.NewGaugeVec(prometheus.GaugeOpts{
+	Namespace: "scheduler",
+	Name:      "location_agents_allocated",
+	Help:      "Agents allocated per location across all hours",
+}, []string{"location"})
+
+// LocationAgentsUnmet tracks unmet agent demand per location (timezone/site).
+var LocationAgentsUnmet = factory.NewGaugeVec(prometheus.GaugeOpts{
+	Namespace: "scheduler",
+	Name:      "location_agents_unmet",
+	Help:      "Agents that could not be allocated per location across all hours",
+}, []string{"location"})
+
+// SkillAgentsUnmet tracks unmet agent demand per required skill, for calls
+// that require one.
+var SkillAgentsUnmet = factory.NewGaugeVec(prometheus.GaugeOpts{
+	Namespace: "scheduler",
+	Name:      "skill_agents_unmet",
+	Help:      "Agents that could not be allocated per required skill across all hours",
+}, []string{"skill"})
+
+// PoolAgentsAllocated tracks the agents drawn from each agent pool.
+var PoolAgentsAllocated = factory.NewGaugeVec(prometheus.GaugeOpts{
+	Namespace: "scheduler",
+	Name:      "pool_agents_allocated",
+	Help:      "Agents allocated from each agent pool across all hours",
+}, []string{"pool"})
+
+// PoolUtilizationPercent tracks how much of each agent pool's headcount was
+// used while it was open.
+var PoolUtilizationPercent = factory.NewGaugeVec(prometheus.GaugeOpts{
+	Namespace: "scheduler",
+	Name:      "pool_utilization_percent",
+	Help:      "Agents allocated from each agent pool as a percentage of its headcount across the hours it was open",
+}, []string{"pool"})
+
+// PoolCost tracks what the agents drawn from each agent pool cost.
+var PoolCost = factory.NewGaugeVec(prometheus.GaugeOpts{
+	Namespace: "scheduler",
+	Name:      "pool_cost",
+	Help:      "Cost of the agents allocated from each agent pool, at its hourly cost",
+}, []string{"pool"})
+
+// OccupancyAgentsAdded tracks the agents the occupancy cap added on top of
+// what the staffing model asked for.
+var OccupancyAgentsAdded = factory.NewGauge(prometheus.GaugeOpts{
+	Namespace: "scheduler",
+	Name:      "occupancy_agents_added",
+	Help:      "Agents added across all hours to keep occupancy under the cap",
+})
+
+// CeilingAgentsUnmet tracks the demand left unmet because customers'
+// ceilings capped their agents, rather than for lack of capacity.
+var CeilingAgentsUnmet = factory.NewGauge(prometheus.GaugeOpts{
+	Namespace: "scheduler",
+	Name:      "ceiling_agents_unmet",
+	Help:      "Agents left unmet across all hours because a customer's ceiling capped its agents",
+})
+
+// =============================================================================
+// PER-CUSTOMER METRICS - Opt-in, see EnablePerCustomerMetrics
+// =============================================================================
+
+// OtherCustomersLabel is the customer label used for customers beyond the
+// per-customer cardinality cap.
+const OtherCustomersLabel = "_other"
+
+// CustomerAgentsDemanded tracks agent demand per customer across all hours.
+var CustomerAgentsDemanded = factory.NewGaugeVec(prometheus.GaugeOpts{
+	Namespace: "scheduler",
+	Name:      "customer_agents_demanded",
+	Help:      "Agents demanded per customer across all hours",
+}, []string{"customer"})
+
+// CustomerAgentsAllocated tracks agents allocated per customer across all hours.
+var CustomerAgentsAllocated = factory.NewGaugeVec(prometheus.GaugeOpts{
+	Namespace: "scheduler",
+	Name:      "customer_agents_allocated",
+	Help:      "Agents allocated per customer across all hours",
+}, []string{"customer"})
+
+// CustomerAgentsUnmet tracks unmet agent demand per customer across all hours.
+var CustomerAgentsUnmet = factory.NewGaugeVec(prometheus.GaugeOpts{
+	Namespace: "scheduler",
+	Name:      "customer_agents_unmet",
+	Help:      "Agents that could not be allocated per customer across all hours",
+}, []string{"customer"})
+
+// perCustomerLimit caps the number of distinct customer label values.
+// Zero disables per-customer metrics entirely.
+var perCustomerLimit atomic.Int64
+
+// EnablePerCustomerMetrics turns on the per-customer gauges. At most limit
+// customers (ranked by demand) get their own series; the rest are folded into
+// OtherCustomersLabel so a large input cannot blow up series cardinality.
+// A limit of zero or less disables the per-customer gauges.
+func EnablePerCustomerMetrics(limit int) {
+	perCustomerLimit.Store(int64(max(limit, 0)))
+}
+
+// CustomerAgents holds one customer's agent totals for a scheduling run.
+type CustomerAgents struct {
+	Demanded  float64
+	Allocated float64
+	Unmet     float64
+}
+
+// RecordPerCustomer publishes per-customer totals, applying the cardinality cap.
+// It is a no-op unless EnablePerCustomerMetrics was called with a positive limit.
+func RecordPerCustomer(customers map[string]CustomerAgents) {
+	limit := int(perCustomerLimit.Load())
+	if limit <= 0 {
+		return
+	}
+
+	// Rank by demand (ties broken by name) so the cap keeps the biggest customers
+	names := make([]string, 0, len(customers))
+	for name := range customers {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		di, dj := customers[names[i]].Demanded, customers[names[j]].Demanded
+		if di != dj {
+			return di > dj
+		}
+		return names[i] < names[j]
+	})
+
+	var other CustomerAgents
+	for i, name := range names {
+		c := customers[name]
+		if i >= limit {
+			other.Demanded += c.Demanded
+			other.Allocated += c.Allocated
+			other.Unmet += c.Unmet
+			continue
+		}
+		CustomerAgentsDemanded.WithLabelValues(name).Set(c.Demanded)
+		CustomerAgentsAllocated.WithLabelValues(name).Set(c.Allocated)
+		CustomerAgentsUnmet.WithLabelValues(name).Set(c.Unmet)
+	}
+
+	if len(names) > limit {
+		CustomerAgentsDemanded.WithLabelValues(OtherCustomersLabel).Set(other.Demanded)
+		CustomerAgentsAllocated.WithLabelValues(OtherCustomersLabel).Set(other.Allocated)
+		CustomerAgentsUnmet.WithLabelValues(OtherCustomersLabel).Set(other.Unmet)
+	}
+}
+
+// =============================================================================
+// IMPORTANT METRICS - Operational Health
+// =============================================================================
+
+// ParserErrorsTotal tracks parse errors by error type.
+var ParserErrorsTotal = factory.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "parser",
+	Name:      "errors_total",
+	Help:      "Total parse errors by error type",
+}, []string{"error_type"})
+
+// ParserRecordsTotal tracks total records successfully parsed.
+var ParserRecordsTotal = factory.NewCounter(prometheus.CounterOpts{
+	Namespace: "parser",
+	Name:      "records_total",
+	Help:      "Total CSV records successfully parsed",
+})
+
+// ParserDurationSeconds tracks time to parse input files.
+var ParserDurationSeconds = factory.NewHistogram(prometheus.HistogramOpts{
+	Namespace: "parser",
+	Name:      "duration_seconds",
+	Help:      "Time taken to parse CSV input file",
+	Buckets:   []float64{0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1.0},
+})
+
+// ParserRowsSkipped tracks invalid rows skipped by the latest lenient parse.
+var ParserRowsSkipped = factory.NewGauge(prometheus.GaugeOpts{
+	Namespace: "parser",
+	Name:      "rows_skipped",
+	Help:      "Invalid rows skipped in lenient mode by the latest parse",
+})
+
+// ParserDistinctTimezones tracks how many timezones the latest input used.
+var ParserDistinctTimezones = factory.NewGauge(prometheus.GaugeOpts{
+	Namespace: "parser",
+	Name:      "distinct_timezones",
+	Help:      "Distinct timezones seen in the latest parsed input",
+})
+
+// ParserZeroCallCustomers tracks customers forecast with zero calls.
+var ParserZeroCallCustomers = factory.NewGauge(prometheus.GaugeOpts{
+	Namespace: "parser",
+	Name:      "zero_call_customers",
+	Help:      "Customers with zero calls in the latest parsed input",
+})
+
+// ParserMaxWindowHours tracks the longest call window in the latest input.
+var ParserMaxWindowHours = factory.NewGauge(prometheus.GaugeOpts{
+	Namespace: "parser",
+	Name:      "max_window_hours",
+	Help:      "Longest call window, in hours, in the latest parsed input",
+})
+
+// SchedulerDurationSeconds tracks time to generate schedule.
+var SchedulerDurationSeconds = factory.NewHistogram(prometheus.HistogramOpts{
+	Namespace: "scheduler",
+	Name:      "duration_seconds",
+	Help:      "Time taken to generate the schedule",
+	Buckets:   []float64{0.0001, 0.0005, 0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25},
+})
+
+// SchedulerCustomersProcessed tracks number of customers per scheduling run.
+var SchedulerCustomersProcessed = factory.NewHistogram(prometheus.HistogramOpts{
+	Namespace: "scheduler",
+	Name:      "customers_processed",
+	Help:      "Number of customers processed per scheduling run",
+	Buckets:   []float64{1, 5, 10, 25, 50, 100, 250, 500, 1000},
+})
+
+// RunsTotal counts completed pipeline runs by status (success or failure).
+// It is a lifetime counter and is not subject to the reset policy.
+var RunsTotal = factory.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "scheduler",
+	Name:      "runs_total",
+	Help:      "Total scheduling runs by status (success, failure)",
+}, []string{"status"})
+
+// LastRunTimestampSeconds tracks when the last successful run completed.
+var LastRunTimestampSeconds = factory.NewGauge(prometheus.GaugeOpts{
+	Namespace: "scheduler",
+	Name:      "last_run_timestamp_seconds",
+	Help:      "Unix timestamp of the last successful scheduling run",
+})
+
+// SchedulerCapacityUsed tracks the capacity used when constraints are applied.
+var SchedulerCapacityUsed = factory.NewGauge(prometheus.GaugeOpts{
+	Namespace: "scheduler",
+	Name:      "capacity_used_total",
+	Help:      "Total capacity used across all hours when capacity constraints applied",
+})
+
+// =============================================================================
+// Helper Functions
+// =============================================================================
+
+// RegisterRuntimeCollectors adds the standard Go runtime and process
+// collectors to the Registry. They are opt-in so batch runs keep a clean
+// business-only scrape; long-running modes enable them to watch memory and GC.
+// Calling it more than once is harmless.
+func RegisterRuntimeCollectors() error {
+	for _, c := range []prometheus.Collector{
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	} {
+		if err := Registry.Register(c); err != nil {
+			var are prometheus.AlreadyRegisteredError
+			if errors.As(err, &are) {
+				continue
+			}
+			return fmt.Errorf("error registering runtime collector: %w", err)
+		}
+	}
+	return nil
+}
+
+// StartRun begins a new scheduling run and returns its ID. Call this at the
+// start of GenerateSchedule; the run's metrics only become visible once it
+// calls PublishRun, so concurrent runs do not see each other's partial state.
+func StartRun() string {
+	return newRunID()
+}
+
+// PublishRun makes runID the latest run. Under a lock shared by every run it
+// resets the scheduler gauges, applies the reset policy to the run-labeled
+// counters, points RunInfo at runID, and then calls publish to set the run's
+// metrics. Concurrent runs are therefore published one at a time, and the
+// gauges always describe exactly one complete run: the last to publish.
+func PublishRun(runID string, publish func()) {
+	runMu.Lock()
+	defer runMu.Unlock()
+
+	ResetSchedulerGauges()
+	if resetPolicy == ResetPerRun {
+		RequestsSatisfaction.Reset()
+	}
+
+	currentRunID = runID
+	RunInfo.Reset()
+	RunInfo.WithLabelValues(currentRunID).Set(1)
+	publish()
+}
+
+// ObserveDuration records seconds on h with an exemplar linking the sample to
+// its run (runID, if non-empty) and trace (if ctx carries a valid span), so a
+// slow run on a dashboard can be followed to its trace or run manifest.
+// Exemplars are only exposed when scraped in OpenMetrics format.
+func ObserveDuration(ctx context.Context, h prometheus.Histogram, seconds float64, runID string) {
+	labels := prometheus.Labels{}
+	if runID != "" {
+		labels[RunIDLabel] = runID
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.HasTraceID() {
+		labels["trace_id"] = sc.TraceID().String()
+	}
+
+	if eo, ok := h.(prometheus.ExemplarObserver); ok && len(labels) > 0 {
+		eo.ObserveWithExemplar(seconds, labels)
+		return
+	}
+	h.Observe(seconds)
+}
+
+// RecordRunResult records the outcome of a full parse-schedule-format run.
+// A nil error counts as success and refreshes LastRunTimestampSeconds.
+func RecordRunResult(err error) {
+	if err != nil {
+		RunsTotal.WithLabelValues("failure").Inc()
+		return
+	}
+	RunsTotal.WithLabelValues("success").Inc()
+	LastRunTimestampSeconds.SetToCurrentTime()
+}
+
+// CurrentRunID returns the ID of the most recently published run, or "" before
+// the first run.
+func CurrentRunID() string {
+	runMu.Lock()
+	defer runMu.Unlock()
+	return currentRunID
+}
+
+// newRunID returns a sortable, collision-resistant run identifier.
+func newRunID() string {
+	var b [4]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return time.Now().UTC().Format("20060102T150405.000000000Z")
+	}
+	return time.Now().UTC().Format("20060102T150405Z") + "-" + hex.EncodeToString(b[:])
+}
+
+// ResetSchedulerGauges resets all scheduler gauges before a new scheduling run.
+// PublishRun calls this; gauges always describe the latest run regardless of
+// the reset policy.
+func ResetSchedulerGauges() {
+	AgentsUnmetTotal.Set(0)
+	AgentsDemandedTotal.Set(0)
+	AgentsAllocatedTotal.Set(0)
+	HoursWithUnmetDemand.Set(0)
+	SchedulerCapacityUsed.Set(0)
+	DemandFulfillmentPercent.Set(0)
+	HighPriorityFulfillmentPercent.Set(0)
+	WorstHourFulfillmentPercent.Set(0)
+	PeakHour.Set(-1)
+	PeakHourAgentsDemanded.Set(0)
+	PeakHourAgentsUnmet.Set(0)
+	UnmetDemandByPriority.Reset()
+	HourlyAgentsDemanded.Reset()
+	HourlyAgentsAllocated.Reset()
+	HourlyAgentsUnmet.Reset()
+	LocationAgentsDemanded.Reset()
+	LocationAgentsAllocated.Reset()
+	LocationAgentsUnmet.Reset()
+	SkillAgentsUnmet.Reset()
+	PoolAgentsAllocated.Reset()
+	PoolUtilizationPercent.Reset()
+	PoolCost.Reset()
+	OccupancyAgentsAdded.Set(0)
+	CeilingAgentsUnmet.Set(0)
+	CustomerAgentsDemanded.Reset()
+	CustomerAgentsAllocated.Reset()
+	CustomerAgentsUnmet.Reset()
+}
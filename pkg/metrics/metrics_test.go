@@ -0,0 +1,70 @@
+package metrics_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/karthikrao-23/agentscheduler/pkg/metrics"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestRegisterRuntimeCollectors(t *testing.T) {
+	assert.NoError(t, metrics.RegisterRuntimeCollectors())
+	// A second registration is a no-op rather than an error
+	assert.NoError(t, metrics.RegisterRuntimeCollectors())
+
+	families, err := metrics.Registry.Gather()
+	assert.NoError(t, err)
+
+	names := make(map[string]bool)
+	for _, mf := range families {
+		names[mf.GetName()] = true
+	}
+	assert.True(t, names["go_goroutines"], "Go runtime collector should be registered")
+}
+
+func TestRecordRunResult(t *testing.T) {
+	before := testutil.ToFloat64(metrics.RunsTotal.WithLabelValues("failure"))
+	metrics.RecordRunResult(errors.New("boom"))
+	assert.Equal(t, before+1, testutil.ToFloat64(metrics.RunsTotal.WithLabelValues("failure")))
+
+	before = testutil.ToFloat64(metrics.RunsTotal.WithLabelValues("success"))
+	metrics.RecordRunResult(nil)
+	assert.Equal(t, before+1, testutil.ToFloat64(metrics.RunsTotal.WithLabelValues("success")))
+	assert.InDelta(t, float64(time.Now().Unix()), testutil.ToFloat64(metrics.LastRunTimestampSeconds), 5)
+}
+
+func TestObserveDuration_Exemplar(t *testing.T) {
+	h := prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "test_duration_seconds",
+		Buckets: []float64{0.1, 1},
+	})
+
+	traceID, _ := trace.TraceIDFromHex("0102030405060708090a0b0c0d0e0f10")
+	spanID, _ := trace.SpanIDFromHex("0102030405060708")
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: traceID,
+		SpanID:  spanID,
+	}))
+
+	metrics.ObserveDuration(ctx, h, 0.05, "run-123")
+
+	var m dto.Metric
+	assert.NoError(t, h.Write(&m))
+	exemplar := m.GetHistogram().GetBucket()[0].GetExemplar()
+	assert.NotNil(t, exemplar)
+
+	labels := make(map[string]string)
+	for _, lp := range exemplar.GetLabel() {
+		labels[lp.GetName()] = lp.GetValue()
+	}
+	assert.Equal(t, "run-123", labels[metrics.RunIDLabel])
+	assert.Equal(t, traceID.String(), labels["trace_id"])
+}
@@ -0,0 +1,111 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/push"
+)
+
+// DefaultJobName is the pushgateway job name used when none is configured.
+const DefaultJobName = "agent_scheduler"
+
+// PushConfig describes how to push the Registry to a Prometheus Pushgateway.
+type PushConfig struct {
+	// URL of the Pushgateway (e.g., http://localhost:9091)
+	URL string
+	// Job is the job name; defaults to DefaultJobName
+	Job string
+	// Grouping holds extra grouping labels such as instance or site
+	Grouping map[string]string
+	// Retries is the number of additional attempts after the first failure
+	Retries int
+	// Backoff is the delay before the first retry; it doubles on each retry
+	Backoff time.Duration
+	// Timeout bounds each individual attempt (0 = no timeout)
+	Timeout time.Duration
+}
+
+// Push pushes all metrics in the Registry to the Pushgateway, retrying with
+// exponential backoff. It returns the last error if every attempt fails.
+func Push(ctx context.Context, cfg PushConfig) error {
+	job := cfg.Job
+	if job == "" {
+		job = DefaultJobName
+	}
+
+	pusher := push.New(cfg.URL, job).Gatherer(Registry)
+	for name, value := range cfg.Grouping {
+		pusher = pusher.Grouping(name, value)
+	}
+
+	return withRetry(ctx, cfg.Retries, cfg.Backoff, cfg.Timeout, pusher.PushContext)
+}
+
+// permanentError marks a failure that retrying cannot fix.
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+
+func (e *permanentError) Unwrap() error { return e.err }
+
+// withRetry calls attempt until it succeeds, returns a permanentError, or
+// retries are exhausted. Each attempt is bounded by timeout (0 = no timeout)
+// and the delay between attempts starts at backoff and doubles.
+func withRetry(ctx context.Context, retries int, backoff, timeout time.Duration, attempt func(context.Context) error) error {
+	var err error
+	for i := 0; i <= retries; i++ {
+		if i > 0 {
+			select {
+			case <-ctx.Done():
+				return fmt.Errorf("cancelled after %d attempts: %w", i, err)
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+		}
+
+		if err = attemptOnce(ctx, timeout, attempt); err == nil {
+			return nil
+		}
+		var perm *permanentError
+		if errors.As(err, &perm) {
+			return perm.err
+		}
+	}
+	return fmt.Errorf("failed after %d attempts: %w", retries+1, err)
+}
+
+// attemptOnce performs a single attempt bounded by timeout.
+func attemptOnce(ctx context.Context, timeout time.Duration, attempt func(context.Context) error) error {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	return attempt(ctx)
+}
+
+// ParseGrouping parses comma-separated name=value pairs (e.g.
+// "instance=host1,site=nyc") into grouping labels.
+func ParseGrouping(s string) (map[string]string, error) {
+	grouping := make(map[string]string)
+	if strings.TrimSpace(s) == "" {
+		return grouping, nil
+	}
+
+	for _, pair := range strings.Split(s, ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		name = strings.TrimSpace(name)
+		value = strings.TrimSpace(value)
+		if !ok || name == "" || value == "" {
+			return nil, fmt.Errorf("invalid grouping label %q: expected name=value", pair)
+		}
+		grouping[name] = value
+	}
+	return grouping, nil
+}
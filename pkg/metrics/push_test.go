@@ -0,0 +1,73 @@
+package metrics_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/karthikrao-23/agentscheduler/pkg/metrics"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPush(t *testing.T) {
+	tests := map[string]struct {
+		failures      int32
+		retries       int
+		expectedCalls int32
+		expectErr     bool
+	}{
+		"SucceedsFirstTry":     {failures: 0, retries: 2, expectedCalls: 1},
+		"SucceedsAfterRetries": {failures: 2, retries: 2, expectedCalls: 3},
+		"ExhaustsRetries":      {failures: 5, retries: 1, expectedCalls: 2, expectErr: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var calls atomic.Int32
+			var path string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				path = r.URL.Path
+				if calls.Add(1) <= tt.failures {
+					w.WriteHeader(http.StatusServiceUnavailable)
+					return
+				}
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			err := metrics.Push(context.Background(), metrics.PushConfig{
+				URL:      server.URL,
+				Job:      "nightly",
+				Grouping: map[string]string{"site": "nyc"},
+				Retries:  tt.retries,
+				Backoff:  time.Millisecond,
+				Timeout:  time.Second,
+			})
+
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.expectedCalls, calls.Load())
+			assert.Equal(t, "/metrics/job/nightly/site/nyc", path)
+		})
+	}
+}
+
+func TestParseGrouping(t *testing.T) {
+	grouping, err := metrics.ParseGrouping("instance=host1, site=nyc")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"instance": "host1", "site": "nyc"}, grouping)
+
+	grouping, err = metrics.ParseGrouping("")
+	assert.NoError(t, err)
+	assert.Empty(t, grouping)
+
+	_, err = metrics.ParseGrouping("site")
+	assert.Error(t, err)
+}
@@ -0,0 +1,215 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/golang/snappy"
+	dto "github.com/prometheus/client_model/go"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// RemoteWriteConfig describes how to send the Registry to a Prometheus
+// remote-write endpoint (e.g. Mimir, Thanos Receive, Cortex).
+type RemoteWriteConfig struct {
+	// URL of the remote-write endpoint (e.g., https://mimir/api/v1/push)
+	URL string
+	// Job is added as the job label; defaults to DefaultJobName
+	Job string
+	// Labels are added to every series, like pushgateway grouping labels
+	Labels map[string]string
+	// Headers are sent with every request (e.g., X-Scope-OrgID for tenancy)
+	Headers map[string]string
+	// Retries, Backoff, and Timeout behave as in PushConfig
+	Retries int
+	Backoff time.Duration
+	Timeout time.Duration
+	// Client is the HTTP client to use; defaults to http.DefaultClient
+	Client *http.Client
+}
+
+// RemoteWrite gathers the Registry and sends it as a single remote-write
+// request, retrying server errors with exponential backoff. Client errors
+// (4xx other than 429) are not retried, per the remote-write spec.
+func RemoteWrite(ctx context.Context, cfg RemoteWriteConfig) error {
+	families, err := Registry.Gather()
+	if err != nil {
+		return fmt.Errorf("error gathering metrics: %w", err)
+	}
+
+	job := cfg.Job
+	if job == "" {
+		job = DefaultJobName
+	}
+	external := map[string]string{"job": job}
+	for name, value := range cfg.Labels {
+		external[name] = value
+	}
+
+	body := snappy.Encode(nil, encodeWriteRequest(families, external, time.Now().UnixMilli()))
+
+	client := cfg.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return withRetry(ctx, cfg.Retries, cfg.Backoff, cfg.Timeout, func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, cfg.URL, bytes.NewReader(body))
+		if err != nil {
+			return &permanentError{err: err}
+		}
+		req.Header.Set("Content-Encoding", "snappy")
+		req.Header.Set("Content-Type", "application/x-protobuf")
+		req.Header.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
+		for name, value := range cfg.Headers {
+			req.Header.Set(name, value)
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode/100 == 2 {
+			return nil
+		}
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		err = fmt.Errorf("remote write returned %s: %s", resp.Status, bytes.TrimSpace(msg))
+		if resp.StatusCode/100 == 4 && resp.StatusCode != http.StatusTooManyRequests {
+			return &permanentError{err: err}
+		}
+		return err
+	})
+}
+
+// series is one remote-write time series with a single sample.
+type series struct {
+	labels map[string]string
+	value  float64
+}
+
+// encodeWriteRequest encodes families as a prometheus.WriteRequest protobuf
+// (remote-write 1.0). Every series gets the external labels and timestamp ts.
+//
+//	message WriteRequest { repeated TimeSeries timeseries = 1; }
+//	message TimeSeries   { repeated Label labels = 1; repeated Sample samples = 2; }
+//	message Label        { string name = 1; string value = 2; }
+//	message Sample       { double value = 1; int64 timestamp = 2; }
+func encodeWriteRequest(families []*dto.MetricFamily, external map[string]string, ts int64) []byte {
+	var buf []byte
+	for _, mf := range families {
+		for _, m := range mf.GetMetric() {
+			for _, s := range flattenMetric(mf.GetName(), mf.GetType(), m) {
+				labels := make(map[string]string, len(external)+len(s.labels))
+				for name, value := range external {
+					labels[name] = value
+				}
+				for name, value := range s.labels {
+					labels[name] = value
+				}
+				buf = protowire.AppendTag(buf, 1, protowire.BytesType)
+				buf = protowire.AppendBytes(buf, encodeTimeSeries(labels, s.value, ts))
+			}
+		}
+	}
+	return buf
+}
+
+// encodeTimeSeries encodes one TimeSeries with labels sorted by name, as
+// remote-write receivers require.
+func encodeTimeSeries(labels map[string]string, value float64, ts int64) []byte {
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var buf []byte
+	for _, name := range names {
+		var label []byte
+		label = protowire.AppendTag(label, 1, protowire.BytesType)
+		label = protowire.AppendString(label, name)
+		label = protowire.AppendTag(label, 2, protowire.BytesType)
+		label = protowire.AppendString(label, labels[name])
+
+		buf = protowire.AppendTag(buf, 1, protowire.BytesType)
+		buf = protowire.AppendBytes(buf, label)
+	}
+
+	var sample []byte
+	sample = protowire.AppendTag(sample, 1, protowire.Fixed64Type)
+	sample = protowire.AppendFixed64(sample, math.Float64bits(value))
+	sample = protowire.AppendTag(sample, 2, protowire.VarintType)
+	sample = protowire.AppendVarint(sample, uint64(ts))
+
+	buf = protowire.AppendTag(buf, 2, protowire.BytesType)
+	buf = protowire.AppendBytes(buf, sample)
+	return buf
+}
+
+// flattenMetric expands a metric into the series Prometheus itself would
+// store, e.g. a histogram becomes _bucket, _sum, and _count series.
+func flattenMetric(name string, typ dto.MetricType, m *dto.Metric) []series {
+	base := map[string]string{"__name__": name}
+	for _, lp := range m.GetLabel() {
+		base[lp.GetName()] = lp.GetValue()
+	}
+	with := func(suffix string, extra map[string]string, value float64) series {
+		labels := make(map[string]string, len(base)+len(extra))
+		for k, v := range base {
+			labels[k] = v
+		}
+		for k, v := range extra {
+			labels[k] = v
+		}
+		labels["__name__"] = name + suffix
+		return series{labels: labels, value: value}
+	}
+
+	switch typ {
+	case dto.MetricType_COUNTER:
+		return []series{with("", nil, m.GetCounter().GetValue())}
+	case dto.MetricType_GAUGE:
+		return []series{with("", nil, m.GetGauge().GetValue())}
+	case dto.MetricType_HISTOGRAM:
+		h := m.GetHistogram()
+		out := make([]series, 0, len(h.GetBucket())+3)
+		for _, b := range h.GetBucket() {
+			le := strconv.FormatFloat(b.GetUpperBound(), 'g', -1, 64)
+			if math.IsInf(b.GetUpperBound(), 1) {
+				le = "+Inf"
+			}
+			out = append(out, with("_bucket", map[string]string{"le": le}, float64(b.GetCumulativeCount())))
+		}
+		if n := len(h.GetBucket()); n == 0 || !math.IsInf(h.GetBucket()[n-1].GetUpperBound(), 1) {
+			out = append(out, with("_bucket", map[string]string{"le": "+Inf"}, float64(h.GetSampleCount())))
+		}
+		out = append(out,
+			with("_sum", nil, h.GetSampleSum()),
+			with("_count", nil, float64(h.GetSampleCount())),
+		)
+		return out
+	case dto.MetricType_SUMMARY:
+		sm := m.GetSummary()
+		out := make([]series, 0, len(sm.GetQuantile())+2)
+		for _, q := range sm.GetQuantile() {
+			quantile := strconv.FormatFloat(q.GetQuantile(), 'g', -1, 64)
+			out = append(out, with("", map[string]string{"quantile": quantile}, q.GetValue()))
+		}
+		out = append(out,
+			with("_sum", nil, sm.GetSampleSum()),
+			with("_count", nil, float64(sm.GetSampleCount())),
+		)
+		return out
+	default:
+		return []series{with("", nil, m.GetUntyped().GetValue())}
+	}
+}
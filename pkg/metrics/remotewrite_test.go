@@ -0,0 +1,70 @@
+package metrics_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/karthikrao-23/agentscheduler/pkg/metrics"
+
+	"github.com/golang/snappy"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRemoteWrite(t *testing.T) {
+	metrics.RecordRunResult(nil)
+
+	var calls atomic.Int32
+	var body []byte
+	var header http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		header = r.Header.Clone()
+		compressed, _ := io.ReadAll(r.Body)
+		body, _ = snappy.Decode(nil, compressed)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	err := metrics.RemoteWrite(context.Background(), metrics.RemoteWriteConfig{
+		URL:     server.URL,
+		Labels:  map[string]string{"site": "nyc"},
+		Headers: map[string]string{"X-Scope-OrgID": "staffing"},
+		Retries: 1,
+		Backoff: time.Millisecond,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), calls.Load(), "server errors should be retried")
+	assert.Equal(t, "snappy", header.Get("Content-Encoding"))
+	assert.Equal(t, "staffing", header.Get("X-Scope-OrgID"))
+	assert.True(t, bytes.Contains(body, []byte("scheduler_runs_total")))
+	assert.True(t, bytes.Contains(body, []byte("agent_scheduler")), "default job label")
+	assert.True(t, bytes.Contains(body, []byte("nyc")))
+}
+
+func TestRemoteWrite_ClientErrorNotRetried(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		http.Error(w, "out of order sample", http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	err := metrics.RemoteWrite(context.Background(), metrics.RemoteWriteConfig{
+		URL:     server.URL,
+		Retries: 3,
+		Backoff: time.Millisecond,
+	})
+
+	assert.ErrorContains(t, err, "out of order sample")
+	assert.Equal(t, int32(1), calls.Load())
+}
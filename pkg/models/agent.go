@@ -0,0 +1,57 @@
+package models
+
+import (
+	"slices"
+	"time"
+)
+
+// Agent is a staff member on the roster who can be assigned to customers.
+type Agent struct {
+	ID   string
+	Name string
+	// Skills are the customer skills the agent can handle
+	Skills []string
+	// Site is where the agent works, e.g. an office or "remote"
+	Site string
+	// Location is the agent's time zone; Availability is in local time
+	Location     Location
+	Availability []Availability
+}
+
+// Availability is a weekly window in which an agent can work, as offsets
+// from midnight in the agent's location. End may pass midnight for
+// overnight shifts.
+type Availability struct {
+	Weekday time.Weekday
+	Start   time.Duration
+	End     time.Duration
+}
+
+// HasSkill reports whether the agent can handle calls requiring skill. No
+// skill is required when skill is empty.
+func (a Agent) HasSkill(skill string) bool {
+	return skill == "" || slices.Contains(a.Skills, skill)
+}
+
+// AvailableAt reports whether t falls in one of the agent's availability
+// windows, in the agent's location. An agent without any availability is
+// treated as always available.
+func (a Agent) AvailableAt(t time.Time) bool {
+	if len(a.Availability) == 0 {
+		return true
+	}
+	local := a.Location.In(t)
+	midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, local.Location())
+	offset := local.Sub(midnight)
+	yesterday := (local.Weekday() + 6) % 7
+	for _, w := range a.Availability {
+		if w.Weekday == local.Weekday() && offset >= w.Start && offset < w.End {
+			return true
+		}
+		// The tail of an overnight window that started the day before
+		if w.Weekday == yesterday && w.End > 24*time.Hour && offset+24*time.Hour < w.End {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,56 @@
+package models_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAgentHasSkill(t *testing.T) {
+	a := models.Agent{Skills: []string{"billing", "spanish"}}
+	assert.True(t, a.HasSkill(""))
+	assert.True(t, a.HasSkill("spanish"))
+	assert.False(t, a.HasSkill("french"))
+}
+
+func TestAgentAvailableAt(t *testing.T) {
+	ny := func() models.Location {
+		loc, err := models.LoadLocation("America/New_York")
+		if err != nil {
+			panic(err)
+		}
+		return loc
+	}()
+	agent := models.Agent{
+		Location: ny,
+		Availability: []models.Availability{
+			{Weekday: time.Monday, Start: 9 * time.Hour, End: 17 * time.Hour},
+			{Weekday: time.Friday, Start: 22 * time.Hour, End: 30 * time.Hour},
+		},
+	}
+
+	// 2026-03-02 is a Monday
+	tests := map[string]struct {
+		at   time.Time
+		want bool
+	}{
+		"InsideWindow":       {at: time.Date(2026, 3, 2, 9, 0, 0, 0, ny.Time()), want: true},
+		"WindowEndExclusive": {at: time.Date(2026, 3, 2, 17, 0, 0, 0, ny.Time()), want: false},
+		"OtherDay":           {at: time.Date(2026, 3, 3, 10, 0, 0, 0, ny.Time()), want: false},
+		"ConvertsToLocal":    {at: time.Date(2026, 3, 2, 15, 0, 0, 0, time.UTC), want: true},
+		"OvernightStart":     {at: time.Date(2026, 3, 6, 23, 0, 0, 0, ny.Time()), want: true},
+		"OvernightTail":      {at: time.Date(2026, 3, 7, 5, 30, 0, 0, ny.Time()), want: true},
+		"AfterOvernight":     {at: time.Date(2026, 3, 7, 6, 0, 0, 0, ny.Time()), want: false},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.want, agent.AvailableAt(tt.at))
+		})
+	}
+
+	assert.True(t, models.Agent{}.AvailableAt(time.Now()), "no availability means always available")
+}
@@ -0,0 +1,28 @@
+package models
+
+import "slices"
+
+// ArrivalCurves describe how each customer's calls arrive within a slot,
+// as calls are rarely spread evenly over it. They map customer names to
+// the share of a slot's calls that arrive in each of its equal parts in
+// turn: {0.2, 0.3, 0.3, 0.2} means 30% of an hour's calls arrive in each of
+// its middle quarters.
+type ArrivalCurves map[string][]float64
+
+// Peakedness returns how much busier the customer's busiest part of a slot
+// is than the slot on average: its share times the number of parts, e.g.
+// 1.2 for the curve above. It reports false for customers without a curve.
+func (c ArrivalCurves) Peakedness(customer string) (float64, bool) {
+	curve, ok := c[customer]
+	if !ok || len(curve) == 0 {
+		return 0, false
+	}
+	total := 0.0
+	for _, share := range curve {
+		total += share
+	}
+	if total <= 0 {
+		return 0, false
+	}
+	return slices.Max(curve) / total * float64(len(curve)), true
+}
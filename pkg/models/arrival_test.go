@@ -0,0 +1,34 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestArrivalCurves_Peakedness(t *testing.T) {
+	curves := models.ArrivalCurves{
+		"Acme":   {0.2, 0.3, 0.3, 0.2},
+		"Globex": {1, 3},
+		"Flat":   {1},
+	}
+	tests := map[string]struct {
+		customer string
+		want     float64
+		ok       bool
+	}{
+		"Quarters": {customer: "Acme", want: 1.2, ok: true},
+		"Unscaled": {customer: "Globex", want: 1.5, ok: true},
+		"Even":     {customer: "Flat", want: 1, ok: true},
+		"Missing":  {customer: "Initech"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, ok := curves.Peakedness(tt.customer)
+			assert.Equal(t, tt.ok, ok)
+			assert.InDelta(t, tt.want, got, 1e-9)
+		})
+	}
+}
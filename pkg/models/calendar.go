@@ -0,0 +1,47 @@
+package models
+
+import "time"
+
+// Calendar lists holidays and blackout windows, when call volume differs
+// from what the input gives for reasons no row can describe, such as a
+// closure or a public holiday.
+type Calendar []CalendarEntry
+
+// CalendarEntry scales the calls of the customers it covers over a window
+// of wall-clock time in each customer's location, e.g. all of Christmas Day
+// or an afternoon a site is closed for training.
+type CalendarEntry struct {
+	Name string
+	// Customer and Location, an IANA name, limit the entry to one customer
+	// or one location; empty covers every one
+	Customer, Location string
+	// Start and End bound the window as wall-clock times, in UTC
+	Start, End time.Time
+	// Multiplier scales the calls in the window; 0 blacks it out
+	Multiplier float64
+}
+
+// Covers reports whether the entry applies to customer in loc at t.
+func (e CalendarEntry) Covers(customer string, loc Location, t time.Time) bool {
+	if e.Customer != "" && e.Customer != customer {
+		return false
+	}
+	if e.Location != "" && e.Location != loc.Name() {
+		return false
+	}
+	local := loc.In(t)
+	wall := time.Date(local.Year(), local.Month(), local.Day(), local.Hour(), local.Minute(), local.Second(), 0, time.UTC)
+	return !wall.Before(e.Start) && wall.Before(e.End)
+}
+
+// Multiplier returns the customer's multiplier in loc at t: that of every
+// entry covering it multiplied together, or 1 when none do.
+func (c Calendar) Multiplier(customer string, loc Location, t time.Time) float64 {
+	m := 1.0
+	for _, e := range c {
+		if e.Covers(customer, loc, t) {
+			m *= e.Multiplier
+		}
+	}
+	return m
+}
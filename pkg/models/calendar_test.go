@@ -0,0 +1,32 @@
+package models_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCalendar_Multiplier(t *testing.T) {
+	ny, err := models.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	cal := models.Calendar{
+		{Name: "Christmas", Start: time.Date(2026, 12, 25, 0, 0, 0, 0, time.UTC), End: time.Date(2026, 12, 26, 0, 0, 0, 0, time.UTC)},
+		{Name: "Training", Customer: "Acme", Location: "America/New_York", Start: time.Date(2026, 12, 24, 14, 0, 0, 0, time.UTC), End: time.Date(2026, 12, 24, 18, 0, 0, 0, time.UTC)},
+		{Name: "Eve", Start: time.Date(2026, 12, 24, 0, 0, 0, 0, time.UTC), End: time.Date(2026, 12, 25, 0, 0, 0, 0, time.UTC), Multiplier: 0.5},
+	}
+
+	// 20:00 UTC is 15:00 in New York
+	afternoon := time.Date(2026, 12, 24, 20, 0, 0, 0, time.UTC)
+	assert.Equal(t, 0.0, cal.Multiplier("Acme", ny, afternoon), "both entries cover Acme in New York")
+	assert.Equal(t, 0.5, cal.Multiplier("Globex", ny, afternoon), "training covers Acme alone")
+	assert.Equal(t, 0.5, cal.Multiplier("Acme", models.NewLocation(time.UTC), afternoon), "training covers New York alone")
+	// 03:00 UTC on the 25th is still the 24th in New York
+	assert.Equal(t, 0.5, cal.Multiplier("Globex", ny, time.Date(2026, 12, 25, 3, 0, 0, 0, time.UTC)))
+	assert.Equal(t, 0.0, cal.Multiplier("Globex", ny, time.Date(2026, 12, 25, 12, 0, 0, 0, time.UTC)))
+	assert.Equal(t, 1.0, cal.Multiplier("Globex", ny, time.Date(2026, 12, 26, 12, 0, 0, 0, time.UTC)))
+	assert.Equal(t, 1.0, models.Calendar(nil).Multiplier("Acme", ny, afternoon))
+}
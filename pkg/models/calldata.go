@@ -0,0 +1,94 @@
+package models
+
+import "time"
+
+// DefaultPriority is the priority NewCallData assigns when none is given.
+const DefaultPriority = PriorityNormal
+
+// CallDataOption configures NewCallData.
+type CallDataOption func(*CallData)
+
+// WithPriority sets the customer's priority.
+func WithPriority(priority Priority) CallDataOption {
+	return func(c *CallData) {
+		c.Priority = priority
+	}
+}
+
+// WithLocation sets the customer's timezone. The start and end times keep
+// their wall-clock values and are re-expressed in loc, so a 9AM window stays
+// 9AM local.
+func WithLocation(loc *time.Location) CallDataOption {
+	return func(c *CallData) {
+		c.Location = NewLocation(loc)
+		c.StartTime = inWallClock(c.StartTime, loc)
+		c.EndTime = inWallClock(c.EndTime, loc)
+	}
+}
+
+// WithSLA sets the service level target: targetPercent of calls answered
+// within thresholdSeconds.
+func WithSLA(targetPercent float64, thresholdSeconds int) CallDataOption {
+	return func(c *CallData) {
+		c.SLATargetPercent = targetPercent
+		c.SLAThresholdSeconds = thresholdSeconds
+	}
+}
+
+// WithSkill sets the agent skill the calls require.
+func WithSkill(skill string) CallDataOption {
+	return func(c *CallData) {
+		c.Skill = skill
+	}
+}
+
+// WithConcurrency sets how many contacts one agent handles at once.
+func WithConcurrency(concurrency int) CallDataOption {
+	return func(c *CallData) {
+		c.Concurrency = concurrency
+	}
+}
+
+// WithHourlyCost sets the cost of one agent-hour.
+func WithHourlyCost(cost float64) CallDataOption {
+	return func(c *CallData) {
+		c.HourlyCost = cost
+	}
+}
+
+// WithWrapUp sets the after-call work that follows each call.
+func WithWrapUp(seconds int) CallDataOption {
+	return func(c *CallData) {
+		c.WrapUpSeconds = seconds
+	}
+}
+
+// NewCallData builds validated CallData for calls arriving between start and
+// end with the given average handle time. The location defaults to that of
+// start and the priority to DefaultPriority; use options to override them.
+func NewCallData(name string, start, end time.Time, calls, avgCallSeconds int, opts ...CallDataOption) (CallData, error) {
+	cd := CallData{
+		CustomerName:               name,
+		AverageCallDurationSeconds: avgCallSeconds,
+		StartTime:                  start,
+		EndTime:                    end,
+		Location:                   NewLocation(start.Location()),
+		NumberOfCalls:              calls,
+		Priority:                   DefaultPriority,
+	}
+	for _, opt := range opts {
+		opt(&cd)
+	}
+	if err := cd.Validate(); err != nil {
+		return CallData{}, err
+	}
+	return cd, nil
+}
+
+// inWallClock returns the time with the same date and clock reading as t in loc.
+func inWallClock(t time.Time, loc *time.Location) time.Time {
+	if loc == nil {
+		return t
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), loc)
+}
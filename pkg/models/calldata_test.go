@@ -0,0 +1,41 @@
+package models_test
+
+import (
+	"testing"
+	"time"
+
+	customerrors "github.com/karthikrao-23/agentscheduler/pkg/errors"
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCallData(t *testing.T) {
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	end := time.Date(2026, 3, 2, 17, 0, 0, 0, time.UTC)
+
+	cd, err := models.NewCallData("Acme", start, end, 400, 300)
+	require.NoError(t, err)
+	assert.Equal(t, "UTC", cd.Location.Name())
+	assert.Equal(t, models.DefaultPriority, cd.Priority)
+
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	require.NoError(t, err)
+	cd, err = models.NewCallData("Tokyo Support", start, end, 400, 300,
+		models.WithLocation(tokyo),
+		models.WithPriority(models.PriorityCritical),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, "Asia/Tokyo", cd.Location.Name())
+	assert.Equal(t, models.PriorityCritical, cd.Priority)
+	assert.Equal(t, 9, cd.StartTime.Hour(), "wall clock is kept in the new location")
+	assert.Equal(t, tokyo, cd.StartTime.Location())
+	assert.Equal(t, 8*time.Hour, cd.Window())
+
+	_, err = models.NewCallData("Acme", start, end, 400, 300, models.WithLocation(nil))
+	assert.ErrorIs(t, err, customerrors.ErrMissingLocation)
+
+	_, err = models.NewCallData("Acme", start, end, 400, 0)
+	assert.ErrorIs(t, err, customerrors.ErrInvalidDuration)
+}
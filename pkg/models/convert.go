@@ -0,0 +1,145 @@
+package models
+
+import (
+	"maps"
+	"slices"
+	"time"
+)
+
+// InLocation returns a copy of s with every requirement moved to the slot
+// its own slot falls in in loc, so that slots total the agents working at
+// the same moment, wherever their customers are. Requirements of a customer
+// without a location, in a schedule whose slots have none, stay where they
+// are. A requirement starting partway through one of loc's slots, as with a
+// half-hour zone offset, goes to the slot it starts in.
+//
+// A generic day is converted with the offsets in effect on date and stays a
+// generic day, with requirements moved past midnight wrapping around it, as
+// the day repeats. A schedule laid out by date gets a day of slots for every
+// date in loc its requirements fall on. Either way, the slots are wall-clock
+// times in loc. Unmet demand moves with the clients it impacts, and each
+// slot's totals are counted again from the requirements that land there.
+// Capacity was shared out in the original slots, so a slot in loc may hold
+// more agents than it allowed.
+func (s *Schedule) InLocation(loc Location, date time.Time) *Schedule {
+	interval := s.Interval()
+	generic := !s.SlotAt(0).HasDate()
+	perDay := int(24 * time.Hour / interval)
+
+	// wall returns the wall-clock time in loc, as UTC, that slot h starts at
+	// for a customer in from
+	wall := func(h int, from Location) time.Time {
+		slot := s.SlotAt(h)
+		if !slot.Location.IsZero() {
+			from = slot.Location
+		}
+		start := slot.Location.In(slot.Start)
+		if generic {
+			start = time.Date(date.Year(), date.Month(), date.Day(), start.Hour(), start.Minute(), 0, 0, time.UTC)
+		}
+		src := from.Time()
+		if src == nil || loc.IsZero() {
+			return start
+		}
+		at := loc.In(time.Date(start.Year(), start.Month(), start.Day(), start.Hour(), start.Minute(), 0, 0, src))
+		return time.Date(at.Year(), at.Month(), at.Day(), at.Hour(), at.Minute(), 0, 0, time.UTC)
+	}
+	midnight := func(t time.Time) time.Time {
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	}
+
+	// Where each requirement and impacted client lands, and the days they
+	// span
+	reqWalls := make([][]time.Time, len(s.HourlyRequirements))
+	var first, last time.Time
+	see := func(t time.Time) {
+		if first.IsZero() || t.Before(first) {
+			first = t
+		}
+		if t.After(last) {
+			last = t
+		}
+	}
+	for h, reqs := range s.HourlyRequirements {
+		reqWalls[h] = make([]time.Time, len(reqs))
+		for i, req := range reqs {
+			reqWalls[h][i] = wall(h, req.Location)
+			see(reqWalls[h][i])
+		}
+	}
+	clientWalls := make([][]time.Time, len(s.UnmetDemands))
+	for i, unmet := range s.UnmetDemands {
+		clientWalls[i] = make([]time.Time, len(unmet.ImpactedClients))
+		for j, client := range unmet.ImpactedClients {
+			clientWalls[i][j] = wall(unmet.Hour, client.Location)
+			see(clientWalls[i][j])
+		}
+	}
+
+	converted := &Schedule{SchemaVersion: s.SchemaVersion}
+	// index returns the slot of converted that wall-clock time t falls in
+	var index func(t time.Time) int
+	if generic {
+		converted.Slots = IntervalSlots(interval)
+		index = func(t time.Time) int {
+			return int(t.Sub(midnight(t)) / interval)
+		}
+	} else if first.IsZero() {
+		// Nothing to move
+		converted.Slots = slices.Clone(s.Slots)
+		index = func(time.Time) int { return 0 }
+	} else {
+		for day := midnight(first); !day.After(last); day = day.AddDate(0, 0, 1) {
+			converted.Slots = append(converted.Slots, DaySlots(day.Year(), day.Month(), day.Day(), interval)...)
+		}
+		start := midnight(first)
+		index = func(t time.Time) int {
+			d := int(midnight(t).Sub(start) / (24 * time.Hour))
+			return d*perDay + int(t.Sub(midnight(t))/interval)
+		}
+	}
+
+	converted.HourlyRequirements = make([][]CustomerRequirement, len(converted.Slots))
+	for h, reqs := range s.HourlyRequirements {
+		for i, req := range reqs {
+			to := index(reqWalls[h][i])
+			converted.HourlyRequirements[to] = append(converted.HourlyRequirements[to], req)
+		}
+	}
+	unmetAt := make(map[int]int)
+	for i, unmet := range s.UnmetDemands {
+		for j, client := range unmet.ImpactedClients {
+			to := index(clientWalls[i][j])
+			k, ok := unmetAt[to]
+			if !ok {
+				k = len(converted.UnmetDemands)
+				unmetAt[to] = k
+				converted.UnmetDemands = append(converted.UnmetDemands, UnmetDemand{Hour: to, Slot: converted.Slots[to]})
+			}
+			u := &converted.UnmetDemands[k]
+			u.ImpactedClients = append(u.ImpactedClients, client)
+			u.UnmetAgents += client.UnmetAgents
+		}
+	}
+	for i := range converted.UnmetDemands {
+		u := &converted.UnmetDemands[i]
+		for _, req := range converted.HourlyRequirements[u.Hour] {
+			u.AllocatedAgents += req.AgentsNeeded
+		}
+		u.TotalDemand = u.AllocatedAgents + u.UnmetAgents
+	}
+	slices.SortStableFunc(converted.UnmetDemands, func(a, b UnmetDemand) int {
+		return a.Hour - b.Hour
+	})
+
+	if s.Metadata != nil {
+		md := *s.Metadata
+		md.Options = maps.Clone(s.Metadata.Options)
+		if md.Options == nil {
+			md.Options = make(map[string]string)
+		}
+		md.Options["output_location"] = loc.Name()
+		converted.Metadata = &md
+	}
+	return converted
+}
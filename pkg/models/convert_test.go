@@ -0,0 +1,65 @@
+package models_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchedule_InLocation(t *testing.T) {
+	pt, err := models.LoadLocation("America/Los_Angeles")
+	require.NoError(t, err)
+	et, err := models.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	// A Monday, when New York is 3 hours ahead of Los Angeles
+	date := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+
+	t.Run("GenericDay", func(t *testing.T) {
+		s := &models.Schedule{
+			Slots:              models.HourlySlots(),
+			HourlyRequirements: make([][]models.CustomerRequirement, 24),
+			UnmetDemands: []models.UnmetDemand{{
+				Hour: 9, TotalDemand: 10, AllocatedAgents: 8, UnmetAgents: 2,
+				ImpactedClients: []models.ImpactedClient{{Name: "Acme", RequestedAgents: 7, AllocatedAgents: 5, UnmetAgents: 2, Location: pt}},
+			}},
+			Metadata: &models.Metadata{RunID: "run-1"},
+		}
+		s.HourlyRequirements[9] = []models.CustomerRequirement{{Name: "Acme", AgentsNeeded: 5, Location: pt}, {Name: "Globex", AgentsNeeded: 3, Location: et}}
+		s.HourlyRequirements[12] = []models.CustomerRequirement{{Name: "Initech", AgentsNeeded: 4, Location: et}}
+		s.HourlyRequirements[22] = []models.CustomerRequirement{{Name: "Night Desk", AgentsNeeded: 2, Location: pt}}
+
+		got := s.InLocation(et, date)
+		require.Equal(t, 24, got.NumSlots())
+		assert.Equal(t, 3, got.AgentsForHour(9))
+		assert.Equal(t, 9, got.AgentsForHour(12), "9AM in Los Angeles is noon in New York")
+		assert.Equal(t, 2, got.AgentsForHour(1), "10PM in Los Angeles wraps to 1AM")
+		assert.Equal(t, 0, got.AgentsForHour(22))
+		require.Len(t, got.UnmetDemands, 1)
+		assert.Equal(t, models.UnmetDemand{
+			Hour: 12, Slot: models.HourSlot(12), TotalDemand: 11, AllocatedAgents: 9, UnmetAgents: 2,
+			ImpactedClients: s.UnmetDemands[0].ImpactedClients,
+		}, got.UnmetDemands[0])
+		assert.Equal(t, "America/New_York", got.Metadata.Options["output_location"])
+		assert.Nil(t, s.Metadata.Options, "the original is unchanged")
+		assert.Equal(t, 8, s.AgentsForHour(9), "the original is unchanged")
+	})
+
+	t.Run("ByDate", func(t *testing.T) {
+		s := &models.Schedule{
+			Slots:              models.DaySlots(2026, 3, 2, time.Hour),
+			HourlyRequirements: make([][]models.CustomerRequirement, 24),
+		}
+		s.HourlyRequirements[22] = []models.CustomerRequirement{{Name: "Night Desk", AgentsNeeded: 2, Location: pt}}
+		s.HourlyRequirements[23] = []models.CustomerRequirement{{Name: "Globex", AgentsNeeded: 3, Location: et}}
+
+		got := s.InLocation(et, date)
+		require.Equal(t, 48, got.NumSlots(), "10PM in Los Angeles is the next day in New York")
+		assert.Equal(t, "2026-03-02 00:00", got.SlotAt(0).String())
+		assert.Equal(t, 3, got.AgentsForHour(23))
+		assert.Equal(t, 2, got.AgentsForHour(25))
+	})
+}
@@ -0,0 +1,203 @@
+package models
+
+import (
+	"slices"
+	"sort"
+)
+
+// ScheduleDiff lists how a schedule's allocations changed relative to another.
+type ScheduleDiff struct {
+	// Changes holds one entry per slot and customer whose allocated or unmet
+	// agents differ, ordered by slot, customer name and location.
+	Changes []RequirementChange
+}
+
+// RequirementChange is the change for one customer in one slot. Customers
+// are identified by name and location.
+type RequirementChange struct {
+	// Hour is the slot index, as in UnmetDemand.Hour
+	Hour     int
+	Slot     TimeSlot
+	Customer string
+	Location Location
+
+	AgentsBefore int
+	AgentsAfter  int
+	UnmetBefore  int
+	UnmetAfter   int
+}
+
+// AgentsDelta returns the change in allocated agents.
+func (c RequirementChange) AgentsDelta() int {
+	return c.AgentsAfter - c.AgentsBefore
+}
+
+// UnmetDelta returns the change in unmet agents.
+func (c RequirementChange) UnmetDelta() int {
+	return c.UnmetAfter - c.UnmetBefore
+}
+
+// CustomerDelta is one customer's change summed over every slot.
+type CustomerDelta struct {
+	Customer string
+	Location Location
+	// Slots is how many slots the customer changed in
+	Slots int
+	// Agents and Unmet are the change in agent-slots allocated and unmet
+	Agents int
+	Unmet  int
+}
+
+// ByCustomer sums the changes per customer, ordered by customer name and
+// location.
+func (d ScheduleDiff) ByCustomer() []CustomerDelta {
+	var deltas []CustomerDelta
+	index := make(map[[2]string]int)
+	for _, c := range d.Changes {
+		k := [2]string{c.Customer, c.Location.Name()}
+		i, ok := index[k]
+		if !ok {
+			i = len(deltas)
+			index[k] = i
+			deltas = append(deltas, CustomerDelta{Customer: c.Customer, Location: c.Location})
+		}
+		deltas[i].Slots++
+		deltas[i].Agents += c.AgentsDelta()
+		deltas[i].Unmet += c.UnmetDelta()
+	}
+	sort.Slice(deltas, func(i, j int) bool {
+		if deltas[i].Customer != deltas[j].Customer {
+			return deltas[i].Customer < deltas[j].Customer
+		}
+		return deltas[i].Location.Name() < deltas[j].Location.Name()
+	})
+	return deltas
+}
+
+// Empty reports whether the diff has no changes.
+func (d ScheduleDiff) Empty() bool {
+	return len(d.Changes) == 0
+}
+
+// Equal reports whether s and other have the same slot layout, requirements
+// and unmet demand. Metadata and SchemaVersion are ignored, so regenerating a
+// schedule from the same input yields an equal schedule.
+func (s *Schedule) Equal(other *Schedule) bool {
+	if s == nil || other == nil {
+		return s == other
+	}
+	if s.NumSlots() != other.NumSlots() {
+		return false
+	}
+	for i := range s.NumSlots() {
+		if !s.SlotAt(i).Equal(other.SlotAt(i)) {
+			return false
+		}
+	}
+	for h := range max(len(s.HourlyRequirements), len(other.HourlyRequirements)) {
+		if !slices.EqualFunc(s.requirementsAt(h), other.requirementsAt(h), requirementEqual) {
+			return false
+		}
+	}
+	return slices.EqualFunc(s.UnmetDemands, other.UnmetDemands, unmetDemandEqual)
+}
+
+// Diff returns the per-slot, per-customer changes from s to other: Before
+// values come from s and After values from other.
+func (s *Schedule) Diff(other *Schedule) ScheduleDiff {
+	type key struct {
+		hour     int
+		customer string
+		location string
+	}
+	changes := make(map[key]*RequirementChange)
+	entry := func(h int, name string, loc Location) *RequirementChange {
+		k := key{h, name, loc.Name()}
+		c, ok := changes[k]
+		if !ok {
+			slot := other.SlotAt(h)
+			if h < s.NumSlots() {
+				slot = s.SlotAt(h)
+			}
+			c = &RequirementChange{Hour: h, Slot: slot, Customer: name, Location: loc}
+			changes[k] = c
+		}
+		return c
+	}
+
+	for h, reqs := range s.HourlyRequirements {
+		for _, req := range reqs {
+			entry(h, req.Name, req.Location).AgentsBefore += req.AgentsNeeded
+		}
+	}
+	for h, reqs := range other.HourlyRequirements {
+		for _, req := range reqs {
+			entry(h, req.Name, req.Location).AgentsAfter += req.AgentsNeeded
+		}
+	}
+	for _, unmet := range s.UnmetDemands {
+		for _, client := range unmet.ImpactedClients {
+			entry(unmet.Hour, client.Name, client.Location).UnmetBefore += client.UnmetAgents
+		}
+	}
+	for _, unmet := range other.UnmetDemands {
+		for _, client := range unmet.ImpactedClients {
+			entry(unmet.Hour, client.Name, client.Location).UnmetAfter += client.UnmetAgents
+		}
+	}
+
+	var diff ScheduleDiff
+	for _, c := range changes {
+		if c.AgentsDelta() != 0 || c.UnmetDelta() != 0 {
+			diff.Changes = append(diff.Changes, *c)
+		}
+	}
+	sort.Slice(diff.Changes, func(i, j int) bool {
+		a, b := diff.Changes[i], diff.Changes[j]
+		if a.Hour != b.Hour {
+			return a.Hour < b.Hour
+		}
+		if a.Customer != b.Customer {
+			return a.Customer < b.Customer
+		}
+		return a.Location.Name() < b.Location.Name()
+	})
+	return diff
+}
+
+// requirementsAt returns the requirements for slot h, or nil when there are
+// none.
+func (s *Schedule) requirementsAt(h int) []CustomerRequirement {
+	if h < 0 || h >= len(s.HourlyRequirements) {
+		return nil
+	}
+	return s.HourlyRequirements[h]
+}
+
+func requirementEqual(a, b CustomerRequirement) bool {
+	return a.Name == b.Name && a.AgentsNeeded == b.AgentsNeeded &&
+		a.Location.Name() == b.Location.Name() &&
+		a.Priority == b.Priority &&
+		a.SLATargetPercent == b.SLATargetPercent &&
+		a.SLAThresholdSeconds == b.SLAThresholdSeconds &&
+		a.Skill == b.Skill &&
+		a.Concurrency == b.Concurrency &&
+		a.HourlyCost == b.HourlyCost &&
+		a.MinAgents == b.MinAgents &&
+		a.CeilingAgents == b.CeilingAgents &&
+		a.FTE == b.FTE
+}
+
+func unmetDemandEqual(a, b UnmetDemand) bool {
+	return a.Hour == b.Hour && a.Slot.Equal(b.Slot) &&
+		a.TotalDemand == b.TotalDemand &&
+		a.AllocatedAgents == b.AllocatedAgents &&
+		a.UnmetAgents == b.UnmetAgents &&
+		slices.EqualFunc(a.ImpactedClients, b.ImpactedClients, func(x, y ImpactedClient) bool {
+			return x.Name == y.Name && x.RequestedAgents == y.RequestedAgents &&
+				x.AllocatedAgents == y.AllocatedAgents && x.UnmetAgents == y.UnmetAgents &&
+				x.Priority == y.Priority && x.Location.Name() == y.Location.Name() &&
+				x.Skill == y.Skill && x.MinAgents == y.MinAgents &&
+				x.CeilingAgents == y.CeilingAgents
+		})
+}
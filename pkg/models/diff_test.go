@@ -0,0 +1,92 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchedule_Equal(t *testing.T) {
+	tests := map[string]struct {
+		modify func(s *models.Schedule)
+		equal  bool
+	}{
+		"identical": {
+			modify: func(s *models.Schedule) {},
+			equal:  true,
+		},
+		"metadata ignored": {
+			modify: func(s *models.Schedule) { s.Metadata = &models.Metadata{RunID: "other"} },
+			equal:  true,
+		},
+		"explicit default slots": {
+			modify: func(s *models.Schedule) { s.Slots = models.HourlySlots() },
+			equal:  true,
+		},
+		"agents changed": {
+			modify: func(s *models.Schedule) { s.HourlyRequirements[9][0].AgentsNeeded++ },
+			equal:  false,
+		},
+		"requirement added": {
+			modify: func(s *models.Schedule) {
+				s.HourlyRequirements[3] = []models.CustomerRequirement{{Name: "Initech", AgentsNeeded: 1}}
+			},
+			equal: false,
+		},
+		"unmet changed": {
+			modify: func(s *models.Schedule) { s.UnmetDemands[0].ImpactedClients[1].UnmetAgents++ },
+			equal:  false,
+		},
+		"slots changed": {
+			modify: func(s *models.Schedule) { s.Slots = models.HourlySlots()[:12] },
+			equal:  false,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			other := newTestSchedule()
+			tt.modify(other)
+			assert.Equal(t, tt.equal, newTestSchedule().Equal(other))
+			assert.Equal(t, tt.equal, other.Equal(newTestSchedule()))
+		})
+	}
+}
+
+func TestSchedule_Diff(t *testing.T) {
+	before := newTestSchedule()
+	after := newTestSchedule()
+	after.HourlyRequirements[9][1].AgentsNeeded = 3
+	after.HourlyRequirements[10] = append(after.HourlyRequirements[10], models.CustomerRequirement{Name: "Globex", AgentsNeeded: 2, Priority: 2})
+	after.UnmetDemands[0].ImpactedClients = after.UnmetDemands[0].ImpactedClients[:1]
+	after.HourlyRequirements[9] = after.HourlyRequirements[9][1:]
+
+	diff := before.Diff(after)
+	require.Len(t, diff.Changes, 3)
+
+	assert.Equal(t, 9, diff.Changes[0].Hour)
+	assert.Equal(t, "Acme", diff.Changes[0].Customer)
+	assert.Equal(t, -4, diff.Changes[0].AgentsDelta())
+
+	assert.Equal(t, "Globex", diff.Changes[1].Customer)
+	assert.Equal(t, 1, diff.Changes[1].AgentsDelta())
+	assert.True(t, diff.Changes[1].Slot.Equal(models.HourSlot(9)))
+
+	assert.Equal(t, 10, diff.Changes[2].Hour)
+	assert.Equal(t, "Globex", diff.Changes[2].Customer)
+	assert.Equal(t, 2, diff.Changes[2].AgentsDelta())
+	assert.Equal(t, -2, diff.Changes[2].UnmetDelta())
+
+	assert.True(t, before.Diff(newTestSchedule()).Empty())
+
+	byCustomer := diff.ByCustomer()
+	require.Len(t, byCustomer, 2)
+	assert.Equal(t, models.CustomerDelta{Customer: "Acme", Slots: 1, Agents: -4}, byCustomer[0])
+	assert.Equal(t, "Globex", byCustomer[1].Customer)
+	assert.Equal(t, 2, byCustomer[1].Slots)
+	assert.Equal(t, 3, byCustomer[1].Agents)
+	assert.Equal(t, -2, byCustomer[1].Unmet)
+}
@@ -0,0 +1,77 @@
+package models
+
+import "iter"
+
+// Entry is one customer's allocation in one slot, flattened out of a
+// Schedule's per-slot requirements and unmet demand.
+type Entry struct {
+	// Hour is the slot index, as in UnmetDemand.Hour
+	Hour     int
+	Slot     TimeSlot
+	Customer string
+	Location Location
+	Priority Priority
+	// Agents is the number of agents allocated
+	Agents int
+	// Unmet is the number of agents demanded but not allocated
+	Unmet int
+}
+
+// Entries returns an iterator over every customer allocation in the
+// schedule, slot by slot. Within a slot, requirements come first in schedule
+// order, followed by customers whose demand went entirely unmet. A customer
+// with a requirement and unmet demand in the same slot yields one entry.
+func (s *Schedule) Entries() iter.Seq[Entry] {
+	return func(yield func(Entry) bool) {
+		type key struct {
+			hour     int
+			customer string
+			location string
+		}
+		unmet := make(map[key]int)
+		impacted := make(map[int][]Entry)
+		for _, u := range s.UnmetDemands {
+			for _, client := range u.ImpactedClients {
+				unmet[key{u.Hour, client.Name, client.Location.Name()}] += client.UnmetAgents
+				impacted[u.Hour] = append(impacted[u.Hour], Entry{
+					Hour:     u.Hour,
+					Slot:     s.SlotAt(u.Hour),
+					Customer: client.Name,
+					Location: client.Location,
+					Priority: client.Priority,
+				})
+			}
+		}
+
+		for h := range max(s.NumSlots(), len(s.HourlyRequirements)) {
+			for _, req := range s.requirementsAt(h) {
+				k := key{h, req.Name, req.Location.Name()}
+				e := Entry{
+					Hour:     h,
+					Slot:     s.SlotAt(h),
+					Customer: req.Name,
+					Location: req.Location,
+					Priority: req.Priority,
+					Agents:   req.AgentsNeeded,
+					Unmet:    unmet[k],
+				}
+				// Attribute unmet agents to the first matching requirement only
+				delete(unmet, k)
+				if !yield(e) {
+					return
+				}
+			}
+			for _, e := range impacted[h] {
+				k := key{h, e.Customer, e.Location.Name()}
+				var ok bool
+				if e.Unmet, ok = unmet[k]; !ok {
+					continue
+				}
+				delete(unmet, k)
+				if !yield(e) {
+					return
+				}
+			}
+		}
+	}
+}
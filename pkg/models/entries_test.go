@@ -0,0 +1,42 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchedule_Entries(t *testing.T) {
+	s := newTestSchedule()
+
+	var got []models.Entry
+	for e := range s.Entries() {
+		got = append(got, e)
+	}
+
+	assert.Equal(t, []models.Entry{
+		{Hour: 9, Slot: models.HourSlot(9), Customer: "Acme", Priority: 1, Agents: 4},
+		{Hour: 9, Slot: models.HourSlot(9), Customer: "Globex", Priority: 2, Agents: 2},
+		{Hour: 10, Slot: models.HourSlot(10), Customer: "Acme", Priority: 1, Agents: 5, Unmet: 1},
+		{Hour: 10, Slot: models.HourSlot(10), Customer: "Globex", Priority: 2, Unmet: 2},
+	}, got)
+
+	// Entries account for every allocated and unmet agent
+	agents, unmet := 0, 0
+	for e := range s.Entries() {
+		agents += e.Agents
+		unmet += e.Unmet
+	}
+	assert.Equal(t, s.TotalAgents(), agents)
+	assert.Equal(t, s.TotalUnmet(), unmet)
+
+	// Stopping early is honoured
+	n := 0
+	for range s.Entries() {
+		n++
+		break
+	}
+	assert.Equal(t, 1, n)
+}
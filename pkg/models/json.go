@@ -0,0 +1,307 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// The JSON forms below carry locations as IANA names (see Location) and times
+// as RFC3339, so models survive a round trip through storage or an API
+// payload.
+
+type callDataJSON struct {
+	CustomerName               string    `json:"customer_name"`
+	AverageCallDurationSeconds int       `json:"average_call_duration_seconds"`
+	StartTime                  time.Time `json:"start_time"`
+	EndTime                    time.Time `json:"end_time"`
+	Location                   Location  `json:"location,omitzero"`
+	NumberOfCalls              int       `json:"number_of_calls"`
+	Priority                   Priority  `json:"priority"`
+	SLATargetPercent           float64   `json:"sla_target_percent,omitempty"`
+	SLAThresholdSeconds        int       `json:"sla_threshold_seconds,omitempty"`
+	Skill                      string    `json:"skill,omitempty"`
+	Concurrency                int       `json:"concurrency,omitempty"`
+	HourlyCost                 float64   `json:"hourly_cost,omitempty"`
+	MinAgents                  int       `json:"min_agents,omitempty"`
+	FloorAgents                int       `json:"floor_agents,omitempty"`
+	CeilingAgents              int       `json:"ceiling_agents,omitempty"`
+	WrapUpSeconds              int       `json:"wrap_up_seconds,omitempty"`
+}
+
+type customerRequirementJSON struct {
+	Name                string   `json:"name"`
+	AgentsNeeded        int      `json:"agents_needed"`
+	Location            Location `json:"location,omitzero"`
+	Priority            Priority `json:"priority"`
+	SLATargetPercent    float64  `json:"sla_target_percent,omitempty"`
+	SLAThresholdSeconds int      `json:"sla_threshold_seconds,omitempty"`
+	Skill               string   `json:"skill,omitempty"`
+	Concurrency         int      `json:"concurrency,omitempty"`
+	HourlyCost          float64  `json:"hourly_cost,omitempty"`
+	MinAgents           int      `json:"min_agents,omitempty"`
+	CeilingAgents       int      `json:"ceiling_agents,omitempty"`
+	FTE                 float64  `json:"fte,omitempty"`
+}
+
+type timeSlotJSON struct {
+	Start    time.Time `json:"start"`
+	Duration string    `json:"duration"`
+	Location Location  `json:"location,omitzero"`
+}
+
+type scheduleJSON struct {
+	SchemaVersion      int                     `json:"schema_version"`
+	Slots              []TimeSlot              `json:"slots,omitempty"`
+	HourlyRequirements [][]CustomerRequirement `json:"hourly_requirements"`
+	UnmetDemands       []unmetDemandJSON       `json:"unmet_demands"`
+	Metadata           *Metadata               `json:"metadata,omitempty"`
+}
+
+type unmetDemandJSON struct {
+	Hour            int                  `json:"hour"`
+	Slot            TimeSlot             `json:"slot"`
+	TotalDemand     int                  `json:"total_demand"`
+	AllocatedAgents int                  `json:"allocated_agents"`
+	UnmetAgents     int                  `json:"unmet_agents"`
+	ImpactedClients []impactedClientJSON `json:"impacted_clients"`
+}
+
+// impactedClientJSON is only used inside a Schedule; ImpactedClient keeps its
+// default encoding because the formatter's JSON output embeds it directly.
+type impactedClientJSON struct {
+	Name            string   `json:"name"`
+	RequestedAgents int      `json:"requested_agents"`
+	AllocatedAgents int      `json:"allocated_agents"`
+	UnmetAgents     int      `json:"unmet_agents"`
+	Priority        Priority `json:"priority"`
+	Location        Location `json:"location,omitzero"`
+	Skill           string   `json:"skill,omitempty"`
+	MinAgents       int      `json:"min_agents,omitempty"`
+	CeilingAgents   int      `json:"ceiling_agents,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s TimeSlot) MarshalJSON() ([]byte, error) {
+	return json.Marshal(timeSlotJSON{
+		Start:    s.Start,
+		Duration: s.Duration.String(),
+		Location: s.Location,
+	})
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *TimeSlot) UnmarshalJSON(b []byte) error {
+	var v timeSlotJSON
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	d, err := time.ParseDuration(v.Duration)
+	if err != nil {
+		return fmt.Errorf("invalid slot duration %q: %w", v.Duration, err)
+	}
+	start := v.Start.UTC()
+	if !v.Location.IsZero() {
+		start = v.Location.In(v.Start)
+	}
+	*s = TimeSlot{Start: start, Duration: d, Location: v.Location}
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (c CallData) MarshalJSON() ([]byte, error) {
+	return json.Marshal(callDataJSON{
+		CustomerName:               c.CustomerName,
+		AverageCallDurationSeconds: c.AverageCallDurationSeconds,
+		StartTime:                  c.StartTime,
+		EndTime:                    c.EndTime,
+		Location:                   c.Location,
+		NumberOfCalls:              c.NumberOfCalls,
+		Priority:                   c.Priority,
+		SLATargetPercent:           c.SLATargetPercent,
+		SLAThresholdSeconds:        c.SLAThresholdSeconds,
+		Skill:                      c.Skill,
+		Concurrency:                c.Concurrency,
+		HourlyCost:                 c.HourlyCost,
+		MinAgents:                  c.MinAgents,
+		FloorAgents:                c.FloorAgents,
+		CeilingAgents:              c.CeilingAgents,
+		WrapUpSeconds:              c.WrapUpSeconds,
+	})
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Start and end times are
+// converted into the decoded location.
+func (c *CallData) UnmarshalJSON(b []byte) error {
+	var v callDataJSON
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	*c = CallData{
+		CustomerName:               v.CustomerName,
+		AverageCallDurationSeconds: v.AverageCallDurationSeconds,
+		StartTime:                  v.Location.In(v.StartTime),
+		EndTime:                    v.Location.In(v.EndTime),
+		Location:                   v.Location,
+		NumberOfCalls:              v.NumberOfCalls,
+		Priority:                   v.Priority,
+		SLATargetPercent:           v.SLATargetPercent,
+		SLAThresholdSeconds:        v.SLAThresholdSeconds,
+		Skill:                      v.Skill,
+		Concurrency:                v.Concurrency,
+		HourlyCost:                 v.HourlyCost,
+		MinAgents:                  v.MinAgents,
+		FloorAgents:                v.FloorAgents,
+		CeilingAgents:              v.CeilingAgents,
+		WrapUpSeconds:              v.WrapUpSeconds,
+	}
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (r CustomerRequirement) MarshalJSON() ([]byte, error) {
+	return json.Marshal(customerRequirementJSON{
+		Name:         r.Name,
+		AgentsNeeded: r.AgentsNeeded,
+		Location:     r.Location,
+		Priority:     r.Priority,
+
+		SLATargetPercent:    r.SLATargetPercent,
+		SLAThresholdSeconds: r.SLAThresholdSeconds,
+		Skill:               r.Skill,
+		Concurrency:         r.Concurrency,
+		HourlyCost:          r.HourlyCost,
+		MinAgents:           r.MinAgents,
+		CeilingAgents:       r.CeilingAgents,
+		FTE:                 r.FTE,
+	})
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (r *CustomerRequirement) UnmarshalJSON(b []byte) error {
+	var v customerRequirementJSON
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	*r = CustomerRequirement{
+		Name:         v.Name,
+		AgentsNeeded: v.AgentsNeeded,
+		Location:     v.Location,
+		Priority:     v.Priority,
+
+		SLATargetPercent:    v.SLATargetPercent,
+		SLAThresholdSeconds: v.SLAThresholdSeconds,
+		Skill:               v.Skill,
+		Concurrency:         v.Concurrency,
+		HourlyCost:          v.HourlyCost,
+		MinAgents:           v.MinAgents,
+		CeilingAgents:       v.CeilingAgents,
+		FTE:                 v.FTE,
+	}
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s Schedule) MarshalJSON() ([]byte, error) {
+	version := s.SchemaVersion
+	if version == 0 {
+		version = CurrentSchemaVersion
+	}
+	v := scheduleJSON{
+		SchemaVersion:      version,
+		Slots:              s.Slots,
+		HourlyRequirements: s.HourlyRequirements,
+		UnmetDemands:       make([]unmetDemandJSON, len(s.UnmetDemands)),
+		Metadata:           s.Metadata,
+	}
+	for i, u := range s.UnmetDemands {
+		clients := make([]impactedClientJSON, len(u.ImpactedClients))
+		for j, client := range u.ImpactedClients {
+			clients[j] = impactedClientJSON{
+				Name:            client.Name,
+				RequestedAgents: client.RequestedAgents,
+				AllocatedAgents: client.AllocatedAgents,
+				UnmetAgents:     client.UnmetAgents,
+				Priority:        client.Priority,
+				Location:        client.Location,
+				Skill:           client.Skill,
+				MinAgents:       client.MinAgents,
+				CeilingAgents:   client.CeilingAgents,
+			}
+		}
+		v.UnmetDemands[i] = unmetDemandJSON{
+			Hour:            u.Hour,
+			Slot:            u.Slot,
+			TotalDemand:     u.TotalDemand,
+			AllocatedAgents: u.AllocatedAgents,
+			UnmetAgents:     u.UnmetAgents,
+			ImpactedClients: clients,
+		}
+	}
+	return json.Marshal(v)
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Schedules written with an older
+// schema version are migrated to CurrentSchemaVersion.
+func (s *Schedule) UnmarshalJSON(b []byte) error {
+	var v scheduleJSON
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	unmet := make([]UnmetDemand, len(v.UnmetDemands))
+	for i, u := range v.UnmetDemands {
+		clients := make([]ImpactedClient, len(u.ImpactedClients))
+		for j, client := range u.ImpactedClients {
+			clients[j] = ImpactedClient{
+				Name:            client.Name,
+				RequestedAgents: client.RequestedAgents,
+				AllocatedAgents: client.AllocatedAgents,
+				UnmetAgents:     client.UnmetAgents,
+				Priority:        client.Priority,
+				Location:        client.Location,
+				Skill:           client.Skill,
+				MinAgents:       client.MinAgents,
+				CeilingAgents:   client.CeilingAgents,
+			}
+		}
+		unmet[i] = UnmetDemand{
+			Hour:            u.Hour,
+			Slot:            u.Slot,
+			TotalDemand:     u.TotalDemand,
+			AllocatedAgents: u.AllocatedAgents,
+			UnmetAgents:     u.UnmetAgents,
+			ImpactedClients: clients,
+		}
+	}
+	*s = Schedule{
+		SchemaVersion:      v.SchemaVersion,
+		Slots:              v.Slots,
+		HourlyRequirements: v.HourlyRequirements,
+		UnmetDemands:       unmet,
+		Metadata:           v.Metadata,
+	}
+	return s.Migrate()
+}
+
+// Save writes s to w in its canonical JSON form, indented, with fields and
+// option names in a fixed order, so that saving a loaded schedule writes the
+// same bytes and saved runs diff line by line. Load reads it back.
+func (s *Schedule) Save(w io.Writer) error {
+	b, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(b, '\n'))
+	return err
+}
+
+// Load reads a schedule written by Save, or any JSON form of one, migrating
+// it from an older schema version.
+func Load(r io.Reader) (*Schedule, error) {
+	var s Schedule
+	if err := json.NewDecoder(r).Decode(&s); err != nil {
+		return nil, fmt.Errorf("decoding schedule: %w", err)
+	}
+	return &s, nil
+}
@@ -0,0 +1,162 @@
+package models_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	customerrors "github.com/karthikrao-23/agentscheduler/pkg/errors"
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCallData_JSON(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	require.NoError(t, err)
+
+	in := models.CallData{
+		CustomerName:               "Tokyo Support",
+		AverageCallDurationSeconds: 300,
+		StartTime:                  time.Date(2026, 3, 2, 9, 0, 0, 0, tokyo),
+		EndTime:                    time.Date(2026, 3, 2, 17, 30, 0, 0, tokyo),
+		Location:                   models.NewLocation(tokyo),
+		NumberOfCalls:              1200,
+		Priority:                   1,
+	}
+
+	b, err := json.Marshal(in)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"customer_name": "Tokyo Support",
+		"average_call_duration_seconds": 300,
+		"start_time": "2026-03-02T09:00:00+09:00",
+		"end_time": "2026-03-02T17:30:00+09:00",
+		"location": "Asia/Tokyo",
+		"number_of_calls": 1200,
+		"priority": 1
+	}`, string(b))
+
+	var out models.CallData
+	require.NoError(t, json.Unmarshal(b, &out))
+	assert.Equal(t, "Asia/Tokyo", out.Location.String())
+	assert.True(t, in.StartTime.Equal(out.StartTime))
+	assert.Equal(t, 9, out.StartTime.Hour(), "times are decoded in the customer's location")
+}
+
+func TestCallData_UnmarshalJSON_InvalidLocation(t *testing.T) {
+	var out models.CallData
+	err := json.Unmarshal([]byte(`{"location": "Mars/Olympus"}`), &out)
+	assert.ErrorContains(t, err, `invalid location "Mars/Olympus"`)
+}
+
+func TestSchedule_JSONRoundTrip(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	reqs := make([][]models.CustomerRequirement, 24)
+	reqs[9] = []models.CustomerRequirement{
+		{Name: "Acme", AgentsNeeded: 4, Location: models.NewLocation(ny), Priority: 1},
+		{Name: "NoSite", AgentsNeeded: 1, Priority: 2},
+	}
+	in := models.Schedule{
+		Slots: models.HourlySlots(),
+		Metadata: &models.Metadata{
+			GeneratedAt: time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC),
+			RunID:       "run-1",
+			InputHash:   "abc123",
+			Options:     map[string]string{"capacity": "10"},
+			ToolVersion: "v1.2.3",
+		},
+		HourlyRequirements: reqs,
+		UnmetDemands: []models.UnmetDemand{
+			{
+				Hour:            9,
+				Slot:            models.HourSlot(9),
+				TotalDemand:     7,
+				AllocatedAgents: 5,
+				UnmetAgents:     2,
+				ImpactedClients: []models.ImpactedClient{
+					{Name: "NoSite", RequestedAgents: 3, AllocatedAgents: 1, UnmetAgents: 2, Priority: 2, Location: models.NewLocation(ny)},
+				},
+			},
+		},
+	}
+
+	b, err := json.Marshal(in)
+	require.NoError(t, err)
+	assert.Contains(t, string(b), `{"name":"Acme","agents_needed":4,"location":"America/New_York","priority":1}`)
+	assert.Contains(t, string(b), `{"name":"NoSite","agents_needed":1,"priority":2}`)
+
+	var out models.Schedule
+	require.NoError(t, json.Unmarshal(b, &out))
+	assert.Len(t, out.HourlyRequirements, 24)
+	assert.Equal(t, "America/New_York", out.HourlyRequirements[9][0].Location.String())
+	assert.True(t, out.HourlyRequirements[9][1].Location.IsZero())
+	require.Len(t, out.Slots, 24)
+	assert.True(t, out.Slots[9].Equal(models.HourSlot(9)))
+	require.Len(t, out.UnmetDemands, 1)
+	assert.True(t, out.UnmetDemands[0].Slot.Equal(models.HourSlot(9)))
+	assert.Equal(t, in.UnmetDemands[0].UnmetAgents, out.UnmetDemands[0].UnmetAgents)
+	assert.Equal(t, "America/New_York", out.UnmetDemands[0].ImpactedClients[0].Location.String())
+	assert.Equal(t, in.Metadata, out.Metadata)
+}
+
+func TestSchedule_SaveLoad(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	reqs := make([][]models.CustomerRequirement, 24)
+	reqs[9] = []models.CustomerRequirement{{Name: "Acme", AgentsNeeded: 4, Location: models.NewLocation(ny), Priority: 1, FTE: 3.5}}
+	in := &models.Schedule{
+		SchemaVersion:      models.CurrentSchemaVersion,
+		Slots:              models.HourlySlots(),
+		HourlyRequirements: reqs,
+		UnmetDemands: []models.UnmetDemand{{
+			Hour: 9, Slot: models.HourSlot(9), TotalDemand: 6, AllocatedAgents: 4, UnmetAgents: 2,
+			ImpactedClients: []models.ImpactedClient{{Name: "Acme", RequestedAgents: 6, AllocatedAgents: 4, UnmetAgents: 2, Priority: 1, Location: models.NewLocation(ny)}},
+		}},
+		Metadata: &models.Metadata{
+			GeneratedAt: time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC),
+			RunID:       "run-1",
+			Options:     map[string]string{"utilization": "0.85", "capacity": "4", "fte": "true"},
+		},
+	}
+
+	var saved bytes.Buffer
+	require.NoError(t, in.Save(&saved))
+	out, err := models.Load(bytes.NewReader(saved.Bytes()))
+	require.NoError(t, err)
+	assert.Equal(t, 3.5, out.HourlyRequirements[9][0].FTE)
+	assert.Equal(t, in.UnmetDemands[0].ImpactedClients, out.UnmetDemands[0].ImpactedClients)
+	assert.Equal(t, in.Metadata, out.Metadata)
+
+	var again bytes.Buffer
+	require.NoError(t, out.Save(&again))
+	assert.Equal(t, saved.String(), again.String(), "a loaded schedule saves the same bytes")
+
+	t.Run("Invalid", func(t *testing.T) {
+		tests := map[string]struct {
+			in          string
+			unsupported bool
+		}{
+			"NewerVersion":    {in: `{"schema_version": 99, "hourly_requirements": []}`, unsupported: true},
+			"NegativeVersion": {in: `{"schema_version": -1, "hourly_requirements": []}`, unsupported: true},
+			"VersionNotInt":   {in: `{"schema_version": "2", "hourly_requirements": []}`},
+			"Truncated":       {in: `{"schema_version": 2, "hourly_requirements": [`},
+		}
+		for name, tt := range tests {
+			t.Run(name, func(t *testing.T) {
+				s, err := models.Load(strings.NewReader(tt.in))
+				require.Error(t, err)
+				assert.Nil(t, s)
+				if tt.unsupported {
+					assert.ErrorIs(t, err, customerrors.ErrUnsupportedSchemaVersion)
+				}
+			})
+		}
+	})
+}
@@ -0,0 +1,87 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// Location is a customer's timezone or site, identified by its IANA name.
+//
+// The zero Location is unset: it has an empty Name, leaves times unchanged
+// in In, and encodes as an empty string, so models never have to guard
+// against a nil *time.Location.
+type Location struct {
+	loc *time.Location
+}
+
+// NewLocation wraps loc; a nil loc gives the zero Location.
+func NewLocation(loc *time.Location) Location {
+	return Location{loc: loc}
+}
+
+// LoadLocation returns the Location with the given IANA name. An empty name
+// gives the zero Location.
+func LoadLocation(name string) (Location, error) {
+	if name == "" {
+		return Location{}, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return Location{}, fmt.Errorf("invalid location %q: %w", name, err)
+	}
+	return Location{loc: loc}, nil
+}
+
+// IsZero reports whether the location is unset.
+func (l Location) IsZero() bool {
+	return l.loc == nil
+}
+
+// Name returns the IANA name, or "" when unset.
+func (l Location) Name() string {
+	if l.loc == nil {
+		return ""
+	}
+	return l.loc.String()
+}
+
+// String returns the IANA name, or "unknown" when unset.
+func (l Location) String() string {
+	if l.loc == nil {
+		return "unknown"
+	}
+	return l.loc.String()
+}
+
+// Time returns the underlying *time.Location, which is nil when unset.
+func (l Location) Time() *time.Location {
+	return l.loc
+}
+
+// In returns t in the location, or t unchanged when unset.
+func (l Location) In(t time.Time) time.Time {
+	if l.loc == nil {
+		return t
+	}
+	return t.In(l.loc)
+}
+
+// Equal reports whether both locations have the same name.
+func (l Location) Equal(other Location) bool {
+	return l.Name() == other.Name()
+}
+
+// MarshalText implements encoding.TextMarshaler, encoding the IANA name.
+func (l Location) MarshalText() ([]byte, error) {
+	return []byte(l.Name()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (l *Location) UnmarshalText(b []byte) error {
+	loc, err := LoadLocation(string(b))
+	if err != nil {
+		return err
+	}
+	*l = loc
+	return nil
+}
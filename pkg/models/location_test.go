@@ -0,0 +1,55 @@
+package models_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocation(t *testing.T) {
+	tokyo, err := models.LoadLocation("Asia/Tokyo")
+	require.NoError(t, err)
+	assert.False(t, tokyo.IsZero())
+	assert.Equal(t, "Asia/Tokyo", tokyo.Name())
+	assert.Equal(t, "Asia/Tokyo", tokyo.String())
+	assert.True(t, tokyo.Equal(models.NewLocation(tokyo.Time())))
+
+	noon := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	assert.Equal(t, 21, tokyo.In(noon).Hour())
+
+	var unset models.Location
+	assert.True(t, unset.IsZero())
+	assert.Equal(t, "", unset.Name())
+	assert.Equal(t, "unknown", unset.String())
+	assert.Nil(t, unset.Time())
+	assert.Equal(t, noon, unset.In(noon))
+	assert.True(t, unset.Equal(models.NewLocation(nil)))
+	assert.False(t, unset.Equal(tokyo))
+
+	_, err = models.LoadLocation("Mars/Olympus_Mons")
+	assert.Error(t, err)
+}
+
+func TestLocation_JSON(t *testing.T) {
+	client := models.ImpactedClient{Name: "Acme", Location: func() models.Location { l, _ := models.LoadLocation("Asia/Tokyo"); return l }()}
+
+	b, err := json.Marshal(client)
+	require.NoError(t, err)
+	assert.Contains(t, string(b), `"Location":"Asia/Tokyo"`)
+
+	var out models.ImpactedClient
+	require.NoError(t, json.Unmarshal(b, &out))
+	assert.Equal(t, "Asia/Tokyo", out.Location.Name())
+
+	// An unset location is omitted rather than encoded as a zone
+	b, err = json.Marshal(models.ImpactedClient{Name: "Acme"})
+	require.NoError(t, err)
+	assert.NotContains(t, string(b), "Location")
+
+	assert.Error(t, json.Unmarshal([]byte(`{"Location":"Mars/Olympus_Mons"}`), &out))
+}
@@ -0,0 +1,88 @@
+package models
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+
+	"github.com/karthikrao-23/agentscheduler/pkg/errors"
+)
+
+// Clone returns a deep copy of s that can be modified without affecting s.
+func (s *Schedule) Clone() *Schedule {
+	if s == nil {
+		return nil
+	}
+	c := &Schedule{
+		SchemaVersion:      s.SchemaVersion,
+		Slots:              slices.Clone(s.Slots),
+		HourlyRequirements: make([][]CustomerRequirement, len(s.HourlyRequirements)),
+		UnmetDemands:       make([]UnmetDemand, len(s.UnmetDemands)),
+	}
+	for h, reqs := range s.HourlyRequirements {
+		c.HourlyRequirements[h] = slices.Clone(reqs)
+	}
+	for i, unmet := range s.UnmetDemands {
+		c.UnmetDemands[i] = unmet
+		c.UnmetDemands[i].ImpactedClients = slices.Clone(unmet.ImpactedClients)
+	}
+	if s.Metadata != nil {
+		md := *s.Metadata
+		md.Options = maps.Clone(s.Metadata.Options)
+		c.Metadata = &md
+	}
+	return c
+}
+
+// Merge combines s and other, e.g. the schedules of separate per-region runs,
+// into a new schedule. Both must use the same slot layout. Requirements are
+// concatenated per slot, with s's first, and unmet demand in the same slot is
+// summed. Neither input is modified. The result has no Metadata, as it was
+// not produced by a single run.
+func (s *Schedule) Merge(other *Schedule) (*Schedule, error) {
+	if s.NumSlots() != other.NumSlots() {
+		return nil, fmt.Errorf("%w: %d slots and %d slots", errors.ErrIncompatibleSchedules, s.NumSlots(), other.NumSlots())
+	}
+	for i := range s.NumSlots() {
+		if !s.SlotAt(i).Equal(other.SlotAt(i)) {
+			return nil, fmt.Errorf("%w: slot %d is %s and %s", errors.ErrIncompatibleSchedules, i, s.SlotAt(i), other.SlotAt(i))
+		}
+	}
+
+	merged := s.Clone()
+	merged.SchemaVersion = CurrentSchemaVersion
+	merged.Metadata = nil
+	if len(merged.Slots) == 0 {
+		merged.Slots = slices.Clone(other.Slots)
+	}
+	for h, reqs := range other.HourlyRequirements {
+		for len(merged.HourlyRequirements) <= h {
+			merged.HourlyRequirements = append(merged.HourlyRequirements, nil)
+		}
+		merged.HourlyRequirements[h] = append(merged.HourlyRequirements[h], reqs...)
+	}
+
+	byHour := make(map[int]int, len(merged.UnmetDemands))
+	for i, unmet := range merged.UnmetDemands {
+		byHour[unmet.Hour] = i
+	}
+	for _, unmet := range other.UnmetDemands {
+		i, ok := byHour[unmet.Hour]
+		if !ok {
+			unmet.ImpactedClients = slices.Clone(unmet.ImpactedClients)
+			byHour[unmet.Hour] = len(merged.UnmetDemands)
+			merged.UnmetDemands = append(merged.UnmetDemands, unmet)
+			continue
+		}
+		m := &merged.UnmetDemands[i]
+		m.TotalDemand += unmet.TotalDemand
+		m.AllocatedAgents += unmet.AllocatedAgents
+		m.UnmetAgents += unmet.UnmetAgents
+		m.ImpactedClients = append(m.ImpactedClients, unmet.ImpactedClients...)
+	}
+	sort.SliceStable(merged.UnmetDemands, func(i, j int) bool {
+		return merged.UnmetDemands[i].Hour < merged.UnmetDemands[j].Hour
+	})
+	return merged, nil
+}
@@ -0,0 +1,71 @@
+package models_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	customerrors "github.com/karthikrao-23/agentscheduler/pkg/errors"
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchedule_Clone(t *testing.T) {
+	s := newTestSchedule()
+	s.Slots = models.HourlySlots()
+	s.Metadata = &models.Metadata{RunID: "run-1", Options: map[string]string{"capacity": "10"}}
+
+	c := s.Clone()
+	require.Equal(t, s, c)
+
+	// Changes to the copy must not reach the original
+	c.HourlyRequirements[9][0].AgentsNeeded = 99
+	c.UnmetDemands[0].ImpactedClients[0].UnmetAgents = 99
+	c.Slots[0] = models.HourSlot(5)
+	c.Metadata.Options["capacity"] = "99"
+	assert.Equal(t, 4, s.HourlyRequirements[9][0].AgentsNeeded)
+	assert.Equal(t, 1, s.UnmetDemands[0].ImpactedClients[0].UnmetAgents)
+	assert.True(t, s.Slots[0].Equal(models.HourSlot(0)))
+	assert.Equal(t, "10", s.Metadata.Options["capacity"])
+
+	assert.Nil(t, (*models.Schedule)(nil).Clone())
+}
+
+func TestSchedule_Merge(t *testing.T) {
+	a := newTestSchedule()
+	b := newTestSchedule()
+	b.HourlyRequirements[11] = []models.CustomerRequirement{{Name: "Initech", AgentsNeeded: 1, Priority: 3}}
+	b.UnmetDemands = append(b.UnmetDemands, models.UnmetDemand{Hour: 8, TotalDemand: 1, UnmetAgents: 1})
+
+	merged, err := a.Merge(b)
+	require.NoError(t, err)
+	assert.Equal(t, a.TotalAgents()+b.TotalAgents(), merged.TotalAgents())
+	assert.Equal(t, a.TotalUnmet()+b.TotalUnmet(), merged.TotalUnmet())
+	assert.Len(t, merged.HourlyRequirements[9], 4)
+	assert.Equal(t, 1, merged.AgentsForCustomer("Initech"))
+	require.Len(t, merged.UnmetDemands, 2)
+	assert.Equal(t, 8, merged.UnmetDemands[0].Hour)
+	assert.Equal(t, 16, merged.UnmetDemands[1].TotalDemand)
+	assert.Len(t, merged.UnmetDemands[1].ImpactedClients, 4)
+	assert.Nil(t, merged.Metadata)
+
+	// Inputs are left untouched
+	assert.Equal(t, newTestSchedule(), a)
+	assert.Len(t, b.UnmetDemands[0].ImpactedClients, 2)
+}
+
+func TestSchedule_Merge_IncompatibleSlots(t *testing.T) {
+	a := newTestSchedule()
+	b := newTestSchedule()
+	b.Slots = models.HourlySlots()
+	b.Slots[3] = models.TimeSlot{Start: time.Date(2024, 3, 1, 3, 0, 0, 0, time.UTC), Duration: time.Hour}
+
+	_, err := a.Merge(b)
+	assert.True(t, errors.Is(err, customerrors.ErrIncompatibleSchedules))
+
+	b.Slots = b.Slots[:12]
+	_, err = a.Merge(b)
+	assert.True(t, errors.Is(err, customerrors.ErrIncompatibleSchedules))
+}
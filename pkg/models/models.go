@@ -0,0 +1,153 @@
+package models
+
+import "time"
+
+// CallData represents the parsed input data for a customer call batch.
+// It is shared across packages to schedule calls.
+type CallData struct {
+	CustomerName               string
+	AverageCallDurationSeconds int
+	StartTime                  time.Time
+	EndTime                    time.Time
+	Location                   Location
+	NumberOfCalls              int
+	Priority                   Priority
+
+	// Optional attributes; the zero value means unset.
+
+	// SLATargetPercent is the share of calls (0-100) that must be answered
+	// within SLAThresholdSeconds.
+	SLATargetPercent    float64
+	SLAThresholdSeconds int
+	// Skill is the agent skill required to handle the calls.
+	Skill string
+	// Concurrency is the number of contacts one agent handles at once, for
+	// channels such as chat.
+	Concurrency int
+	// HourlyCost is the cost of one agent-hour for this customer.
+	HourlyCost float64
+	// MinAgents is the agents the customer is guaranteed in every slot of
+	// its window, capacity permitting, ahead of any other customer's
+	// demand beyond its own guarantee.
+	MinAgents int
+	// FloorAgents is the fewest agents the row is staffed with in every slot
+	// of its window, however few its calls.
+	FloorAgents int
+	// CeilingAgents is the most agents the customer is scheduled in every
+	// slot of its window, however many its calls; the calls beyond it go
+	// unserved.
+	CeilingAgents int
+	// WrapUpSeconds is the after-call work that follows each call, when
+	// AverageCallDurationSeconds leaves it out.
+	WrapUpSeconds int
+}
+
+// Schedule represents the agent requirements per time slot.
+//
+// A Schedule returned by the scheduler is never modified afterwards and is safe
+// for concurrent reads, e.g. by several formatters at once. Code that needs to
+// adjust a schedule must work on its own copy.
+type Schedule struct {
+	// SchemaVersion is the version of the schedule's persisted form; see
+	// CurrentSchemaVersion and Migrate.
+	SchemaVersion int
+	// Slots describes each entry of HourlyRequirements; Slots[i] is the
+	// interval HourlyRequirements[i] covers. Empty means the 24 wall-clock
+	// hours returned by HourlySlots.
+	Slots []TimeSlot
+	// HourlyRequirements maps a slot index (the hour 0-23 for the default
+	// layout) to a list of customer requirements
+	HourlyRequirements [][]CustomerRequirement
+	// UnmetDemands tracks slots where capacity was exceeded
+	UnmetDemands []UnmetDemand
+	// Metadata records how the schedule was produced; nil for schedules not
+	// built by the scheduler.
+	Metadata *Metadata
+}
+
+// Metadata makes a saved or published schedule self-describing.
+type Metadata struct {
+	// GeneratedAt is when the schedule was generated, in UTC
+	GeneratedAt time.Time `json:"generated_at"`
+	// RunID is the metrics run that produced the schedule
+	RunID string `json:"run_id,omitempty"`
+	// InputHash is the hex SHA-256 of the input CallData in its JSON form
+	InputHash string `json:"input_hash,omitempty"`
+	// Options lists the scheduling options in effect, by name
+	Options map[string]string `json:"options,omitempty"`
+	// ToolVersion is the scheduler version that generated the schedule
+	ToolVersion string `json:"tool_version,omitempty"`
+}
+
+// CustomerRequirement holds the number of agents needed for a specific customer.
+type CustomerRequirement struct {
+	Name         string
+	AgentsNeeded int
+	Location     Location
+	Priority     Priority
+
+	// Optional attributes carried over from CallData; the zero value means
+	// unset.
+	SLATargetPercent    float64
+	SLAThresholdSeconds int
+	Skill               string
+	Concurrency         int
+	HourlyCost          float64
+	MinAgents           int
+	CeilingAgents       int
+	// FTE is the agents needed before rounding up, when the scheduler was
+	// asked for them; see AgentsFTE
+	FTE float64
+}
+
+// AgentsFTE returns the agents r needs as full-time equivalents: FTE when
+// the scheduler filled it in, and AgentsNeeded otherwise.
+func (r CustomerRequirement) AgentsFTE() float64 {
+	if r.FTE > 0 {
+		return r.FTE
+	}
+	return float64(r.AgentsNeeded)
+}
+
+// UnmetDemand tracks when demand cannot be met due to capacity constraints
+type UnmetDemand struct {
+	// Hour is the index of the slot in Schedule.HourlyRequirements, which
+	// is the hour of day for the default hourly layout
+	Hour int
+	// Slot is the interval the shortfall occurred in
+	Slot            TimeSlot
+	TotalDemand     int
+	AllocatedAgents int
+	UnmetAgents     int
+	ImpactedClients []ImpactedClient
+}
+
+// ImpactedClient represents a customer whose demand was not fully met
+type ImpactedClient struct {
+	Name            string
+	RequestedAgents int
+	AllocatedAgents int
+	UnmetAgents     int
+	Priority        Priority
+	// Location is the customer's timezone/site, encoded as its IANA name
+	Location Location `json:",omitzero"`
+	// Skill is the agent skill the customer's calls require, if any
+	Skill string `json:",omitempty"`
+	// MinAgents is the agents the customer was guaranteed, if any
+	MinAgents int `json:",omitempty"`
+	// CeilingAgents is the most agents the customer could be scheduled,
+	// when that left some of its demand unmet
+	CeilingAgents int `json:",omitempty"`
+}
+
+// Capped reports whether the client's ceiling, rather than capacity, left
+// some of its demand unmet.
+func (c ImpactedClient) Capped() bool {
+	return c.CeilingAgents > 0 && c.RequestedAgents > c.CeilingAgents
+}
+
+// BelowMinimum reports whether the client got fewer agents than it was
+// guaranteed, or than it needed when that was less.
+func (c ImpactedClient) BelowMinimum() bool {
+	return c.AllocatedAgents < min(c.MinAgents, c.RequestedAgents)
+}
@@ -0,0 +1,63 @@
+package models
+
+import (
+	"slices"
+	"time"
+)
+
+// AgentPool is a group of interchangeable agents who share a set of skills,
+// e.g. a billing team, staffed with Headcount agents in every slot it is
+// open.
+type AgentPool struct {
+	Name string
+	// Skills are the customer skills the pool's agents can handle; calls
+	// that need no skill can go to any pool
+	Skills    []string
+	Headcount int
+	// Open and Close are the pool's daily hours of operation, as offsets
+	// from midnight in Location; a Close at or before Open runs past
+	// midnight, and both 0 keeps the pool open around the clock
+	Open, Close time.Duration
+	// Location is the pool's time zone; when unset, or when slots are laid
+	// out in each customer's location, hours are read on the slot's own
+	// wall clock
+	Location Location
+	// HourlyCost is what one of the pool's agents costs per hour
+	HourlyCost float64
+	// Tier orders pools for overflow: demand only spills into a pool once
+	// every pool of a lower tier that can take it is used up
+	Tier int
+}
+
+// Serves reports whether the pool's agents can handle calls requiring skill.
+func (p AgentPool) Serves(skill string) bool {
+	return skill == "" || slices.Contains(p.Skills, skill)
+}
+
+// OpenAt reports whether the pool is open at the start of slot. A slot with
+// a location is an absolute moment, read in the pool's
+// location; any other slot is wall-clock time, taken as is.
+func (p AgentPool) OpenAt(slot TimeSlot) bool {
+	if p.Open == 0 && p.Close == 0 {
+		return true
+	}
+	local := slot.Start
+	if !slot.Location.IsZero() {
+		local = p.Location.In(slot.Start)
+	}
+	offset := time.Duration(local.Hour())*time.Hour + time.Duration(local.Minute())*time.Minute
+	if p.Open < p.Close {
+		return offset >= p.Open && offset < p.Close
+	}
+	return offset >= p.Open || offset < p.Close
+}
+
+// UnmetBySkill totals the slot's unmet agents by the skill their calls
+// required, with "" for calls that required none.
+func (u UnmetDemand) UnmetBySkill() map[string]int {
+	bySkill := make(map[string]int)
+	for _, client := range u.ImpactedClients {
+		bySkill[client.Skill] += client.UnmetAgents
+	}
+	return bySkill
+}
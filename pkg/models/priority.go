@@ -0,0 +1,90 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/karthikrao-23/agentscheduler/pkg/errors"
+)
+
+// Priority ranks customers for allocation when capacity is short; lower
+// values are served first. Any value from MinPriority to MaxPriority is
+// valid, and the first four have names.
+type Priority int
+
+// Named priority levels.
+const (
+	PriorityCritical Priority = 1
+	PriorityHigh     Priority = 2
+	PriorityNormal   Priority = 3
+	PriorityLow      Priority = 4
+)
+
+// Priority bounds accepted by Validate; 1 is the highest priority.
+const (
+	MinPriority Priority = 1
+	MaxPriority Priority = 10
+)
+
+var priorityNames = map[Priority]string{
+	PriorityCritical: "critical",
+	PriorityHigh:     "high",
+	PriorityNormal:   "normal",
+	PriorityLow:      "low",
+}
+
+// ParsePriority parses a priority given by name ("critical", "high",
+// "normal" or "low", in any case) or as an integer. The error wraps
+// errors.ErrInvalidPriority.
+func ParsePriority(s string) (Priority, error) {
+	s = strings.TrimSpace(s)
+	for p, name := range priorityNames {
+		if strings.EqualFold(s, name) {
+			return p, nil
+		}
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q is not a priority name or number", errors.ErrInvalidPriority, s)
+	}
+	p := Priority(n)
+	if !p.Valid() {
+		return 0, fmt.Errorf("%w: must be between %d and %d, got %d", errors.ErrInvalidPriority, MinPriority, MaxPriority, p)
+	}
+	return p, nil
+}
+
+// Valid reports whether p is between MinPriority and MaxPriority.
+func (p Priority) Valid() bool {
+	return p >= MinPriority && p <= MaxPriority
+}
+
+// String returns the priority's name, or its number for unnamed levels.
+func (p Priority) String() string {
+	if name, ok := priorityNames[p]; ok {
+		return name
+	}
+	return strconv.Itoa(int(p))
+}
+
+// UnmarshalJSON implements json.Unmarshaler, accepting either a number or a
+// string understood by ParsePriority. Priorities always encode as numbers.
+func (p *Priority) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err == nil {
+		parsed, err := ParsePriority(s)
+		if err != nil {
+			return err
+		}
+		*p = parsed
+		return nil
+	}
+	var n int
+	if err := json.Unmarshal(b, &n); err != nil {
+		return fmt.Errorf("%w: %s", errors.ErrInvalidPriority, b)
+	}
+	*p = Priority(n)
+	return nil
+}
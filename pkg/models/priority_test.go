@@ -0,0 +1,62 @@
+package models_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	customerrors "github.com/karthikrao-23/agentscheduler/pkg/errors"
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePriority(t *testing.T) {
+	tests := map[string]struct {
+		input   string
+		want    models.Priority
+		wantErr bool
+	}{
+		"Name":          {input: "high", want: models.PriorityHigh},
+		"NameAnyCase":   {input: " Critical ", want: models.PriorityCritical},
+		"Number":        {input: "3", want: models.PriorityNormal},
+		"UnnamedNumber": {input: "7", want: 7},
+		"OutOfRange":    {input: "11", wantErr: true},
+		"Zero":          {input: "0", wantErr: true},
+		"UnknownName":   {input: "urgent", wantErr: true},
+		"Empty":         {input: "", wantErr: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := models.ParsePriority(tt.input)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, customerrors.ErrInvalidPriority)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestPriority_String(t *testing.T) {
+	assert.Equal(t, "low", models.PriorityLow.String())
+	assert.Equal(t, "7", models.Priority(7).String())
+}
+
+func TestPriority_JSON(t *testing.T) {
+	var req models.CustomerRequirement
+	require.NoError(t, json.Unmarshal([]byte(`{"name":"Acme","priority":"high"}`), &req))
+	assert.Equal(t, models.PriorityHigh, req.Priority)
+
+	require.NoError(t, json.Unmarshal([]byte(`{"name":"Acme","priority":4}`), &req))
+	assert.Equal(t, models.PriorityLow, req.Priority)
+
+	// Priorities are written as numbers for compatibility
+	b, err := json.Marshal(req)
+	require.NoError(t, err)
+	assert.Contains(t, string(b), `"priority":4`)
+
+	assert.ErrorIs(t, json.Unmarshal([]byte(`{"priority":"urgent"}`), &req), customerrors.ErrInvalidPriority)
+}
@@ -0,0 +1,60 @@
+package models
+
+// Scenario describes one what-if run: a named set of scheduling options to
+// generate a schedule with. Zero values leave the scheduler's defaults in
+// place, so a Scenario only needs the options it varies.
+type Scenario struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// Utilization is the agent utilization (0-1]; 0 means the default of 1
+	Utilization float64 `json:"utilization,omitempty"`
+	// Capacity caps the agents in every hour; 0 means unlimited
+	Capacity int `json:"capacity,omitempty"`
+	// CapacityProfile sets a capacity per hour of the day, as with
+	// scheduler.WithCapacityProfile
+	CapacityProfile []int `json:"capacity_profile,omitempty"`
+}
+
+// ScenarioResult is the outcome of running a Scenario: the schedule it
+// produced and a summary for comparing scenarios side by side.
+type ScenarioResult struct {
+	Scenario Scenario  `json:"scenario"`
+	Schedule *Schedule `json:"schedule"`
+	Summary  Summary   `json:"summary"`
+}
+
+// Summary holds the headline figures of a schedule.
+type Summary struct {
+	TotalDemand int `json:"total_demand"`
+	TotalAgents int `json:"total_agents"`
+	TotalUnmet  int `json:"total_unmet"`
+	// FulfillmentRate is the fraction (0-1) of demanded agents allocated
+	FulfillmentRate float64 `json:"fulfillment_rate"`
+	// PeakHour is the slot with the highest demand, or -1 without demand
+	PeakHour           int `json:"peak_hour"`
+	PeakDemand         int `json:"peak_demand"`
+	HoursWithShortfall int `json:"hours_with_shortfall"`
+}
+
+// Summary returns the schedule's headline figures.
+func (s *Schedule) Summary() Summary {
+	peakHour, peakDemand := s.PeakHour()
+	return Summary{
+		TotalDemand:        s.TotalDemand(),
+		TotalAgents:        s.TotalAgents(),
+		TotalUnmet:         s.TotalUnmet(),
+		FulfillmentRate:    s.FulfillmentRate(),
+		PeakHour:           peakHour,
+		PeakDemand:         peakDemand,
+		HoursWithShortfall: len(s.HoursWithShortfall()),
+	}
+}
+
+// NewScenarioResult pairs a scenario with the schedule it produced.
+func NewScenarioResult(scenario Scenario, schedule *Schedule) ScenarioResult {
+	return ScenarioResult{
+		Scenario: scenario,
+		Schedule: schedule,
+		Summary:  schedule.Summary(),
+	}
+}
@@ -0,0 +1,43 @@
+package models_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchedule_Summary(t *testing.T) {
+	assert.Equal(t, models.Summary{
+		TotalDemand:        14,
+		TotalAgents:        11,
+		TotalUnmet:         3,
+		FulfillmentRate:    11.0 / 14.0,
+		PeakHour:           10,
+		PeakDemand:         8,
+		HoursWithShortfall: 1,
+	}, newTestSchedule().Summary())
+
+	empty := (&models.Schedule{}).Summary()
+	assert.Equal(t, -1, empty.PeakHour)
+	assert.Equal(t, 1.0, empty.FulfillmentRate)
+}
+
+func TestScenarioResult_JSON(t *testing.T) {
+	result := models.NewScenarioResult(models.Scenario{Name: "lean", Capacity: 5}, newTestSchedule())
+	assert.Equal(t, 3, result.Summary.TotalUnmet)
+
+	b, err := json.Marshal(result)
+	require.NoError(t, err)
+	assert.Contains(t, string(b), `"scenario":{"name":"lean","capacity":5}`)
+	assert.Contains(t, string(b), `"total_unmet":3`)
+
+	var out models.ScenarioResult
+	require.NoError(t, json.Unmarshal(b, &out))
+	assert.Equal(t, result.Scenario, out.Scenario)
+	assert.Equal(t, result.Summary, out.Summary)
+	assert.True(t, result.Schedule.Equal(out.Schedule))
+}
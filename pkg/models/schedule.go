@@ -0,0 +1,155 @@
+package models
+
+import (
+	"sort"
+	"time"
+)
+
+// SlotAt returns the time slot for index i of HourlyRequirements.
+func (s *Schedule) SlotAt(i int) TimeSlot {
+	if i >= 0 && i < len(s.Slots) {
+		return s.Slots[i]
+	}
+	return HourSlot(i)
+}
+
+// NumSlots returns the number of time slots in the schedule's layout.
+func (s *Schedule) NumSlots() int {
+	if len(s.Slots) > 0 {
+		return len(s.Slots)
+	}
+	return 24
+}
+
+// Interval returns the length of the schedule's slots.
+func (s *Schedule) Interval() time.Duration {
+	return s.SlotAt(0).Duration
+}
+
+// TotalAgents returns the agents allocated across all hours.
+func (s *Schedule) TotalAgents() int {
+	total := 0
+	for _, reqs := range s.HourlyRequirements {
+		for _, req := range reqs {
+			total += req.AgentsNeeded
+		}
+	}
+	return total
+}
+
+// TotalUnmet returns the agents that could not be allocated across all hours.
+func (s *Schedule) TotalUnmet() int {
+	total := 0
+	for _, unmet := range s.UnmetDemands {
+		total += unmet.UnmetAgents
+	}
+	return total
+}
+
+// TotalDemand returns the agents demanded across all hours, whether they were
+// allocated or left unmet.
+func (s *Schedule) TotalDemand() int {
+	return s.TotalAgents() + s.TotalUnmet()
+}
+
+// AgentsForHour returns the agents allocated in hour h.
+func (s *Schedule) AgentsForHour(h int) int {
+	if h < 0 || h >= len(s.HourlyRequirements) {
+		return 0
+	}
+	total := 0
+	for _, req := range s.HourlyRequirements[h] {
+		total += req.AgentsNeeded
+	}
+	return total
+}
+
+// DemandForHour returns the agents demanded in hour h.
+func (s *Schedule) DemandForHour(h int) int {
+	demand := s.AgentsForHour(h)
+	for _, unmet := range s.UnmetDemands {
+		if unmet.Hour == h {
+			demand += unmet.UnmetAgents
+		}
+	}
+	return demand
+}
+
+// PeakHour returns the earliest hour with the highest demand and that demand.
+// It returns -1 and 0 when nothing was demanded.
+func (s *Schedule) PeakHour() (hour, demanded int) {
+	hour = -1
+	for h := range s.HourlyRequirements {
+		if d := s.DemandForHour(h); d > demanded {
+			hour, demanded = h, d
+		}
+	}
+	return hour, demanded
+}
+
+// AgentsForCustomer returns the agents allocated to the named customer across
+// all hours.
+func (s *Schedule) AgentsForCustomer(name string) int {
+	total := 0
+	for _, reqs := range s.HourlyRequirements {
+		for _, req := range reqs {
+			if req.Name == name {
+				total += req.AgentsNeeded
+			}
+		}
+	}
+	return total
+}
+
+// HoursWithShortfall returns the hours where demand exceeded capacity, in
+// ascending order.
+func (s *Schedule) HoursWithShortfall() []int {
+	hours := make([]int, 0, len(s.UnmetDemands))
+	for _, unmet := range s.UnmetDemands {
+		if unmet.UnmetAgents > 0 {
+			hours = append(hours, unmet.Hour)
+		}
+	}
+	sort.Ints(hours)
+	return hours
+}
+
+// FulfillmentRate returns the fraction (0-1) of demanded agents that were
+// allocated. A schedule without demand counts as fully met.
+func (s *Schedule) FulfillmentRate() float64 {
+	return fulfillmentRate(s.TotalAgents(), s.TotalDemand())
+}
+
+// PriorityTotals returns the agents allocated to and left unmet for
+// customers at the given priority.
+func (s *Schedule) PriorityTotals(priority Priority) (allocated, unmet int) {
+	for _, reqs := range s.HourlyRequirements {
+		for _, req := range reqs {
+			if req.Priority == priority {
+				allocated += req.AgentsNeeded
+			}
+		}
+	}
+	for _, u := range s.UnmetDemands {
+		for _, client := range u.ImpactedClients {
+			if client.Priority == priority {
+				unmet += client.UnmetAgents
+			}
+		}
+	}
+	return allocated, unmet
+}
+
+// PriorityFulfillmentRate is like FulfillmentRate but only counts customers
+// at the given priority.
+func (s *Schedule) PriorityFulfillmentRate(priority Priority) float64 {
+	allocated, unmet := s.PriorityTotals(priority)
+	return fulfillmentRate(allocated, allocated+unmet)
+}
+
+func fulfillmentRate(allocated, demanded int) float64 {
+	if demanded <= 0 {
+		return 1
+	}
+	return float64(allocated) / float64(demanded)
+}
@@ -0,0 +1,119 @@
+package models_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSchedule() *models.Schedule {
+	reqs := make([][]models.CustomerRequirement, 24)
+	reqs[9] = []models.CustomerRequirement{
+		{Name: "Acme", AgentsNeeded: 4, Priority: 1},
+		{Name: "Globex", AgentsNeeded: 2, Priority: 2},
+	}
+	reqs[10] = []models.CustomerRequirement{
+		{Name: "Acme", AgentsNeeded: 5, Priority: 1},
+	}
+	return &models.Schedule{
+		HourlyRequirements: reqs,
+		UnmetDemands: []models.UnmetDemand{
+			{
+				Hour:            10,
+				TotalDemand:     8,
+				AllocatedAgents: 5,
+				UnmetAgents:     3,
+				ImpactedClients: []models.ImpactedClient{
+					{Name: "Acme", RequestedAgents: 6, AllocatedAgents: 5, UnmetAgents: 1, Priority: 1},
+					{Name: "Globex", RequestedAgents: 2, AllocatedAgents: 0, UnmetAgents: 2, Priority: 2},
+				},
+			},
+		},
+	}
+}
+
+func TestSchedule_Totals(t *testing.T) {
+	s := newTestSchedule()
+
+	assert.Equal(t, 11, s.TotalAgents())
+	assert.Equal(t, 3, s.TotalUnmet())
+	assert.Equal(t, 14, s.TotalDemand())
+	assert.Equal(t, 6, s.AgentsForHour(9))
+	assert.Equal(t, 8, s.DemandForHour(10))
+	assert.Equal(t, 0, s.AgentsForHour(24))
+	assert.Equal(t, 9, s.AgentsForCustomer("Acme"))
+	assert.Equal(t, 0, s.AgentsForCustomer("Initech"))
+	assert.Equal(t, []int{10}, s.HoursWithShortfall())
+	assert.InDelta(t, 11.0/14.0, s.FulfillmentRate(), 1e-9)
+	assert.Equal(t, 1.0, (&models.Schedule{}).FulfillmentRate(), "no demand counts as fully met")
+
+	allocated, unmet := s.PriorityTotals(1)
+	assert.Equal(t, 9, allocated)
+	assert.Equal(t, 1, unmet)
+	assert.InDelta(t, 0.9, s.PriorityFulfillmentRate(1), 1e-9)
+}
+
+func TestSchedule_PeakHour(t *testing.T) {
+	tests := map[string]struct {
+		schedule     *models.Schedule
+		wantHour     int
+		wantDemanded int
+	}{
+		"Empty": {
+			schedule:     &models.Schedule{HourlyRequirements: make([][]models.CustomerRequirement, 24)},
+			wantHour:     -1,
+			wantDemanded: 0,
+		},
+		"UnmetCountsTowardsDemand": {
+			schedule:     newTestSchedule(),
+			wantHour:     10,
+			wantDemanded: 8,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			hour, demanded := tt.schedule.PeakHour()
+			assert.Equal(t, tt.wantHour, hour)
+			assert.Equal(t, tt.wantDemanded, demanded)
+		})
+	}
+}
+
+func TestSchedule_SlotAt(t *testing.T) {
+	s := &models.Schedule{}
+	assert.Equal(t, 24, s.NumSlots())
+	assert.Equal(t, "09:00", s.SlotAt(9).String())
+
+	ny, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	// 2026-11-01 is the US fall-back day, so 01:00 local happens twice
+	first := time.Date(2026, 11, 1, 5, 0, 0, 0, time.UTC)
+	s.Slots = []models.TimeSlot{
+		{Start: first, Duration: time.Hour, Location: models.NewLocation(ny)},
+		{Start: first.Add(time.Hour), Duration: time.Hour, Location: models.NewLocation(ny)},
+	}
+	assert.Equal(t, 2, s.NumSlots())
+	assert.Equal(t, "2026-11-01 01:00 EDT", s.SlotAt(0).String())
+	assert.Equal(t, "2026-11-01 01:00 EST", s.SlotAt(1).String())
+	assert.False(t, s.SlotAt(0).Equal(s.SlotAt(1)))
+	assert.Equal(t, 1, s.SlotAt(1).Hour())
+}
+
+func TestIntervalSlots(t *testing.T) {
+	slots := models.IntervalSlots(15 * time.Minute)
+	require.Len(t, slots, 96)
+	assert.Equal(t, "09:15", slots[37].String())
+	assert.Equal(t, 9, slots[37].Hour())
+	assert.Equal(t, "23:45", slots[95].String())
+
+	s := &models.Schedule{Slots: slots}
+	assert.Equal(t, 15*time.Minute, s.Interval())
+	assert.Equal(t, time.Hour, (&models.Schedule{}).Interval())
+	assert.Equal(t, models.HourlySlots(), models.IntervalSlots(time.Hour))
+}
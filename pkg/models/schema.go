@@ -0,0 +1,61 @@
+package models
+
+import (
+	"fmt"
+
+	"github.com/karthikrao-23/agentscheduler/pkg/errors"
+)
+
+// Schedule schema versions. Bump CurrentSchemaVersion whenever the persisted
+// form of a Schedule changes and add a migration from the previous version.
+const (
+	// SchemaVersion1 is the original JSON form: hourly requirements and unmet
+	// demand keyed by hour only.
+	SchemaVersion1 = 1
+	// SchemaVersion2 adds time slots to the schedule and to unmet demand.
+	SchemaVersion2 = 2
+
+	CurrentSchemaVersion = SchemaVersion2
+)
+
+// migrations[v] upgrades a schedule from version v to v+1.
+var migrations = map[int]func(*Schedule){
+	SchemaVersion1: migrateV1ToV2,
+}
+
+// Migrate upgrades s from its SchemaVersion to CurrentSchemaVersion. A zero
+// version is treated as version 1, the form written before schedules carried
+// a version. Schedules from a newer version, or from one that never existed
+// such as a negative one, cannot be migrated.
+func (s *Schedule) Migrate() error {
+	if s.SchemaVersion == 0 {
+		s.SchemaVersion = SchemaVersion1
+	}
+	if s.SchemaVersion > CurrentSchemaVersion {
+		return fmt.Errorf("%w: %d (newest supported is %d)", errors.ErrUnsupportedSchemaVersion, s.SchemaVersion, CurrentSchemaVersion)
+	}
+	for s.SchemaVersion < CurrentSchemaVersion {
+		migrate, ok := migrations[s.SchemaVersion]
+		if !ok {
+			return fmt.Errorf("%w: %d", errors.ErrUnsupportedSchemaVersion, s.SchemaVersion)
+		}
+		migrate(s)
+		s.SchemaVersion++
+	}
+	return nil
+}
+
+// migrateV1ToV2 fills in the wall-clock hour slots that version 1 implied.
+func migrateV1ToV2(s *Schedule) {
+	if len(s.Slots) == 0 {
+		s.Slots = make([]TimeSlot, len(s.HourlyRequirements))
+		for h := range s.Slots {
+			s.Slots[h] = HourSlot(h)
+		}
+	}
+	for i := range s.UnmetDemands {
+		if s.UnmetDemands[i].Slot.Duration == 0 {
+			s.UnmetDemands[i].Slot = s.SlotAt(s.UnmetDemands[i].Hour)
+		}
+	}
+}
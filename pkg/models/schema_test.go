@@ -0,0 +1,52 @@
+package models_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	customerrors "github.com/karthikrao-23/agentscheduler/pkg/errors"
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchedule_UnmarshalJSON_MigratesV1(t *testing.T) {
+	// Version 1 schedules carry no schema_version and no slots
+	v1 := `{
+		"hourly_requirements": [[], [{"name": "Acme", "agents_needed": 3, "location": "UTC", "priority": 1}]],
+		"unmet_demands": [{"hour": 1, "total_demand": 5, "allocated_agents": 3, "unmet_agents": 2, "impacted_clients": []}]
+	}`
+
+	var s models.Schedule
+	require.NoError(t, json.Unmarshal([]byte(v1), &s))
+	assert.Equal(t, models.CurrentSchemaVersion, s.SchemaVersion)
+	require.Len(t, s.Slots, 2)
+	assert.True(t, s.Slots[1].Equal(models.HourSlot(1)))
+	assert.True(t, s.UnmetDemands[0].Slot.Equal(models.HourSlot(1)))
+}
+
+func TestSchedule_UnmarshalJSON_UnsupportedVersion(t *testing.T) {
+	tests := map[string]string{
+		"Newer":    `{"schema_version": 99, "hourly_requirements": []}`,
+		"Negative": `{"schema_version": -1, "hourly_requirements": []}`,
+	}
+	for name, in := range tests {
+		t.Run(name, func(t *testing.T) {
+			var s models.Schedule
+			err := json.Unmarshal([]byte(in), &s)
+			assert.ErrorIs(t, err, customerrors.ErrUnsupportedSchemaVersion)
+		})
+	}
+}
+
+func TestSchedule_MarshalJSON_WritesVersion(t *testing.T) {
+	b, err := json.Marshal(models.Schedule{})
+	require.NoError(t, err)
+
+	var v struct {
+		SchemaVersion int `json:"schema_version"`
+	}
+	require.NoError(t, json.Unmarshal(b, &v))
+	assert.Equal(t, models.CurrentSchemaVersion, v.SchemaVersion)
+}
@@ -0,0 +1,52 @@
+package models
+
+import "time"
+
+// Seasonality scales call volume by month and week of the year, so that
+// one baseline input can be planned for any season, e.g. a December peak.
+type Seasonality []SeasonalMultiplier
+
+// SeasonalMultiplier scales a customer's calls, or every customer's, in one
+// month or one ISO week of the year.
+type SeasonalMultiplier struct {
+	// Customer is empty for every customer
+	Customer string
+	// Month is the month scaled, or 0 for a week
+	Month time.Month
+	// Week is the ISO week scaled, or 0 for a month
+	Week       int
+	Multiplier float64
+}
+
+// Multiplier returns the customer's multiplier at t: its month's times its
+// week's. For each, the customer's own multiplier is used if it has one,
+// otherwise the one for every customer, or 1 when there is neither.
+func (s Seasonality) Multiplier(customer string, t time.Time) float64 {
+	_, week := t.ISOWeek()
+	month := 1.0
+	weekly := 1.0
+	for _, m := range s {
+		if m.Customer != "" && m.Customer != customer {
+			continue
+		}
+		// Every customer's multiplier applies unless the customer's own does
+		own := m.Customer != ""
+		switch {
+		case m.Month != 0 && m.Month == t.Month() && (own || !s.has(customer, m.Month, 0)):
+			month = m.Multiplier
+		case m.Week != 0 && m.Week == week && (own || !s.has(customer, 0, m.Week)):
+			weekly = m.Multiplier
+		}
+	}
+	return month * weekly
+}
+
+// has reports whether customer has its own multiplier for month or week.
+func (s Seasonality) has(customer string, month time.Month, week int) bool {
+	for _, m := range s {
+		if m.Customer == customer && m.Month == month && m.Week == week {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,24 @@
+package models_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSeasonality_Multiplier(t *testing.T) {
+	s := models.Seasonality{
+		{Month: time.December, Multiplier: 1.5},
+		{Customer: "Acme", Month: time.December, Multiplier: 2},
+		{Week: 52, Multiplier: 0.5},
+	}
+	// 2026-12-24 is in ISO week 52, and 2026-12-10 in week 50
+	assert.Equal(t, 1.5, s.Multiplier("Globex", time.Date(2026, 12, 10, 9, 0, 0, 0, time.UTC)))
+	assert.Equal(t, 2.0, s.Multiplier("Acme", time.Date(2026, 12, 10, 9, 0, 0, 0, time.UTC)), "the customer's own month replaces every customer's")
+	assert.Equal(t, 0.75, s.Multiplier("Globex", time.Date(2026, 12, 24, 9, 0, 0, 0, time.UTC)), "month and week multiply")
+	assert.Equal(t, 1.0, s.Multiplier("Globex", time.Date(2026, 11, 10, 9, 0, 0, 0, time.UTC)))
+	assert.Equal(t, 1.0, models.Seasonality(nil).Multiplier("Acme", time.Date(2026, 12, 10, 9, 0, 0, 0, time.UTC)))
+}
@@ -0,0 +1,92 @@
+package models
+
+import "time"
+
+// TimeSlot identifies one scheduling interval of Duration starting at Start.
+//
+// When Location is unset the slot is a wall-clock interval shared by every
+// customer's local time: 09:00 means 9AM wherever each customer is. Start then
+// only carries a date and a clock time, stored in UTC, and a zero date means
+// the schedule describes a generic day. When Location is set, Start is an
+// absolute instant in that location, so the two 01:00 hours of a DST fall-back
+// day are distinct slots.
+type TimeSlot struct {
+	Start    time.Time
+	Duration time.Duration
+	Location Location
+}
+
+// HourSlot returns the wall-clock slot for hour h (0-23) of a generic day.
+func HourSlot(h int) TimeSlot {
+	return TimeSlot{
+		Start:    time.Date(0, 1, 1, h, 0, 0, 0, time.UTC),
+		Duration: time.Hour,
+	}
+}
+
+// HourlySlots returns the 24 wall-clock hour slots of a generic day, the
+// layout of a default Schedule.
+func HourlySlots() []TimeSlot {
+	return IntervalSlots(time.Hour)
+}
+
+// IntervalSlots returns the wall-clock slots of a generic day, each interval
+// long. interval should divide a day evenly; a remainder is left uncovered.
+func IntervalSlots(interval time.Duration) []TimeSlot {
+	return DaySlots(0, 1, 1, interval)
+}
+
+// DaySlots is like IntervalSlots but for the given calendar date; year 0 is
+// the generic day.
+func DaySlots(year int, month time.Month, day int, interval time.Duration) []TimeSlot {
+	midnight := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	slots := make([]TimeSlot, 24*time.Hour/interval)
+	for i := range slots {
+		slots[i] = TimeSlot{
+			Start:    midnight.Add(time.Duration(i) * interval),
+			Duration: interval,
+		}
+	}
+	return slots
+}
+
+// End returns the first instant after the slot.
+func (s TimeSlot) End() time.Time {
+	return s.Start.Add(s.Duration)
+}
+
+// Hour returns the hour of day the slot starts in, in the slot's location.
+func (s TimeSlot) Hour() int {
+	return s.local().Hour()
+}
+
+// HasDate reports whether the slot is tied to a calendar date rather than a
+// generic day.
+func (s TimeSlot) HasDate() bool {
+	return s.Start.Year() != 0
+}
+
+// Equal reports whether both slots cover the same interval in the same
+// location.
+func (s TimeSlot) Equal(other TimeSlot) bool {
+	return s.Start.Equal(other.Start) && s.Duration == other.Duration &&
+		s.Location.Equal(other.Location)
+}
+
+// String formats the slot start as "15:04", prefixed with the date when the
+// slot has one and suffixed with the zone abbreviation when it has a location.
+func (s TimeSlot) String() string {
+	layout := "15:04"
+	if s.HasDate() {
+		layout = "2006-01-02 15:04"
+	}
+	if !s.Location.IsZero() {
+		layout += " MST"
+	}
+	return s.local().Format(layout)
+}
+
+// local returns Start as a wall-clock time in the slot's location.
+func (s TimeSlot) local() time.Time {
+	return s.Location.In(s.Start)
+}
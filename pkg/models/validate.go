@@ -0,0 +1,90 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/karthikrao-23/agentscheduler/pkg/errors"
+)
+
+// MaxWindow is the longest call window Validate accepts. Windows are
+// clock times within a day, so anything longer cannot be represented.
+const MaxWindow = 24 * time.Hour
+
+// Validate checks that the call data can be scheduled. The returned error
+// wraps one of the sentinel errors in pkg/errors, so callers can use
+// errors.Is to tell the failures apart.
+//
+// Zero calls is valid: such rows schedule no agents and are surfaced by the
+// parser_zero_call_customers metric instead.
+func (c CallData) Validate() error {
+	if c.AverageCallDurationSeconds <= 0 {
+		return fmt.Errorf("%w: must be positive, got %d", errors.ErrInvalidDuration, c.AverageCallDurationSeconds)
+	}
+	if c.NumberOfCalls < 0 {
+		return fmt.Errorf("%w: must not be negative, got %d", errors.ErrInvalidNumberOfCalls, c.NumberOfCalls)
+	}
+	if !c.Priority.Valid() {
+		return fmt.Errorf("%w: must be between %d and %d, got %d", errors.ErrInvalidPriority, MinPriority, MaxPriority, c.Priority)
+	}
+	if c.Location.IsZero() {
+		return errors.ErrMissingLocation
+	}
+	if c.StartTime.IsZero() || c.EndTime.IsZero() {
+		return fmt.Errorf("%w: start and end times are required", errors.ErrInvalidWindow)
+	}
+
+	// Overnight windows wrap to the next day, as in the scheduler
+	window := c.Window()
+	if window <= 0 || window > MaxWindow {
+		return fmt.Errorf("%w: length %s must be positive and at most %s", errors.ErrInvalidWindow, window, MaxWindow)
+	}
+
+	// Optional attributes only need to be sane when set
+	// No number of agents answers every call in time
+	if math.IsNaN(c.SLATargetPercent) || c.SLATargetPercent < 0 || c.SLATargetPercent >= 100 {
+		return fmt.Errorf("%w: target must be at least 0 and less than 100 percent, got %g", errors.ErrInvalidSLA, c.SLATargetPercent)
+	}
+	if c.SLAThresholdSeconds < 0 {
+		return fmt.Errorf("%w: threshold must not be negative, got %d", errors.ErrInvalidSLA, c.SLAThresholdSeconds)
+	}
+	if (c.SLATargetPercent > 0) != (c.SLAThresholdSeconds > 0) {
+		return fmt.Errorf("%w: target and threshold must be set together", errors.ErrInvalidSLA)
+	}
+	if c.WrapUpSeconds < 0 {
+		return fmt.Errorf("%w: wrap-up must not be negative, got %d", errors.ErrInvalidDuration, c.WrapUpSeconds)
+	}
+	if c.Concurrency < 0 {
+		return fmt.Errorf("%w: must not be negative, got %d", errors.ErrInvalidConcurrency, c.Concurrency)
+	}
+	if c.HourlyCost < 0 {
+		return fmt.Errorf("%w: hourly cost must not be negative, got %g", errors.ErrInvalidCost, c.HourlyCost)
+	}
+	if c.MinAgents < 0 {
+		return fmt.Errorf("%w: must not be negative, got %d", errors.ErrInvalidMinAgents, c.MinAgents)
+	}
+	if c.FloorAgents < 0 || c.CeilingAgents < 0 {
+		return fmt.Errorf("%w: must not be negative, got floor %d and ceiling %d", errors.ErrInvalidAgentLimits, c.FloorAgents, c.CeilingAgents)
+	}
+	if c.CeilingAgents > 0 && c.FloorAgents > c.CeilingAgents {
+		return fmt.Errorf("%w: floor %d is above ceiling %d", errors.ErrInvalidAgentLimits, c.FloorAgents, c.CeilingAgents)
+	}
+	return nil
+}
+
+// HandleSeconds returns how long an agent spends on each call: the call
+// itself and the wrap-up after it.
+func (c CallData) HandleSeconds() int {
+	return c.AverageCallDurationSeconds + c.WrapUpSeconds
+}
+
+// Window returns the length of the call window. An end time before the start
+// time is treated as an overnight window ending the next day.
+func (c CallData) Window() time.Duration {
+	end := c.EndTime
+	if end.Before(c.StartTime) {
+		end = end.Add(24 * time.Hour)
+	}
+	return end.Sub(c.StartTime)
+}
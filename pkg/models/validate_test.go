@@ -0,0 +1,112 @@
+package models_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	customerrors "github.com/karthikrao-23/agentscheduler/pkg/errors"
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCallData_Validate(t *testing.T) {
+	valid := func() models.CallData {
+		return models.CallData{
+			CustomerName:               "Acme",
+			AverageCallDurationSeconds: 300,
+			StartTime:                  time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC),
+			EndTime:                    time.Date(2026, 3, 2, 17, 0, 0, 0, time.UTC),
+			Location:                   models.NewLocation(time.UTC),
+			NumberOfCalls:              100,
+			Priority:                   1,
+		}
+	}
+
+	tests := map[string]struct {
+		mutate  func(*models.CallData)
+		wantErr error
+	}{
+		"Valid": {
+			mutate: func(*models.CallData) {},
+		},
+		"ZeroCallsAllowed": {
+			mutate: func(c *models.CallData) { c.NumberOfCalls = 0 },
+		},
+		"OvernightWindow": {
+			mutate: func(c *models.CallData) {
+				c.StartTime = c.StartTime.Add(13 * time.Hour) // 10PM
+				c.EndTime = c.EndTime.Add(-11 * time.Hour)    // 6AM
+			},
+		},
+		"ZeroDuration": {
+			mutate:  func(c *models.CallData) { c.AverageCallDurationSeconds = 0 },
+			wantErr: customerrors.ErrInvalidDuration,
+		},
+		"NegativeCalls": {
+			mutate:  func(c *models.CallData) { c.NumberOfCalls = -1 },
+			wantErr: customerrors.ErrInvalidNumberOfCalls,
+		},
+		"PriorityTooHigh": {
+			mutate:  func(c *models.CallData) { c.Priority = models.MaxPriority + 1 },
+			wantErr: customerrors.ErrInvalidPriority,
+		},
+		"MissingLocation": {
+			mutate:  func(c *models.CallData) { c.Location = models.Location{} },
+			wantErr: customerrors.ErrMissingLocation,
+		},
+		"MissingEndTime": {
+			mutate:  func(c *models.CallData) { c.EndTime = time.Time{} },
+			wantErr: customerrors.ErrInvalidWindow,
+		},
+		"EmptyWindow": {
+			mutate:  func(c *models.CallData) { c.EndTime = c.StartTime },
+			wantErr: customerrors.ErrInvalidWindow,
+		},
+		"WindowTooLong": {
+			mutate:  func(c *models.CallData) { c.EndTime = c.StartTime.Add(25 * time.Hour) },
+			wantErr: customerrors.ErrInvalidWindow,
+		},
+		"SLASet": {
+			mutate: func(c *models.CallData) { c.SLATargetPercent, c.SLAThresholdSeconds = 80, 20 },
+		},
+		"SLATargetAbove100": {
+			mutate:  func(c *models.CallData) { c.SLATargetPercent, c.SLAThresholdSeconds = 120, 20 },
+			wantErr: customerrors.ErrInvalidSLA,
+		},
+		"SLATarget100": {
+			mutate:  func(c *models.CallData) { c.SLATargetPercent, c.SLAThresholdSeconds = 100, 20 },
+			wantErr: customerrors.ErrInvalidSLA,
+		},
+		"SLATargetNaN": {
+			mutate:  func(c *models.CallData) { c.SLATargetPercent, c.SLAThresholdSeconds = math.NaN(), 20 },
+			wantErr: customerrors.ErrInvalidSLA,
+		},
+		"SLATargetWithoutThreshold": {
+			mutate:  func(c *models.CallData) { c.SLATargetPercent = 80 },
+			wantErr: customerrors.ErrInvalidSLA,
+		},
+		"NegativeConcurrency": {
+			mutate:  func(c *models.CallData) { c.Concurrency = -1 },
+			wantErr: customerrors.ErrInvalidConcurrency,
+		},
+		"NegativeCost": {
+			mutate:  func(c *models.CallData) { c.HourlyCost = -0.5 },
+			wantErr: customerrors.ErrInvalidCost,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			cd := valid()
+			tt.mutate(&cd)
+			err := cd.Validate()
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
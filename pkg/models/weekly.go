@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// WeeklyPattern scales each customer's call volume by day of the week, so
+// that one row of call data can describe a whole week. It maps customer
+// names to multipliers indexed by time.Weekday: a multiplier of 1.2 on
+// Monday means 20% more calls on Mondays than the row gives.
+type WeeklyPattern map[string][7]float64
+
+// Multiplier returns the customer's multiplier for day, or 1 for customers
+// without a pattern.
+func (p WeeklyPattern) Multiplier(customer string, day time.Weekday) float64 {
+	multipliers, ok := p[customer]
+	if !ok {
+		return 1
+	}
+	return multipliers[day]
+}
@@ -0,0 +1,18 @@
+package models_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWeeklyPattern_Multiplier(t *testing.T) {
+	p := models.WeeklyPattern{"Acme": {time.Sunday: 0, time.Monday: 1.5, time.Saturday: 0.5}}
+	assert.Equal(t, 1.5, p.Multiplier("Acme", time.Monday))
+	assert.Equal(t, 0.0, p.Multiplier("Acme", time.Sunday))
+	assert.Equal(t, 1.0, p.Multiplier("Globex", time.Sunday), "customers without a pattern keep their volume")
+	assert.Equal(t, 1.0, models.WeeklyPattern(nil).Multiplier("Acme", time.Monday))
+}
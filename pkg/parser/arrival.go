@@ -0,0 +1,64 @@
+package parser
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/karthikrao-23/agentscheduler/pkg/errors"
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+)
+
+// ParseArrivalCurves reads per-customer intra-slot arrival curves from CSV,
+// one customer per row, with the share of calls arriving in each equal part
+// of a slot in turn:
+//
+//	#CustomerName, :00, :15, :30, :45
+//	Acme, 0.2, 0.3, 0.3, 0.2
+//
+// Lines starting with '#' are headers or comments. A curve needs at least
+// one share; shares must be finite and not negative, and need not sum to 1,
+// but may not all be 0. A customer listed twice keeps its last row.
+func ParseArrivalCurves(r io.Reader) (models.ArrivalCurves, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	curves := make(models.ArrivalCurves)
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return curves, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error reading CSV at line %d: %w", line, err)
+		}
+		if strings.HasPrefix(record[0], "#") {
+			continue
+		}
+		if len(record) < 2 {
+			return nil, &errors.ParseError{Line: line, Record: record, Err: errors.ErrInvalidFieldCount}
+		}
+
+		curve := make([]float64, len(record)-1)
+		total := 0.0
+		for i, field := range record[1:] {
+			share, err := strconv.ParseFloat(strings.TrimSpace(field), 64)
+			if err == nil && (share < 0 || math.IsNaN(share) || math.IsInf(share, 0)) {
+				err = fmt.Errorf("share %d must be a finite, non-negative number, got %g", i+1, share)
+			}
+			if err != nil {
+				return nil, &errors.ParseError{Line: line, Record: record, Err: fmt.Errorf("%w: %v", errors.ErrInvalidMultiplier, err)}
+			}
+			curve[i] = share
+			total += share
+		}
+		if total == 0 {
+			return nil, &errors.ParseError{Line: line, Record: record, Err: fmt.Errorf("%w: shares must not all be 0", errors.ErrInvalidMultiplier)}
+		}
+		curves[strings.TrimSpace(record[0])] = curve
+	}
+}
@@ -0,0 +1,59 @@
+package parser_test
+
+import (
+	"strings"
+	"testing"
+
+	customerrors "github.com/karthikrao-23/agentscheduler/pkg/errors"
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+	"github.com/karthikrao-23/agentscheduler/pkg/parser"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseArrivalCurves(t *testing.T) {
+	tests := map[string]struct {
+		input   string
+		want    models.ArrivalCurves
+		wantErr error
+	}{
+		"Valid": {
+			input: `
+#CustomerName, :00, :15, :30, :45
+Acme, 0.2, 0.3, 0.3, 0.2
+Globex, 1, 3
+`,
+			want: models.ArrivalCurves{
+				"Acme":   {0.2, 0.3, 0.3, 0.2},
+				"Globex": {1, 3},
+			},
+		},
+		"NoShares": {
+			input:   "Acme",
+			wantErr: customerrors.ErrInvalidFieldCount,
+		},
+		"Negative": {
+			input:   "Acme, 0.5, -0.5",
+			wantErr: customerrors.ErrInvalidMultiplier,
+		},
+		"AllZero": {
+			input:   "Acme, 0, 0",
+			wantErr: customerrors.ErrInvalidMultiplier,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := parser.ParseArrivalCurves(strings.NewReader(strings.TrimSpace(tt.input)))
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				var parseErr *customerrors.ParseError
+				assert.ErrorAs(t, err, &parseErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
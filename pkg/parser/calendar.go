@@ -0,0 +1,111 @@
+package parser
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/karthikrao-23/agentscheduler/pkg/errors"
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+)
+
+// calendarLayout is a calendar window's start or end with a time of day; a
+// date alone is also accepted.
+const calendarLayout = "2006-01-02 15:04"
+
+// ParseCalendar reads holidays and blackout windows from CSV, one entry per
+// row, each scaling the calls of a customer, a location, or both, over a
+// window:
+//
+//	#Name, Customer, Location, Start, End, Multiplier
+//	Christmas, , , 2026-12-25, 2026-12-25, 0
+//	Training, Acme, ET, 2026-12-24 14:00, 2026-12-24 18:00, 0
+//	Christmas Eve, , , 2026-12-24, 2026-12-24, 0.5
+//
+// Lines starting with '#' are headers or comments. Customer and Location
+// may be empty to cover every customer or location; Location takes the
+// same names as call data. Start and End are wall-clock times in each
+// customer's location, as "2006-01-02 15:04" or a date, and an End given as
+// a date runs to the end of that day. Multipliers must be finite and not
+// negative, and 0 blacks the window out.
+func ParseCalendar(r io.Reader) (models.Calendar, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	var cal models.Calendar
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return cal, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error reading CSV at line %d: %w", line, err)
+		}
+		if strings.HasPrefix(record[0], "#") {
+			continue
+		}
+		if len(record) != 6 {
+			return nil, &errors.ParseError{Line: line, Record: record, Err: errors.ErrInvalidFieldCount}
+		}
+
+		entry := models.CalendarEntry{
+			Name:     strings.TrimSpace(record[0]),
+			Customer: strings.TrimSpace(record[1]),
+		}
+		if code := strings.TrimSpace(record[2]); code != "" {
+			loc, err := calendarLocation(code)
+			if err != nil {
+				return nil, &errors.ParseError{Line: line, Record: record, Err: fmt.Errorf("%w: %v", errors.ErrMissingLocation, err)}
+			}
+			entry.Location = loc.String()
+		}
+		var dateOnly bool
+		if entry.Start, _, err = parseCalendarTime(record[3]); err != nil {
+			return nil, &errors.ParseError{Line: line, Record: record, Err: fmt.Errorf("%w: %v", errors.ErrInvalidDate, err)}
+		}
+		if entry.End, dateOnly, err = parseCalendarTime(record[4]); err != nil {
+			return nil, &errors.ParseError{Line: line, Record: record, Err: fmt.Errorf("%w: %v", errors.ErrInvalidDate, err)}
+		}
+		if dateOnly {
+			entry.End = entry.End.AddDate(0, 0, 1)
+		}
+		if !entry.End.After(entry.Start) {
+			return nil, &errors.ParseError{Line: line, Record: record, Err: fmt.Errorf("%w: end must be after start", errors.ErrInvalidWindow)}
+		}
+		entry.Multiplier, err = strconv.ParseFloat(strings.TrimSpace(record[5]), 64)
+		if err == nil && (entry.Multiplier < 0 || math.IsNaN(entry.Multiplier) || math.IsInf(entry.Multiplier, 0)) {
+			err = fmt.Errorf("must be a finite, non-negative number, got %g", entry.Multiplier)
+		}
+		if err != nil {
+			return nil, &errors.ParseError{Line: line, Record: record, Err: fmt.Errorf("%w: %v", errors.ErrInvalidMultiplier, err)}
+		}
+		cal = append(cal, entry)
+	}
+}
+
+// parseCalendarTime parses value as wall-clock time in UTC, reporting
+// whether it was a date alone.
+func parseCalendarTime(value string) (time.Time, bool, error) {
+	value = strings.TrimSpace(value)
+	if t, err := time.Parse(time.DateOnly, value); err == nil {
+		return t, true, nil
+	}
+	t, err := time.Parse(calendarLayout, value)
+	return t, false, err
+}
+
+// calendarLocation resolves code as call data's timezones are, but fails
+// for a name it does not know rather than falling back to Pacific Time, as
+// an entry for the wrong location would go unnoticed.
+func calendarLocation(code string) (*time.Location, error) {
+	switch code {
+	case "PT", "ET", "CT", "MT", "UTC":
+		return getTimezoneLocation(code)
+	}
+	return loadLocation(code)
+}
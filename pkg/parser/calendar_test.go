@@ -0,0 +1,68 @@
+package parser_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	customerrors "github.com/karthikrao-23/agentscheduler/pkg/errors"
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+	"github.com/karthikrao-23/agentscheduler/pkg/parser"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCalendar(t *testing.T) {
+	tests := map[string]struct {
+		input   string
+		want    models.Calendar
+		wantErr error
+	}{
+		"Valid": {
+			input: `
+#Name, Customer, Location, Start, End, Multiplier
+Christmas, , , 2026-12-25, 2026-12-25, 0
+Training, Acme, ET, 2026-12-24 14:00, 2026-12-24 18:00, 0.5
+`,
+			want: models.Calendar{
+				{Name: "Christmas", Start: time.Date(2026, 12, 25, 0, 0, 0, 0, time.UTC), End: time.Date(2026, 12, 26, 0, 0, 0, 0, time.UTC)},
+				{Name: "Training", Customer: "Acme", Location: "America/New_York", Start: time.Date(2026, 12, 24, 14, 0, 0, 0, time.UTC), End: time.Date(2026, 12, 24, 18, 0, 0, 0, time.UTC), Multiplier: 0.5},
+			},
+		},
+		"MissingMultiplier": {
+			input:   "Christmas, , , 2026-12-25, 2026-12-25",
+			wantErr: customerrors.ErrInvalidFieldCount,
+		},
+		"UnknownLocation": {
+			input:   "Christmas, , Nowhere/Special, 2026-12-25, 2026-12-25, 0",
+			wantErr: customerrors.ErrMissingLocation,
+		},
+		"BadDate": {
+			input:   "Christmas, , , 25/12/2026, 2026-12-25, 0",
+			wantErr: customerrors.ErrInvalidDate,
+		},
+		"EndBeforeStart": {
+			input:   "Training, , , 2026-12-24 18:00, 2026-12-24 14:00, 0",
+			wantErr: customerrors.ErrInvalidWindow,
+		},
+		"Negative": {
+			input:   "Christmas, , , 2026-12-25, 2026-12-25, -1",
+			wantErr: customerrors.ErrInvalidMultiplier,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := parser.ParseCalendar(strings.NewReader(strings.TrimSpace(tt.input)))
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				var parseErr *customerrors.ParseError
+				assert.ErrorAs(t, err, &parseErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
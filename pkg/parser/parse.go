@@ -0,0 +1,620 @@
+package parser
+
+import (
+	"context"
+	"encoding/csv"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/karthikrao-23/agentscheduler/pkg/errors"
+	"github.com/karthikrao-23/agentscheduler/pkg/metrics"
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+	"github.com/karthikrao-23/agentscheduler/pkg/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"golang.org/x/sync/errgroup"
+)
+
+// Parse reads CSV data from the reader and returns a slice of CallData.
+// It expects lines starting with '#' to be headers/comments.
+// The time fields are expected to be in "3PM" or "3:04PM" format.
+// The timezone is determined by the header column (e.g., StartTimePT -> Pacific Time).
+// Supports both US timezone codes (PT, ET, CT, MT, UTC) and full IANA timezone names
+// (e.g., StartTimeAsia/Tokyo, StartTimeEurope/London) for international timezones.
+// Multiple timezone headers can appear throughout the CSV; each sets the timezone
+// for all subsequent rows until the next timezone header is encountered.
+// Defaults to Pacific Time if not specified.
+//
+// Rows are dated today unless they have a Date column ("2006-01-02") between
+// AverageCallDurationSeconds and StartTime, in which case their window starts
+// on that date; an end time before the start time then falls on the next day.
+// A Skill column after Priority sets the agent skill the calls require.
+//
+// A header row can instead name the optional columns after Priority, in any
+// order, for the rows up to the next header: Skill; MinAgents, the agents
+// the customer is guaranteed each hour; and FloorAgents and CeilingAgents,
+// the fewest and most agents it is scheduled each hour, with
+// MaxConcurrentAgents another name for CeilingAgents; and WrapUpSeconds,
+// the after-call work added to each call's handle time. Unknown names are
+// ignored, and an empty value leaves the attribute unset.
+func Parse(r io.Reader) ([]models.CallData, error) {
+	return ParseContext(context.Background(), r)
+}
+
+// DefaultBatchSize is how many rows Stream passes on at a time unless
+// WithBatchSize is given.
+const DefaultBatchSize = 1024
+
+// Option configures optional parser behavior.
+type Option func(*config)
+
+type config struct {
+	lenient   bool
+	workers   int
+	batchSize int
+}
+
+// WithLenient makes the parser skip invalid data rows instead of failing.
+// Skipped rows are still counted in parser_errors_total and reported by
+// parser_rows_skipped.
+func WithLenient(lenient bool) Option {
+	return func(c *config) {
+		c.lenient = lenient
+	}
+}
+
+// WithWorkers converts rows on up to n goroutines while the rest of the
+// input is read. Rows are still returned in input order, and the first
+// invalid row is the one reported. Defaults to 1.
+func WithWorkers(n int) Option {
+	return func(c *config) {
+		c.workers = n
+	}
+}
+
+// WithBatchSize sets how many rows Stream passes to its callback at a time,
+// which is also the unit of work WithWorkers shares out. Defaults to
+// DefaultBatchSize.
+func WithBatchSize(n int) Option {
+	return func(c *config) {
+		c.batchSize = n
+	}
+}
+
+// ParseContext is like Parse but records a tracing span as a child of ctx and
+// stops with ctx.Err() if ctx is cancelled before the input is consumed.
+func ParseContext(ctx context.Context, r io.Reader, opts ...Option) ([]models.CallData, error) {
+	var data []models.CallData
+	err := Stream(ctx, r, func(batch []models.CallData) error {
+		data = append(data, batch...)
+		return nil
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// row is a data row waiting to be converted, with the time zone and columns
+// in effect where it appeared.
+type row struct {
+	line    int
+	record  []string
+	zone    *zone
+	columns *columns
+}
+
+// columns is the layout a header row declares: whether rows have a Date
+// column, and the optional columns that follow Priority, by normalized name.
+type columns struct {
+	dated bool
+	extra []string
+}
+
+// headerColumns returns the layout header declares, or nil when it names no
+// columns after Priority and rows keep the positional layout.
+func headerColumns(header []string) *columns {
+	if len(header) < 4 {
+		return nil
+	}
+	cols := &columns{}
+	switch {
+	case strings.HasPrefix(strings.TrimSpace(header[2]), "StartTime"):
+	case strings.HasPrefix(strings.TrimSpace(header[3]), "StartTime"):
+		cols.dated = true
+	default:
+		return nil
+	}
+	if len(header) <= cols.base() {
+		return nil
+	}
+	for _, name := range header[cols.base():] {
+		cols.extra = append(cols.extra, strings.ToLower(strings.NewReplacer(" ", "", "_", "").Replace(name)))
+	}
+	return cols
+}
+
+// base returns the number of columns up to and including Priority.
+func (c *columns) base() int {
+	if c.dated {
+		return 7
+	}
+	return 6
+}
+
+// chunk is a batch of rows converted on its own.
+type chunk struct {
+	done chan struct{}
+	data []models.CallData
+	// skipped holds the error type of each row skipped in lenient mode
+	skipped []string
+	err     *errors.ParseError
+	errType string
+}
+
+// Stream parses r like ParseContext, but passes the rows to fn in batches as
+// they are parsed instead of returning them all at the end, so a large input
+// can be processed while it is still being read. Batches arrive in input
+// order, from the calling goroutine, and fn may keep them. If fn returns an
+// error, Stream stops and returns it.
+func Stream(ctx context.Context, r io.Reader, fn func([]models.CallData) error, opts ...Option) (err error) {
+	cfg := config{batchSize: DefaultBatchSize}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	cfg.batchSize = max(cfg.batchSize, 1)
+
+	records := 0
+	ctx, span := tracing.Tracer("github.com/karthikrao-23/agentscheduler/pkg/parser").Start(ctx, "parser.Parse")
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "parse failed")
+		}
+		span.SetAttributes(attribute.Int("parser.records", records))
+		span.End()
+	}()
+
+	// Track parse duration
+	// The run ID is assigned by the scheduler, so only the trace links here
+	start := time.Now()
+	defer func() {
+		metrics.ObserveDuration(ctx, metrics.ParserDurationSeconds, time.Since(start).Seconds(), "")
+	}()
+
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	// Set default location to Pacific Time
+	loc, err := loadLocation("America/Los_Angeles")
+	if err != nil {
+		metrics.ParserErrorsTotal.WithLabelValues("location_load").Inc()
+		return fmt.Errorf("error loading location: %w", err)
+	}
+	// Rows are dated today in their timezone as of the start of the parse,
+	// worked out once per timezone rather than for every time field
+	zones := make(map[*time.Location]*zone)
+	zoneFor := func(loc *time.Location) *zone {
+		z, ok := zones[loc]
+		if !ok {
+			z = newZone(loc, start)
+			zones[loc] = z
+		}
+		return z
+	}
+	current := zoneFor(loc)
+	var layout *columns
+
+	var group *errgroup.Group
+	if cfg.workers > 1 {
+		group = new(errgroup.Group)
+		group.SetLimit(cfg.workers)
+	}
+	var pending []*chunk
+	var quality inputQuality
+
+	// drain hands converted chunks to fn in input order, stopping at the
+	// first still being converted once no more than limit remain. Bounding
+	// the chunks in flight keeps a slow fn from buffering the whole input
+	drain := func(limit int) error {
+		for len(pending) > 0 {
+			c := pending[0]
+			if len(pending) <= limit {
+				select {
+				case <-c.done:
+				default:
+					return nil
+				}
+			}
+			<-c.done
+			pending[0] = nil
+			pending = pending[1:]
+
+			for _, errType := range c.skipped {
+				metrics.ParserErrorsTotal.WithLabelValues(errType).Inc()
+			}
+			if c.err != nil {
+				metrics.ParserErrorsTotal.WithLabelValues(c.errType).Inc()
+				return c.err
+			}
+			quality.skipped += len(c.skipped)
+			for _, cd := range c.data {
+				quality.add(cd)
+			}
+			metrics.ParserRecordsTotal.Add(float64(len(c.data)))
+			records += len(c.data)
+			if len(c.data) > 0 {
+				if err := fn(c.data); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	// submit converts a batch of rows, on a worker if there are several
+	submit := func(rows []row) error {
+		c := &chunk{done: make(chan struct{})}
+		pending = append(pending, c)
+		if group == nil {
+			convert(c, rows, cfg.lenient)
+			return drain(0)
+		}
+		group.Go(func() error {
+			convert(c, rows, cfg.lenient)
+			return nil
+		})
+		return drain(2 * cfg.workers)
+	}
+	defer func() {
+		if group != nil {
+			_ = group.Wait()
+		}
+	}()
+
+	lineNum := 0
+	rows := make([]row, 0, cfg.batchSize)
+	for {
+		// Stop promptly if the caller gave up, e.g. on a request deadline
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		record, err := reader.Read()
+		lineNum++
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			// Rows before the unreadable one may hold an earlier error
+			if err := drain(0); err != nil {
+				return err
+			}
+			metrics.ParserErrorsTotal.WithLabelValues("csv_read").Inc()
+			return fmt.Errorf("error reading CSV at line %d: %w", lineNum, err)
+		}
+
+		// Handle headers/comments
+		if len(record) > 0 && strings.HasPrefix(record[0], "#") {
+			// Check for timezone definition in header
+			// We expect the 3rd field (index 2) to be StartTimeXX, or the
+			// 4th when the 3rd is Date
+			if len(record) >= 4 {
+				for _, field := range record[2:4] {
+					headerTime := strings.TrimSpace(field)
+					if !strings.HasPrefix(headerTime, "StartTime") {
+						continue
+					}
+					tzCode := strings.TrimPrefix(headerTime, "StartTime")
+					// Only process if we can resolve a timezone from it
+					if newLoc, err := getTimezoneLocation(tzCode); err == nil {
+						// Update the current timezone for subsequent rows
+						current = zoneFor(newLoc)
+					}
+					break
+				}
+			}
+			layout = headerColumns(record)
+			continue
+		}
+
+		rows = append(rows, row{line: lineNum, record: record, zone: current, columns: layout})
+		if len(rows) == cfg.batchSize {
+			if err := submit(rows); err != nil {
+				return err
+			}
+			rows = make([]row, 0, cfg.batchSize)
+		}
+	}
+	if len(rows) > 0 {
+		if err := submit(rows); err != nil {
+			return err
+		}
+	}
+	if err := drain(0); err != nil {
+		return err
+	}
+
+	quality.publish()
+	return nil
+}
+
+// convert parses a chunk's rows, stopping at the first invalid one unless
+// lenient is set.
+func convert(c *chunk, rows []row, lenient bool) {
+	defer close(c.done)
+	c.data = make([]models.CallData, 0, len(rows))
+	for _, r := range rows {
+		cd, errorType, err := parseRecord(r.record, r.zone, r.columns)
+		if err != nil {
+			if lenient {
+				c.skipped = append(c.skipped, errorType)
+				continue
+			}
+			c.err = &errors.ParseError{
+				Line:   r.line,
+				Record: r.record,
+				Err:    err,
+			}
+			c.errType = errorType
+			return
+		}
+		c.data = append(c.data, cd)
+	}
+}
+
+// parseRecord converts one data row into CallData, laid out as cols declares
+// or, when cols is nil, by position. On failure it returns the error type
+// used as the parser_errors_total label alongside the error.
+func parseRecord(record []string, z *zone, cols *columns) (models.CallData, string, error) {
+	var date, skill string
+	var extra []string
+	switch {
+	case cols != nil:
+		if len(record) != cols.base()+len(cols.extra) {
+			return models.CallData{}, "invalid_field_count", errors.ErrInvalidFieldCount
+		}
+		extra = record[cols.base():]
+		if cols.dated {
+			date = strings.TrimSpace(record[2])
+			record = []string{record[0], record[1], record[3], record[4], record[5], record[6]}
+		}
+		record = record[:6]
+	case len(record) == 6:
+	case len(record) == 7:
+		// A row with either a Date or a Skill; only a Date can stand where
+		// the start time would
+		if _, err := z.parseTime(strings.TrimSpace(record[2])); err == nil {
+			skill = strings.TrimSpace(record[6])
+			record = record[:6]
+			break
+		}
+		date = strings.TrimSpace(record[2])
+		record = []string{record[0], record[1], record[3], record[4], record[5], record[6]}
+	case len(record) == 8:
+		date = strings.TrimSpace(record[2])
+		skill = strings.TrimSpace(record[7])
+		record = []string{record[0], record[1], record[3], record[4], record[5], record[6]}
+	default:
+		return models.CallData{}, "invalid_field_count", errors.ErrInvalidFieldCount
+	}
+
+	var err error
+	cd := models.CallData{Skill: skill}
+	if date != "" {
+		day, err := time.ParseInLocation(time.DateOnly, date, z.loc)
+		if err != nil {
+			return cd, "invalid_date", fmt.Errorf("%w: %v", errors.ErrInvalidDate, err)
+		}
+		z = &zone{loc: z.loc, year: day.Year(), month: day.Month(), day: day.Day()}
+	}
+	cd.Location = models.NewLocation(z.loc)
+	cd.CustomerName = strings.TrimSpace(record[0])
+
+	cd.AverageCallDurationSeconds, err = strconv.Atoi(strings.TrimSpace(record[1]))
+	if err != nil {
+		return cd, "invalid_duration", fmt.Errorf("%w: %v", errors.ErrInvalidDuration, err)
+	}
+
+	// Parse times using "3:04PM" or "3PM" format
+	// Note: This sets the date to the current date to handle DST correctly.
+	cd.StartTime, err = z.parseTime(strings.TrimSpace(record[2]))
+	if err != nil {
+		return cd, "invalid_start_time", fmt.Errorf("%w: %v", errors.ErrInvalidStartTime, err)
+	}
+
+	cd.EndTime, err = z.parseTime(strings.TrimSpace(record[3]))
+	if err != nil {
+		return cd, "invalid_end_time", fmt.Errorf("%w: %v", errors.ErrInvalidEndTime, err)
+	}
+	if date != "" && cd.EndTime.Before(cd.StartTime) {
+		// Overnight: the window ends on the next day
+		cd.EndTime = time.Date(z.year, z.month, z.day+1, cd.EndTime.Hour(), cd.EndTime.Minute(), 0, 0, z.loc)
+	}
+
+	cd.NumberOfCalls, err = strconv.Atoi(strings.TrimSpace(record[4]))
+	if err != nil {
+		return cd, "invalid_number_of_calls", fmt.Errorf("%w: %v", errors.ErrInvalidNumberOfCalls, err)
+	}
+
+	cd.Priority, err = models.ParsePriority(record[5])
+	if err != nil {
+		return cd, "invalid_priority", err
+	}
+
+	for i, value := range extra {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		switch cols.extra[i] {
+		case "skill":
+			cd.Skill = value
+		case "minagents":
+			if cd.MinAgents, err = strconv.Atoi(value); err != nil {
+				return cd, "invalid_min_agents", fmt.Errorf("%w: %v", errors.ErrInvalidMinAgents, err)
+			}
+		case "flooragents":
+			if cd.FloorAgents, err = strconv.Atoi(value); err != nil {
+				return cd, "invalid_agent_limits", fmt.Errorf("%w: %v", errors.ErrInvalidAgentLimits, err)
+			}
+		case "ceilingagents", "maxconcurrentagents":
+			if cd.CeilingAgents, err = strconv.Atoi(value); err != nil {
+				return cd, "invalid_agent_limits", fmt.Errorf("%w: %v", errors.ErrInvalidAgentLimits, err)
+			}
+		case "wrapupseconds":
+			if cd.WrapUpSeconds, err = strconv.Atoi(value); err != nil {
+				return cd, "invalid_duration", fmt.Errorf("%w: %v", errors.ErrInvalidDuration, err)
+			}
+		}
+	}
+
+	if err := cd.Validate(); err != nil {
+		return cd, validationErrorType(err), err
+	}
+
+	return cd, "", nil
+}
+
+// validationErrorType returns the parser_errors_total label for an error
+// from CallData.Validate.
+func validationErrorType(err error) string {
+	switch {
+	case stderrors.Is(err, errors.ErrInvalidDuration):
+		return "invalid_duration"
+	case stderrors.Is(err, errors.ErrInvalidNumberOfCalls):
+		return "invalid_number_of_calls"
+	case stderrors.Is(err, errors.ErrInvalidPriority):
+		return "invalid_priority"
+	case stderrors.Is(err, errors.ErrMissingLocation):
+		return "missing_location"
+	case stderrors.Is(err, errors.ErrInvalidSLA):
+		return "invalid_sla"
+	case stderrors.Is(err, errors.ErrInvalidConcurrency):
+		return "invalid_concurrency"
+	case stderrors.Is(err, errors.ErrInvalidCost):
+		return "invalid_cost"
+	case stderrors.Is(err, errors.ErrInvalidMinAgents):
+		return "invalid_min_agents"
+	case stderrors.Is(err, errors.ErrInvalidAgentLimits):
+		return "invalid_agent_limits"
+	default:
+		return "invalid_window"
+	}
+}
+
+// inputQuality accumulates metrics describing the parsed input itself.
+type inputQuality struct {
+	timezones         map[string]bool
+	zeroCallCustomers map[string]bool
+	maxWindowHours    float64
+	skipped           int
+}
+
+// add accounts for one parsed row.
+func (q *inputQuality) add(cd models.CallData) {
+	if q.timezones == nil {
+		q.timezones = make(map[string]bool)
+		q.zeroCallCustomers = make(map[string]bool)
+	}
+	if !cd.Location.IsZero() {
+		q.timezones[cd.Location.Name()] = true
+	}
+	if cd.NumberOfCalls == 0 {
+		q.zeroCallCustomers[cd.CustomerName] = true
+	}
+
+	q.maxWindowHours = max(q.maxWindowHours, cd.Window().Hours())
+}
+
+// publish sets the input-quality metrics.
+func (q *inputQuality) publish() {
+	metrics.ParserRowsSkipped.Set(float64(q.skipped))
+	metrics.ParserDistinctTimezones.Set(float64(len(q.timezones)))
+	metrics.ParserZeroCallCustomers.Set(float64(len(q.zeroCallCustomers)))
+	metrics.ParserMaxWindowHours.Set(q.maxWindowHours)
+}
+
+// timeLayouts are the accepted formats for start and end times.
+var timeLayouts = []string{"3:04PM", "3PM"}
+
+// zone is a timezone rows are parsed in, along with the date they are
+// given there.
+type zone struct {
+	loc       *time.Location
+	year, day int
+	month     time.Month
+}
+
+func newZone(loc *time.Location, now time.Time) *zone {
+	year, month, day := now.In(loc).Date()
+	return &zone{loc: loc, year: year, month: month, day: day}
+}
+
+func (z *zone) parseTime(value string) (time.Time, error) {
+	var lastErr error
+	for _, layout := range timeLayouts {
+		// ParseInLocation uses year 0 if not specified.
+		// We want to use the current date to respect DST rules for "today".
+		t, err := time.ParseInLocation(layout, value, z.loc)
+		if err == nil {
+			// Normalize to today's date
+			t = time.Date(z.year, z.month, z.day, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), z.loc)
+			return t, nil
+		}
+		lastErr = err
+	}
+	return time.Time{}, lastErr
+}
+
+// locations caches loadLocation results, failures included, by name;
+// time.LoadLocation reads and parses the zoneinfo database on every call.
+var locations sync.Map
+
+type cachedLocation struct {
+	loc *time.Location
+	err error
+}
+
+// loadLocation is time.LoadLocation, cached for the life of the process.
+func loadLocation(name string) (*time.Location, error) {
+	if c, ok := locations.Load(name); ok {
+		return c.(cachedLocation).loc, c.(cachedLocation).err
+	}
+	loc, err := time.LoadLocation(name)
+	locations.Store(name, cachedLocation{loc: loc, err: err})
+	return loc, err
+}
+
+func getTimezoneLocation(code string) (*time.Location, error) {
+	code = strings.TrimSpace(code)
+
+	// First, try common US timezone abbreviations
+	switch code {
+	case "PT":
+		return loadLocation("America/Los_Angeles")
+	case "ET":
+		return loadLocation("America/New_York")
+	case "CT":
+		return loadLocation("America/Chicago")
+	case "MT":
+		return loadLocation("America/Denver")
+	case "UTC":
+		return time.UTC, nil
+	default:
+		// If not a known abbreviation, try to load it as a full IANA timezone name
+		// This supports international timezones like "Asia/Tokyo", "Europe/London", etc.
+		loc, err := loadLocation(code)
+		if err != nil {
+			// If that fails too, default to Pacific Time
+			return loadLocation("America/Los_Angeles")
+		}
+		return loc, nil
+	}
+}
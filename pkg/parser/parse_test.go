@@ -0,0 +1,635 @@
+package parser_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/karthikrao-23/agentscheduler/internal/benchdata"
+	customerrors "github.com/karthikrao-23/agentscheduler/pkg/errors"
+	"github.com/karthikrao-23/agentscheduler/pkg/metrics"
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+	"github.com/karthikrao-23/agentscheduler/pkg/parser"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	// Helper to create time.Time from "3PM" or "3:04PM" format string, in PST/PDT, today
+	parseTime := func(s string) time.Time {
+		loc, err := time.LoadLocation("America/Los_Angeles")
+		if err != nil {
+			panic(err)
+		}
+		now := time.Now().In(loc)
+
+		layouts := []string{"3:04PM", "3PM"}
+		var t time.Time
+		for _, layout := range layouts {
+			t, err = time.ParseInLocation(layout, s, loc)
+			if err == nil {
+				break
+			}
+		}
+		if err != nil {
+			panic(err)
+		}
+		return time.Date(now.Year(), now.Month(), now.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), loc)
+	}
+
+	tests := map[string]struct {
+		input         string
+		expectedData  []models.CallData
+		expectedError error
+	}{
+		"ValidInput_SingleLine": {
+			input: `
+Stanford Hospital, 300, 9:30AM, 7:30PM, 20000, 1
+`,
+			expectedData: []models.CallData{
+				{
+					CustomerName:               "Stanford Hospital",
+					AverageCallDurationSeconds: 300,
+					StartTime:                  parseTime("9:30AM"),
+					EndTime:                    parseTime("7:30PM"),
+					Location:                   func() models.Location { l, _ := models.LoadLocation("America/Los_Angeles"); return l }(),
+					NumberOfCalls:              20000,
+					Priority:                   1,
+				},
+			},
+			expectedError: nil,
+		},
+		"ValidInput_MultipleLines_WithComments": {
+			input: `
+# This is a comment
+# CustomerName, Duration, Start, End, Calls, Priority
+VNS, 120, 6AM, 1PM, 40500, 1
+CVS, 180, 11AM, 3PM, 50000, 3
+`,
+			expectedData: []models.CallData{
+				{
+					CustomerName:               "VNS",
+					AverageCallDurationSeconds: 120,
+					StartTime:                  parseTime("6AM"),
+					EndTime:                    parseTime("1PM"),
+					Location:                   func() models.Location { l, _ := models.LoadLocation("America/Los_Angeles"); return l }(),
+					NumberOfCalls:              40500,
+					Priority:                   1,
+				},
+				{
+					CustomerName:               "CVS",
+					AverageCallDurationSeconds: 180,
+					StartTime:                  parseTime("11AM"),
+					EndTime:                    parseTime("3PM"),
+					Location:                   func() models.Location { l, _ := models.LoadLocation("America/Los_Angeles"); return l }(),
+					NumberOfCalls:              50000,
+					Priority:                   3,
+				},
+			},
+			expectedError: nil,
+		},
+		"Error_InvalidFieldCount": {
+			input: `
+Stanford Hospital, 300, 9AM, 7PM, 20000
+`,
+			expectedData:  nil,
+			expectedError: customerrors.ErrInvalidFieldCount,
+		},
+		"Error_InvalidDuration": {
+			input: `
+Stanford Hospital, abc, 9AM, 7PM, 20000, 1
+`,
+			expectedData:  nil,
+			expectedError: customerrors.ErrInvalidDuration,
+		},
+		"Error_InvalidStartTime": {
+			input: `
+Stanford Hospital, 300, 99AM, 7PM, 20000, 1
+`,
+			expectedData:  nil,
+			expectedError: customerrors.ErrInvalidStartTime,
+		},
+		"Error_InvalidEndTime": {
+			input: `
+Stanford Hospital, 300, 9AM, 25PM, 20000, 1
+`,
+			expectedData:  nil,
+			expectedError: customerrors.ErrInvalidEndTime,
+		},
+		"Error_InvalidNumberOfCalls": {
+			input: `
+Stanford Hospital, 300, 9AM, 7PM, xyz, 1
+`,
+			expectedData:  nil,
+			expectedError: customerrors.ErrInvalidNumberOfCalls,
+		},
+		"Error_InvalidPriority": {
+			input: `
+Stanford Hospital, 300, 9AM, 7PM, 20000, p1
+`,
+			expectedData:  nil,
+			expectedError: customerrors.ErrInvalidPriority,
+		},
+		"Error_ZeroDuration": {
+			input: `
+Stanford Hospital, 0, 9AM, 7PM, 20000, 1
+`,
+			expectedData:  nil,
+			expectedError: customerrors.ErrInvalidDuration,
+		},
+		"Error_PriorityOutOfRange": {
+			input: `
+Stanford Hospital, 300, 9AM, 7PM, 20000, 0
+`,
+			expectedData:  nil,
+			expectedError: customerrors.ErrInvalidPriority,
+		},
+		"ValidInput_NamedPriority": {
+			input: `
+Stanford Hospital, 300, 9:30AM, 7:30PM, 20000, High
+`,
+			expectedData: []models.CallData{
+				{
+					CustomerName:               "Stanford Hospital",
+					AverageCallDurationSeconds: 300,
+					StartTime:                  parseTime("9:30AM"),
+					EndTime:                    parseTime("7:30PM"),
+					Location:                   func() models.Location { l, _ := models.LoadLocation("America/Los_Angeles"); return l }(),
+					NumberOfCalls:              20000,
+					Priority:                   models.PriorityHigh,
+				},
+			},
+			expectedError: nil,
+		},
+		"Error_EmptyWindow": {
+			input: `
+Stanford Hospital, 300, 9AM, 9AM, 20000, 1
+`,
+			expectedData:  nil,
+			expectedError: customerrors.ErrInvalidWindow,
+		},
+		"Error_StartTimeAfterEndTime": {
+			input: `
+Stanford Hospital, 300, 7PM, 9AM, 20000, 1
+`,
+			expectedData: []models.CallData{
+				{
+					CustomerName:               "Stanford Hospital",
+					AverageCallDurationSeconds: 300,
+					StartTime:                  parseTime("7PM"),
+					EndTime:                    parseTime("9AM"),
+					Location:                   func() models.Location { l, _ := models.LoadLocation("America/Los_Angeles"); return l }(),
+					NumberOfCalls:              20000,
+					Priority:                   1,
+				},
+			},
+			expectedError: nil,
+		},
+		"ValidInput_EasternTime": {
+			input: `
+#CustomerName, Duration, StartTimeET, EndTimeET, Calls, Priority
+VNS, 120, 6AM, 1PM, 40500, 1
+`,
+			expectedData: []models.CallData{
+				{
+					CustomerName:               "VNS",
+					AverageCallDurationSeconds: 120,
+					StartTime: func() time.Time {
+						loc, _ := time.LoadLocation("America/New_York")
+						now := time.Now().In(loc)
+						return time.Date(now.Year(), now.Month(), now.Day(), 6, 0, 0, 0, loc)
+					}(),
+					EndTime: func() time.Time {
+						loc, _ := time.LoadLocation("America/New_York")
+						now := time.Now().In(loc)
+						return time.Date(now.Year(), now.Month(), now.Day(), 13, 0, 0, 0, loc)
+					}(),
+					Location:      func() models.Location { l, _ := models.LoadLocation("America/New_York"); return l }(),
+					NumberOfCalls: 40500,
+					Priority:      1,
+				},
+			},
+			expectedError: nil,
+		},
+		"ValidInput_MultipleTimezones": {
+			input: `
+#CustomerName, Duration, StartTimePT, EndTimePT, Calls, Priority
+West Coast, 120, 9AM, 5PM, 10000, 1
+#CustomerName, Duration, StartTimeET, EndTimeET, Calls, Priority
+East Coast, 180, 9AM, 5PM, 15000, 2
+`,
+			expectedData: []models.CallData{
+				{
+					CustomerName:               "West Coast",
+					AverageCallDurationSeconds: 120,
+					StartTime: func() time.Time {
+						loc, _ := time.LoadLocation("America/Los_Angeles")
+						now := time.Now().In(loc)
+						return time.Date(now.Year(), now.Month(), now.Day(), 9, 0, 0, 0, loc)
+					}(),
+					EndTime: func() time.Time {
+						loc, _ := time.LoadLocation("America/Los_Angeles")
+						now := time.Now().In(loc)
+						return time.Date(now.Year(), now.Month(), now.Day(), 17, 0, 0, 0, loc)
+					}(),
+					Location:      func() models.Location { l, _ := models.LoadLocation("America/Los_Angeles"); return l }(),
+					NumberOfCalls: 10000,
+					Priority:      1,
+				},
+				{
+					CustomerName:               "East Coast",
+					AverageCallDurationSeconds: 180,
+					StartTime: func() time.Time {
+						loc, _ := time.LoadLocation("America/New_York")
+						now := time.Now().In(loc)
+						return time.Date(now.Year(), now.Month(), now.Day(), 9, 0, 0, 0, loc)
+					}(),
+					EndTime: func() time.Time {
+						loc, _ := time.LoadLocation("America/New_York")
+						now := time.Now().In(loc)
+						return time.Date(now.Year(), now.Month(), now.Day(), 17, 0, 0, 0, loc)
+					}(),
+					Location:      func() models.Location { l, _ := models.LoadLocation("America/New_York"); return l }(),
+					NumberOfCalls: 15000,
+					Priority:      2,
+				},
+			},
+			expectedError: nil,
+		},
+		"ValidInput_InternationalTimezones": {
+			input: `
+#CustomerName, Duration, StartTimeAsia/Tokyo, EndTimeAsia/Tokyo, Calls, Priority
+Tokyo Office, 300, 9AM, 5PM, 10000, 1
+#CustomerName, Duration, StartTimeEurope/London, EndTimeEurope/London, Calls, Priority
+London Office, 240, 10AM, 6PM, 8000, 2
+`,
+			expectedData: []models.CallData{
+				{
+					CustomerName:               "Tokyo Office",
+					AverageCallDurationSeconds: 300,
+					StartTime: func() time.Time {
+						loc, _ := time.LoadLocation("Asia/Tokyo")
+						now := time.Now().In(loc)
+						return time.Date(now.Year(), now.Month(), now.Day(), 9, 0, 0, 0, loc)
+					}(),
+					EndTime: func() time.Time {
+						loc, _ := time.LoadLocation("Asia/Tokyo")
+						now := time.Now().In(loc)
+						return time.Date(now.Year(), now.Month(), now.Day(), 17, 0, 0, 0, loc)
+					}(),
+					Location:      func() models.Location { l, _ := models.LoadLocation("Asia/Tokyo"); return l }(),
+					NumberOfCalls: 10000,
+					Priority:      1,
+				},
+				{
+					CustomerName:               "London Office",
+					AverageCallDurationSeconds: 240,
+					StartTime: func() time.Time {
+						loc, _ := time.LoadLocation("Europe/London")
+						now := time.Now().In(loc)
+						return time.Date(now.Year(), now.Month(), now.Day(), 10, 0, 0, 0, loc)
+					}(),
+					EndTime: func() time.Time {
+						loc, _ := time.LoadLocation("Europe/London")
+						now := time.Now().In(loc)
+						return time.Date(now.Year(), now.Month(), now.Day(), 18, 0, 0, 0, loc)
+					}(),
+					Location:      func() models.Location { l, _ := models.LoadLocation("Europe/London"); return l }(),
+					NumberOfCalls: 8000,
+					Priority:      2,
+				},
+			},
+			expectedError: nil,
+		},
+		"ValidInput_Dated": {
+			input: `
+#CustomerName, Duration, Date, StartTimeUTC, EndTimeUTC, Calls, Priority
+Night Desk, 300, 2026-03-02, 9PM, 5AM, 800, 2
+Day Desk, 300, 2026-03-03, 9AM, 5PM, 1000, 1
+`,
+			expectedData: []models.CallData{
+				{
+					CustomerName:               "Night Desk",
+					AverageCallDurationSeconds: 300,
+					StartTime:                  time.Date(2026, 3, 2, 21, 0, 0, 0, time.UTC),
+					EndTime:                    time.Date(2026, 3, 3, 5, 0, 0, 0, time.UTC),
+					Location:                   models.NewLocation(time.UTC),
+					NumberOfCalls:              800,
+					Priority:                   2,
+				},
+				{
+					CustomerName:               "Day Desk",
+					AverageCallDurationSeconds: 300,
+					StartTime:                  time.Date(2026, 3, 3, 9, 0, 0, 0, time.UTC),
+					EndTime:                    time.Date(2026, 3, 3, 17, 0, 0, 0, time.UTC),
+					Location:                   models.NewLocation(time.UTC),
+					NumberOfCalls:              1000,
+					Priority:                   1,
+				},
+			},
+		},
+		"ValidInput_Skill": {
+			input: `
+#CustomerName, Duration, Date, StartTimeUTC, EndTimeUTC, Calls, Priority, Skill
+Billing Line, 300, 2026-03-02, 9AM, 5PM, 1000, 1, billing
+#CustomerName, Duration, StartTimeUTC, EndTimeUTC, Calls, Priority, Skill
+Help Desk, 300, 9AM, 5PM, 800, 2, support
+`,
+			expectedData: []models.CallData{
+				{
+					CustomerName:               "Billing Line",
+					AverageCallDurationSeconds: 300,
+					StartTime:                  time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC),
+					EndTime:                    time.Date(2026, 3, 2, 17, 0, 0, 0, time.UTC),
+					Location:                   models.NewLocation(time.UTC),
+					NumberOfCalls:              1000,
+					Priority:                   1,
+					Skill:                      "billing",
+				},
+				{
+					CustomerName:               "Help Desk",
+					AverageCallDurationSeconds: 300,
+					StartTime: func() time.Time {
+						now := time.Now().UTC()
+						return time.Date(now.Year(), now.Month(), now.Day(), 9, 0, 0, 0, time.UTC)
+					}(),
+					EndTime: func() time.Time {
+						now := time.Now().UTC()
+						return time.Date(now.Year(), now.Month(), now.Day(), 17, 0, 0, 0, time.UTC)
+					}(),
+					Location:      models.NewLocation(time.UTC),
+					NumberOfCalls: 800,
+					Priority:      2,
+					Skill:         "support",
+				},
+			},
+		},
+		"ValidInput_NamedColumns": {
+			input: `
+#CustomerName, Duration, Date, StartTimeUTC, EndTimeUTC, Calls, Priority, MinAgents, Region, Skill
+Billing Line, 300, 2026-03-02, 9AM, 5PM, 1000, 1, 3, west, billing
+Help Desk, 300, 2026-03-02, 9AM, 5PM, 800, 2, , east,
+`,
+			expectedData: []models.CallData{
+				{
+					CustomerName:               "Billing Line",
+					AverageCallDurationSeconds: 300,
+					StartTime:                  time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC),
+					EndTime:                    time.Date(2026, 3, 2, 17, 0, 0, 0, time.UTC),
+					Location:                   models.NewLocation(time.UTC),
+					NumberOfCalls:              1000,
+					Priority:                   1,
+					Skill:                      "billing",
+					MinAgents:                  3,
+				},
+				{
+					CustomerName:               "Help Desk",
+					AverageCallDurationSeconds: 300,
+					StartTime:                  time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC),
+					EndTime:                    time.Date(2026, 3, 2, 17, 0, 0, 0, time.UTC),
+					Location:                   models.NewLocation(time.UTC),
+					NumberOfCalls:              800,
+					Priority:                   2,
+				},
+			},
+		},
+		"NamedColumnsMissingField": {
+			input: `
+#CustomerName, Duration, StartTimeUTC, EndTimeUTC, Calls, Priority, MinAgents
+Help Desk, 300, 9AM, 5PM, 800, 2
+`,
+			expectedError: customerrors.ErrInvalidFieldCount,
+		},
+		"InvalidMinAgents": {
+			input: `
+#CustomerName, Duration, StartTimeUTC, EndTimeUTC, Calls, Priority, MinAgents
+Help Desk, 300, 9AM, 5PM, 800, 2, -1
+`,
+			expectedError: customerrors.ErrInvalidMinAgents,
+		},
+		"ValidInput_AgentLimits": {
+			input: `
+#CustomerName, Duration, StartTimeUTC, EndTimeUTC, Calls, Priority, FloorAgents, CeilingAgents
+Help Desk, 300, 9AM, 5PM, 800, 2, 2, 20
+`,
+			expectedData: []models.CallData{
+				{
+					CustomerName:               "Help Desk",
+					AverageCallDurationSeconds: 300,
+					StartTime: func() time.Time {
+						now := time.Now().UTC()
+						return time.Date(now.Year(), now.Month(), now.Day(), 9, 0, 0, 0, time.UTC)
+					}(),
+					EndTime: func() time.Time {
+						now := time.Now().UTC()
+						return time.Date(now.Year(), now.Month(), now.Day(), 17, 0, 0, 0, time.UTC)
+					}(),
+					Location:      models.NewLocation(time.UTC),
+					NumberOfCalls: 800,
+					Priority:      2,
+					FloorAgents:   2,
+					CeilingAgents: 20,
+				},
+			},
+		},
+		"ValidInput_MaxConcurrentAgentsAndWrapUp": {
+			input: `
+#CustomerName, Duration, StartTimeUTC, EndTimeUTC, Calls, Priority, MaxConcurrentAgents, Wrap_Up_Seconds
+Help Desk, 300, 9AM, 5PM, 800, 2, 12, 45
+`,
+			expectedData: []models.CallData{
+				{
+					CustomerName:               "Help Desk",
+					AverageCallDurationSeconds: 300,
+					StartTime: func() time.Time {
+						now := time.Now().UTC()
+						return time.Date(now.Year(), now.Month(), now.Day(), 9, 0, 0, 0, time.UTC)
+					}(),
+					EndTime: func() time.Time {
+						now := time.Now().UTC()
+						return time.Date(now.Year(), now.Month(), now.Day(), 17, 0, 0, 0, time.UTC)
+					}(),
+					Location:      models.NewLocation(time.UTC),
+					NumberOfCalls: 800,
+					Priority:      2,
+					CeilingAgents: 12,
+					WrapUpSeconds: 45,
+				},
+			},
+		},
+		"InvalidWrapUp": {
+			input: `
+#CustomerName, Duration, StartTimeUTC, EndTimeUTC, Calls, Priority, WrapUpSeconds
+Help Desk, 300, 9AM, 5PM, 800, 2, -30
+`,
+			expectedError: customerrors.ErrInvalidDuration,
+		},
+		"FloorAboveCeiling": {
+			input: `
+#CustomerName, Duration, StartTimeUTC, EndTimeUTC, Calls, Priority, FloorAgents, CeilingAgents
+Help Desk, 300, 9AM, 5PM, 800, 2, 30, 20
+`,
+			expectedError: customerrors.ErrInvalidAgentLimits,
+		},
+		"InvalidDate": {
+			input: `
+Night Desk, 300, 03/02/2026, 9PM, 5AM, 800, 2
+`,
+			expectedError: customerrors.ErrInvalidDate,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := strings.NewReader(strings.TrimSpace(tt.input))
+			got, err := parser.Parse(r)
+
+			if tt.expectedError != nil {
+				// Check if it's a wrapped error or string match
+				if !errors.Is(err, tt.expectedError) && err.Error() != tt.expectedError.Error() {
+					t.Errorf("Parse() error = %v, expectedError %v", err, tt.expectedError)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("Parse() unexpected error = %v", err)
+				return
+			}
+
+			assert.Equal(t, got, tt.expectedData, fmt.Sprintf("Parse() = %v, want %v", got, tt.expectedData))
+		})
+	}
+}
+
+func TestParseContext_Lenient(t *testing.T) {
+	input := `
+#CustomerName, Duration, StartTimeET, EndTimeET, Calls, Priority
+VNS, 120, 6AM, 1PM, 40500, 1
+Broken, abc, 9AM, 5PM, 100, 1
+#CustomerName, Duration, StartTimeUTC, EndTimeUTC, Calls, Priority
+Night Line, 300, 10PM, 6AM, 0, 2
+Short Row, 300, 9AM
+`
+
+	// Strict mode fails on the first bad row
+	_, err := parser.ParseContext(context.Background(), strings.NewReader(input))
+	assert.ErrorIs(t, err, customerrors.ErrInvalidDuration)
+
+	got, err := parser.ParseContext(context.Background(), strings.NewReader(input), parser.WithLenient(true))
+	assert.NoError(t, err)
+	assert.Len(t, got, 2)
+	assert.Equal(t, "VNS", got[0].CustomerName)
+	assert.Equal(t, "Night Line", got[1].CustomerName)
+
+	assert.Equal(t, 2.0, testutil.ToFloat64(metrics.ParserRowsSkipped))
+	assert.Equal(t, 2.0, testutil.ToFloat64(metrics.ParserDistinctTimezones))
+	assert.Equal(t, 1.0, testutil.ToFloat64(metrics.ParserZeroCallCustomers))
+	assert.Equal(t, 8.0, testutil.ToFloat64(metrics.ParserMaxWindowHours))
+}
+
+func TestParse_RepeatedTimezones(t *testing.T) {
+	input := `
+#CustomerName, Duration, StartTimeAsia/Tokyo, EndTimeAsia/Tokyo, Calls, Priority
+Tokyo A, 180, 9AM, 5PM, 100, 1
+#CustomerName, Duration, StartTimeNowhere/Else, EndTimeNowhere/Else, Calls, Priority
+Fallback, 180, 9AM, 5PM, 100, 1
+#CustomerName, Duration, StartTimeAsia/Tokyo, EndTimeAsia/Tokyo, Calls, Priority
+Tokyo B, 180, 9AM, 5PM, 100, 1
+`
+	got, err := parser.Parse(strings.NewReader(input))
+	assert.NoError(t, err)
+	assert.Len(t, got, 3)
+
+	// A timezone seen again parses as it did the first time
+	assert.Equal(t, "Asia/Tokyo", got[2].Location.Name())
+	assert.True(t, got[0].StartTime.Equal(got[2].StartTime))
+	assert.True(t, got[0].EndTime.Equal(got[2].EndTime))
+	assert.Equal(t, "America/Los_Angeles", got[1].Location.Name(), "unknown timezones fall back to Pacific")
+}
+
+func TestParseContext_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := parser.ParseContext(ctx, strings.NewReader("VNS, 120, 6AM, 1PM, 40500, 1\n"))
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestStream(t *testing.T) {
+	var sb strings.Builder
+	sb.WriteString("#CustomerName, Duration, StartTimeET, EndTimeET, Calls, Priority\n")
+	for i := range 50 {
+		if i == 25 {
+			sb.WriteString("#CustomerName, Duration, StartTimeUTC, EndTimeUTC, Calls, Priority\n")
+		}
+		fmt.Fprintf(&sb, "Cust%d, 300, 9AM, 5PM, %d, %d\n", i, 100+i, i%3+1)
+	}
+	input := sb.String()
+	want, err := parser.ParseContext(context.Background(), strings.NewReader(input))
+	assert.NoError(t, err)
+	assert.Len(t, want, 50)
+
+	tests := map[string]struct {
+		opts []parser.Option
+	}{
+		"Sequential": {opts: []parser.Option{parser.WithBatchSize(7)}},
+		"Workers":    {opts: []parser.Option{parser.WithBatchSize(7), parser.WithWorkers(4)}},
+		"OneBatch":   {opts: []parser.Option{parser.WithWorkers(4)}},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var got []models.CallData
+			var batches int
+			err := parser.Stream(context.Background(), strings.NewReader(input), func(batch []models.CallData) error {
+				got = append(got, batch...)
+				batches++
+				return nil
+			}, tt.opts...)
+			assert.NoError(t, err)
+			assert.Equal(t, want, got, "rows arrive in input order")
+			assert.Positive(t, batches)
+		})
+	}
+
+	t.Run("FirstErrorInInputOrder", func(t *testing.T) {
+		bad := strings.Replace(input, "Cust10, 300", "Cust10, abc", 1)
+		bad = strings.Replace(bad, "Cust40, 300", "Cust40, abc", 1)
+		err := parser.Stream(context.Background(), strings.NewReader(bad), func([]models.CallData) error { return nil },
+			parser.WithBatchSize(3), parser.WithWorkers(4))
+		var parseErr *customerrors.ParseError
+		assert.ErrorAs(t, err, &parseErr)
+		assert.Equal(t, 12, parseErr.Line)
+	})
+
+	t.Run("CallbackError", func(t *testing.T) {
+		stop := errors.New("stop")
+		calls := 0
+		err := parser.Stream(context.Background(), strings.NewReader(input), func([]models.CallData) error {
+			calls++
+			return stop
+		}, parser.WithBatchSize(5), parser.WithWorkers(2))
+		assert.ErrorIs(t, err, stop)
+		assert.Equal(t, 1, calls)
+	})
+}
+
+func BenchmarkParse(b *testing.B) {
+	for _, n := range benchdata.Sizes {
+		b.Run(benchdata.Name(n), func(b *testing.B) {
+			input := benchdata.CSV(n)
+			b.SetBytes(int64(len(input)))
+			b.ReportAllocs()
+			for b.Loop() {
+				if _, err := parser.Parse(bytes.NewReader(input)); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
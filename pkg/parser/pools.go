@@ -0,0 +1,126 @@
+package parser
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/karthikrao-23/agentscheduler/pkg/errors"
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+)
+
+// ParseAgentPools reads agent pools from CSV, one pool per row, with the
+// skills its agents handle separated by ";":
+//
+//	#Pool, Skills, Headcount, Hours, Location, HourlyCost, Tier
+//	Billing, billing, 10
+//	Generalists, billing;support, 25
+//	Offshore, billing;support, 40, 22:00-06:00, Asia/Kolkata, 9.5, 1
+//
+// Lines starting with '#' are headers or comments. Headcounts must be whole
+// numbers and not negative. The columns after Headcount are optional, and
+// may be left empty: Hours are the pool's daily hours of operation as
+// "HH:MM-HH:MM", passing midnight when the end is at or before the start,
+// in Location, which takes the same names as call data; HourlyCost is what
+// one agent costs per hour, and Tier the pool's overflow order, lowest
+// first.
+func ParseAgentPools(r io.Reader) ([]models.AgentPool, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	var pools []models.AgentPool
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return pools, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error reading CSV at line %d: %w", line, err)
+		}
+		if strings.HasPrefix(record[0], "#") {
+			continue
+		}
+		if len(record) < 3 || len(record) > 7 {
+			return nil, &errors.ParseError{Line: line, Record: record, Err: errors.ErrInvalidFieldCount}
+		}
+		// Optional columns left off are empty
+		field := func(i int) string {
+			if i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		headcount, err := strconv.Atoi(field(2))
+		if err == nil && headcount < 0 {
+			err = fmt.Errorf("must not be negative, got %d", headcount)
+		}
+		if err != nil {
+			return nil, &errors.ParseError{Line: line, Record: record, Err: fmt.Errorf("%w: %v", errors.ErrInvalidHeadcount, err)}
+		}
+		pool := models.AgentPool{Name: field(0), Headcount: headcount}
+		for skill := range strings.SplitSeq(record[1], ";") {
+			if skill = strings.TrimSpace(skill); skill != "" {
+				pool.Skills = append(pool.Skills, skill)
+			}
+		}
+		if hours := field(3); hours != "" {
+			if pool.Open, pool.Close, err = parseHours(hours); err != nil {
+				return nil, &errors.ParseError{Line: line, Record: record, Err: fmt.Errorf("%w: %v", errors.ErrInvalidHours, err)}
+			}
+		}
+		if code := field(4); code != "" {
+			loc, err := calendarLocation(code)
+			if err != nil {
+				return nil, &errors.ParseError{Line: line, Record: record, Err: fmt.Errorf("%w: %v", errors.ErrMissingLocation, err)}
+			}
+			pool.Location = models.NewLocation(loc)
+		}
+		if cost := field(5); cost != "" {
+			pool.HourlyCost, err = strconv.ParseFloat(cost, 64)
+			if err == nil && (pool.HourlyCost < 0 || math.IsNaN(pool.HourlyCost) || math.IsInf(pool.HourlyCost, 0)) {
+				err = fmt.Errorf("must be a finite, non-negative number, got %g", pool.HourlyCost)
+			}
+			if err != nil {
+				return nil, &errors.ParseError{Line: line, Record: record, Err: fmt.Errorf("%w: %v", errors.ErrInvalidCost, err)}
+			}
+		}
+		if tier := field(6); tier != "" {
+			pool.Tier, err = strconv.Atoi(tier)
+			if err == nil && pool.Tier < 0 {
+				err = fmt.Errorf("must not be negative, got %d", pool.Tier)
+			}
+			if err != nil {
+				return nil, &errors.ParseError{Line: line, Record: record, Err: fmt.Errorf("%w: %v", errors.ErrInvalidTier, err)}
+			}
+		}
+		pools = append(pools, pool)
+	}
+}
+
+// parseHours parses "HH:MM-HH:MM" into offsets from midnight.
+func parseHours(s string) (open, close time.Duration, err error) {
+	from, to, ok := strings.Cut(s, "-")
+	if !ok {
+		return 0, 0, fmt.Errorf("want HH:MM-HH:MM, got %q", s)
+	}
+	for _, part := range []struct {
+		value string
+		into  *time.Duration
+	}{{from, &open}, {to, &close}} {
+		t, err := time.Parse("15:04", strings.TrimSpace(part.value))
+		if err != nil {
+			return 0, 0, err
+		}
+		*part.into = time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+	}
+	if open == close {
+		return 0, 0, fmt.Errorf("must open and close at different times, got %q", s)
+	}
+	return open, close, nil
+}
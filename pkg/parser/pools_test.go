@@ -0,0 +1,93 @@
+package parser_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	customerrors "github.com/karthikrao-23/agentscheduler/pkg/errors"
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+	"github.com/karthikrao-23/agentscheduler/pkg/parser"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAgentPools(t *testing.T) {
+	eastern, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	tests := map[string]struct {
+		input   string
+		want    []models.AgentPool
+		wantErr error
+	}{
+		"Valid": {
+			input: `
+#Pool, Skills, Headcount
+Billing, billing, 10
+Generalists, billing; support, 25
+Trainees, , 3
+`,
+			want: []models.AgentPool{
+				{Name: "Billing", Skills: []string{"billing"}, Headcount: 10},
+				{Name: "Generalists", Skills: []string{"billing", "support"}, Headcount: 25},
+				{Name: "Trainees", Headcount: 3},
+			},
+		},
+		"HoursCostAndTier": {
+			input: `
+#Pool, Skills, Headcount, Hours, Location, HourlyCost, Tier
+Onshore, billing, 10, 09:00-17:00, ET, 30
+Offshore, billing, 40, 22:00-06:00, UTC, 9.5, 1
+Overflow, billing, 5, , , , 2
+`,
+			want: []models.AgentPool{
+				{Name: "Onshore", Skills: []string{"billing"}, Headcount: 10, Open: 9 * time.Hour, Close: 17 * time.Hour, Location: models.NewLocation(eastern), HourlyCost: 30},
+				{Name: "Offshore", Skills: []string{"billing"}, Headcount: 40, Open: 22 * time.Hour, Close: 6 * time.Hour, Location: models.NewLocation(time.UTC), HourlyCost: 9.5, Tier: 1},
+				{Name: "Overflow", Skills: []string{"billing"}, Headcount: 5, Tier: 2},
+			},
+		},
+		"MissingHeadcount": {
+			input:   "Billing, billing",
+			wantErr: customerrors.ErrInvalidFieldCount,
+		},
+		"NegativeHeadcount": {
+			input:   "Billing, billing, -1",
+			wantErr: customerrors.ErrInvalidHeadcount,
+		},
+		"FractionalHeadcount": {
+			input:   "Billing, billing, 2.5",
+			wantErr: customerrors.ErrInvalidHeadcount,
+		},
+		"InvalidHours": {
+			input:   "Billing, billing, 2, 09:00",
+			wantErr: customerrors.ErrInvalidHours,
+		},
+		"UnknownLocation": {
+			input:   "Billing, billing, 2, 09:00-17:00, Mars/Olympus",
+			wantErr: customerrors.ErrMissingLocation,
+		},
+		"NegativeCost": {
+			input:   "Billing, billing, 2, , , -1",
+			wantErr: customerrors.ErrInvalidCost,
+		},
+		"NegativeTier": {
+			input:   "Billing, billing, 2, , , , -1",
+			wantErr: customerrors.ErrInvalidTier,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := parser.ParseAgentPools(strings.NewReader(strings.TrimSpace(tt.input)))
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				var parseErr *customerrors.ParseError
+				assert.ErrorAs(t, err, &parseErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
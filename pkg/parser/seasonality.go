@@ -0,0 +1,89 @@
+package parser
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/karthikrao-23/agentscheduler/pkg/errors"
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+)
+
+// ParseSeasonality reads seasonal multipliers from CSV, one per row, each
+// scaling a customer's calls, or every customer's when the name is empty,
+// in a month, named in full or by its first three letters, or an ISO week
+// of the year, as W1 to W53:
+//
+//	#CustomerName, Period, Multiplier
+//	, Dec, 1.4
+//	Acme, W51, 1.8
+//
+// Lines starting with '#' are headers or comments. Multipliers must be
+// finite and not negative. A period listed twice for the same customer
+// keeps its last row.
+func ParseSeasonality(r io.Reader) (models.Seasonality, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	var seasonality models.Seasonality
+	index := make(map[models.SeasonalMultiplier]int)
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return seasonality, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error reading CSV at line %d: %w", line, err)
+		}
+		if strings.HasPrefix(record[0], "#") {
+			continue
+		}
+		if len(record) != 3 {
+			return nil, &errors.ParseError{Line: line, Record: record, Err: errors.ErrInvalidFieldCount}
+		}
+
+		m := models.SeasonalMultiplier{Customer: strings.TrimSpace(record[0])}
+		if m.Month, m.Week, err = parsePeriod(record[1]); err != nil {
+			return nil, &errors.ParseError{Line: line, Record: record, Err: fmt.Errorf("%w: %v", errors.ErrInvalidDate, err)}
+		}
+		multiplier, err := strconv.ParseFloat(strings.TrimSpace(record[2]), 64)
+		if err == nil && (multiplier < 0 || math.IsNaN(multiplier) || math.IsInf(multiplier, 0)) {
+			err = fmt.Errorf("must be a finite, non-negative number, got %g", multiplier)
+		}
+		if err != nil {
+			return nil, &errors.ParseError{Line: line, Record: record, Err: fmt.Errorf("%w: %v", errors.ErrInvalidMultiplier, err)}
+		}
+
+		if i, ok := index[m]; ok {
+			seasonality[i].Multiplier = multiplier
+			continue
+		}
+		index[m] = len(seasonality)
+		m.Multiplier = multiplier
+		seasonality = append(seasonality, m)
+	}
+}
+
+// parsePeriod parses a month, e.g. "Dec" or "December", or an ISO week,
+// e.g. "W51".
+func parsePeriod(value string) (time.Month, int, error) {
+	value = strings.TrimSpace(value)
+	if rest, ok := strings.CutPrefix(strings.ToUpper(value), "W"); ok {
+		week, err := strconv.Atoi(rest)
+		if err != nil || week < 1 || week > 53 {
+			return 0, 0, fmt.Errorf("week must be W1 to W53, got %q", value)
+		}
+		return 0, week, nil
+	}
+	for _, layout := range []string{"Jan", "January"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.Month(), 0, nil
+		}
+	}
+	return 0, 0, fmt.Errorf("period must be a month or a week such as W51, got %q", value)
+}
@@ -0,0 +1,67 @@
+package parser_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	customerrors "github.com/karthikrao-23/agentscheduler/pkg/errors"
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+	"github.com/karthikrao-23/agentscheduler/pkg/parser"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSeasonality(t *testing.T) {
+	tests := map[string]struct {
+		input   string
+		want    models.Seasonality
+		wantErr error
+	}{
+		"Valid": {
+			input: `
+#CustomerName, Period, Multiplier
+, Dec, 1.4
+Acme, w51, 1.8
+Acme, November, 1.1
+, December, 1.5
+`,
+			want: models.Seasonality{
+				{Month: time.December, Multiplier: 1.5},
+				{Customer: "Acme", Week: 51, Multiplier: 1.8},
+				{Customer: "Acme", Month: time.November, Multiplier: 1.1},
+			},
+		},
+		"MissingMultiplier": {
+			input:   "Acme, Dec",
+			wantErr: customerrors.ErrInvalidFieldCount,
+		},
+		"UnknownMonth": {
+			input:   "Acme, Decembre, 1.4",
+			wantErr: customerrors.ErrInvalidDate,
+		},
+		"WeekOutOfRange": {
+			input:   "Acme, W54, 1.4",
+			wantErr: customerrors.ErrInvalidDate,
+		},
+		"Negative": {
+			input:   "Acme, Dec, -1",
+			wantErr: customerrors.ErrInvalidMultiplier,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := parser.ParseSeasonality(strings.NewReader(strings.TrimSpace(tt.input)))
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				var parseErr *customerrors.ParseError
+				assert.ErrorAs(t, err, &parseErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package parser
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/karthikrao-23/agentscheduler/pkg/errors"
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+)
+
+// weekdays are the days of a weekly pattern row, in column order.
+var weekdays = []time.Weekday{
+	time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday, time.Sunday,
+}
+
+// ParseWeeklyPattern reads per-customer weekday multipliers from CSV, one
+// customer per row, Monday first:
+//
+//	#CustomerName, Mon, Tue, Wed, Thu, Fri, Sat, Sun
+//	Acme, 1.2, 1, 1, 1, 0.9, 0.5, 0
+//
+// Lines starting with '#' are headers or comments. Multipliers must be
+// finite and not negative. A customer listed twice keeps its last row.
+func ParseWeeklyPattern(r io.Reader) (models.WeeklyPattern, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	pattern := make(models.WeeklyPattern)
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return pattern, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error reading CSV at line %d: %w", line, err)
+		}
+		if strings.HasPrefix(record[0], "#") {
+			continue
+		}
+		if len(record) != 1+len(weekdays) {
+			return nil, &errors.ParseError{Line: line, Record: record, Err: errors.ErrInvalidFieldCount}
+		}
+
+		var multipliers [7]float64
+		for i, day := range weekdays {
+			m, err := strconv.ParseFloat(strings.TrimSpace(record[1+i]), 64)
+			if err == nil && (m < 0 || math.IsNaN(m) || math.IsInf(m, 0)) {
+				err = fmt.Errorf("%s must be a finite, non-negative number, got %g", day, m)
+			}
+			if err != nil {
+				return nil, &errors.ParseError{Line: line, Record: record, Err: fmt.Errorf("%w: %v", errors.ErrInvalidMultiplier, err)}
+			}
+			multipliers[day] = m
+		}
+		pattern[strings.TrimSpace(record[0])] = multipliers
+	}
+}
@@ -0,0 +1,60 @@
+package parser_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	customerrors "github.com/karthikrao-23/agentscheduler/pkg/errors"
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+	"github.com/karthikrao-23/agentscheduler/pkg/parser"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseWeeklyPattern(t *testing.T) {
+	tests := map[string]struct {
+		input   string
+		want    models.WeeklyPattern
+		wantErr error
+	}{
+		"Valid": {
+			input: `
+#CustomerName, Mon, Tue, Wed, Thu, Fri, Sat, Sun
+Acme, 1.2, 1, 1, 1, 0.9, 0.5, 0
+Globex, 1, 1, 1, 1, 1, 2, 2
+`,
+			want: models.WeeklyPattern{
+				"Acme":   {time.Sunday: 0, time.Monday: 1.2, time.Tuesday: 1, time.Wednesday: 1, time.Thursday: 1, time.Friday: 0.9, time.Saturday: 0.5},
+				"Globex": {time.Sunday: 2, time.Monday: 1, time.Tuesday: 1, time.Wednesday: 1, time.Thursday: 1, time.Friday: 1, time.Saturday: 2},
+			},
+		},
+		"MissingDay": {
+			input:   "Acme, 1, 1, 1, 1, 1, 1",
+			wantErr: customerrors.ErrInvalidFieldCount,
+		},
+		"Negative": {
+			input:   "Acme, 1, 1, 1, 1, 1, 1, -1",
+			wantErr: customerrors.ErrInvalidMultiplier,
+		},
+		"NotANumber": {
+			input:   "Acme, 1, 1, high, 1, 1, 1, 1",
+			wantErr: customerrors.ErrInvalidMultiplier,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := parser.ParseWeeklyPattern(strings.NewReader(strings.TrimSpace(tt.input)))
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				var parseErr *customerrors.ParseError
+				assert.ErrorAs(t, err, &parseErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
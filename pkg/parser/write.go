@@ -0,0 +1,41 @@
+package parser
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+)
+
+// WriteCSV writes data in the input format Parse reads, starting a new
+// timezone header whenever the location changes. Times are written as
+// "3:04PM" in each row's location. Only the six input columns are written;
+// optional attributes such as SLA targets are dropped.
+func WriteCSV(w io.Writer, data []models.CallData) error {
+	cw := csv.NewWriter(w)
+	var current string
+	for i, cd := range data {
+		loc := cd.Location.Name()
+		if i == 0 || loc != current {
+			current = loc
+			header := []string{"#CustomerName", "AverageCallDurationSeconds", "StartTime" + loc, "EndTime" + loc, "NumberOfCalls", "Priority"}
+			if err := cw.Write(header); err != nil {
+				return err
+			}
+		}
+		record := []string{
+			cd.CustomerName,
+			strconv.Itoa(cd.AverageCallDurationSeconds),
+			cd.Location.In(cd.StartTime).Format("3:04PM"),
+			cd.Location.In(cd.EndTime).Format("3:04PM"),
+			strconv.Itoa(cd.NumberOfCalls),
+			strconv.Itoa(int(cd.Priority)),
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,48 @@
+package parser_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+	"github.com/karthikrao-23/agentscheduler/pkg/parser"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteCSV(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	require.NoError(t, err)
+	day := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	data := []models.CallData{
+		{CustomerName: "Acme", AverageCallDurationSeconds: 300, StartTime: day.Add(9 * time.Hour), EndTime: day.Add(17*time.Hour + 30*time.Minute),
+			Location: models.NewLocation(time.UTC), NumberOfCalls: 100, Priority: models.PriorityCritical},
+		{CustomerName: "Globex", AverageCallDurationSeconds: 600, StartTime: day.Add(10 * time.Hour), EndTime: day.Add(12 * time.Hour),
+			Location: models.NewLocation(time.UTC), NumberOfCalls: 20, Priority: models.PriorityLow},
+		{CustomerName: "Initech", AverageCallDurationSeconds: 120, StartTime: time.Date(2026, 3, 2, 8, 0, 0, 0, tokyo), EndTime: time.Date(2026, 3, 2, 20, 0, 0, 0, tokyo),
+			Location: models.NewLocation(tokyo), NumberOfCalls: 50, Priority: models.PriorityHigh},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, parser.WriteCSV(&buf, data))
+	assert.Equal(t, "#CustomerName,AverageCallDurationSeconds,StartTimeUTC,EndTimeUTC,NumberOfCalls,Priority\n"+
+		"Acme,300,9:00AM,5:30PM,100,1\n"+
+		"Globex,600,10:00AM,12:00PM,20,4\n"+
+		"#CustomerName,AverageCallDurationSeconds,StartTimeAsia/Tokyo,EndTimeAsia/Tokyo,NumberOfCalls,Priority\n"+
+		"Initech,120,8:00AM,8:00PM,50,2\n", buf.String())
+
+	// The output parses back to the same rows
+	parsed, err := parser.Parse(&buf)
+	require.NoError(t, err)
+	require.Len(t, parsed, 3)
+	for i, cd := range parsed {
+		assert.Equal(t, data[i].CustomerName, cd.CustomerName)
+		assert.Equal(t, data[i].Location.Name(), cd.Location.Name())
+		assert.Equal(t, data[i].StartTime.Hour(), cd.StartTime.Hour())
+		assert.Equal(t, data[i].EndTime.Minute(), cd.EndTime.Minute())
+		assert.Equal(t, data[i].NumberOfCalls, cd.NumberOfCalls)
+		assert.Equal(t, data[i].Priority, cd.Priority)
+	}
+}
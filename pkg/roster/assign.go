@@ -0,0 +1,250 @@
+package roster
+
+import (
+	"cmp"
+	"encoding/csv"
+	"io"
+	"math"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+	"github.com/karthikrao-23/agentscheduler/pkg/shifts"
+)
+
+// Assignment is an agent put to work for a slot, on a customer's calls, or
+// for a shift.
+type Assignment struct {
+	Start, End time.Time
+	// Customer is empty for a shift
+	Customer string
+	Agent    models.Agent
+}
+
+// Gap is work in a slot or shift that no agent on the roster was free for.
+type Gap struct {
+	Start, End time.Time
+	// Customer is empty for a shift
+	Customer string
+	Agents   int
+}
+
+// Coverage is the roster put against a schedule or its shifts.
+type Coverage struct {
+	// Assignments and Gaps are in slot or shift order
+	Assignments []Assignment
+	Gaps        []Gap
+}
+
+// Short returns the agents the gaps are short, across all of them.
+func (c *Coverage) Short() int {
+	total := 0
+	for _, g := range c.Gaps {
+		total += g.Agents
+	}
+	return total
+}
+
+// span is a stretch of time an agent works.
+type span struct {
+	start, end time.Time
+}
+
+// overlaps reports whether s and o share any instant.
+func (s span) overlaps(o span) bool {
+	return s.start.Before(o.end) && o.start.Before(s.end)
+}
+
+// Assign puts agents on every customer's allocated agents in schedule, slot
+// by slot. An agent covers a customer in a slot if it takes the customer,
+// is available for the whole slot, and is not already covering another
+// customer then, in this slot or, for customers in other locations, any
+// other slot at the same instant. Customers are served in priority order,
+// then by name; each gets agents who covered it up to the slot's start
+// first, so agents stay on one customer, and then the agents with the
+// fewest skills, so those who can take other customers are left for them.
+// Slots are read in each customer's location, unless they have their own,
+// and those of a generic day are placed on date.
+func Assign(schedule *models.Schedule, agents []models.Agent, date time.Time) *Coverage {
+	coverage := &Coverage{}
+	// working is when each agent is covering a customer, and previous the
+	// customer of its latest assignment
+	working := make(map[string][]span)
+	previous := make(map[string]Assignment)
+	for h, reqs := range schedule.HourlyRequirements {
+		slot := schedule.SlotAt(h)
+		wall := slot.Location.In(slot.Start)
+		if !slot.HasDate() {
+			wall = time.Date(date.Year(), date.Month(), date.Day(), wall.Hour(), wall.Minute(), 0, 0, time.UTC)
+		}
+		reqs = slices.Clone(reqs)
+		slices.SortStableFunc(reqs, func(a, b models.CustomerRequirement) int {
+			return cmp.Or(cmp.Compare(a.Priority, b.Priority), cmp.Compare(a.Name, b.Name))
+		})
+
+		for _, req := range reqs {
+			// A slot with a location is the same moment for every customer
+			loc := req.Location.Time()
+			if !slot.Location.IsZero() {
+				loc = slot.Location.Time()
+			}
+			if loc == nil {
+				loc = time.UTC
+			}
+			start := time.Date(wall.Year(), wall.Month(), wall.Day(), wall.Hour(), wall.Minute(), 0, 0, loc)
+			end := start.Add(slot.Duration)
+			at := span{start, end}
+			// An agent stays on a customer it covered right up to start
+			stays := func(i int) bool {
+				p, ok := previous[agents[i].ID]
+				return ok && p.Customer == req.Name && p.End.Equal(start)
+			}
+
+			var free []int
+			for i, agent := range agents {
+				if !slices.ContainsFunc(working[agent.ID], at.overlaps) && takes(agent, req) && availableFor(agent, start, end) {
+					free = append(free, i)
+				}
+			}
+			slices.SortStableFunc(free, func(i, j int) int {
+				if si, sj := stays(i), stays(j); si != sj {
+					if si {
+						return -1
+					}
+					return 1
+				}
+				return cmp.Compare(breadth(agents[i]), breadth(agents[j]))
+			})
+
+			got := min(req.AgentsNeeded, len(free))
+			for _, i := range free[:got] {
+				a := Assignment{Start: start, End: end, Customer: req.Name, Agent: agents[i]}
+				working[agents[i].ID] = append(working[agents[i].ID], at)
+				previous[agents[i].ID] = a
+				coverage.Assignments = append(coverage.Assignments, a)
+			}
+			if got < req.AgentsNeeded {
+				coverage.Gaps = append(coverage.Gaps, Gap{Start: start, End: end, Customer: req.Name, Agents: req.AgentsNeeded - got})
+			}
+		}
+	}
+	return coverage
+}
+
+// AssignShifts puts agents on plan's shifts, in order of start. An agent
+// works a shift if available for all of it, and not on another shift that
+// overlaps it. A shift's wall-clock times are read in each agent's own
+// location, as schedules are laid out in each customer's, and those of a
+// generic day are placed on date. Agents free for the fewest shifts are
+// put on them first, so the shifts only they can work keep them.
+func AssignShifts(plan *shifts.Plan, agents []models.Agent, date time.Time) *Coverage {
+	// Each shift as wall-clock times, and each agent as when it can work it
+	walls := make([]span, len(plan.Shifts))
+	can := make([][]bool, len(agents))
+	options := make([]int, len(agents))
+	for s, shift := range plan.Shifts {
+		start := shift.Start
+		if start.Year() == 0 {
+			start = time.Date(date.Year(), date.Month(), date.Day(), start.Hour(), start.Minute(), 0, 0, time.UTC)
+		}
+		walls[s] = span{start, start.Add(shift.Length)}
+	}
+	for i, agent := range agents {
+		can[i] = make([]bool, len(plan.Shifts))
+		for s, wall := range walls {
+			start, end := inLocation(wall.start, agent.Location), inLocation(wall.end, agent.Location)
+			if availableFor(agent, start, end) {
+				can[i][s] = true
+				options[i]++
+			}
+		}
+	}
+	order := make([]int, len(agents))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(i, j int) int {
+		return cmp.Compare(options[i], options[j])
+	})
+
+	coverage := &Coverage{}
+	working := make([][]span, len(agents))
+	for s, shift := range plan.Shifts {
+		got := 0
+		for _, i := range order {
+			if got == shift.Agents {
+				break
+			}
+			if !can[i][s] || slices.ContainsFunc(working[i], walls[s].overlaps) {
+				continue
+			}
+			working[i] = append(working[i], walls[s])
+			got++
+			coverage.Assignments = append(coverage.Assignments, Assignment{Start: walls[s].start, End: walls[s].end, Agent: agents[i]})
+		}
+		if got < shift.Agents {
+			coverage.Gaps = append(coverage.Gaps, Gap{Start: walls[s].start, End: walls[s].end, Agents: shift.Agents - got})
+		}
+	}
+	return coverage
+}
+
+// takes reports whether agent can take req's calls: skills name customers,
+// or the skill a customer's requirement calls for, and an agent without any
+// can take every customer.
+func takes(agent models.Agent, req models.CustomerRequirement) bool {
+	return len(agent.Skills) == 0 || agent.HasSkill(req.Name) || (req.Skill != "" && agent.HasSkill(req.Skill))
+}
+
+// breadth ranks how many customers agent can take; one without skills can
+// take any.
+func breadth(agent models.Agent) int {
+	if len(agent.Skills) == 0 {
+		return math.MaxInt
+	}
+	return len(agent.Skills)
+}
+
+// inLocation returns wall-clock time wall in loc, or in UTC when loc is
+// unset.
+func inLocation(wall time.Time, loc models.Location) time.Time {
+	l := loc.Time()
+	if l == nil {
+		l = time.UTC
+	}
+	return time.Date(wall.Year(), wall.Month(), wall.Day(), wall.Hour(), wall.Minute(), 0, 0, l)
+}
+
+// availableFor reports whether agent is available throughout [start, end),
+// checked every 15 minutes, the finest slot a schedule has.
+func availableFor(agent models.Agent, start, end time.Time) bool {
+	for t := start; t.Before(end); t = t.Add(15 * time.Minute) {
+		if !agent.AvailableAt(t) {
+			return false
+		}
+	}
+	return true
+}
+
+// WriteCoverage writes coverage as CSV: a row per assignment with its agent,
+// then a row per gap with the agents it is short.
+func WriteCoverage(w io.Writer, coverage *Coverage) error {
+	const layout = "2006-01-02 15:04"
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"Start", "End", "Customer", "Agent ID", "Agent Name", "Short"}); err != nil {
+		return err
+	}
+	for _, a := range coverage.Assignments {
+		if err := cw.Write([]string{a.Start.Format(layout), a.End.Format(layout), a.Customer, a.Agent.ID, a.Agent.Name, ""}); err != nil {
+			return err
+		}
+	}
+	for _, g := range coverage.Gaps {
+		if err := cw.Write([]string{g.Start.Format(layout), g.End.Format(layout), g.Customer, "", "", strconv.Itoa(g.Agents)}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
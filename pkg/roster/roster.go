@@ -0,0 +1,212 @@
+// Package roster loads agents from HR and roster systems for roster
+// assignment, so assignments are made against real staff rather than a
+// hand-built file. Assign and AssignShifts then put them on a schedule or
+// its shifts, reporting where the roster falls short.
+//
+// Every source supplies the same six fields per agent:
+//
+//	id, name, skills, site, timezone, availability
+//
+// Skills are separated by ";". Availability is a ";"-separated list of
+// windows such as "Mon-Fri 09:00-17:00" or "Sat 22:00-06:00" (overnight), in
+// the agent's time zone; "Daily" covers every day. Empty availability means
+// always available.
+package roster
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+)
+
+// Columns are the roster CSV header, in order.
+var Columns = []string{"id", "name", "skills", "site", "timezone", "availability"}
+
+// ParseCSV reads agents from CSV with a Columns header row. Columns may
+// appear in any order; unknown columns are ignored.
+func ParseCSV(r io.Reader) ([]models.Agent, error) {
+	cr := csv.NewReader(r)
+	cr.TrimLeadingSpace = true
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("reading roster header: %w", err)
+	}
+	index := make(map[string]int)
+	for i, name := range header {
+		index[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, col := range []string{"id", "name"} {
+		if _, ok := index[col]; !ok {
+			return nil, fmt.Errorf("roster is missing the %q column", col)
+		}
+	}
+
+	var agents []models.Agent
+	for line := 2; ; line++ {
+		record, err := cr.Read()
+		if err == io.EOF {
+			return agents, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading roster line %d: %w", line, err)
+		}
+		field := func(col string) string {
+			if i, ok := index[col]; ok && i < len(record) {
+				return record[i]
+			}
+			return ""
+		}
+		agent, err := NewAgent(field("id"), field("name"), field("skills"), field("site"), field("timezone"), field("availability"))
+		if err != nil {
+			return nil, fmt.Errorf("roster line %d: %w", line, err)
+		}
+		agents = append(agents, agent)
+	}
+}
+
+// NewAgent builds an agent from the six roster fields in their text form.
+func NewAgent(id, name, skills, site, timezone, availability string) (models.Agent, error) {
+	agent := models.Agent{
+		ID:   strings.TrimSpace(id),
+		Name: strings.TrimSpace(name),
+		Site: strings.TrimSpace(site),
+	}
+	if agent.ID == "" {
+		return models.Agent{}, fmt.Errorf("agent %q has no id", agent.Name)
+	}
+	for skill := range strings.SplitSeq(skills, ";") {
+		if skill = strings.TrimSpace(skill); skill != "" {
+			agent.Skills = append(agent.Skills, skill)
+		}
+	}
+
+	var err error
+	if agent.Location, err = models.LoadLocation(strings.TrimSpace(timezone)); err != nil {
+		return models.Agent{}, fmt.Errorf("agent %s: %w", agent.ID, err)
+	}
+	if agent.Availability, err = ParseAvailability(availability); err != nil {
+		return models.Agent{}, fmt.Errorf("agent %s: %w", agent.ID, err)
+	}
+	return agent, nil
+}
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// ParseAvailability parses a ";"-separated list of windows such as
+// "Mon-Fri 09:00-17:00", expanding day ranges into one window per day.
+func ParseAvailability(s string) ([]models.Availability, error) {
+	var windows []models.Availability
+	for spec := range strings.SplitSeq(s, ";") {
+		spec = strings.TrimSpace(spec)
+		if spec == "" {
+			continue
+		}
+		days, hours, ok := strings.Cut(spec, " ")
+		if !ok {
+			return nil, fmt.Errorf("invalid availability %q: want days and hours, e.g. Mon-Fri 09:00-17:00", spec)
+		}
+		first, last, err := parseDays(days)
+		if err != nil {
+			return nil, fmt.Errorf("invalid availability %q: %w", spec, err)
+		}
+		start, end, err := parseHours(strings.TrimSpace(hours))
+		if err != nil {
+			return nil, fmt.Errorf("invalid availability %q: %w", spec, err)
+		}
+		for d := first; ; d = (d + 1) % 7 {
+			windows = append(windows, models.Availability{Weekday: d, Start: start, End: end})
+			if d == last {
+				break
+			}
+		}
+	}
+	return windows, nil
+}
+
+// parseDays parses "Daily", "Mon", or a range such as "Mon-Fri" or
+// "Sat-Sun"; ranges may wrap around the week.
+func parseDays(s string) (first, last time.Weekday, err error) {
+	if strings.EqualFold(s, "daily") {
+		return time.Sunday, time.Saturday, nil
+	}
+	from, to, isRange := strings.Cut(s, "-")
+	if !isRange {
+		to = from
+	}
+	first, ok := weekdays[strings.ToLower(from)]
+	if !ok {
+		return 0, 0, fmt.Errorf("unknown day %q", from)
+	}
+	if last, ok = weekdays[strings.ToLower(to)]; !ok {
+		return 0, 0, fmt.Errorf("unknown day %q", to)
+	}
+	return first, last, nil
+}
+
+// parseHours parses "09:00-17:00" into offsets from midnight. An end at or
+// before the start is an overnight window ending the next day.
+func parseHours(s string) (start, end time.Duration, err error) {
+	from, to, ok := strings.Cut(s, "-")
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid hours %q: want HH:MM-HH:MM", s)
+	}
+	if start, err = parseClock(from); err != nil {
+		return 0, 0, err
+	}
+	if end, err = parseClock(to); err != nil {
+		return 0, 0, err
+	}
+	if end <= start {
+		end += 24 * time.Hour
+	}
+	return start, end, nil
+}
+
+func parseClock(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(s))
+	if err != nil {
+		if strings.TrimSpace(s) == "24:00" {
+			return 24 * time.Hour, nil
+		}
+		return 0, fmt.Errorf("invalid time %q: want HH:MM", s)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// WriteCSV writes agents as roster CSV that ParseCSV reads back, with one
+// availability window per day.
+func WriteCSV(w io.Writer, agents []models.Agent) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(Columns); err != nil {
+		return err
+	}
+	for _, a := range agents {
+		record := []string{a.ID, a.Name, strings.Join(a.Skills, ";"), a.Site, a.Location.Name(), FormatAvailability(a.Availability)}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// FormatAvailability formats windows in the form ParseAvailability reads,
+// e.g. "Mon 09:00-17:00;Tue 09:00-17:00".
+func FormatAvailability(windows []models.Availability) string {
+	specs := make([]string, len(windows))
+	for i, w := range windows {
+		specs[i] = fmt.Sprintf("%s %s-%s", w.Weekday.String()[:3], formatClock(w.Start), formatClock(w.End%(24*time.Hour)))
+	}
+	return strings.Join(specs, ";")
+}
+
+func formatClock(d time.Duration) string {
+	return fmt.Sprintf("%02d:%02d", int(d.Hours()), int(d.Minutes())%60)
+}
@@ -0,0 +1,238 @@
+package roster_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+	"github.com/karthikrao-23/agentscheduler/pkg/roster"
+	"github.com/karthikrao-23/agentscheduler/pkg/shifts"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const rosterCSV = `id,name,skills,site,timezone,availability
+a1,Ada Lovelace,Acme;Globex,London,Europe/London,Mon-Fri 09:00-17:00
+a2,Grace Hopper,Acme,remote,America/New_York,Sat-Sun 22:00-06:00
+a3,Alan Turing,,London,,
+`
+
+func TestParseCSV(t *testing.T) {
+	agents, err := roster.ParseCSV(strings.NewReader(rosterCSV))
+	require.NoError(t, err)
+	require.Len(t, agents, 3)
+
+	ada := agents[0]
+	assert.Equal(t, "a1", ada.ID)
+	assert.Equal(t, "Ada Lovelace", ada.Name)
+	assert.Equal(t, []string{"Acme", "Globex"}, ada.Skills)
+	assert.Equal(t, "London", ada.Site)
+	assert.Equal(t, "Europe/London", ada.Location.Name())
+	assert.Len(t, ada.Availability, 5)
+
+	grace := agents[1]
+	require.Len(t, grace.Availability, 2)
+	assert.Equal(t, models.Availability{Weekday: time.Saturday, Start: 22 * time.Hour, End: 30 * time.Hour}, grace.Availability[0])
+
+	assert.Empty(t, agents[2].Skills)
+	assert.Empty(t, agents[2].Availability)
+}
+
+func TestParseCSV_Errors(t *testing.T) {
+	tests := map[string]struct {
+		input string
+		err   string
+	}{
+		"missing id column": {
+			input: "name,skills\nAda,Acme\n",
+			err:   `missing the "id" column`,
+		},
+		"empty id": {
+			input: "id,name\n,Ada\n",
+			err:   "line 2",
+		},
+		"invalid timezone": {
+			input: "id,name,timezone\na1,Ada,Mars/Olympus\n",
+			err:   "invalid location",
+		},
+		"invalid day": {
+			input: "id,name,availability\na1,Ada,Funday 09:00-17:00\n",
+			err:   `unknown day "Funday"`,
+		},
+		"invalid hours": {
+			input: "id,name,availability\na1,Ada,Mon 9am-5pm\n",
+			err:   "want HH:MM",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := roster.ParseCSV(strings.NewReader(tc.input))
+			assert.ErrorContains(t, err, tc.err)
+		})
+	}
+}
+
+func TestParseAvailability(t *testing.T) {
+	tests := map[string]struct {
+		spec string
+		want []time.Weekday
+	}{
+		"single day":    {spec: "Wed 09:00-17:00", want: []time.Weekday{time.Wednesday}},
+		"range":         {spec: "Mon-Wed 09:00-17:00", want: []time.Weekday{time.Monday, time.Tuesday, time.Wednesday}},
+		"wrapping":      {spec: "Fri-Mon 09:00-17:00", want: []time.Weekday{time.Friday, time.Saturday, time.Sunday, time.Monday}},
+		"daily":         {spec: "daily 00:00-24:00", want: []time.Weekday{0, 1, 2, 3, 4, 5, 6}},
+		"several specs": {spec: "Mon 09:00-12:00; Tue 13:00-17:00", want: []time.Weekday{time.Monday, time.Tuesday}},
+		"empty":         {spec: ""},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			windows, err := roster.ParseAvailability(tc.spec)
+			require.NoError(t, err)
+			var days []time.Weekday
+			for _, w := range windows {
+				days = append(days, w.Weekday)
+			}
+			assert.Equal(t, tc.want, days)
+		})
+	}
+}
+
+func TestWriteCSV_RoundTrip(t *testing.T) {
+	agents, err := roster.ParseCSV(strings.NewReader(rosterCSV))
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, roster.WriteCSV(&buf, agents))
+	assert.Contains(t, buf.String(), "a2,Grace Hopper,Acme,remote,America/New_York,Sat 22:00-06:00;Sun 22:00-06:00\n")
+
+	again, err := roster.ParseCSV(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, agents, again)
+}
+
+func TestWorkday(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, _ := r.BasicAuth()
+		assert.Equal(t, "isu", user)
+		assert.Equal(t, "secret", pass)
+		assert.Equal(t, "json", r.URL.Query().Get("format"))
+		w.Write([]byte(`{"Report_Entry":[
+			{"Employee_ID":"1001","Worker":"Ada Lovelace","Skills":"Acme; Globex","Location":"London","Time_Zone":"Europe/London","Availability":"Mon-Fri 09:00-17:00"}
+		]}`))
+	}))
+	defer srv.Close()
+
+	w := &roster.Workday{ReportURL: srv.URL + "/ccx/service/customreport2/acme/ISU/Agent_Roster", Username: "isu", Password: "secret"}
+	agents, err := w.Agents(context.Background())
+	require.NoError(t, err)
+	require.Len(t, agents, 1)
+	assert.Equal(t, "1001", agents[0].ID)
+	assert.Equal(t, []string{"Acme", "Globex"}, agents[0].Skills)
+	assert.True(t, agents[0].AvailableAt(time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)), "Monday 10:00 in London")
+}
+
+func TestWorkday_Error(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "invalid credentials", http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	_, err := (&roster.Workday{ReportURL: srv.URL}).Agents(context.Background())
+	assert.ErrorContains(t, err, "401")
+}
+
+func TestAssign(t *testing.T) {
+	agents, err := roster.ParseCSV(strings.NewReader(`id,name,skills,site,timezone,availability
+a1,Ada,Acme;Globex,,UTC,Mon 09:00-17:00
+a2,Bob,Acme,,UTC,Mon 09:00-12:00
+a3,Cy,,,UTC,
+`))
+	require.NoError(t, err)
+	reqs := make([][]models.CustomerRequirement, 24)
+	reqs[9] = []models.CustomerRequirement{
+		{Name: "Globex", AgentsNeeded: 1, Priority: 2},
+		{Name: "Acme", AgentsNeeded: 2, Priority: 1},
+	}
+	reqs[10] = []models.CustomerRequirement{{Name: "Acme", AgentsNeeded: 1, Priority: 1}}
+	reqs[12] = []models.CustomerRequirement{{Name: "Acme", AgentsNeeded: 3, Priority: 1}}
+	monday := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+
+	coverage := roster.Assign(&models.Schedule{HourlyRequirements: reqs}, agents, monday)
+	var got []string
+	for _, a := range coverage.Assignments {
+		got = append(got, a.Start.Format("15:04")+" "+a.Customer+" "+a.Agent.ID)
+	}
+	// Agents stay on their customer, those with fewest skills first, and
+	// Cy, who can take anyone, last; Bob is gone by 12:00
+	assert.Equal(t, []string{
+		"09:00 Acme a2", "09:00 Acme a1", "09:00 Globex a3",
+		"10:00 Acme a2",
+		"12:00 Acme a1", "12:00 Acme a3",
+	}, got)
+	assert.Equal(t, []roster.Gap{{Start: monday.Add(12 * time.Hour), End: monday.Add(13 * time.Hour), Customer: "Acme", Agents: 1}}, coverage.Gaps)
+	assert.Equal(t, 1, coverage.Short())
+
+	var buf bytes.Buffer
+	require.NoError(t, roster.WriteCoverage(&buf, coverage))
+	assert.Contains(t, buf.String(), "Start,End,Customer,Agent ID,Agent Name,Short\n2026-03-02 09:00,2026-03-02 10:00,Acme,a2,Bob,\n")
+	assert.Contains(t, buf.String(), "2026-03-02 12:00,2026-03-02 13:00,Acme,,,1\n")
+}
+
+func TestAssign_TimeZones(t *testing.T) {
+	agents, err := roster.ParseCSV(strings.NewReader(`id,name,skills,site,timezone,availability
+a1,Ada,,,UTC,
+`))
+	require.NoError(t, err)
+	newYork, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	london, err := time.LoadLocation("Europe/London")
+	require.NoError(t, err)
+	// In summer, 04:00 in New York and 09:00 in London are both 08:00 UTC
+	reqs := make([][]models.CustomerRequirement, 24)
+	reqs[4] = []models.CustomerRequirement{{Name: "Initech", AgentsNeeded: 1, Priority: 1, Location: models.NewLocation(newYork)}}
+	reqs[9] = []models.CustomerRequirement{{Name: "Hooli", AgentsNeeded: 1, Priority: 1, Location: models.NewLocation(london)}}
+	reqs[10] = []models.CustomerRequirement{{Name: "Hooli", AgentsNeeded: 1, Priority: 1, Location: models.NewLocation(london)}}
+	monday := time.Date(2026, 7, 6, 0, 0, 0, 0, time.UTC)
+
+	coverage := roster.Assign(&models.Schedule{HourlyRequirements: reqs}, agents, monday)
+	var got []string
+	for _, a := range coverage.Assignments {
+		got = append(got, a.Start.UTC().Format("15:04")+" "+a.Customer+" "+a.Agent.ID)
+	}
+	assert.Equal(t, []string{"08:00 Initech a1", "09:00 Hooli a1"}, got, "Ada is not on two customers at 08:00 UTC")
+	require.Len(t, coverage.Gaps, 1)
+	assert.Equal(t, "Hooli", coverage.Gaps[0].Customer)
+	assert.True(t, coverage.Gaps[0].Start.Equal(time.Date(2026, 7, 6, 8, 0, 0, 0, time.UTC)))
+}
+
+func TestAssignShifts(t *testing.T) {
+	agents, err := roster.ParseCSV(strings.NewReader(`id,name,skills,site,timezone,availability
+a1,Ada,,,UTC,Daily 00:00-24:00
+a2,Bob,,,UTC,Mon 09:00-17:00
+a3,Cy,,,UTC,Mon 17:00-01:00
+`))
+	require.NoError(t, err)
+	at := func(hour int) time.Time {
+		return time.Date(0, 1, 1, hour, 0, 0, 0, time.UTC)
+	}
+	plan := &shifts.Plan{Shifts: []shifts.Shift{
+		{Start: at(9), Length: 8 * time.Hour, Agents: 3},
+		{Start: at(17), Length: 8 * time.Hour, Agents: 1},
+	}}
+
+	coverage := roster.AssignShifts(plan, agents, time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC))
+	var got []string
+	for _, a := range coverage.Assignments {
+		got = append(got, a.Start.Format("15:04")+" "+a.Agent.ID)
+	}
+	// Ada could work either, so Bob and Cy are placed first
+	assert.Equal(t, []string{"09:00 a2", "09:00 a1", "17:00 a3"}, got)
+	require.Len(t, coverage.Gaps, 1)
+	assert.Equal(t, 1, coverage.Gaps[0].Agents)
+}
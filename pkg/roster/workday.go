@@ -0,0 +1,84 @@
+package roster
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+)
+
+// Workday reads agents from a Workday custom report published as a web
+// service (RaaS). The report must expose the fields Employee_ID, Worker,
+// Skills, Location (the agent's site), Time_Zone, and Availability, with
+// Skills and Availability in the roster text form.
+type Workday struct {
+	// ReportURL is the report's RaaS endpoint, e.g.
+	// https://wd2-impl-services1.workday.com/ccx/service/customreport2/acme/ISU/Agent_Roster
+	ReportURL string
+	Username  string
+	Password  string
+	// Client sends the request; defaults to http.DefaultClient
+	Client *http.Client
+}
+
+type workdayEntry struct {
+	EmployeeID   string `json:"Employee_ID"`
+	Worker       string `json:"Worker"`
+	Skills       string `json:"Skills"`
+	Location     string `json:"Location"`
+	TimeZone     string `json:"Time_Zone"`
+	Availability string `json:"Availability"`
+}
+
+// Agents fetches the report and returns its agents.
+func (w *Workday) Agents(ctx context.Context) ([]models.Agent, error) {
+	endpoint, err := url.Parse(w.ReportURL)
+	if err != nil {
+		return nil, fmt.Errorf("workday: invalid report URL: %w", err)
+	}
+	params := endpoint.Query()
+	params.Set("format", "json")
+	endpoint.RawQuery = params.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("workday: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(w.Username, w.Password)
+
+	client := w.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("workday: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("workday: unexpected status %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+
+	var report struct {
+		Entries []workdayEntry `json:"Report_Entry"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&report); err != nil {
+		return nil, fmt.Errorf("workday: decoding report: %w", err)
+	}
+	agents := make([]models.Agent, 0, len(report.Entries))
+	for _, e := range report.Entries {
+		agent, err := NewAgent(e.EmployeeID, e.Worker, e.Skills, e.Location, e.TimeZone, e.Availability)
+		if err != nil {
+			return nil, fmt.Errorf("workday: %w", err)
+		}
+		agents = append(agents, agent)
+	}
+	return agents, nil
+}
@@ -0,0 +1,182 @@
+package scheduler
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+)
+
+// WithAgentPools staffs every slot from pools rather than from one
+// interchangeable capacity: each customer's calls can only go to pools that
+// serve the skill they require, so a slot can fall short on one skill while
+// another pool has agents to spare. The headcount of the pools open in a
+// slot is its capacity, which rules out WithCapacity and
+// WithCapacityProfile; a slot no pool is open for leaves all of its demand
+// unmet. Allocation is by PoolAllocator, or by OptimalAllocator given with
+// WithAllocator before or after, which is then handed the pools; other
+// allocators do not route by skill and are rejected. The agents drawn from
+// each pool, with their cost, are published as metrics.
+func WithAgentPools(pools []models.AgentPool) Option {
+	return func(c *config) {
+		c.pools = pools
+	}
+}
+
+// PoolAllocator is the Allocator for skill-based routing: it fills requests
+// in the same priority order as PriorityAllocator, but from the pools that
+// serve each request's skill. Pools are drawn on by tier, so demand only
+// overflows into a pool once those of lower tiers are used up, and within a
+// tier the most specialized first, so generalists are left for calls that
+// only they can take, then the cheapest. As with
+// PriorityAllocator, guarantees (MinAgents) are handed out first. Unmet demand
+// carries each impacted client's skill; see models.UnmetDemand.UnmetBySkill.
+type PoolAllocator struct {
+	Pools []models.AgentPool
+
+	// used, if set, receives the agents drawn from each pool
+	used []int
+}
+
+// Allocate shares out the pools' headcount, with capacity capping the total.
+func (a PoolAllocator) Allocate(requests []models.CustomerRequirement, capacity int) ([]models.CustomerRequirement, *models.UnmetDemand) {
+	if len(requests) == 0 {
+		return nil, nil
+	}
+
+	totalDemand := sumDemand(requests)
+	sortRequests(requests)
+
+	// Pools in drawing order, each with the agents it has left
+	order := make([]int, len(a.Pools))
+	free := make([]int, len(a.Pools))
+	for i, pool := range a.Pools {
+		order[i] = i
+		free[i] = pool.Headcount
+	}
+	slices.SortStableFunc(order, func(i, j int) int {
+		return cmp.Or(
+			cmp.Compare(a.Pools[i].Tier, a.Pools[j].Tier),
+			cmp.Compare(len(a.Pools[i].Skills), len(a.Pools[j].Skills)),
+			cmp.Compare(a.Pools[i].HourlyCost, a.Pools[j].HourlyCost),
+		)
+	})
+
+	allocated := make([]models.CustomerRequirement, 0, len(requests))
+	impactedClients := impactedPool.get()
+	defer func() { impactedPool.put(impactedClients) }()
+	remaining := capacity
+	unmetAgents := 0
+	// draw takes up to want agents for req from the pools serving its skill
+	draw := func(req models.CustomerRequirement, want int) int {
+		got := 0
+		for _, i := range order {
+			if got == want || remaining == 0 {
+				break
+			}
+			if !a.Pools[i].Serves(req.Skill) {
+				continue
+			}
+			n := min(free[i], want-got, remaining)
+			free[i] -= n
+			if a.used != nil {
+				a.used[i] += n
+			}
+			remaining -= n
+			got += n
+		}
+		return got
+	}
+	guaranteed := make([]int, len(requests))
+	for i, req := range requests {
+		if req.MinAgents > 0 {
+			guaranteed[i] = draw(req, min(req.MinAgents, req.AgentsNeeded))
+		}
+	}
+
+	for i, req := range requests {
+		got := guaranteed[i] + draw(req, req.AgentsNeeded-guaranteed[i])
+
+		if got > 0 || req.AgentsNeeded == 0 {
+			partial := req
+			partial.AgentsNeeded = got
+			allocated = append(allocated, partial)
+		}
+		if got < req.AgentsNeeded {
+			impactedClients = append(impactedClients, models.ImpactedClient{
+				Name:            req.Name,
+				RequestedAgents: req.AgentsNeeded,
+				AllocatedAgents: got,
+				UnmetAgents:     req.AgentsNeeded - got,
+				Priority:        req.Priority,
+				Location:        req.Location,
+				Skill:           req.Skill,
+				MinAgents:       req.MinAgents,
+			})
+			unmetAgents += req.AgentsNeeded - got
+		}
+	}
+
+	if len(impactedClients) > 0 {
+		return allocated, &models.UnmetDemand{
+			TotalDemand:     totalDemand,
+			AllocatedAgents: totalDemand - unmetAgents,
+			UnmetAgents:     unmetAgents,
+			ImpactedClients: slices.Clone(impactedClients),
+		}
+	}
+	return allocated, nil
+}
+
+// headcount returns the agents across all pools.
+func headcount(pools []models.AgentPool) int {
+	total := 0
+	for _, pool := range pools {
+		total += pool.Headcount
+	}
+	return total
+}
+
+// openPools returns c's pools as they stand in slot: those closed then have
+// no headcount. Pools keep their order, so the agents drawn from each line
+// up with c.pools.
+func (c config) openPools(slot models.TimeSlot) []models.AgentPool {
+	open := slices.Clone(c.pools)
+	for i := range open {
+		if !open[i].OpenAt(slot) {
+			open[i].Headcount = 0
+		}
+	}
+	return open
+}
+
+// withPools returns allocator drawing on pools, recording the agents it
+// draws from each in used. validate has ruled out allocators that do not
+// draw on pools.
+func withPools(allocator Allocator, pools []models.AgentPool, used []int) Allocator {
+	switch a := allocator.(type) {
+	case PoolAllocator:
+		a.Pools, a.used = pools, used
+		return a
+	case OptimalAllocator:
+		a.Pools, a.used = pools, used
+		return a
+	}
+	return allocator
+}
+
+// routesBySkill reports whether allocator can draw on agent pools.
+func routesBySkill(allocator Allocator) bool {
+	switch allocator.(type) {
+	case PoolAllocator, OptimalAllocator:
+		return true
+	}
+	return false
+}
+
+// clock formats d, an offset from midnight, as "15:04".
+func clock(d time.Duration) string {
+	return fmt.Sprintf("%02d:%02d", int(d.Hours()), int(d.Minutes())%60)
+}
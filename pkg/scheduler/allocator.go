@@ -0,0 +1,245 @@
+package scheduler
+
+import (
+	"slices"
+	"strconv"
+
+	"github.com/karthikrao-23/agentscheduler/pkg/metrics"
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+)
+
+// Allocator distributes one hour's capacity across the requests competing for
+// it. It returns the requirements as allocated and, when capacity falls short,
+// the unmet demand for the hour (with Hour left for the caller to set).
+// Implementations may reorder requests in place.
+type Allocator interface {
+	Allocate(requests []models.CustomerRequirement, capacity int) ([]models.CustomerRequirement, *models.UnmetDemand)
+}
+
+// PriorityAllocator is the default Allocator: strict priority order, so
+// lower-priority customers only get capacity once higher ones are fully met.
+type PriorityAllocator struct{}
+
+// Allocate fills requests in priority order (1 = highest, ties broken by
+// name, then by the remaining fields) until capacity runs out; the first
+// request that does not fit gets the remainder and every later request gets
+// nothing. Requests with MinAgents are first given their guarantee, in the
+// same order, and only the capacity left after that is filled by priority.
+// Hours with tens of thousands of requests are summed and sorted in shards,
+// concurrently.
+func (PriorityAllocator) Allocate(requests []models.CustomerRequirement, capacity int) ([]models.CustomerRequirement, *models.UnmetDemand) {
+	if len(requests) == 0 {
+		return nil, nil
+	}
+
+	totalDemand := sumDemand(requests)
+	sortRequests(requests)
+	if capacity >= totalDemand {
+		return requests, nil
+	}
+
+	allocated := make([]models.CustomerRequirement, 0, len(requests))
+	// Clients are gathered in pooled scratch space and copied out at their
+	// final size
+	impactedClients := impactedPool.get()
+	defer func() { impactedPool.put(impactedClients) }()
+	remaining := capacity
+	guaranteed := guarantees(requests, &remaining)
+
+	for i, req := range requests {
+		got := 0
+		if guaranteed != nil {
+			got = guaranteed[i]
+		}
+		extra := min(req.AgentsNeeded-got, max(remaining, 0))
+		got += extra
+		remaining -= extra
+
+		if got == req.AgentsNeeded {
+			// Full allocation
+			allocated = append(allocated, req)
+			continue
+		}
+		if got > 0 {
+			// Partial allocation - give what's left
+			partial := req
+			partial.AgentsNeeded = got
+			allocated = append(allocated, partial)
+		}
+		impactedClients = append(impactedClients, models.ImpactedClient{
+			Name:            req.Name,
+			RequestedAgents: req.AgentsNeeded,
+			AllocatedAgents: got,
+			UnmetAgents:     req.AgentsNeeded - got,
+			Priority:        req.Priority,
+			Location:        req.Location,
+			Skill:           req.Skill,
+			MinAgents:       req.MinAgents,
+		})
+	}
+
+	// Only create UnmetDemand if there are impacted clients
+	if len(impactedClients) > 0 {
+		return allocated, &models.UnmetDemand{
+			TotalDemand:     totalDemand,
+			AllocatedAgents: capacity,
+			UnmetAgents:     totalDemand - capacity,
+			ImpactedClients: slices.Clone(impactedClients),
+		}
+	}
+	return allocated, nil
+}
+
+// guarantees hands out each request's MinAgents, up to what it needs, from
+// *remaining in request order, and returns what each got; nil when no
+// request has a guarantee.
+func guarantees(requests []models.CustomerRequirement, remaining *int) []int {
+	if !slices.ContainsFunc(requests, func(req models.CustomerRequirement) bool { return req.MinAgents > 0 }) {
+		return nil
+	}
+	guaranteed := make([]int, len(requests))
+	for i, req := range requests {
+		guaranteed[i] = min(req.MinAgents, req.AgentsNeeded, max(*remaining, 0))
+		*remaining -= guaranteed[i]
+	}
+	return guaranteed
+}
+
+// ProportionalAllocator shares a short hour's capacity out pro rata to
+// demand, so every customer gets some agents rather than lower priorities
+// getting none. Priority only breaks ties when rounding.
+type ProportionalAllocator struct{}
+
+// Allocate gives each request capacity * AgentsNeeded / total demand agents,
+// rounded down; the agents rounding leaves over go one each to the requests
+// with the largest remainders, and between equal remainders in priority
+// order, so all of capacity is used. Requests with MinAgents are first given
+// their guarantee, and what is left of capacity is shared pro rata to the
+// demand beyond the guarantees.
+func (ProportionalAllocator) Allocate(requests []models.CustomerRequirement, capacity int) ([]models.CustomerRequirement, *models.UnmetDemand) {
+	if len(requests) == 0 {
+		return nil, nil
+	}
+
+	totalDemand := sumDemand(requests)
+	sortRequests(requests)
+	if capacity >= totalDemand {
+		return requests, nil
+	}
+
+	left := capacity
+	guaranteed := guarantees(requests, &left)
+	if guaranteed == nil {
+		guaranteed = make([]int, len(requests))
+	}
+	// Guarantees fit in capacity, which is short of demand, so some demand
+	// is always left to share by
+	shared, rest := left, totalDemand
+	for _, g := range guaranteed {
+		rest -= g
+	}
+
+	allocated := make([]models.CustomerRequirement, len(requests))
+	remainders := make([]int, len(requests))
+	for i, req := range requests {
+		want := req.AgentsNeeded - guaranteed[i]
+		allocated[i] = req
+		allocated[i].AgentsNeeded = guaranteed[i] + shared*want/rest
+		remainders[i] = shared * want % rest
+		left -= allocated[i].AgentsNeeded - guaranteed[i]
+	}
+	// Fewer agents are left over than there are requests, so each gets at
+	// most one
+	order := make([]int, len(requests))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(i, j int) int {
+		return remainders[j] - remainders[i]
+	})
+	for _, i := range order[:left] {
+		allocated[i].AgentsNeeded++
+	}
+
+	// Requests rounded down to nothing are left out, as PriorityAllocator
+	// leaves out the ones it starves
+	kept := allocated[:0]
+	var impactedClients []models.ImpactedClient
+	for i, req := range requests {
+		if allocated[i].AgentsNeeded > 0 || req.AgentsNeeded == 0 {
+			kept = append(kept, allocated[i])
+		}
+		if allocated[i].AgentsNeeded == req.AgentsNeeded {
+			continue
+		}
+		impactedClients = append(impactedClients, models.ImpactedClient{
+			Name:            req.Name,
+			RequestedAgents: req.AgentsNeeded,
+			AllocatedAgents: allocated[i].AgentsNeeded,
+			UnmetAgents:     req.AgentsNeeded - allocated[i].AgentsNeeded,
+			Priority:        req.Priority,
+			Location:        req.Location,
+			Skill:           req.Skill,
+			MinAgents:       req.MinAgents,
+		})
+	}
+	return kept, &models.UnmetDemand{
+		TotalDemand:     totalDemand,
+		AllocatedAgents: capacity,
+		UnmetAgents:     totalDemand - capacity,
+		ImpactedClients: impactedClients,
+	}
+}
+
+// countByPriority counts the requests at each priority level.
+func countByPriority(requests []models.CustomerRequirement) map[models.Priority]int {
+	counts := make(map[models.Priority]int)
+	for _, req := range requests {
+		counts[req.Priority]++
+	}
+	return counts
+}
+
+// satisfactionKey identifies one scheduler_requests_satisfaction_total series
+// within a run.
+type satisfactionKey struct {
+	priority models.Priority
+	outcome  string
+}
+
+// satisfaction tallies allocation outcomes during a run so they can be
+// published together with the run's other metrics.
+type satisfaction map[satisfactionKey]int
+
+// record counts one hour's allocation outcomes per priority.
+// Impacted clients are partial or none depending on whether they got any
+// agents; every other request in the hour was met in full.
+func (s satisfaction) record(requests map[models.Priority]int, unmet *models.UnmetDemand) {
+	full := requests
+	if unmet != nil {
+		full = make(map[models.Priority]int, len(requests))
+		for priority, n := range requests {
+			full[priority] = n
+		}
+		for _, client := range unmet.ImpactedClients {
+			outcome := metrics.OutcomePartial
+			if client.AllocatedAgents == 0 {
+				outcome = metrics.OutcomeNone
+			}
+			s[satisfactionKey{client.Priority, outcome}]++
+			full[client.Priority]--
+		}
+	}
+	for priority, n := range full {
+		if n > 0 {
+			s[satisfactionKey{priority, metrics.OutcomeFull}] += n
+		}
+	}
+}
+
+// publish adds the tallied outcomes to the satisfaction counters for runID.
+func (s satisfaction) publish(runID string) {
+	for k, n := range s {
+		metrics.RequestsSatisfaction.WithLabelValues(strconv.Itoa(int(k.priority)), k.outcome, runID).Add(float64(n))
+	}
+}
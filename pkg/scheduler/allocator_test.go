@@ -0,0 +1,572 @@
+package scheduler_test
+
+import (
+	"cmp"
+	"fmt"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/karthikrao-23/agentscheduler/internal/benchdata"
+	"github.com/karthikrao-23/agentscheduler/pkg/metrics"
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+	"github.com/karthikrao-23/agentscheduler/pkg/scheduler"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPriorityAllocator_Allocate(t *testing.T) {
+	tests := map[string]struct {
+		requests      []models.CustomerRequirement
+		capacity      int
+		wantAllocated map[string]int
+		wantUnmet     int
+		wantImpacted  []string
+	}{
+		"UnderCapacity": {
+			requests: []models.CustomerRequirement{
+				{Name: "B", AgentsNeeded: 3, Priority: 2},
+				{Name: "A", AgentsNeeded: 4, Priority: 1},
+			},
+			capacity:      10,
+			wantAllocated: map[string]int{"A": 4, "B": 3},
+		},
+		"PartialThenNone": {
+			requests: []models.CustomerRequirement{
+				{Name: "Low", AgentsNeeded: 5, Priority: 3},
+				{Name: "Mid", AgentsNeeded: 5, Priority: 2},
+				{Name: "High", AgentsNeeded: 5, Priority: 1},
+			},
+			capacity:      8,
+			wantAllocated: map[string]int{"High": 5, "Mid": 3},
+			wantUnmet:     7,
+			wantImpacted:  []string{"Mid", "Low"},
+		},
+		"TieBrokenByName": {
+			requests: []models.CustomerRequirement{
+				{Name: "Zeta", AgentsNeeded: 4, Priority: 1},
+				{Name: "Alpha", AgentsNeeded: 4, Priority: 1},
+			},
+			capacity:      4,
+			wantAllocated: map[string]int{"Alpha": 4},
+			wantUnmet:     4,
+			wantImpacted:  []string{"Zeta"},
+		},
+		"MinimumFirst": {
+			requests: []models.CustomerRequirement{
+				{Name: "Low", AgentsNeeded: 5, Priority: 3, MinAgents: 2},
+				{Name: "High", AgentsNeeded: 8, Priority: 1},
+			},
+			capacity:      8,
+			wantAllocated: map[string]int{"High": 6, "Low": 2},
+			wantUnmet:     5,
+			wantImpacted:  []string{"High", "Low"},
+		},
+		"MinimumsShort": {
+			requests: []models.CustomerRequirement{
+				{Name: "B", AgentsNeeded: 5, Priority: 2, MinAgents: 4},
+				{Name: "A", AgentsNeeded: 5, Priority: 1, MinAgents: 4},
+			},
+			capacity:      6,
+			wantAllocated: map[string]int{"A": 4, "B": 2},
+			wantUnmet:     4,
+			wantImpacted:  []string{"A", "B"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			allocated, unmet := scheduler.PriorityAllocator{}.Allocate(tt.requests, tt.capacity)
+
+			got := make(map[string]int)
+			for _, req := range allocated {
+				got[req.Name] = req.AgentsNeeded
+			}
+			assert.Equal(t, tt.wantAllocated, got)
+
+			if tt.wantImpacted == nil {
+				assert.Nil(t, unmet)
+				return
+			}
+			if assert.NotNil(t, unmet) {
+				assert.Equal(t, tt.wantUnmet, unmet.UnmetAgents)
+				var impacted []string
+				for _, client := range unmet.ImpactedClients {
+					impacted = append(impacted, client.Name)
+				}
+				assert.Equal(t, tt.wantImpacted, impacted)
+			}
+		})
+	}
+}
+
+func TestProportionalAllocator_Allocate(t *testing.T) {
+	tests := map[string]struct {
+		requests      []models.CustomerRequirement
+		capacity      int
+		wantAllocated map[string]int
+		wantUnmet     int
+	}{
+		"UnderCapacity": {
+			requests: []models.CustomerRequirement{
+				{Name: "B", AgentsNeeded: 3, Priority: 2},
+				{Name: "A", AgentsNeeded: 4, Priority: 1},
+			},
+			capacity:      10,
+			wantAllocated: map[string]int{"A": 4, "B": 3},
+		},
+		"ProRata": {
+			requests: []models.CustomerRequirement{
+				{Name: "Low", AgentsNeeded: 10, Priority: 3},
+				{Name: "High", AgentsNeeded: 30, Priority: 1},
+			},
+			capacity:      20,
+			wantAllocated: map[string]int{"High": 15, "Low": 5},
+			wantUnmet:     20,
+		},
+		"LargestRemainder": {
+			// Shares of 7/3 each; the leftover agent goes to the highest
+			// priority
+			requests: []models.CustomerRequirement{
+				{Name: "C", AgentsNeeded: 5, Priority: 3},
+				{Name: "B", AgentsNeeded: 5, Priority: 2},
+				{Name: "A", AgentsNeeded: 5, Priority: 1},
+			},
+			capacity:      7,
+			wantAllocated: map[string]int{"A": 3, "B": 2, "C": 2},
+			wantUnmet:     8,
+		},
+		"MinimumFirst": {
+			// Low's guarantee of 4 comes first, leaving 6 to share 9:3
+			requests: []models.CustomerRequirement{
+				{Name: "Low", AgentsNeeded: 7, Priority: 3, MinAgents: 4},
+				{Name: "High", AgentsNeeded: 9, Priority: 1},
+			},
+			capacity:      10,
+			wantAllocated: map[string]int{"High": 5, "Low": 5},
+			wantUnmet:     6,
+		},
+		"RoundedToNothing": {
+			requests: []models.CustomerRequirement{
+				{Name: "Big", AgentsNeeded: 98, Priority: 2},
+				{Name: "Tiny", AgentsNeeded: 2, Priority: 1},
+			},
+			capacity:      10,
+			wantAllocated: map[string]int{"Big": 10},
+			wantUnmet:     90,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			allocated, unmet := scheduler.ProportionalAllocator{}.Allocate(tt.requests, tt.capacity)
+
+			got := make(map[string]int)
+			for _, req := range allocated {
+				got[req.Name] = req.AgentsNeeded
+			}
+			assert.Equal(t, tt.wantAllocated, got)
+
+			if tt.wantUnmet == 0 {
+				assert.Nil(t, unmet)
+				return
+			}
+			if assert.NotNil(t, unmet) {
+				assert.Equal(t, tt.wantUnmet, unmet.UnmetAgents)
+				assert.Equal(t, tt.capacity, unmet.AllocatedAgents)
+				assert.Len(t, unmet.ImpactedClients, len(tt.requests))
+			}
+		})
+	}
+}
+
+func TestPoolAllocator_Allocate(t *testing.T) {
+	pools := []models.AgentPool{
+		{Name: "Generalists", Skills: []string{"billing", "support"}, Headcount: 4},
+		{Name: "Billing", Skills: []string{"billing"}, Headcount: 3},
+	}
+	tests := map[string]struct {
+		requests      []models.CustomerRequirement
+		wantAllocated map[string]int
+		wantUnmet     map[string]int
+	}{
+		"UnderHeadcount": {
+			requests: []models.CustomerRequirement{
+				{Name: "Acme", AgentsNeeded: 3, Priority: 1, Skill: "billing"},
+				{Name: "Globex", AgentsNeeded: 4, Priority: 2, Skill: "support"},
+			},
+			// Billing agents take Acme, leaving the generalists for Globex
+			wantAllocated: map[string]int{"Acme": 3, "Globex": 4},
+		},
+		"SkillShort": {
+			requests: []models.CustomerRequirement{
+				{Name: "Acme", AgentsNeeded: 2, Priority: 1, Skill: "billing"},
+				{Name: "Globex", AgentsNeeded: 6, Priority: 2, Skill: "support"},
+			},
+			// A billing agent is left idle, as no pool serving support has
+			// any agents left
+			wantAllocated: map[string]int{"Acme": 2, "Globex": 4},
+			wantUnmet:     map[string]int{"support": 2},
+		},
+		"UnknownSkill": {
+			requests: []models.CustomerRequirement{
+				{Name: "Acme", AgentsNeeded: 2, Priority: 1, Skill: "spanish"},
+				{Name: "Globex", AgentsNeeded: 2, Priority: 2},
+			},
+			wantAllocated: map[string]int{"Globex": 2},
+			wantUnmet:     map[string]int{"spanish": 2},
+		},
+		"PriorityFirst": {
+			requests: []models.CustomerRequirement{
+				{Name: "Low", AgentsNeeded: 5, Priority: 3, Skill: "billing"},
+				{Name: "High", AgentsNeeded: 5, Priority: 1, Skill: "billing"},
+			},
+			wantAllocated: map[string]int{"High": 5, "Low": 2},
+			wantUnmet:     map[string]int{"billing": 3},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			allocated, unmet := scheduler.PoolAllocator{Pools: pools}.Allocate(tt.requests, 7)
+
+			got := make(map[string]int)
+			for _, req := range allocated {
+				got[req.Name] = req.AgentsNeeded
+			}
+			assert.Equal(t, tt.wantAllocated, got)
+
+			if tt.wantUnmet == nil {
+				assert.Nil(t, unmet)
+				return
+			}
+			if assert.NotNil(t, unmet) {
+				assert.Equal(t, tt.wantUnmet, unmet.UnmetBySkill())
+			}
+		})
+	}
+}
+
+func TestOptimalAllocator_Allocate(t *testing.T) {
+	pools := []models.AgentPool{
+		{Name: "Billing", Skills: []string{"billing"}, Headcount: 3},
+		{Name: "Support", Skills: []string{"support"}, Headcount: 3},
+	}
+	tests := map[string]struct {
+		allocator     scheduler.OptimalAllocator
+		requests      []models.CustomerRequirement
+		capacity      int
+		wantAllocated map[string]int
+		wantUnmet     int
+	}{
+		"UnderCapacity": {
+			requests: []models.CustomerRequirement{
+				{Name: "B", AgentsNeeded: 3, Priority: 2},
+				{Name: "A", AgentsNeeded: 4, Priority: 1},
+			},
+			capacity:      10,
+			wantAllocated: map[string]int{"A": 4, "B": 3},
+		},
+		"DefaultWeights": {
+			requests: []models.CustomerRequirement{
+				{Name: "Low", AgentsNeeded: 5, Priority: 3},
+				{Name: "Mid", AgentsNeeded: 5, Priority: 2},
+				{Name: "High", AgentsNeeded: 5, Priority: 1},
+			},
+			capacity:      8,
+			wantAllocated: map[string]int{"High": 5, "Mid": 3},
+			wantUnmet:     7,
+		},
+		"Weights": {
+			allocator: scheduler.OptimalAllocator{Weights: map[models.Priority]float64{1: 1, 2: 3}},
+			requests: []models.CustomerRequirement{
+				{Name: "High", AgentsNeeded: 5, Priority: 1},
+				{Name: "Mid", AgentsNeeded: 5, Priority: 2},
+			},
+			capacity:      8,
+			wantAllocated: map[string]int{"High": 3, "Mid": 5},
+			wantUnmet:     2,
+		},
+		"MinimumFirst": {
+			requests: []models.CustomerRequirement{
+				{Name: "Low", AgentsNeeded: 5, Priority: 3, MinAgents: 2},
+				{Name: "High", AgentsNeeded: 8, Priority: 1},
+			},
+			capacity:      8,
+			wantAllocated: map[string]int{"High": 6, "Low": 2},
+			wantUnmet:     5,
+		},
+		"AcrossPools": {
+			// PoolAllocator would staff High from Billing and leave Mid
+			// with nothing; High is better served by Support, at Low's cost
+			allocator: scheduler.OptimalAllocator{Pools: pools},
+			requests: []models.CustomerRequirement{
+				{Name: "High", AgentsNeeded: 3, Priority: 1},
+				{Name: "Mid", AgentsNeeded: 3, Priority: 2, Skill: "billing"},
+				{Name: "Low", AgentsNeeded: 3, Priority: 3, Skill: "support"},
+			},
+			capacity:      6,
+			wantAllocated: map[string]int{"High": 3, "Mid": 3},
+			wantUnmet:     3,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			allocated, unmet := tt.allocator.Allocate(tt.requests, tt.capacity)
+
+			got := make(map[string]int)
+			for _, req := range allocated {
+				got[req.Name] = req.AgentsNeeded
+			}
+			assert.Equal(t, tt.wantAllocated, got)
+
+			if tt.wantUnmet == 0 {
+				assert.Nil(t, unmet)
+				return
+			}
+			if assert.NotNil(t, unmet) {
+				assert.Equal(t, tt.wantUnmet, unmet.UnmetAgents)
+				assert.Equal(t, unmet.TotalDemand-tt.wantUnmet, unmet.AllocatedAgents)
+			}
+		})
+	}
+}
+
+func TestGenerateSchedule_WithAgentPools(t *testing.T) {
+	now := time.Now().UTC()
+	at := func(hour int) time.Time {
+		return time.Date(now.Year(), now.Month(), now.Day(), hour, 0, 0, 0, time.UTC)
+	}
+	input := []models.CallData{
+		// 6 agents an hour needing billing, 4 needing support
+		{CustomerName: "Acme", AverageCallDurationSeconds: 3600, StartTime: at(9), EndTime: at(11), Location: models.NewLocation(time.UTC), NumberOfCalls: 12, Priority: 1, Skill: "billing"},
+		{CustomerName: "Globex", AverageCallDurationSeconds: 3600, StartTime: at(10), EndTime: at(11), Location: models.NewLocation(time.UTC), NumberOfCalls: 4, Priority: 1, Skill: "support"},
+	}
+	pools := []models.AgentPool{
+		{Name: "Billing", Skills: []string{"billing"}, Headcount: 5},
+		{Name: "Support", Skills: []string{"support"}, Headcount: 5},
+	}
+
+	sched, err := scheduler.GenerateSchedule(input, scheduler.WithAgentPools(pools))
+	require.NoError(t, err)
+	assert.Equal(t, 5, sched.AgentsForHour(9))
+	assert.Equal(t, 9, sched.AgentsForHour(10))
+	// Only billing runs short, although 10 agents would cover 9:00
+	require.Len(t, sched.UnmetDemands, 2)
+	assert.Equal(t, map[string]int{"billing": 1}, sched.UnmetDemands[0].UnmetBySkill())
+	assert.Equal(t, map[string]int{"billing": 1}, sched.UnmetDemands[1].UnmetBySkill())
+	assert.Equal(t, "billing", sched.UnmetDemands[0].ImpactedClients[0].Skill)
+	assert.Equal(t, "Billing(billing)=5,Support(support)=5", sched.Metadata.Options["agent_pools"])
+
+	// OptimalAllocator routes by skill too, without being given the pools
+	sched, err = scheduler.GenerateSchedule(input, scheduler.WithAllocator(scheduler.OptimalAllocator{}), scheduler.WithAgentPools(pools))
+	require.NoError(t, err)
+	assert.Equal(t, 5, sched.AgentsForHour(9))
+	assert.Equal(t, 9, sched.AgentsForHour(10))
+}
+
+func TestGenerateSchedule_WithPoolOverflow(t *testing.T) {
+	now := time.Now().UTC()
+	at := func(hour int) time.Time {
+		return time.Date(now.Year(), now.Month(), now.Day(), hour, 0, 0, 0, time.UTC)
+	}
+	utc := models.NewLocation(time.UTC)
+	// 6 agents at 9:00, while both pools are open, and at 17:00, after
+	// Onshore closes
+	input := []models.CallData{
+		{CustomerName: "Acme", AverageCallDurationSeconds: 3600, StartTime: at(9), EndTime: at(10), Location: utc, NumberOfCalls: 6, Priority: 1, Skill: "billing"},
+		{CustomerName: "Acme", AverageCallDurationSeconds: 3600, StartTime: at(17), EndTime: at(18), Location: utc, NumberOfCalls: 6, Priority: 1, Skill: "billing"},
+	}
+	// Offshore is cheaper, but only takes the overflow
+	pools := []models.AgentPool{
+		{Name: "Onshore", Skills: []string{"billing"}, Headcount: 4, Open: 9 * time.Hour, Close: 17 * time.Hour, HourlyCost: 30},
+		{Name: "Offshore", Skills: []string{"billing"}, Headcount: 10, HourlyCost: 10, Tier: 1},
+	}
+
+	tests := map[string][]scheduler.Option{
+		"Priority": {scheduler.WithAgentPools(pools)},
+		"Optimal":  {scheduler.WithAgentPools(pools), scheduler.WithAllocator(scheduler.OptimalAllocator{})},
+		// The pools reach the allocator whichever option comes first
+		"OptimalFirst": {scheduler.WithAllocator(scheduler.OptimalAllocator{}), scheduler.WithAgentPools(pools)},
+	}
+	for name, opts := range tests {
+		t.Run(name, func(t *testing.T) {
+			sched, err := scheduler.GenerateSchedule(input, opts...)
+			require.NoError(t, err)
+			assert.Equal(t, 6, sched.AgentsForHour(9))
+			assert.Equal(t, 6, sched.AgentsForHour(17))
+			assert.Empty(t, sched.UnmetDemands)
+
+			assert.Equal(t, 4.0, testutil.ToFloat64(metrics.PoolAgentsAllocated.WithLabelValues("Onshore")))
+			assert.Equal(t, 8.0, testutil.ToFloat64(metrics.PoolAgentsAllocated.WithLabelValues("Offshore")))
+			// Onshore is open 8 hours a day, Offshore all 24
+			assert.Equal(t, 12.5, testutil.ToFloat64(metrics.PoolUtilizationPercent.WithLabelValues("Onshore")))
+			assert.InDelta(t, 100.0/30, testutil.ToFloat64(metrics.PoolUtilizationPercent.WithLabelValues("Offshore")), 1e-9)
+			assert.Equal(t, 120.0, testutil.ToFloat64(metrics.PoolCost.WithLabelValues("Onshore")))
+			assert.Equal(t, 80.0, testutil.ToFloat64(metrics.PoolCost.WithLabelValues("Offshore")))
+		})
+	}
+
+	t.Run("AllClosed", func(t *testing.T) {
+		closed := []models.AgentPool{{Name: "Onshore", Skills: []string{"billing"}, Headcount: 4, Open: 9 * time.Hour, Close: 17 * time.Hour}}
+		sched, err := scheduler.GenerateSchedule(input, scheduler.WithAgentPools(closed))
+		require.NoError(t, err)
+		assert.Equal(t, 4, sched.AgentsForHour(9))
+		assert.Equal(t, 0, sched.AgentsForHour(17))
+		require.Len(t, sched.UnmetDemands, 2)
+		assert.Equal(t, 6, sched.UnmetDemands[1].UnmetAgents)
+		assert.Equal(t, "Onshore(billing)=4@09:00-17:00", sched.Metadata.Options["agent_pools"])
+	})
+}
+
+func TestGenerateSchedule_WithReservedCapacity(t *testing.T) {
+	now := time.Now().UTC()
+	at := func(hour int) time.Time {
+		return time.Date(now.Year(), now.Month(), now.Day(), hour, 0, 0, 0, time.UTC)
+	}
+	utc := models.NewLocation(time.UTC)
+	// 10 agents each at 9:00
+	input := []models.CallData{
+		{CustomerName: "Committed", AverageCallDurationSeconds: 3600, StartTime: at(9), EndTime: at(10), Location: utc, NumberOfCalls: 10, Priority: 2},
+		{CustomerName: "Urgent", AverageCallDurationSeconds: 3600, StartTime: at(9), EndTime: at(10), Location: utc, NumberOfCalls: 10, Priority: 1},
+		{CustomerName: "BestEffort", AverageCallDurationSeconds: 3600, StartTime: at(9), EndTime: at(10), Location: utc, NumberOfCalls: 10, Priority: 4, MinAgents: 5},
+	}
+	agents := func(sched *models.Schedule) map[string]int {
+		got := make(map[string]int)
+		for _, req := range sched.HourlyRequirements[9] {
+			got[req.Name] += req.AgentsNeeded
+		}
+		return got
+	}
+
+	t.Run("Priority", func(t *testing.T) {
+		// Without the reserve, BestEffort's guarantee and Urgent take all 10
+		sched, err := scheduler.GenerateSchedule(input, scheduler.WithCapacity(10), scheduler.WithReservedCapacity(map[models.Priority]float64{2: 30}))
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int{"BestEffort": 5, "Committed": 3, "Urgent": 2}, agents(sched))
+		require.Len(t, sched.UnmetDemands, 1)
+		unmet := sched.UnmetDemands[0]
+		assert.Equal(t, 30, unmet.TotalDemand)
+		assert.Equal(t, 10, unmet.AllocatedAgents)
+		for _, client := range unmet.ImpactedClients {
+			if client.Name == "Committed" {
+				assert.Equal(t, 10, client.RequestedAgents)
+				assert.Equal(t, 3, client.AllocatedAgents)
+			}
+		}
+		assert.Equal(t, "2=30", sched.Metadata.Options["reserved_capacity"])
+	})
+
+	t.Run("Unneeded", func(t *testing.T) {
+		// A reserve no one at its priority needs goes to the others
+		sched, err := scheduler.GenerateSchedule(input, scheduler.WithCapacity(10), scheduler.WithReservedCapacity(map[models.Priority]float64{3: 50}))
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int{"BestEffort": 5, "Urgent": 5}, agents(sched))
+	})
+
+	t.Run("Proportional", func(t *testing.T) {
+		sched, err := scheduler.GenerateSchedule(input, scheduler.WithCapacity(20), scheduler.WithAllocator(scheduler.ProportionalAllocator{}),
+			scheduler.WithReservedCapacity(map[models.Priority]float64{1: 50}))
+		require.NoError(t, err)
+		// Urgent's 10 come from its reserve; the other 10 go 5 to
+		// BestEffort's guarantee and the rest pro rata, 10:5
+		assert.Equal(t, map[string]int{"BestEffort": 7, "Committed": 3, "Urgent": 10}, agents(sched))
+	})
+}
+
+// capAllocator gives every request at most one agent.
+type capAllocator struct{ calls int }
+
+func (a *capAllocator) Allocate(requests []models.CustomerRequirement, capacity int) ([]models.CustomerRequirement, *models.UnmetDemand) {
+	a.calls++
+	for i := range requests {
+		requests[i].AgentsNeeded = min(requests[i].AgentsNeeded, 1)
+	}
+	return requests, nil
+}
+
+func TestGenerateSchedule_WithAllocator(t *testing.T) {
+	now := time.Now().UTC()
+	input := []models.CallData{
+		{
+			CustomerName:               "Custom",
+			AverageCallDurationSeconds: 3600,
+			StartTime:                  time.Date(now.Year(), now.Month(), now.Day(), 10, 0, 0, 0, time.UTC),
+			EndTime:                    time.Date(now.Year(), now.Month(), now.Day(), 12, 0, 0, 0, time.UTC),
+			Location:                   models.NewLocation(time.UTC),
+			NumberOfCalls:              20,
+			Priority:                   1,
+		},
+	}
+
+	alloc := &capAllocator{}
+	sched, err := scheduler.GenerateSchedule(input, scheduler.WithCapacity(100), scheduler.WithAllocator(alloc))
+	require.NoError(t, err)
+
+	assert.Equal(t, 24, alloc.calls, "allocator should run once per constrained hour")
+	assert.Equal(t, 1, sched.HourlyRequirements[10][0].AgentsNeeded)
+	assert.Equal(t, 1, sched.HourlyRequirements[11][0].AgentsNeeded)
+	assert.Empty(t, sched.UnmetDemands)
+}
+
+func TestPriorityAllocator_AllocateWide(t *testing.T) {
+	// Wide hours are sorted in shards; the result must match one sort of the
+	// whole hour, with ties on name broken by AgentsNeeded
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(3))
+	const n = 25_000
+	requests := make([]models.CustomerRequirement, n)
+	demand := 0
+	for i := range requests {
+		requests[i] = models.CustomerRequirement{
+			Name:         fmt.Sprintf("Customer %03d", (i*7919)%500),
+			AgentsNeeded: i + 1,
+			Priority:     models.Priority(1 + (i*31)%4),
+		}
+		demand += i + 1
+	}
+	want := slices.Clone(requests)
+	slices.SortFunc(want, func(a, b models.CustomerRequirement) int {
+		return cmp.Or(cmp.Compare(a.Priority, b.Priority), strings.Compare(a.Name, b.Name), cmp.Compare(a.AgentsNeeded, b.AgentsNeeded))
+	})
+
+	got, unmet := scheduler.PriorityAllocator{}.Allocate(slices.Clone(requests), demand)
+	assert.Nil(t, unmet)
+	assert.Equal(t, want, got)
+
+	got, unmet = scheduler.PriorityAllocator{}.Allocate(slices.Clone(requests), demand/2)
+	require.NotNil(t, unmet)
+	assert.Equal(t, demand, unmet.TotalDemand)
+	assert.Equal(t, demand-demand/2, unmet.UnmetAgents)
+	// Capacity goes to the requests in sorted order
+	remaining, full := demand/2, 0
+	for i, req := range got {
+		assert.Equal(t, want[i].Name, req.Name)
+		assert.Equal(t, min(want[i].AgentsNeeded, remaining), req.AgentsNeeded)
+		if req.AgentsNeeded == want[i].AgentsNeeded {
+			full++
+		}
+		remaining -= req.AgentsNeeded
+	}
+	assert.Zero(t, remaining)
+	assert.Len(t, unmet.ImpactedClients, n-full)
+}
+
+func BenchmarkPriorityAllocator_Allocate(b *testing.B) {
+	for _, n := range benchdata.Sizes {
+		b.Run(benchdata.Name(n), func(b *testing.B) {
+			requests, capacity := benchdata.Requirements(n)
+			scratch := make([]models.CustomerRequirement, n)
+			b.ReportAllocs()
+			for b.Loop() {
+				// Allocate sorts its input, so each run starts from the original order
+				copy(scratch, requests)
+				scheduler.PriorityAllocator{}.Allocate(scratch, capacity)
+			}
+		})
+	}
+}
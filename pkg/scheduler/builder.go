@@ -0,0 +1,515 @@
+package scheduler
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"hash"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/karthikrao-23/agentscheduler/pkg/errors"
+	"github.com/karthikrao-23/agentscheduler/pkg/metrics"
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/sync/errgroup"
+)
+
+// Builder generates a schedule from call data added in batches, so a caller
+// streaming its input can have rows expanded while later ones are still
+// being parsed. The schedule is the one GenerateScheduleContext would produce
+// for the batches concatenated in the order they were added.
+//
+// A Builder is not safe for concurrent use; WithWorkers parallelizes the
+// work inside it.
+type Builder struct {
+	ctx   context.Context
+	cfg   config
+	span  trace.Span
+	runID string
+	start time.Time
+
+	// group runs batch expansion when there is more than one worker; pending
+	// holds the batches in the order added, until they are merged
+	group   *errgroup.Group
+	pending []*batch
+
+	// days holds each day's per-slot requirements by day number; every row
+	// lands on day 0 unless WithMultiDay is set
+	days map[int][][]models.CustomerRequirement
+	// index locates each slot's aggregated requirements with
+	// WithAggregation, by day number like days
+	index map[int][]map[requirementKey]int
+	rows  int
+	// added is the rows handed to Add, expanded or not
+	added int
+	// occupancyAgents is the agents WithMaxOccupancy added
+	occupancyAgents int
+	hash            hash.Hash
+	err             error
+
+	// firstDay is the day number of the schedule's first slot
+	firstDay int
+	// reuse, set by a Replanner, holds the slots to take a past run's
+	// allocation for instead of allocating them; allocations, if set,
+	// receives every slot's allocation
+	reuse       map[slotKey]slotAllocation
+	allocations map[slotKey]slotAllocation
+	// poolAgents holds the agents drawn from each of WithAgentPools' pools,
+	// by slot
+	poolAgents [][]int
+}
+
+// requirementKey identifies the requirements WithAggregation merges: those
+// of one customer that agree on everything but the agents needed.
+type requirementKey struct {
+	name                string
+	location            string
+	priority            models.Priority
+	slaTargetPercent    float64
+	slaThresholdSeconds int
+	skill               string
+	concurrency         int
+	hourlyCost          float64
+	minAgents           int
+	ceilingAgents       int
+}
+
+// requirementKeyOf returns the requirementKey req is merged by.
+func requirementKeyOf(req models.CustomerRequirement) requirementKey {
+	return requirementKey{
+		name:                req.Name,
+		location:            req.Location.String(),
+		priority:            req.Priority,
+		slaTargetPercent:    req.SLATargetPercent,
+		slaThresholdSeconds: req.SLAThresholdSeconds,
+		skill:               req.Skill,
+		concurrency:         req.Concurrency,
+		hourlyCost:          req.HourlyCost,
+		minAgents:           req.MinAgents,
+		ceilingAgents:       req.CeilingAgents,
+	}
+}
+
+// batch is one Add call's data, expanded on its own.
+type batch struct {
+	done chan struct{}
+	days map[int][][]models.CustomerRequirement
+	// encoded is the batch's rows in JSON, comma-separated, for the input hash
+	encoded         []byte
+	rows            int
+	occupancyAgents int
+	err             error
+}
+
+// NewBuilder starts a schedule under opts, recording its tracing span as a
+// child of ctx. It returns a *errors.ConstraintViolationError for invalid
+// options. Build must be called to end the run, even after Add fails.
+func NewBuilder(ctx context.Context, opts ...Option) (*Builder, error) {
+	cfg := newConfig(opts...)
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+
+	ctx, span := tracer.Start(ctx, "scheduler.GenerateSchedule", trace.WithAttributes(
+		attribute.Float64("scheduler.utilization", cfg.utilization),
+		attribute.Int("scheduler.capacity_per_hour", cfg.capacity),
+		attribute.Bool("scheduler.capacity_profile", len(cfg.capacityProfile) > 0),
+		attribute.String("scheduler.interval", cfg.interval.String()),
+	))
+
+	// Start a new metrics run
+	runID := metrics.StartRun()
+	span.SetAttributes(attribute.String("scheduler.run_id", runID))
+
+	b := &Builder{
+		ctx:   ctx,
+		cfg:   cfg,
+		span:  span,
+		runID: runID,
+		start: time.Now(),
+		days:  make(map[int][][]models.CustomerRequirement),
+		hash:  sha256.New(),
+	}
+	if cfg.aggregate {
+		b.index = make(map[int][]map[requirementKey]int)
+	}
+	if cfg.workers > 1 {
+		b.group = new(errgroup.Group)
+		b.group.SetLimit(cfg.workers)
+	}
+	b.hash.Write([]byte("["))
+	return b, nil
+}
+
+// Add expands data into the schedule. With WithWorkers, it returns once a
+// worker has picked the batch up, and data must not be modified until Build
+// returns; at most twice as many batches as workers are held at once, so a
+// caller streaming its input waits rather than buffering it. Once Add fails,
+// because the Builder's context was cancelled or a row cannot be scheduled
+// (a *errors.RowError, numbering rows across every Add), later calls and
+// Build return the same error.
+func (b *Builder) Add(data []models.CallData) error {
+	if b.err != nil {
+		return b.err
+	}
+	first := b.added
+	b.added += len(data)
+	if b.group == nil {
+		b.merge(b.expand(data, first))
+		return b.err
+	}
+
+	done := make(chan struct{})
+	bt := &batch{done: done}
+	b.pending = append(b.pending, bt)
+	b.group.Go(func() error {
+		defer close(done)
+		expanded := b.expand(data, first)
+		bt.days, bt.encoded, bt.rows, bt.occupancyAgents, bt.err = expanded.days, expanded.encoded, expanded.rows, expanded.occupancyAgents, expanded.err
+		return nil
+	})
+	b.mergeDone(2 * b.cfg.workers)
+	return b.err
+}
+
+// Build allocates capacity, publishes the run's metrics, and returns the
+// schedule. It returns errors.ErrEmptyInput if no rows were added, and
+// ctx.Err() if the Builder's context was cancelled.
+func (b *Builder) Build() (*models.Schedule, error) {
+	defer b.span.End()
+	defer func() {
+		metrics.ObserveDuration(b.ctx, metrics.SchedulerDurationSeconds, time.Since(b.start).Seconds(), b.runID)
+	}()
+
+	b.mergeDone(0)
+	for _, index := range b.index {
+		putIndex(index)
+	}
+	b.index = nil
+	if b.err != nil {
+		b.span.RecordError(b.err)
+		b.span.SetStatus(codes.Error, "schedule cancelled")
+		return nil, b.err
+	}
+	if b.rows == 0 {
+		return nil, errors.ErrEmptyInput
+	}
+
+	// Track customers processed
+	b.span.SetAttributes(attribute.Int("scheduler.customers", b.rows))
+	if b.cfg.maxOccupancy > 0 {
+		b.span.SetAttributes(attribute.Int("scheduler.occupancy_agents_added", b.occupancyAgents))
+	}
+	metrics.SchedulerCustomersProcessed.Observe(float64(b.rows))
+
+	b.hash.Write([]byte("]"))
+	slots, hourly := b.layout()
+	return b.finish(slots, hourly, hex.EncodeToString(b.hash.Sum(nil))), nil
+}
+
+// EmptySchedule returns the schedule for no demand under opts: every slot
+// laid out with nothing required, and the run's metadata. Streaming callers
+// publish it once all of their input has expired. It returns a
+// *errors.ConstraintViolationError for invalid options.
+func EmptySchedule(ctx context.Context, opts ...Option) (*models.Schedule, error) {
+	b, err := NewBuilder(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	defer b.span.End()
+	b.hash.Write([]byte("]"))
+	slots, hourly := b.layout()
+	return b.finish(slots, hourly, hex.EncodeToString(b.hash.Sum(nil))), nil
+}
+
+// finish allocates hourly, the requirements in each of slots, into the
+// schedule for input that hashed to inputHash, and publishes its metrics.
+func (b *Builder) finish(slots []models.TimeSlot, hourly [][]models.CustomerRequirement, inputHash string) *models.Schedule {
+	capped := clamp(hourly)
+	if b.cfg.smoothing != nil {
+		b.cfg.smoothing.smooth(hourly, b.cfg.fte)
+	}
+	schedule := models.Schedule{
+		SchemaVersion:      models.CurrentSchemaVersion,
+		Slots:              slots,
+		HourlyRequirements: hourly,
+		UnmetDemands:       make([]models.UnmetDemand, 0),
+		Metadata:           newMetadata(b.runID, inputHash, b.cfg),
+	}
+	// Apply capacity constraints to every hour that has a limit
+	outcomes := make(satisfaction)
+	if b.cfg.constrained() {
+		_, allocSpan := tracer.Start(b.ctx, "scheduler.allocate")
+		b.allocate(&schedule, outcomes)
+		allocSpan.SetAttributes(attribute.Int("scheduler.hours_with_unmet_demand", len(schedule.UnmetDemands)))
+		allocSpan.End()
+	}
+	addCapped(&schedule, capped)
+	if b.cfg.fte {
+		settleFTE(schedule.HourlyRequirements)
+	}
+	b.cfg.hooks.run(&schedule)
+	// Publish this run's metrics in one step so concurrent runs don't interleave
+	metrics.PublishRun(b.runID, func() {
+		outcomes.publish(b.runID)
+		computeScheduleMetrics(&schedule, b.cfg)
+		metrics.OccupancyAgentsAdded.Set(float64(b.occupancyAgents))
+		publishPoolUsage(&schedule, b.cfg, b.poolAgents)
+	})
+
+	return &schedule
+}
+
+// layout lines the days up into the schedule's slots: one generic day, or
+// when laid out by date every date from the first to the last, empty ones
+// included.
+func (b *Builder) layout() ([]models.TimeSlot, [][]models.CustomerRequirement) {
+	if !b.cfg.byDate() || len(b.days) == 0 {
+		return b.cfg.located(models.IntervalSlots(b.cfg.interval)), b.cfg.slotsOn(b.days, 0)
+	}
+	days := slices.Sorted(maps.Keys(b.days))
+	b.firstDay = days[0]
+	var slots []models.TimeSlot
+	var hourly [][]models.CustomerRequirement
+	for d := days[0]; d <= days[len(days)-1]; d++ {
+		year, month, day := dayDate(d)
+		slots = append(slots, models.DaySlots(year, month, day, b.cfg.interval)...)
+		hourly = append(hourly, b.cfg.slotsOn(b.days, d)...)
+	}
+	return b.cfg.located(slots), hourly
+}
+
+// located marks slots as UTC under WithUTC. Day slots start at wall-clock
+// times in UTC already, so they are the same instants.
+func (c config) located(slots []models.TimeSlot) []models.TimeSlot {
+	if c.utc {
+		for i := range slots {
+			slots[i].Location = models.NewLocation(time.UTC)
+		}
+	}
+	return slots
+}
+
+// expand converts a batch of rows into per-slot requirements, in pooled
+// slices that merge hands back.
+func (b *Builder) expand(data []models.CallData, first int) batch {
+	expanded := batch{days: make(map[int][][]models.CustomerRequirement)}
+	encoded := bytes.NewBuffer(bytePool.get())
+	enc := json.NewEncoder(encoded)
+	for i, cd := range data {
+		if err := b.ctx.Err(); err != nil {
+			expanded.err = err
+			return expanded
+		}
+		if err := check(cd); err != nil {
+			expanded.err = &errors.RowError{Row: first + i, Customer: cd.CustomerName, Err: err}
+			return expanded
+		}
+		if i > 0 {
+			encoded.WriteByte(',')
+		}
+		if err := enc.Encode(cd); err != nil {
+			expanded.err = err
+			return expanded
+		}
+		// Encode ends each row with a newline, which Marshal does not
+		encoded.Truncate(encoded.Len() - 1)
+		expanded.occupancyAgents += b.cfg.expand(b.ctx, cd, expanded.days)
+	}
+	expanded.encoded = encoded.Bytes()
+	expanded.rows = len(data)
+	return expanded
+}
+
+// merge appends an expanded batch to the schedule, keeping the order rows
+// were added in.
+func (b *Builder) merge(expanded batch) {
+	if b.err != nil {
+		return
+	}
+	if expanded.err != nil {
+		b.err = expanded.err
+		return
+	}
+	if expanded.rows == 0 {
+		return
+	}
+	for d, slots := range expanded.days {
+		hourly := b.cfg.slotsOn(b.days, d)
+		var index []map[requirementKey]int
+		if b.index != nil {
+			if index = b.index[d]; index == nil {
+				index = getIndex(b.cfg.slots())
+				b.index[d] = index
+			}
+		}
+		for h, reqs := range slots {
+			if index == nil {
+				hourly[h] = append(hourly[h], reqs...)
+				continue
+			}
+			for _, req := range reqs {
+				key := requirementKeyOf(req)
+				if i, ok := index[h][key]; ok {
+					hourly[h][i].AgentsNeeded += req.AgentsNeeded
+					hourly[h][i].FTE += req.FTE
+					continue
+				}
+				index[h][key] = len(hourly[h])
+				hourly[h] = append(hourly[h], req)
+			}
+		}
+	}
+	if b.rows > 0 {
+		b.hash.Write([]byte(","))
+	}
+	b.hash.Write(expanded.encoded)
+	b.rows += expanded.rows
+	b.occupancyAgents += expanded.occupancyAgents
+
+	// The batch has been copied into the schedule
+	for _, slots := range expanded.days {
+		for _, reqs := range slots {
+			requirementPool.put(reqs)
+		}
+	}
+	bytePool.put(expanded.encoded)
+}
+
+// slotsOn returns day d's per-slot requirements in days, adding pooled
+// empty ones the first time the day is seen.
+func (c config) slotsOn(days map[int][][]models.CustomerRequirement, d int) [][]models.CustomerRequirement {
+	slots, ok := days[d]
+	if !ok {
+		slots = make([][]models.CustomerRequirement, c.slots())
+		for h := range slots {
+			slots[h] = requirementPool.get()
+		}
+		days[d] = slots
+	}
+	return slots
+}
+
+// dayOf returns the day number a slot starting at local falls on: days since
+// 1970-01-01 when laid out by date, and 0 otherwise.
+func (c config) dayOf(local time.Time) int {
+	if !c.byDate() {
+		return 0
+	}
+	year, month, day := local.Date()
+	return int(time.Date(year, month, day, 0, 0, 0, 0, time.UTC).Unix() / (24 * 60 * 60))
+}
+
+// dayDate returns the date of day number d.
+func dayDate(d int) (int, time.Month, int) {
+	return time.Unix(int64(d)*24*60*60, 0).UTC().Date()
+}
+
+// slotKey returns the day number and slot of the day of the schedule's slot
+// h.
+func (b *Builder) slotKey(h int) slotKey {
+	n := b.cfg.slots()
+	return slotKey{day: b.firstDay + h/n, slot: h % n}
+}
+
+// mergeDone merges pending batches in order, stopping at the first that is
+// still being expanded once no more than limit remain.
+func (b *Builder) mergeDone(limit int) {
+	for len(b.pending) > 0 {
+		bt := b.pending[0]
+		if len(b.pending) <= limit {
+			select {
+			case <-bt.done:
+			default:
+				return
+			}
+		}
+		<-bt.done
+		b.merge(*bt)
+		b.pending[0] = nil
+		b.pending = b.pending[1:]
+	}
+}
+
+// allocate shares out each constrained slot's capacity, on the configured
+// workers, and tallies the outcomes in slot order.
+func (b *Builder) allocate(schedule *models.Schedule, outcomes satisfaction) {
+	slots := len(schedule.HourlyRequirements)
+	requests := make([]map[models.Priority]int, slots)
+	unmet := make([]*models.UnmetDemand, slots)
+	if len(b.cfg.pools) > 0 {
+		b.poolAgents = make([][]int, slots)
+	}
+	allocateHour := func(h int) {
+		cfg := b.cfg
+		capacity := cfg.capacityForSlot(h)
+		if capacity <= 0 {
+			return
+		}
+		reqs := schedule.HourlyRequirements[h]
+		requests[h] = countByPriority(reqs)
+		if a, ok := b.reuse[b.slotKey(h)]; ok {
+			schedule.HourlyRequirements[h], unmet[h] = a.clone()
+			if b.poolAgents != nil {
+				b.poolAgents[h] = slices.Clone(a.poolAgents)
+			}
+			requirementPool.put(reqs)
+			return
+		}
+		if b.poolAgents != nil {
+			open := cfg.openPools(schedule.SlotAt(h))
+			b.poolAgents[h] = make([]int, len(open))
+			cfg.allocator = withPools(cfg.allocator, open, b.poolAgents[h])
+			capacity = headcount(open)
+		}
+		schedule.HourlyRequirements[h], unmet[h] = cfg.allocate(reqs, capacity)
+		// PriorityAllocator returns a new slice whenever capacity falls
+		// short, leaving the hour's requirements unreferenced
+		if _, ok := b.cfg.allocator.(PriorityAllocator); ok && unmet[h] != nil {
+			requirementPool.put(reqs)
+		}
+	}
+
+	if b.cfg.workers > 1 {
+		var g errgroup.Group
+		g.SetLimit(b.cfg.workers)
+		for h := range slots {
+			g.Go(func() error {
+				allocateHour(h)
+				return nil
+			})
+		}
+		_ = g.Wait()
+	} else {
+		for h := range slots {
+			allocateHour(h)
+		}
+	}
+
+	for h := range slots {
+		if b.cfg.capacityForSlot(h) <= 0 {
+			continue
+		}
+		outcomes.record(requests[h], unmet[h])
+		if b.allocations != nil {
+			a := slotAllocation{reqs: schedule.HourlyRequirements[h], unmet: unmet[h]}
+			a.reqs, a.unmet = a.clone()
+			if b.poolAgents != nil {
+				a.poolAgents = slices.Clone(b.poolAgents[h])
+			}
+			b.allocations[b.slotKey(h)] = a
+		}
+		if unmet[h] != nil {
+			unmet[h].Hour = h
+			unmet[h].Slot = schedule.SlotAt(h)
+			schedule.UnmetDemands = append(schedule.UnmetDemands, *unmet[h])
+		}
+	}
+}
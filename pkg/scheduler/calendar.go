@@ -0,0 +1,35 @@
+package scheduler
+
+import (
+	"time"
+
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+)
+
+// WithCalendar scales every slot's calls by the holidays and blackout
+// windows in cal that cover it, read in each customer's location, so input
+// need not be edited for them. A slot an entry blacks out with a
+// multiplier of 0 gets no requirement at all, not even a floor. It needs a
+// schedule laid out by date, from WithMultiDay or WithWeeklyPattern.
+func WithCalendar(cal models.Calendar) Option {
+	return func(c *config) {
+		c.calendar = cal
+	}
+}
+
+// WithSeasonality scales every slot's calls by the customer's multipliers
+// in seasonality for the month and ISO week the slot falls in, in the
+// customer's location, so one baseline input can be planned for a peak
+// season. Rows are dated today unless the input gives dates, so plan a
+// season with WithMultiDay and rows dated in it.
+func WithSeasonality(seasonality models.Seasonality) Option {
+	return func(c *config) {
+		c.seasonality = seasonality
+	}
+}
+
+// scaleFor returns how much the calendar and seasonality scale cd's calls
+// in the slot starting at t.
+func (c config) scaleFor(cd models.CallData, t time.Time) float64 {
+	return c.calendar.Multiplier(cd.CustomerName, cd.Location, t) * c.seasonality.Multiplier(cd.CustomerName, cd.Location.In(t))
+}
@@ -0,0 +1,58 @@
+package scheduler
+
+import "math"
+
+// serviceLevel is a staffing target: targetPercent of calls answered within
+// thresholdSeconds.
+type serviceLevel struct {
+	targetPercent    float64
+	thresholdSeconds int
+}
+
+// WithErlangC staffs every hour with the Erlang C model instead of raw
+// workload: each customer gets the fewest agents that answer targetPercent
+// of its calls within thresholdSeconds, given its offered load. Rows that
+// carry their own SLA are staffed to it instead. Utilization still applies
+// on top, as shrinkage.
+//
+// Erlang C staffs to the rate at which calls arrive, so an hour a call window
+// only partly covers needs as many agents as a full one for the time the
+// window is open.
+func WithErlangC(targetPercent float64, thresholdSeconds int) Option {
+	return func(c *config) {
+		c.erlangC = &serviceLevel{targetPercent: targetPercent, thresholdSeconds: thresholdSeconds}
+	}
+}
+
+// erlangCAgents returns the fewest agents that answer targetPercent of calls
+// arriving at callsPerHour, each lasting durationSeconds, within
+// thresholdSeconds. Targets are checked to be below 100 percent, which no
+// number of agents meets, but the search stops far enough past the load that
+// only rounding could keep it from meeting any other.
+func erlangCAgents(callsPerHour float64, durationSeconds int, sla serviceLevel) int {
+	if callsPerHour <= 0 || durationSeconds <= 0 {
+		return 0
+	}
+	// Offered load in Erlangs: the agents that would be busy all hour
+	load := callsPerHour * float64(durationSeconds) / 3600
+	target := sla.targetPercent / 100
+
+	// Erlang B by recurrence, from B(0) = 1, up to the first agent count
+	// that can keep up with the load at all
+	agents := int(math.Floor(load)) + 1
+	blocking := 1.0
+	for n := 1; n <= agents; n++ {
+		blocking = load * blocking / (float64(n) + load*blocking)
+	}
+	for {
+		n := float64(agents)
+		// Erlang C: the probability that a call has to wait
+		wait := n * blocking / (n - load*(1-blocking))
+		answered := 1 - wait*math.Exp(-(n-load)*float64(sla.thresholdSeconds)/float64(durationSeconds))
+		if answered >= target || n > load+40*math.Sqrt(load)+40 {
+			return agents
+		}
+		agents++
+		blocking = load * blocking / (float64(agents) + load*blocking)
+	}
+}
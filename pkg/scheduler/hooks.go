@@ -0,0 +1,51 @@
+package scheduler
+
+import "github.com/karthikrao-23/agentscheduler/pkg/models"
+
+// Hooks are called with the scheduler's decisions slot by slot, so an
+// embedder can stream them into its own systems rather than walk the
+// finished schedule. Any hook may be nil.
+type Hooks struct {
+	// OnAllocated is called with each requirement of a slot, as allocated
+	OnAllocated func(slot models.TimeSlot, req models.CustomerRequirement)
+	// OnUnmet is called with a slot's unmet demand, for slots with any
+	OnUnmet func(unmet models.UnmetDemand)
+	// OnHourComplete is called once a slot's other hooks have been, with
+	// its index in the schedule
+	OnHourComplete func(h int, slot models.TimeSlot)
+}
+
+// WithHooks calls hooks for every slot of the schedule, in order, once its
+// allocation is final, on the goroutine that builds the schedule and before
+// it is returned. Hooks do not change the schedule, and must not hold on to
+// the requirements they are passed beyond the call.
+func WithHooks(hooks Hooks) Option {
+	return func(c *config) {
+		c.hooks = hooks
+	}
+}
+
+// run calls h's hooks for every slot of schedule.
+func (h Hooks) run(schedule *models.Schedule) {
+	if h.OnAllocated == nil && h.OnUnmet == nil && h.OnHourComplete == nil {
+		return
+	}
+	unmet := make(map[int]models.UnmetDemand, len(schedule.UnmetDemands))
+	for _, u := range schedule.UnmetDemands {
+		unmet[u.Hour] = u
+	}
+	for i, reqs := range schedule.HourlyRequirements {
+		slot := schedule.SlotAt(i)
+		if h.OnAllocated != nil {
+			for _, req := range reqs {
+				h.OnAllocated(slot, req)
+			}
+		}
+		if u, ok := unmet[i]; ok && h.OnUnmet != nil {
+			h.OnUnmet(u)
+		}
+		if h.OnHourComplete != nil {
+			h.OnHourComplete(i, slot)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package scheduler
+
+import (
+	"slices"
+
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+)
+
+// clamp holds every requirement in hourly, the schedule's requirements by
+// slot, to its customer's CeilingAgents. It returns the demand the ceilings
+// left unmet by slot, or nil if they cut none. Floors are applied as rows
+// are expanded.
+func clamp(hourly [][]models.CustomerRequirement) []*models.UnmetDemand {
+	var capped []*models.UnmetDemand
+	for h, reqs := range hourly {
+		var clients []models.ImpactedClient
+		demand, cut := 0, 0
+		for i := range reqs {
+			req := &reqs[i]
+			demand += req.AgentsNeeded
+			if req.CeilingAgents == 0 || req.AgentsNeeded <= req.CeilingAgents {
+				continue
+			}
+			clients = append(clients, models.ImpactedClient{
+				Name:            req.Name,
+				RequestedAgents: req.AgentsNeeded,
+				AllocatedAgents: req.CeilingAgents,
+				UnmetAgents:     req.AgentsNeeded - req.CeilingAgents,
+				Priority:        req.Priority,
+				Location:        req.Location,
+				Skill:           req.Skill,
+				MinAgents:       req.MinAgents,
+				CeilingAgents:   req.CeilingAgents,
+			})
+			cut += req.AgentsNeeded - req.CeilingAgents
+			req.AgentsNeeded = req.CeilingAgents
+		}
+		if clients == nil {
+			continue
+		}
+		if capped == nil {
+			capped = make([]*models.UnmetDemand, len(hourly))
+		}
+		capped[h] = &models.UnmetDemand{
+			TotalDemand:     demand,
+			AllocatedAgents: demand - cut,
+			UnmetAgents:     cut,
+			ImpactedClients: clients,
+		}
+	}
+	return capped
+}
+
+// settleFTE caps every requirement's FTE at the whole agents it ended up
+// with, once ceilings and capacity have cut them.
+func settleFTE(hourly [][]models.CustomerRequirement) {
+	for _, reqs := range hourly {
+		for i := range reqs {
+			reqs[i].FTE = min(reqs[i].FTE, float64(reqs[i].AgentsNeeded))
+		}
+	}
+}
+
+// addCapped folds the demand ceilings left unmet, by slot as clamp returns
+// it, into schedule's unmet demand. A client that capacity then cut short of
+// its ceiling as well is reported once, with its demand before the ceiling.
+func addCapped(schedule *models.Schedule, capped []*models.UnmetDemand) {
+	if capped == nil {
+		return
+	}
+	short := make(map[int]int, len(schedule.UnmetDemands))
+	for i, unmet := range schedule.UnmetDemands {
+		short[unmet.Hour] = i
+	}
+	for h, c := range capped {
+		if c == nil {
+			continue
+		}
+		i, ok := short[h]
+		if !ok {
+			c.Hour = h
+			c.Slot = schedule.SlotAt(h)
+			schedule.UnmetDemands = append(schedule.UnmetDemands, *c)
+			continue
+		}
+		unmet := &schedule.UnmetDemands[i]
+		unmet.TotalDemand += c.UnmetAgents
+		unmet.UnmetAgents += c.UnmetAgents
+		for _, client := range c.ImpactedClients {
+			j := slices.IndexFunc(unmet.ImpactedClients, func(x models.ImpactedClient) bool {
+				return x.CeilingAgents == 0 && x.RequestedAgents == client.CeilingAgents && keyOfClient(x) == keyOfClient(client)
+			})
+			if j < 0 {
+				unmet.ImpactedClients = append(unmet.ImpactedClients, client)
+				continue
+			}
+			short := &unmet.ImpactedClients[j]
+			short.RequestedAgents = client.RequestedAgents
+			short.UnmetAgents = short.RequestedAgents - short.AllocatedAgents
+			short.CeilingAgents = client.CeilingAgents
+		}
+	}
+	slices.SortStableFunc(schedule.UnmetDemands, func(a, b models.UnmetDemand) int {
+		return a.Hour - b.Hour
+	})
+}
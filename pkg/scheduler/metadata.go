@@ -0,0 +1,56 @@
+package scheduler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+	"github.com/karthikrao-23/agentscheduler/pkg/version"
+)
+
+// newMetadata describes how a schedule was produced from the input with
+// inputHash.
+func newMetadata(runID, inputHash string, cfg config) *models.Metadata {
+	return &models.Metadata{
+		GeneratedAt: time.Now().UTC(),
+		RunID:       runID,
+		InputHash:   inputHash,
+		Options:     cfg.describe(),
+		ToolVersion: version.String(),
+	}
+}
+
+// InputHash returns the hex SHA-256 of data in its JSON form, as recorded in
+// Metadata.InputHash. Equal inputs hash equally regardless of where they
+// were parsed from.
+func InputHash(data []models.CallData) string {
+	b, err := json.Marshal(data)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+// CacheKey identifies the schedule GenerateSchedule would produce for data
+// under opts: the input hash together with the options in effect, as
+// recorded in Metadata. Custom allocators are only distinguished by type.
+func CacheKey(data []models.CallData, opts ...Option) string {
+	described := newConfig(opts...).describe()
+	names := make([]string, 0, len(described))
+	for name := range described {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	h := sha256.New()
+	h.Write([]byte(InputHash(data)))
+	for _, name := range names {
+		fmt.Fprintf(h, "\n%s=%s", name, described[name])
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
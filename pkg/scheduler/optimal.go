@@ -0,0 +1,280 @@
+package scheduler
+
+import (
+	"math"
+	"slices"
+
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+)
+
+// OptimalAllocator shares out a short slot so as to maximize its weighted
+// satisfied demand: the sum over requests of the agents each gets times its
+// priority's weight. Unlike PriorityAllocator's single greedy pass, this
+// holds across agent pools too, where drawing on a generalist for one skill
+// can leave another skill short that a better choice would have served.
+//
+// It solves the slot as a min-cost flow from requests, through the pools
+// that serve their skill, to capacity. Requests with the same weight and
+// skill are pooled into one class first, so the network stays small however
+// many requests a slot has; a class's agents go to its requests in the same
+// order as PriorityAllocator, which also breaks ties between equal weights.
+// Guarantees (MinAgents) are met before any weighted demand. Among
+// allocations of the same weighted demand, it draws on pools of lower tiers
+// first, so demand overflows to higher tiers only when it has to.
+type OptimalAllocator struct {
+	// Weights is each priority's value per agent. Priorities without one
+	// weigh 1/priority, so priority 1 counts 1, priority 2 counts 0.5, and
+	// so on; weights must be positive.
+	Weights map[models.Priority]float64
+	// Pools, if any, are the agent pools the slot is staffed from, as with
+	// WithAgentPools; otherwise any agent serves any request.
+	Pools []models.AgentPool
+
+	// used, if set, receives the agents drawn from each pool
+	used []int
+}
+
+// weight returns priority's value per agent.
+func (a OptimalAllocator) weight(priority models.Priority) float64 {
+	if w, ok := a.Weights[priority]; ok {
+		return w
+	}
+	return 1 / float64(priority)
+}
+
+// demandClass is demand that is interchangeable for the flow: of the same
+// skill, weight, and whether it is guaranteed.
+type demandClass struct {
+	skill      string
+	weight     float64
+	guaranteed bool
+}
+
+// Allocate shares out capacity, and with Pools their headcount, to maximize
+// weighted satisfied demand.
+func (a OptimalAllocator) Allocate(requests []models.CustomerRequirement, capacity int) ([]models.CustomerRequirement, *models.UnmetDemand) {
+	if len(requests) == 0 {
+		return nil, nil
+	}
+
+	totalDemand := sumDemand(requests)
+	sortRequests(requests)
+	if len(a.Pools) == 0 && capacity >= totalDemand {
+		return requests, nil
+	}
+
+	// Classes in the order first seen, with their demand; each request's
+	// guaranteed and weighted demand belong to one class each
+	var classes []demandClass
+	var demand []int
+	index := make(map[demandClass]int)
+	classOf := func(c demandClass, agents int) int {
+		i, ok := index[c]
+		if !ok {
+			i = len(classes)
+			index[c] = i
+			classes = append(classes, c)
+			demand = append(demand, 0)
+		}
+		demand[i] += agents
+		return i
+	}
+	guaranteedClass := make([]int, len(requests))
+	weightedClass := make([]int, len(requests))
+	for i, req := range requests {
+		g := min(req.MinAgents, req.AgentsNeeded)
+		guaranteedClass[i] = -1
+		if g > 0 {
+			guaranteedClass[i] = classOf(demandClass{skill: req.Skill, guaranteed: true}, g)
+		}
+		weightedClass[i] = classOf(demandClass{skill: req.Skill, weight: a.weight(req.Priority)}, req.AgentsNeeded-g)
+	}
+
+	// A guaranteed agent is worth more than all weighted demand together
+	bonus := 1.0
+	for i, c := range classes {
+		bonus += c.weight * float64(demand[i])
+	}
+	pools := a.Pools
+	if len(pools) == 0 {
+		pools = []models.AgentPool{{Headcount: capacity}}
+	}
+	f := newFlow(3 + len(classes) + len(pools))
+	const supply, source, sink = 0, 1, 2
+	f.edge(supply, source, capacity, 0)
+	for i, c := range classes {
+		value := c.weight
+		if c.guaranteed {
+			value = bonus
+		}
+		f.edge(source, 3+i, demand[i], -value)
+		for j, pool := range pools {
+			if len(a.Pools) == 0 || pool.Serves(c.skill) {
+				f.edge(3+i, 3+len(classes)+j, demand[i], 0)
+			}
+		}
+	}
+	// Each tier up costs a sliver of an agent's least value, so little that
+	// all of them together are worth less than one agent
+	least, top := math.Inf(1), 0
+	for _, c := range classes {
+		if !c.guaranteed {
+			least = math.Min(least, c.weight)
+		}
+	}
+	least = math.Min(least, bonus)
+	for _, pool := range pools {
+		top = max(top, pool.Tier)
+	}
+	tierCost := least / float64((top+1)*(capacity+1))
+	for j, pool := range pools {
+		f.edge(3+len(classes)+j, sink, pool.Headcount, float64(pool.Tier)*tierCost)
+	}
+	f.run(supply, sink)
+	if a.used != nil {
+		for j := range a.Pools {
+			a.used[j] += f.flowOut(3+len(classes)+j, sink)
+		}
+	}
+
+	// Hand each class's agents to its requests in allocation order,
+	// guarantees first
+	got := make([]int, len(requests))
+	for i, req := range requests {
+		if k := guaranteedClass[i]; k >= 0 {
+			n := min(f.flowOut(source, 3+k), min(req.MinAgents, req.AgentsNeeded))
+			f.take(source, 3+k, n)
+			got[i] = n
+		}
+	}
+	for i, req := range requests {
+		k := weightedClass[i]
+		n := min(f.flowOut(source, 3+k), req.AgentsNeeded-min(req.MinAgents, req.AgentsNeeded))
+		f.take(source, 3+k, n)
+		got[i] += n
+	}
+
+	allocated := make([]models.CustomerRequirement, 0, len(requests))
+	var impactedClients []models.ImpactedClient
+	unmetAgents := 0
+	for i, req := range requests {
+		if got[i] > 0 || req.AgentsNeeded == 0 {
+			partial := req
+			partial.AgentsNeeded = got[i]
+			allocated = append(allocated, partial)
+		}
+		if got[i] == req.AgentsNeeded {
+			continue
+		}
+		impactedClients = append(impactedClients, models.ImpactedClient{
+			Name:            req.Name,
+			RequestedAgents: req.AgentsNeeded,
+			AllocatedAgents: got[i],
+			UnmetAgents:     req.AgentsNeeded - got[i],
+			Priority:        req.Priority,
+			Location:        req.Location,
+			Skill:           req.Skill,
+			MinAgents:       req.MinAgents,
+		})
+		unmetAgents += req.AgentsNeeded - got[i]
+	}
+	if len(impactedClients) == 0 {
+		return allocated, nil
+	}
+	return allocated, &models.UnmetDemand{
+		TotalDemand:     totalDemand,
+		AllocatedAgents: totalDemand - unmetAgents,
+		UnmetAgents:     unmetAgents,
+		ImpactedClients: impactedClients,
+	}
+}
+
+// flow is a min-cost flow network, solved by successive shortest paths.
+type flow struct {
+	// edges holds each edge followed by its reverse, so edge e's reverse is
+	// e^1
+	edges []flowEdge
+	adj   [][]int
+}
+
+type flowEdge struct {
+	to, capacity int
+	cost         float64
+}
+
+func newFlow(nodes int) *flow {
+	return &flow{adj: make([][]int, nodes)}
+}
+
+// edge adds an edge from u to v carrying up to capacity at cost per unit.
+func (f *flow) edge(u, v, capacity int, cost float64) {
+	f.adj[u] = append(f.adj[u], len(f.edges))
+	f.edges = append(f.edges, flowEdge{to: v, capacity: capacity, cost: cost})
+	f.adj[v] = append(f.adj[v], len(f.edges))
+	f.edges = append(f.edges, flowEdge{to: u, cost: -cost})
+}
+
+// run pushes flow from s to t along the cheapest path while that path has
+// a negative cost, which leaves the cheapest flow of any amount.
+func (f *flow) run(s, t int) {
+	// Costs are sums of weights, so anything within eps of 0 is 0
+	const eps = 1e-9
+	n := len(f.adj)
+	dist := make([]float64, n)
+	via := make([]int, n)
+	for {
+		// Bellman-Ford, as costs are negative; the network is small
+		for i := range dist {
+			dist[i], via[i] = math.Inf(1), -1
+		}
+		dist[s] = 0
+		for changed, round := true, 0; changed && round < n; round++ {
+			changed = false
+			for u := range n {
+				if math.IsInf(dist[u], 1) {
+					continue
+				}
+				for _, e := range f.adj[u] {
+					edge := f.edges[e]
+					if edge.capacity > 0 && dist[u]+edge.cost < dist[edge.to]-eps {
+						dist[edge.to] = dist[u] + edge.cost
+						via[edge.to] = e
+						changed = true
+					}
+				}
+			}
+		}
+		if via[t] < 0 || dist[t] > -eps {
+			return
+		}
+		push := math.MaxInt
+		for v := t; v != s; v = f.edges[via[v]^1].to {
+			push = min(push, f.edges[via[v]].capacity)
+		}
+		for v := t; v != s; v = f.edges[via[v]^1].to {
+			f.edges[via[v]].capacity -= push
+			f.edges[via[v]^1].capacity += push
+		}
+	}
+}
+
+// flowOut returns the flow left on the edge from u to v, as take has not
+// yet handed it out.
+func (f *flow) flowOut(u, v int) int {
+	e := f.find(u, v)
+	return f.edges[e^1].capacity
+}
+
+// take hands out n of the flow on the edge from u to v.
+func (f *flow) take(u, v, n int) {
+	e := f.find(u, v)
+	f.edges[e^1].capacity -= n
+}
+
+// find returns the edge from u to v.
+func (f *flow) find(u, v int) int {
+	i := slices.IndexFunc(f.adj[u], func(e int) bool {
+		return e%2 == 0 && f.edges[e].to == v
+	})
+	return f.adj[u][i]
+}
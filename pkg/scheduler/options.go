@@ -0,0 +1,605 @@
+package scheduler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/karthikrao-23/agentscheduler/pkg/errors"
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+)
+
+// Option configures schedule generation.
+type Option func(*config)
+
+type config struct {
+	utilization     float64
+	maxOccupancy    float64
+	capacity        int
+	capacityProfile []int
+	allocator       Allocator
+	workers         int
+	aggregate       bool
+	interval        time.Duration
+	multiDay        bool
+	utc             bool
+	weekly          models.WeeklyPattern
+	calendar        models.Calendar
+	seasonality     models.Seasonality
+	peakedness      float64
+	arrivalCurves   models.ArrivalCurves
+	pools           []models.AgentPool
+	reserved        map[models.Priority]float64
+	smoothing       *ramp
+	fte             bool
+	hooks           Hooks
+	// labeledMetrics publishes the gauges labeled by customer, location,
+	// skill, and pool
+	labeledMetrics bool
+	// erlangC is the service level to staff to with Erlang C, or nil to
+	// staff to raw workload
+	erlangC *serviceLevel
+	// poisson is the percentile of calls in progress to staff for, or 0 to
+	// staff otherwise
+	poisson float64
+}
+
+// newConfig returns the defaults (full utilization, unlimited capacity,
+// priority allocation, aggregated requirements, hourly slots) with opts
+// applied on top.
+func newConfig(opts ...Option) config {
+	cfg := config{utilization: 1.0, allocator: PriorityAllocator{}, aggregate: true, interval: time.Hour, labeledMetrics: true}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	// Pools route by skill, which the default allocator does not
+	if _, ok := cfg.allocator.(PriorityAllocator); ok && len(cfg.pools) > 0 {
+		cfg.allocator = PoolAllocator{Pools: cfg.pools}
+	}
+	return cfg
+}
+
+// WithUtilization sets the utilization multiplier (between 0 and 1) used to
+// inflate raw workload into agents. Defaults to 1.0.
+func WithUtilization(utilization float64) Option {
+	return func(c *config) {
+		c.utilization = utilization
+	}
+}
+
+// WithMaxOccupancy caps the share of their time agents spend on calls, e.g.
+// 0.9 for 90%, by staffing every slot with at least its workload divided by
+// maxOccupancy. The workload model otherwise keeps agents busy all slot, and
+// Erlang C nearly so at high volumes. Utilization still applies on top.
+// Defaults to 0, no cap.
+func WithMaxOccupancy(maxOccupancy float64) Option {
+	return func(c *config) {
+		c.maxOccupancy = maxOccupancy
+	}
+}
+
+// WithFTE fills in every requirement's FTE: the agents it needs before they
+// are rounded up to whole agents, which otherwise compounds into a large
+// overstatement over many small customers. Allocation still shares out whole
+// agents, and a requirement's FTE never exceeds the agents it is allocated.
+// Off by default.
+func WithFTE(fte bool) Option {
+	return func(c *config) {
+		c.fte = fte
+	}
+}
+
+// WithCapacity caps the agents allocated in every hour. 0 means unlimited,
+// which is the default.
+func WithCapacity(capacityPerHour int) Option {
+	return func(c *config) {
+		c.capacity = capacityPerHour
+	}
+}
+
+// WithCapacityProfile sets a separate capacity for each hour of the day,
+// indexed by hour (0-23). Hours missing from the profile fall back to
+// WithCapacity; a value of 0 leaves that hour unlimited.
+func WithCapacityProfile(capacityByHour []int) Option {
+	return func(c *config) {
+		c.capacityProfile = capacityByHour
+	}
+}
+
+// WithAllocator replaces the PriorityAllocator used to share out capacity in
+// every hour that has a limit.
+func WithAllocator(a Allocator) Option {
+	return func(c *config) {
+		c.allocator = a
+	}
+}
+
+// WithWorkers expands call data and allocates hours on up to n goroutines.
+// The schedule is the same for any n, but the Allocator must then be safe
+// for concurrent use, as PriorityAllocator is. Defaults to 1.
+func WithWorkers(n int) Option {
+	return func(c *config) {
+		c.workers = n
+	}
+}
+
+// WithLabeledMetrics controls whether a run publishes the gauges labeled by
+// customer, location, skill, and pool, alongside its totals. Turn it off
+// when the metrics are scraped by someone who may not see the input's
+// client names, as for a multi-tenant server's runs. The schedule is the
+// same either way. On by default.
+func WithLabeledMetrics(labeled bool) Option {
+	return func(c *config) {
+		c.labeledMetrics = labeled
+	}
+}
+
+// WithAggregation controls whether the requirements of rows for the same
+// customer and hour are merged as they are added, so a schedule's size grows
+// with the number of customers rather than rows. Allocation then sees one
+// request per customer per hour, with the rows' agents summed. On by
+// default; turn it off to keep a requirement per row.
+func WithAggregation(aggregate bool) Option {
+	return func(c *config) {
+		c.aggregate = aggregate
+	}
+}
+
+// WithInterval sets the length of the schedule's slots, e.g. 15 or 30
+// minutes to plan as most contact centers do. It must be a whole number of
+// minutes that divides an hour evenly. Each slot then needs the agents to
+// work its share of calls within it, and the hourly capacities of
+// WithCapacity and WithCapacityProfile apply to every slot in the hour.
+// Defaults to an hour.
+func WithInterval(interval time.Duration) Option {
+	return func(c *config) {
+		c.interval = interval
+	}
+}
+
+// WithMultiDay keys the schedule by calendar date as well as time of day, so
+// a week or a month of call data schedules in one run: there is a day of
+// slots for every date from the first row's to the last's, and overnight
+// windows run on into the next day. Off by default, when every row is
+// folded into one generic day whatever its date. Capacities apply to each
+// day alike.
+func WithMultiDay(multiDay bool) Option {
+	return func(c *config) {
+		c.multiDay = multiDay
+	}
+}
+
+// WithUTC keys the schedule by the UTC time each slot starts rather than
+// each customer's local time of day, so customers in different timezones
+// working at the same moment share a slot, and its capacity: 9AM in Los
+// Angeles and noon in New York are both 17:00 UTC. Slots then carry UTC as
+// their location, and per-hour capacities are by UTC hour. Off by default,
+// when 9AM is 9AM wherever each customer is.
+func WithUTC(utc bool) Option {
+	return func(c *config) {
+		c.utc = utc
+	}
+}
+
+// WithWeeklyPattern repeats every row on each day of the week it starts in,
+// Monday to Sunday, with its calls scaled by the customer's multiplier for
+// that weekday, so one row describes a week. The schedule is laid out by
+// date as with WithMultiDay. Customers missing from pattern keep the same
+// volume every day.
+func WithWeeklyPattern(pattern models.WeeklyPattern) Option {
+	return func(c *config) {
+		c.weekly = pattern
+	}
+}
+
+// validate checks the options for values no schedule can satisfy.
+func (c config) validate() error {
+	if !finite(c.utilization) || c.utilization <= 0 || c.utilization > 1 {
+		return &errors.ConstraintViolationError{
+			Constraint: "utilization",
+			Value:      c.utilization,
+			Detail:     "must be greater than 0 and at most 1",
+			Err:        errors.ErrInvalidUtilization,
+		}
+	}
+	if !finite(c.maxOccupancy) || c.maxOccupancy < 0 || c.maxOccupancy > 1 {
+		return &errors.ConstraintViolationError{
+			Constraint: "max_occupancy",
+			Value:      c.maxOccupancy,
+			Detail:     "must be greater than 0 and at most 1, or 0 for no cap",
+			Err:        errors.ErrInfeasibleConstraints,
+		}
+	}
+	if c.peakedness != 0 && (!finite(c.peakedness) || c.peakedness < 1) {
+		return &errors.ConstraintViolationError{
+			Constraint: "peakedness",
+			Value:      c.peakedness,
+			Detail:     "must be finite and at least 1, as no part of a slot is busier than its peak",
+			Err:        errors.ErrInfeasibleConstraints,
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(c.arrivalCurves)) {
+		if _, ok := c.arrivalCurves.Peakedness(name); !ok || slices.ContainsFunc(c.arrivalCurves[name], func(share float64) bool { return !finite(share) || share < 0 }) {
+			return &errors.ConstraintViolationError{
+				Constraint: fmt.Sprintf("arrival_curves[%s]", name),
+				Value:      c.arrivalCurves[name],
+				Detail:     "must have finite, non-negative shares that are not all 0",
+				Err:        errors.ErrInfeasibleConstraints,
+			}
+		}
+	}
+	if c.interval < time.Minute || c.interval%time.Minute != 0 || time.Hour%c.interval != 0 {
+		return &errors.ConstraintViolationError{
+			Constraint: "interval",
+			Value:      c.interval,
+			Detail:     "must be a whole number of minutes that divides an hour evenly",
+			Err:        errors.ErrInfeasibleConstraints,
+		}
+	}
+	if c.capacity < 0 {
+		return &errors.ConstraintViolationError{
+			Constraint: "capacity",
+			Value:      c.capacity,
+			Detail:     "must not be negative",
+			Err:        errors.ErrInfeasibleConstraints,
+		}
+	}
+	if len(c.capacityProfile) > 24 {
+		return &errors.ConstraintViolationError{
+			Constraint: "capacity_profile",
+			Value:      len(c.capacityProfile),
+			Detail:     "has more entries than hours in a day",
+			Err:        errors.ErrInfeasibleConstraints,
+		}
+	}
+	for h, capacity := range c.capacityProfile {
+		if capacity < 0 {
+			return &errors.ConstraintViolationError{
+				Constraint: fmt.Sprintf("capacity_profile[%d]", h),
+				Value:      capacity,
+				Detail:     "must not be negative",
+				Err:        errors.ErrInfeasibleConstraints,
+			}
+		}
+	}
+	if len(c.pools) > 0 {
+		if c.capacity > 0 || len(c.capacityProfile) > 0 {
+			return &errors.ConstraintViolationError{
+				Constraint: "agent_pools",
+				Value:      len(c.pools),
+				Detail:     "cannot be combined with a capacity limit, as their headcount is the capacity",
+				Err:        errors.ErrInfeasibleConstraints,
+			}
+		}
+		for i, pool := range c.pools {
+			if pool.Headcount < 0 {
+				return &errors.ConstraintViolationError{
+					Constraint: fmt.Sprintf("agent_pools[%d].headcount", i),
+					Value:      pool.Headcount,
+					Detail:     "must not be negative",
+					Err:        errors.ErrInfeasibleConstraints,
+				}
+			}
+			for _, hour := range []time.Duration{pool.Open, pool.Close} {
+				if hour < 0 || hour >= 24*time.Hour {
+					return &errors.ConstraintViolationError{
+						Constraint: fmt.Sprintf("agent_pools[%d].hours", i),
+						Value:      hour,
+						Detail:     "must open and close within the day",
+						Err:        errors.ErrInfeasibleConstraints,
+					}
+				}
+			}
+			if !finite(pool.HourlyCost) || pool.HourlyCost < 0 {
+				return &errors.ConstraintViolationError{
+					Constraint: fmt.Sprintf("agent_pools[%d].hourly_cost", i),
+					Value:      pool.HourlyCost,
+					Detail:     "must be finite and not negative",
+					Err:        errors.ErrInfeasibleConstraints,
+				}
+			}
+			if pool.Tier < 0 {
+				return &errors.ConstraintViolationError{
+					Constraint: fmt.Sprintf("agent_pools[%d].tier", i),
+					Value:      pool.Tier,
+					Detail:     "must not be negative",
+					Err:        errors.ErrInfeasibleConstraints,
+				}
+			}
+		}
+		if !routesBySkill(c.allocator) {
+			return &errors.ConstraintViolationError{
+				Constraint: "allocator",
+				Value:      fmt.Sprintf("%T", c.allocator),
+				Detail:     "cannot be combined with agent pools, as it does not route by skill",
+				Err:        errors.ErrInfeasibleConstraints,
+			}
+		}
+		// A capacity of 0 would mean unlimited
+		if headcount(c.pools) == 0 {
+			return &errors.ConstraintViolationError{
+				Constraint: "agent_pools",
+				Value:      0,
+				Detail:     "must have a headcount between them",
+				Err:        errors.ErrInfeasibleConstraints,
+			}
+		}
+	}
+	if len(c.reserved) > 0 {
+		if len(c.pools) > 0 {
+			return &errors.ConstraintViolationError{
+				Constraint: "reserved_capacity",
+				Value:      len(c.reserved),
+				Detail:     "cannot be combined with agent pools",
+				Err:        errors.ErrInfeasibleConstraints,
+			}
+		}
+		total := 0.0
+		for _, priority := range slices.Sorted(maps.Keys(c.reserved)) {
+			percent := c.reserved[priority]
+			if !priority.Valid() || !finite(percent) || percent <= 0 || percent > 100 {
+				return &errors.ConstraintViolationError{
+					Constraint: fmt.Sprintf("reserved_capacity[%d]", priority),
+					Value:      percent,
+					Detail:     "must be for a valid priority and greater than 0 and at most 100 percent",
+					Err:        errors.ErrInfeasibleConstraints,
+				}
+			}
+			total += percent
+		}
+		if total > 100 {
+			return &errors.ConstraintViolationError{
+				Constraint: "reserved_capacity",
+				Value:      total,
+				Detail:     "must not reserve more than 100 percent in total",
+				Err:        errors.ErrInfeasibleConstraints,
+			}
+		}
+	}
+	if optimal, ok := c.allocator.(OptimalAllocator); ok {
+		for _, priority := range slices.Sorted(maps.Keys(optimal.Weights)) {
+			if w := optimal.Weights[priority]; !priority.Valid() || !finite(w) || w <= 0 {
+				return &errors.ConstraintViolationError{
+					Constraint: fmt.Sprintf("allocator_weights[%d]", priority),
+					Value:      w,
+					Detail:     "must be for a valid priority, finite, and greater than 0",
+					Err:        errors.ErrInfeasibleConstraints,
+				}
+			}
+		}
+	}
+	if c.smoothing != nil {
+		if c.smoothing.window < 1 {
+			return &errors.ConstraintViolationError{
+				Constraint: "smoothing_window",
+				Value:      c.smoothing.window,
+				Detail:     "must be at least 1 slot",
+				Err:        errors.ErrInfeasibleConstraints,
+			}
+		}
+		if c.smoothing.maxDelta < 1 {
+			return &errors.ConstraintViolationError{
+				Constraint: "smoothing_max_delta",
+				Value:      c.smoothing.maxDelta,
+				Detail:     "must be at least 1 agent",
+				Err:        errors.ErrInfeasibleConstraints,
+			}
+		}
+	}
+	if c.calendar != nil && !c.byDate() {
+		return &errors.ConstraintViolationError{
+			Constraint: "calendar",
+			Value:      len(c.calendar),
+			Detail:     "needs a schedule laid out by date, with multi-day or a weekly pattern",
+			Err:        errors.ErrInfeasibleConstraints,
+		}
+	}
+	if c.poisson != 0 {
+		if c.erlangC != nil {
+			return &errors.ConstraintViolationError{
+				Constraint: "staffing_model",
+				Value:      "poisson",
+				Detail:     "cannot be combined with Erlang C",
+				Err:        errors.ErrInfeasibleConstraints,
+			}
+		}
+		// No number of agents is enough for every moment
+		if !finite(c.poisson) || c.poisson <= 0 || c.poisson >= 100 {
+			return &errors.ConstraintViolationError{
+				Constraint: "poisson_percentile",
+				Value:      c.poisson,
+				Detail:     "must be greater than 0 and less than 100",
+				Err:        errors.ErrInfeasibleConstraints,
+			}
+		}
+	}
+	if c.erlangC != nil {
+		// No number of agents answers every call in time, so 100% is out too
+		if !finite(c.erlangC.targetPercent) || c.erlangC.targetPercent <= 0 || c.erlangC.targetPercent >= 100 {
+			return &errors.ConstraintViolationError{
+				Constraint: "sla_target",
+				Value:      c.erlangC.targetPercent,
+				Detail:     "must be greater than 0 and less than 100 percent",
+				Err:        errors.ErrInvalidSLA,
+			}
+		}
+		if c.erlangC.thresholdSeconds <= 0 {
+			return &errors.ConstraintViolationError{
+				Constraint: "sla_threshold_seconds",
+				Value:      c.erlangC.thresholdSeconds,
+				Detail:     "must be positive",
+				Err:        errors.ErrInvalidSLA,
+			}
+		}
+	}
+	return nil
+}
+
+// finite reports whether x is neither NaN nor infinite. Every range check
+// needs it, as NaN fails every comparison and so passes any written as a
+// rejection.
+func finite(x float64) bool {
+	return !math.IsNaN(x) && !math.IsInf(x, 0)
+}
+
+// capacityFor returns the capacity for hour h (0 = unlimited).
+func (c config) capacityFor(h int) int {
+	if len(c.pools) > 0 {
+		return headcount(c.pools)
+	}
+	if h < len(c.capacityProfile) {
+		return c.capacityProfile[h]
+	}
+	return c.capacity
+}
+
+// byDate reports whether the schedule is laid out by calendar date rather
+// than as one generic day.
+func (c config) byDate() bool {
+	return c.multiDay || c.weekly != nil
+}
+
+// slots returns the number of slots in a day.
+func (c config) slots() int {
+	return int(24 * time.Hour / c.interval)
+}
+
+// slotHour returns the hour of day slot i falls in, on whichever day.
+func (c config) slotHour(i int) int {
+	return i % c.slots() / int(time.Hour/c.interval)
+}
+
+// capacityForSlot returns the capacity for slot i: that of its hour.
+func (c config) capacityForSlot(i int) int {
+	return c.capacityFor(c.slotHour(i))
+}
+
+// constrained reports whether any hour has a capacity limit.
+func (c config) constrained() bool {
+	for h := range 24 {
+		if c.capacityFor(h) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// describe lists the options in effect for the schedule's metadata.
+func (c config) describe() map[string]string {
+	opts := map[string]string{
+		"utilization": strconv.FormatFloat(c.utilization, 'g', -1, 64),
+		"capacity":    strconv.Itoa(c.capacity),
+		"allocator":   fmt.Sprintf("%T", c.allocator),
+	}
+	if c.maxOccupancy > 0 {
+		opts["max_occupancy"] = strconv.FormatFloat(c.maxOccupancy, 'g', -1, 64)
+	}
+	if !c.aggregate {
+		opts["aggregation"] = "false"
+	}
+	if c.interval != time.Hour {
+		opts["interval"] = c.interval.String()
+	}
+	if c.multiDay {
+		opts["multi_day"] = "true"
+	}
+	if c.utc {
+		opts["utc"] = "true"
+	}
+	if c.fte {
+		opts["fte"] = "true"
+	}
+	if c.weekly != nil {
+		// The pattern could be long, and only needs telling apart
+		h := sha256.New()
+		for _, name := range slices.Sorted(maps.Keys(c.weekly)) {
+			fmt.Fprintf(h, "%s=%v\n", name, c.weekly[name])
+		}
+		opts["weekly_pattern"] = hex.EncodeToString(h.Sum(nil))
+	}
+	if c.peakedness != 0 && c.peakedness != 1 {
+		opts["peakedness"] = strconv.FormatFloat(c.peakedness, 'g', -1, 64)
+	}
+	if c.arrivalCurves != nil {
+		h := sha256.New()
+		for _, name := range slices.Sorted(maps.Keys(c.arrivalCurves)) {
+			fmt.Fprintf(h, "%s=%v\n", name, c.arrivalCurves[name])
+		}
+		opts["arrival_curves"] = hex.EncodeToString(h.Sum(nil))
+	}
+	if c.calendar != nil {
+		h := sha256.New()
+		for _, e := range c.calendar {
+			fmt.Fprintf(h, "%+v\n", e)
+		}
+		opts["calendar"] = hex.EncodeToString(h.Sum(nil))
+	}
+	if c.seasonality != nil {
+		h := sha256.New()
+		for _, m := range c.seasonality {
+			fmt.Fprintf(h, "%+v\n", m)
+		}
+		opts["seasonality"] = hex.EncodeToString(h.Sum(nil))
+	}
+	if c.erlangC != nil {
+		opts["staffing_model"] = "erlang_c"
+		opts["sla_target"] = strconv.FormatFloat(c.erlangC.targetPercent, 'g', -1, 64)
+		opts["sla_threshold_seconds"] = strconv.Itoa(c.erlangC.thresholdSeconds)
+	}
+	if c.poisson != 0 {
+		opts["staffing_model"] = "poisson"
+		opts["poisson_percentile"] = strconv.FormatFloat(c.poisson, 'g', -1, 64)
+	}
+	if len(c.pools) > 0 {
+		pools := make([]string, len(c.pools))
+		for i, pool := range c.pools {
+			pools[i] = fmt.Sprintf("%s(%s)=%d", pool.Name, strings.Join(pool.Skills, ";"), pool.Headcount)
+			// Hours, cost and tier only when set, so plain pools read as before
+			if pool.Open != 0 || pool.Close != 0 {
+				pools[i] += fmt.Sprintf("@%s-%s", clock(pool.Open), clock(pool.Close))
+				if !pool.Location.IsZero() {
+					pools[i] += " " + pool.Location.String()
+				}
+			}
+			if pool.HourlyCost != 0 {
+				pools[i] += "$" + strconv.FormatFloat(pool.HourlyCost, 'g', -1, 64)
+			}
+			if pool.Tier != 0 {
+				pools[i] += "#" + strconv.Itoa(pool.Tier)
+			}
+		}
+		opts["agent_pools"] = strings.Join(pools, ",")
+	}
+	if len(c.reserved) > 0 {
+		var tiers []string
+		for _, priority := range slices.Sorted(maps.Keys(c.reserved)) {
+			tiers = append(tiers, fmt.Sprintf("%d=%s", priority, strconv.FormatFloat(c.reserved[priority], 'g', -1, 64)))
+		}
+		opts["reserved_capacity"] = strings.Join(tiers, ",")
+	}
+	if optimal, ok := c.allocator.(OptimalAllocator); ok && len(optimal.Weights) > 0 {
+		var weights []string
+		for _, priority := range slices.Sorted(maps.Keys(optimal.Weights)) {
+			weights = append(weights, fmt.Sprintf("%d=%s", priority, strconv.FormatFloat(optimal.Weights[priority], 'g', -1, 64)))
+		}
+		opts["allocator_weights"] = strings.Join(weights, ",")
+	}
+	if c.smoothing != nil {
+		opts["smoothing_window"] = strconv.Itoa(c.smoothing.window)
+		opts["smoothing_max_delta"] = strconv.Itoa(c.smoothing.maxDelta)
+	}
+	if len(c.capacityProfile) > 0 {
+		hours := make([]string, len(c.capacityProfile))
+		for h, capacity := range c.capacityProfile {
+			hours[h] = strconv.Itoa(capacity)
+		}
+		opts["capacity_profile"] = strings.Join(hours, ",")
+	}
+	return opts
+}
@@ -0,0 +1,38 @@
+package scheduler
+
+import (
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+)
+
+// WithPeakedness staffs every slot for calls arriving factor times faster
+// than its average, as calls bunch up within a slot rather than arriving
+// evenly, and agents spread over the whole slot fall short in its busiest
+// part. A factor of 1.2 staffs for a peak 20% above the average. Customers
+// with an arrival curve use its peakedness instead. Defaults to 1.
+func WithPeakedness(factor float64) Option {
+	return func(c *config) {
+		c.peakedness = factor
+	}
+}
+
+// WithArrivalCurves staffs each customer in curves for the busiest part of
+// every slot by its curve, rather than the slot's average: a customer with
+// 30% of an hour's calls in its busiest quarter is staffed for 1.2 times its
+// hourly rate. Curves apply to slots of any length.
+func WithArrivalCurves(curves models.ArrivalCurves) Option {
+	return func(c *config) {
+		c.arrivalCurves = curves
+	}
+}
+
+// peakFor returns how much faster than average customer's calls arrive in
+// the busiest part of a slot.
+func (c config) peakFor(customer string) float64 {
+	if peak, ok := c.arrivalCurves.Peakedness(customer); ok {
+		return peak
+	}
+	if c.peakedness > 0 {
+		return c.peakedness
+	}
+	return 1
+}
@@ -0,0 +1,43 @@
+package scheduler
+
+import "math"
+
+// WithPoisson staffs every slot for the calls in progress at once, assuming
+// they arrive as a Poisson process: the fewest agents that are enough for
+// all of them at least percentile percent of the time, rather than the
+// average number the workload formula staffs for. With calls arriving at
+// random, the number in progress is Poisson distributed with the offered
+// load as its mean, so 10 Erlangs take 15 agents at the 95th percentile.
+//
+// Like WithErlangC, which it replaces, it staffs to the rate calls arrive at
+// while their window is open. Utilization still applies on top.
+func WithPoisson(percentile float64) Option {
+	return func(c *config) {
+		c.poisson = percentile
+	}
+}
+
+// poissonAgents returns the fewest agents that cover the calls in progress
+// at percentile, for calls arriving at callsPerHour and each lasting
+// durationSeconds.
+func poissonAgents(callsPerHour float64, durationSeconds int, percentile float64) int {
+	if callsPerHour <= 0 || durationSeconds <= 0 {
+		return 0
+	}
+	load := callsPerHour * float64(durationSeconds) / 3600
+	target := percentile / 100
+
+	// Sum the distribution up to the target, in logs as e^-load underflows
+	// for loads in the hundreds
+	logLoad := math.Log(load)
+	cumulative := 0.0
+	for n := 0; ; n++ {
+		logFactorial, _ := math.Lgamma(float64(n) + 1)
+		cumulative += math.Exp(-load + float64(n)*logLoad - logFactorial)
+		// The tail past the mean holds little mass, so rounding in the sum
+		// can leave it just short of a target close to 1
+		if cumulative >= target-1e-12 || float64(n) > load+40*math.Sqrt(load)+40 {
+			return n
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package scheduler
+
+import (
+	"sync"
+
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+)
+
+// Pools for the slices and maps a run builds and throws away, so that
+// back-to-back runs, as in serve mode, reuse them rather than leaving them
+// for the garbage collector. Nothing that ends up in a returned schedule is
+// ever put back.
+var (
+	requirementPool slicePool[models.CustomerRequirement]
+	impactedPool    slicePool[models.ImpactedClient]
+	bytePool        slicePool[byte]
+	indexPool       = sync.Pool{New: func() any {
+		return new([]map[requirementKey]int)
+	}}
+)
+
+// maxPooledCap bounds the slices kept for reuse, so one huge run does not
+// pin its memory for the runs after it.
+const maxPooledCap = 1 << 16
+
+// slicePool reuses slices' backing arrays.
+type slicePool[T any] struct {
+	pool sync.Pool
+}
+
+// get returns an empty, non-nil slice, with spare capacity if one was pooled.
+func (p *slicePool[T]) get() []T {
+	if s, ok := p.pool.Get().(*[]T); ok {
+		return (*s)[:0]
+	}
+	return make([]T, 0)
+}
+
+// put hands s back for reuse; the caller must not use it afterwards.
+func (p *slicePool[T]) put(s []T) {
+	if cap(s) == 0 || cap(s) > maxPooledCap {
+		return
+	}
+	// Drop references so pooled slices don't keep names and locations alive
+	clear(s)
+	s = s[:0]
+	p.pool.Put(&s)
+}
+
+// getIndex returns an empty aggregation index for a run with n slots.
+func getIndex(n int) []map[requirementKey]int {
+	index := *indexPool.Get().(*[]map[requirementKey]int)
+	// Maps beyond len, left by a run with more slots, are kept and empty
+	index = index[:cap(index)]
+	for len(index) < n {
+		index = append(index, make(map[requirementKey]int))
+	}
+	return index[:n]
+}
+
+// putIndex clears a run's aggregation index and hands it back for reuse.
+func putIndex(index []map[requirementKey]int) {
+	for _, m := range index {
+		if len(m) > maxPooledCap {
+			return
+		}
+	}
+	for _, m := range index {
+		clear(m)
+	}
+	indexPool.Put(&index)
+}
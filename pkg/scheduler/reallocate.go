@@ -0,0 +1,105 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/karthikrao-23/agentscheduler/pkg/errors"
+	"github.com/karthikrao-23/agentscheduler/pkg/metrics"
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+)
+
+// Reallocate allocates the demand of saved, a schedule generated earlier and
+// typically read back with models.Load, again under opts, such as a new
+// capacity or allocator, without the input it was generated from. Each
+// slot's demand is what it asked for before its ceilings and capacity cut
+// it: its requirements, restored from the unmet demand to the agents they
+// requested, with those capacity left without any agents added back. The
+// schedule keeps saved's slots, and their interval, and its input hash;
+// WithInterval, if given, must match the interval. Hourly capacities apply
+// to every slot in their hour, as when saved was generated.
+//
+// Demand is taken as saved, so options that shape it from the input, such as
+// WithUtilization, WithErlangC, or WithMultiDay, have no effect; smoothing
+// saved applied stays in the demand. Clients capacity dropped come back with
+// the fields UnmetDemand records for them only. It returns the same errors as
+// GenerateScheduleContext.
+func Reallocate(ctx context.Context, saved *models.Schedule, opts ...Option) (*models.Schedule, error) {
+	if saved == nil || len(saved.HourlyRequirements) == 0 {
+		return nil, errors.ErrEmptyInput
+	}
+	// Slots map to the hours capacities are given for by their interval
+	interval := saved.SlotAt(0).Duration
+	if set := newConfig(opts...).interval; set != time.Hour && set != interval {
+		return nil, &errors.ConstraintViolationError{
+			Constraint: "interval",
+			Value:      set,
+			Detail:     fmt.Sprintf("must match the saved schedule's slots of %s", interval),
+			Err:        errors.ErrInfeasibleConstraints,
+		}
+	}
+	b, err := NewBuilder(ctx, append(slices.Clone(opts), WithInterval(interval))...)
+	if err != nil {
+		return nil, err
+	}
+	defer b.span.End()
+	if err := b.ctx.Err(); err != nil {
+		return nil, err
+	}
+	slots := make([]models.TimeSlot, len(saved.HourlyRequirements))
+	for h := range slots {
+		slots[h] = saved.SlotAt(h)
+	}
+	inputHash := ""
+	if saved.Metadata != nil {
+		inputHash = saved.Metadata.InputHash
+	}
+	schedule := b.finish(slots, demand(saved), inputHash)
+	metrics.ObserveDuration(b.ctx, metrics.SchedulerDurationSeconds, time.Since(b.start).Seconds(), b.runID)
+	return schedule, nil
+}
+
+// demand returns each slot of schedule's requirements as they were before
+// allocation, in new slices.
+func demand(schedule *models.Schedule) [][]models.CustomerRequirement {
+	hourly := make([][]models.CustomerRequirement, len(schedule.HourlyRequirements))
+	for h, reqs := range schedule.HourlyRequirements {
+		hourly[h] = slices.Clone(reqs)
+	}
+	for _, unmet := range schedule.UnmetDemands {
+		if unmet.Hour < 0 || unmet.Hour >= len(hourly) {
+			continue
+		}
+		reqs := hourly[unmet.Hour]
+		restored := make([]bool, len(reqs))
+		for _, client := range unmet.ImpactedClients {
+			// The requirement the client was allocated from, if capacity left
+			// it any agents
+			i := -1
+			for j, req := range reqs {
+				if !restored[j] && keyOfRequest(req) == keyOfClient(client) && req.AgentsNeeded == client.AllocatedAgents {
+					i = j
+					break
+				}
+			}
+			if i < 0 {
+				reqs = append(reqs, models.CustomerRequirement{
+					Name:          client.Name,
+					Location:      client.Location,
+					Priority:      client.Priority,
+					Skill:         client.Skill,
+					MinAgents:     client.MinAgents,
+					CeilingAgents: client.CeilingAgents,
+				})
+				restored = append(restored, false)
+				i = len(reqs) - 1
+			}
+			reqs[i].AgentsNeeded = client.RequestedAgents
+			restored[i] = true
+		}
+		hourly[unmet.Hour] = reqs
+	}
+	return hourly
+}
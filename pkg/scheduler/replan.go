@@ -0,0 +1,212 @@
+package scheduler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"hash/maphash"
+	"slices"
+
+	"github.com/karthikrao-23/agentscheduler/pkg/errors"
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+)
+
+// Replanner regenerates a schedule as its input changes between runs, such
+// as a forecast revised through the day, redoing only the work the change
+// touches: rows seen in the last run are not expanded again, and with a
+// capacity, slots whose rows are the same, in the same order, keep the last
+// run's allocation. Each schedule is the one GenerateScheduleContext would
+// produce for the same data and options. With WithSmoothing, which ties
+// slots together, every slot is allocated afresh.
+//
+// A Replanner holds its last run's rows and allocations between runs. It is
+// not safe for concurrent use.
+type Replanner struct {
+	opts []Option
+	seed maphash.Seed
+	// rows holds the last run's rows, expanded, by their JSON encoding
+	rows map[string]*expandedRow
+	// slots holds the last run's allocation of every slot with rows
+	slots map[slotKey]slotPlan
+	stats ReplanStats
+}
+
+// ReplanStats counts the work a Replanner's last run did.
+type ReplanStats struct {
+	// Rows is the rows scheduled, and Expanded those not in the run before
+	Rows, Expanded int
+	// Slots is the slots with rows that were allocated capacity, and
+	// Reallocated those whose rows changed since the run before
+	Slots, Reallocated int
+}
+
+// slotKey locates a slot by day number and slot of the day.
+type slotKey struct {
+	day, slot int
+}
+
+// expandedRow is one row's requirements, and the agents WithMaxOccupancy
+// added to them.
+type expandedRow struct {
+	hash            uint64
+	reqs            []placedRequirement
+	occupancyAgents int
+}
+
+type placedRequirement struct {
+	slot slotKey
+	req  models.CustomerRequirement
+}
+
+// slotPlan is a slot's allocation, with a fingerprint of the rows it was
+// allocated from.
+type slotPlan struct {
+	fingerprint uint64
+	slotAllocation
+}
+
+// slotAllocation is a slot's requirements once allocated, the demand
+// allocation left unmet, and with agent pools, the agents drawn from each.
+type slotAllocation struct {
+	reqs       []models.CustomerRequirement
+	unmet      *models.UnmetDemand
+	poolAgents []int
+}
+
+// clone returns a copy of a that shares nothing with it.
+func (a slotAllocation) clone() ([]models.CustomerRequirement, *models.UnmetDemand) {
+	reqs := slices.Clone(a.reqs)
+	if a.unmet == nil {
+		return reqs, nil
+	}
+	unmet := *a.unmet
+	unmet.ImpactedClients = slices.Clone(unmet.ImpactedClients)
+	return reqs, &unmet
+}
+
+// NewReplanner returns a Replanner that schedules under opts. It returns a
+// *errors.ConstraintViolationError for invalid options.
+func NewReplanner(opts ...Option) (*Replanner, error) {
+	if err := newConfig(opts...).validate(); err != nil {
+		return nil, err
+	}
+	return &Replanner{
+		opts:  opts,
+		seed:  maphash.MakeSeed(),
+		rows:  make(map[string]*expandedRow),
+		slots: make(map[slotKey]slotPlan),
+	}, nil
+}
+
+// Stats returns the work the last run did.
+func (r *Replanner) Stats() ReplanStats {
+	return r.stats
+}
+
+// Schedule generates the schedule for data, reusing what it can of the last
+// run. It returns the same errors as GenerateScheduleContext; a failed run
+// leaves the last successful one to reuse.
+func (r *Replanner) Schedule(ctx context.Context, data []models.CallData) (*models.Schedule, error) {
+	if len(data) == 0 {
+		return nil, errors.ErrEmptyInput
+	}
+	b, err := NewBuilder(ctx, r.opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := ReplanStats{Rows: len(data)}
+	rows := make(map[string]*expandedRow, len(r.rows))
+	// Each slot's rows, in order, folded into a fingerprint
+	fingerprints := make(map[slotKey]uint64)
+	expanded := batch{days: make(map[int][][]models.CustomerRequirement), rows: len(data)}
+	encoded := bytes.NewBuffer(bytePool.get())
+	enc := json.NewEncoder(encoded)
+	for i, cd := range data {
+		if err := b.ctx.Err(); err != nil {
+			b.merge(batch{err: err})
+			return b.Build()
+		}
+		if err := check(cd); err != nil {
+			b.merge(batch{err: &errors.RowError{Row: i, Customer: cd.CustomerName, Err: err}})
+			return b.Build()
+		}
+		if i > 0 {
+			encoded.WriteByte(',')
+		}
+		start := encoded.Len()
+		if err := enc.Encode(cd); err != nil {
+			b.merge(batch{err: err})
+			return b.Build()
+		}
+		// Encode ends each row with a newline, which Marshal does not
+		encoded.Truncate(encoded.Len() - 1)
+
+		key := string(encoded.Bytes()[start:])
+		row, ok := rows[key]
+		if !ok {
+			if row, ok = r.rows[key]; !ok {
+				row = b.cfg.expandRow(b.ctx, cd)
+				row.hash = maphash.String(r.seed, key)
+				stats.Expanded++
+			}
+			rows[key] = row
+		}
+		for _, p := range row.reqs {
+			hourly := b.cfg.slotsOn(expanded.days, p.slot.day)
+			hourly[p.slot.slot] = append(hourly[p.slot.slot], p.req)
+			fingerprints[p.slot] = (fingerprints[p.slot] ^ row.hash) * fingerprintPrime
+		}
+		expanded.occupancyAgents += row.occupancyAgents
+	}
+	expanded.encoded = encoded.Bytes()
+	b.merge(expanded)
+
+	if b.cfg.smoothing == nil {
+		b.reuse = make(map[slotKey]slotAllocation)
+		for k, fingerprint := range fingerprints {
+			if last, ok := r.slots[k]; ok && last.fingerprint == fingerprint {
+				b.reuse[k] = last.slotAllocation
+			}
+		}
+	}
+	b.allocations = make(map[slotKey]slotAllocation)
+	schedule, err := b.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	slots := make(map[slotKey]slotPlan, len(fingerprints))
+	for k, fingerprint := range fingerprints {
+		a, ok := b.allocations[k]
+		if !ok {
+			continue
+		}
+		slots[k] = slotPlan{fingerprint: fingerprint, slotAllocation: a}
+		stats.Slots++
+		if _, ok := b.reuse[k]; !ok {
+			stats.Reallocated++
+		}
+	}
+	r.rows, r.slots, r.stats = rows, slots, stats
+	return schedule, nil
+}
+
+// fingerprintPrime is FNV-1a's 64-bit prime, which mixes each row's hash
+// into its slots' fingerprints so that order counts.
+const fingerprintPrime = 1099511628211
+
+// expandRow expands cd on its own, with its requirements listed by slot.
+func (c config) expandRow(ctx context.Context, cd models.CallData) *expandedRow {
+	days := make(map[int][][]models.CustomerRequirement)
+	row := &expandedRow{occupancyAgents: c.expand(ctx, cd, days)}
+	for d, slots := range days {
+		for h, reqs := range slots {
+			for _, req := range reqs {
+				row.reqs = append(row.reqs, placedRequirement{slot: slotKey{d, h}, req: req})
+			}
+			requirementPool.put(reqs)
+		}
+	}
+	return row
+}
@@ -0,0 +1,107 @@
+package scheduler
+
+import (
+	"math"
+
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+)
+
+// WithReservedCapacity holds a share of every slot's capacity for each
+// priority tier, in percent, e.g. {1: 60} for 60% held for priority 1. A
+// tier's demand is met from its reserve before anything else, so no other
+// tier can crowd it out, whether by priority, by minimum guarantees, or by
+// proportional allocation. The rest of capacity, and whatever of a reserve
+// its tier does not need in a slot, is then shared out by the Allocator
+// among the demand that is left. Reserves cannot be combined with
+// WithAgentPools, as they ignore skills.
+func WithReservedCapacity(percentByPriority map[models.Priority]float64) Option {
+	return func(c *config) {
+		c.reserved = percentByPriority
+	}
+}
+
+// reserveKey identifies the requests whose reserved agents are added back
+// after allocation; the fields are those an ImpactedClient carries.
+type reserveKey struct {
+	name     string
+	location string
+	priority models.Priority
+	skill    string
+}
+
+func keyOfRequest(req models.CustomerRequirement) reserveKey {
+	return reserveKey{req.Name, req.Location.Name(), req.Priority, req.Skill}
+}
+
+func keyOfClient(client models.ImpactedClient) reserveKey {
+	return reserveKey{client.Name, client.Location.Name(), client.Priority, client.Skill}
+}
+
+// allocate shares out capacity among requests with the configured Allocator,
+// after meeting each tier's demand from its reserve.
+func (c config) allocate(requests []models.CustomerRequirement, capacity int) ([]models.CustomerRequirement, *models.UnmetDemand) {
+	if len(c.reserved) == 0 || len(requests) == 0 {
+		return c.allocator.Allocate(requests, capacity)
+	}
+
+	// Meet each tier from its reserve in allocation order, and pass on
+	// only the demand that is left
+	sortRequests(requests)
+	reserves := make(map[models.Priority]int, len(c.reserved))
+	for priority, percent := range c.reserved {
+		reserves[priority] = int(math.Floor(float64(capacity) * percent / 100))
+	}
+	granted := make(map[reserveKey]int)
+	minimums := make(map[reserveKey]int)
+	rest := make([]models.CustomerRequirement, len(requests))
+	total := 0
+	for i, req := range requests {
+		g := min(req.AgentsNeeded, reserves[req.Priority])
+		reserves[req.Priority] -= g
+		total += g
+		k := keyOfRequest(req)
+		granted[k] += g
+		minimums[k] = max(minimums[k], req.MinAgents)
+		rest[i] = req
+		rest[i].AgentsNeeded -= g
+		rest[i].MinAgents = max(req.MinAgents-g, 0)
+	}
+	if total == 0 {
+		return c.allocator.Allocate(requests, capacity)
+	}
+
+	allocated, unmet := c.allocator.Allocate(rest, capacity-total)
+	// The reserved agents of requests the Allocator left out entirely are
+	// added back as requests of their own
+	pending := make(map[reserveKey]int, len(granted))
+	for k, g := range granted {
+		pending[k] = g
+	}
+	for i := range allocated {
+		k := keyOfRequest(allocated[i])
+		allocated[i].AgentsNeeded += pending[k]
+		allocated[i].MinAgents = max(allocated[i].MinAgents, minimums[k])
+		delete(pending, k)
+	}
+	for _, req := range requests {
+		k := keyOfRequest(req)
+		if pending[k] > 0 {
+			req.AgentsNeeded = pending[k]
+			allocated = append(allocated, req)
+			delete(pending, k)
+		}
+	}
+
+	if unmet != nil {
+		unmet.TotalDemand += total
+		unmet.AllocatedAgents += total
+		for i := range unmet.ImpactedClients {
+			client := &unmet.ImpactedClients[i]
+			k := keyOfClient(*client)
+			client.RequestedAgents += granted[k]
+			client.AllocatedAgents += granted[k]
+			client.MinAgents = minimums[k]
+		}
+	}
+	return allocated, unmet
+}
@@ -0,0 +1,33 @@
+package scheduler
+
+import (
+	"context"
+
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+)
+
+// WithScenario applies the options a scenario sets. Options given after it
+// override the scenario's.
+func WithScenario(scenario models.Scenario) Option {
+	return func(c *config) {
+		if scenario.Utilization != 0 {
+			c.utilization = scenario.Utilization
+		}
+		if scenario.Capacity != 0 {
+			c.capacity = scenario.Capacity
+		}
+		if len(scenario.CapacityProfile) > 0 {
+			c.capacityProfile = scenario.CapacityProfile
+		}
+	}
+}
+
+// RunScenario generates a schedule for data under scenario and summarizes it.
+// opts are applied after the scenario, e.g. to pick an Allocator.
+func RunScenario(ctx context.Context, data []models.CallData, scenario models.Scenario, opts ...Option) (models.ScenarioResult, error) {
+	schedule, err := GenerateScheduleContext(ctx, data, append([]Option{WithScenario(scenario)}, opts...)...)
+	if err != nil {
+		return models.ScenarioResult{}, err
+	}
+	return models.NewScenarioResult(scenario, schedule), nil
+}
@@ -0,0 +1,514 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/karthikrao-23/agentscheduler/pkg/errors"
+	"github.com/karthikrao-23/agentscheduler/pkg/metrics"
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+	"github.com/karthikrao-23/agentscheduler/pkg/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracer records spans for schedule generation and allocation.
+var tracer = tracing.Tracer("github.com/karthikrao-23/agentscheduler/pkg/scheduler")
+
+// GenerateSchedule calculates the number of agents needed per hour for each customer.
+// Without options it assumes full utilization and unlimited capacity.
+// It returns the same errors as GenerateScheduleContext.
+func GenerateSchedule(data []models.CallData, opts ...Option) (*models.Schedule, error) {
+	return GenerateScheduleContext(context.Background(), data, opts...)
+}
+
+// GenerateScheduleContext is like GenerateSchedule but records tracing spans as
+// children of ctx. Per-customer spans are only emitted when debug tracing is on.
+//
+// It is safe to call concurrently. Each call publishes its metrics atomically
+// once the schedule is complete, so the scheduler gauges always describe the
+// last run to finish.
+//
+// It returns errors.ErrEmptyInput for empty data, a *errors.RowError for
+// the first row without a handle time or a window, or with negative calls,
+// a *errors.ConstraintViolationError for invalid options, and ctx.Err() if
+// ctx is cancelled before the schedule is complete.
+func GenerateScheduleContext(ctx context.Context, data []models.CallData, opts ...Option) (*models.Schedule, error) {
+	if len(data) == 0 {
+		return nil, errors.ErrEmptyInput
+	}
+	b, err := NewBuilder(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	// Hand the rows to WithWorkers' goroutines a batch at a time, a few
+	// batches per worker so that a slow one does not hold the rest up
+	size := len(data)
+	if b.cfg.workers > 1 {
+		size = max((len(data)+4*b.cfg.workers-1)/(4*b.cfg.workers), minBatchRows)
+	}
+	for i := 0; i < len(data); i += size {
+		if err := b.Add(data[i:min(i+size, len(data))]); err != nil {
+			b.Build()
+			return nil, err
+		}
+	}
+	return b.Build()
+}
+
+// Scheduler generates schedules under one set of options, checked once, for
+// services that embed the scheduler and run it on input after input. It is
+// safe for concurrent use.
+type Scheduler struct {
+	opts []Option
+}
+
+// New returns a Scheduler for opts, such as WithUtilization, WithCapacity,
+// WithAllocator, and WithInterval. It returns a
+// *errors.ConstraintViolationError for invalid options.
+func New(opts ...Option) (*Scheduler, error) {
+	if err := newConfig(opts...).validate(); err != nil {
+		return nil, err
+	}
+	return &Scheduler{opts: slices.Clone(opts)}, nil
+}
+
+// Schedule generates the schedule for data, as GenerateScheduleContext does
+// under the Scheduler's options.
+func (s *Scheduler) Schedule(ctx context.Context, data []models.CallData) (*models.Schedule, error) {
+	return GenerateScheduleContext(ctx, data, s.opts...)
+}
+
+// NewBuilder starts a schedule under the Scheduler's options, for input
+// added in batches; see Builder.
+func (s *Scheduler) NewBuilder(ctx context.Context) (*Builder, error) {
+	return NewBuilder(ctx, s.opts...)
+}
+
+// check returns why cd cannot be scheduled, if it cannot: a row without a
+// handle time or a window, or with negative calls, would otherwise add
+// nothing to the schedule, or nonsense, without anyone noticing, and one
+// with a service level of 100 percent or more, or NaN, no agents can meet.
+// Rows are not
+// held to all of models.CallData.Validate, so that those built in code
+// without a priority or location still schedule as before.
+func check(cd models.CallData) error {
+	if cd.AverageCallDurationSeconds <= 0 {
+		return fmt.Errorf("%w: must be positive, got %d", errors.ErrInvalidDuration, cd.AverageCallDurationSeconds)
+	}
+	if cd.WrapUpSeconds < 0 {
+		return fmt.Errorf("%w: wrap-up must not be negative, got %d", errors.ErrInvalidDuration, cd.WrapUpSeconds)
+	}
+	if cd.NumberOfCalls < 0 {
+		return fmt.Errorf("%w: must not be negative, got %d", errors.ErrInvalidNumberOfCalls, cd.NumberOfCalls)
+	}
+	if math.IsNaN(cd.SLATargetPercent) || cd.SLATargetPercent < 0 || cd.SLATargetPercent >= 100 {
+		return fmt.Errorf("%w: target must be at least 0 and less than 100 percent, got %g", errors.ErrInvalidSLA, cd.SLATargetPercent)
+	}
+	if cd.SLAThresholdSeconds < 0 {
+		return fmt.Errorf("%w: threshold must not be negative, got %d", errors.ErrInvalidSLA, cd.SLAThresholdSeconds)
+	}
+	if window := cd.Window(); cd.StartTime.IsZero() || cd.EndTime.IsZero() || window <= 0 || window > models.MaxWindow {
+		return fmt.Errorf("%w: length %s must be positive and at most %s", errors.ErrInvalidWindow, window, models.MaxWindow)
+	}
+	return nil
+}
+
+// minBatchRows is the fewest rows GenerateScheduleContext hands a worker at
+// once, below which the batch costs more to merge than to expand.
+const minBatchRows = 1024
+
+// expand appends cd's requirement for every slot its window touches to
+// days, indexed by day number and slot of the day. It returns the agents
+// WithMaxOccupancy added to them.
+func (c config) expand(ctx context.Context, cd models.CallData, days map[int][][]models.CustomerRequirement) int {
+	if tracing.Debug() {
+		_, customerSpan := tracer.Start(ctx, "scheduler.expandCustomer", trace.WithAttributes(
+			attribute.String("customer.name", cd.CustomerName),
+			attribute.Int("customer.priority", int(cd.Priority)),
+			attribute.Int("customer.calls", cd.NumberOfCalls),
+		))
+		defer customerSpan.End()
+	}
+
+	if c.weekly == nil {
+		return c.expandWindow(cd, 1, days)
+	}
+	// Repeat the window on every day of the week it starts in, Monday
+	// first, each with that weekday's volume
+	monday := -((int(cd.StartTime.Weekday()) + 6) % 7)
+	added := 0
+	for offset := monday; offset < monday+7; offset++ {
+		day := cd
+		day.StartTime = addDays(cd.StartTime, offset)
+		day.EndTime = addDays(cd.EndTime, offset)
+		added += c.expandWindow(day, c.weekly.Multiplier(cd.CustomerName, day.StartTime.Weekday()), days)
+	}
+	return added
+}
+
+// addDays moves t by n calendar days, keeping its wall-clock time across DST
+// changes.
+func addDays(t time.Time, n int) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day()+n, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+}
+
+// expandWindow is expand for a single call window, with its call volume
+// scaled by scale.
+func (c config) expandWindow(cd models.CallData, scale float64, days map[int][][]models.CustomerRequirement) int {
+	start := cd.StartTime
+	end := cd.EndTime
+
+	// Handle overnight shifts (e.g., 9PM to 5AM)
+	if end.Before(start) {
+		end = end.Add(24 * time.Hour)
+	}
+
+	// Find the elapsed duration in hours and not use wall clock to
+	// account for DST.
+	durationHours := end.Sub(start).Hours()
+	if durationHours <= 0 {
+		return 0
+	}
+
+	// Staff for the busiest part of each slot
+	callsPerHour := float64(cd.NumberOfCalls) * scale / durationHours * c.peakFor(cd.CustomerName)
+
+	// Erlang C and Poisson staffing depend only on the arrival rate, which
+	// is the same in every hour of the window
+	modelAgents := c.modelAgents(cd, callsPerHour)
+
+	// Slots are UTC's under WithUTC, so the window's must line up with them
+	if c.utc {
+		start, end = start.UTC(), end.UTC()
+	}
+
+	// Determine the slot boundaries to schedule
+	// Round start down to slot boundary, round end up to slot boundary
+	minutes := int(c.interval / time.Minute)
+	startBoundary := time.Date(start.Year(), start.Month(), start.Day(),
+		start.Hour(), start.Minute()-start.Minute()%minutes, 0, 0, start.Location())
+	endBoundary := time.Date(end.Year(), end.Month(), end.Day(),
+		end.Hour(), end.Minute()-end.Minute()%minutes, 0, 0, end.Location())
+
+	// If end time has minutes/seconds past the boundary, we need to include
+	// that slot too
+	if end.After(endBoundary) {
+		endBoundary = endBoundary.Add(c.interval)
+	}
+
+	// Iterate slot by slot at slot boundaries
+	added := 0
+	for t := startBoundary; t.Before(endBoundary); t = t.Add(c.interval) {
+		// Calculate the fraction of this slot that's actually being used
+		slotStart := t
+		slotEnd := t.Add(c.interval)
+
+		// Clamp to actual work window
+		actualStart := slotStart
+		if start.After(slotStart) {
+			actualStart = start
+		}
+		actualEnd := slotEnd
+		if end.Before(slotEnd) {
+			actualEnd = end
+		}
+
+		// Calculate the hours of this slot being used
+		hoursUsedInThisSlot := actualEnd.Sub(actualStart).Hours()
+		if hoursUsedInThisSlot <= 0 {
+			continue
+		}
+
+		// Scale the slot by its season and any holidays or blackouts
+		// covering it
+		local := cd.Location.In(t)
+		if c.utc {
+			local = t
+		}
+		rate, staffed := callsPerHour, modelAgents
+		if m := c.scaleFor(cd, t); m != 1 {
+			if m == 0 {
+				continue
+			}
+			rate *= m
+			staffed = c.modelAgents(cd, rate)
+		}
+
+		// Calls in this specific slot based on fraction
+		callsThisSlot := rate * hoursUsedInThisSlot
+
+		// Agents = ceil(calls_this_slot * handle_time / slot_seconds)
+		workload := callsThisSlot * float64(cd.HandleSeconds()) / c.interval.Seconds()
+		agentsNeeded := int(math.Ceil(workload))
+		if c.erlangC != nil || c.poisson > 0 {
+			// Erlang C and Poisson staff the whole slot to the arrival rate
+			agentsNeeded = staffed
+			workload = rate * float64(cd.HandleSeconds()) / 3600
+		}
+
+		// The same, unrounded, for WithFTE
+		fte := workload
+		if c.erlangC != nil || c.poisson > 0 {
+			fte = float64(agentsNeeded)
+		}
+
+		// Top up agents who would be busier than the occupancy cap allows
+		capped := max(agentsNeeded, occupancyAgents(workload, c.maxOccupancy))
+		if c.maxOccupancy > 0 {
+			fte = max(fte, workload/c.maxOccupancy)
+		}
+
+		// Adjust agents needed based on utilization
+		utilizationMultiplier := 1 / c.utilization
+		uncapped := int(math.Ceil(float64(agentsNeeded) * utilizationMultiplier))
+		agentsNeeded = int(math.Ceil(float64(capped) * utilizationMultiplier))
+		added += agentsNeeded - uncapped
+		agentsNeeded = max(agentsNeeded, cd.FloorAgents)
+		fte = max(fte*utilizationMultiplier, float64(cd.FloorAgents))
+		if !c.fte {
+			fte = 0
+		}
+
+		h := (local.Hour()*60 + local.Minute()) / minutes
+		hourly := c.slotsOn(days, c.dayOf(local))
+		hourly[h] = append(
+			hourly[h], models.CustomerRequirement{
+				Name:                cd.CustomerName,
+				AgentsNeeded:        agentsNeeded,
+				Location:            cd.Location,
+				Priority:            cd.Priority,
+				SLATargetPercent:    cd.SLATargetPercent,
+				SLAThresholdSeconds: cd.SLAThresholdSeconds,
+				Skill:               cd.Skill,
+				Concurrency:         cd.Concurrency,
+				HourlyCost:          cd.HourlyCost,
+				MinAgents:           cd.MinAgents,
+				CeilingAgents:       cd.CeilingAgents,
+				FTE:                 fte,
+			},
+		)
+	}
+	return added
+}
+
+// modelAgents returns the agents Erlang C or Poisson staffing needs for cd's
+// calls arriving at callsPerHour, or 0 under the workload formula.
+func (c config) modelAgents(cd models.CallData, callsPerHour float64) int {
+	switch {
+	case c.erlangC != nil:
+		sla := *c.erlangC
+		if cd.SLATargetPercent > 0 {
+			sla = serviceLevel{targetPercent: cd.SLATargetPercent, thresholdSeconds: cd.SLAThresholdSeconds}
+		}
+		return erlangCAgents(callsPerHour, cd.HandleSeconds(), sla)
+	case c.poisson > 0:
+		return poissonAgents(callsPerHour, cd.HandleSeconds(), c.poisson)
+	}
+	return 0
+}
+
+// occupancyAgents returns the fewest agents that work workload Erlangs
+// without being busier than maxOccupancy, or 0 with no cap.
+func occupancyAgents(workload, maxOccupancy float64) int {
+	if maxOccupancy <= 0 {
+		return 0
+	}
+	// Allow for float error, so 9 Erlangs at 90% is 10 agents rather than 11
+	return int(math.Ceil(workload/maxOccupancy - 1e-9))
+}
+
+// PublishMetrics publishes schedule's metrics as the latest run's, under its
+// own run ID, as if it had just been generated under opts. Services that
+// serve a stored schedule again, e.g. from a cache, call it so that the
+// metrics describe the schedule they served. The agents drawn from each
+// pool are not kept in a schedule, so they are not published again.
+func PublishMetrics(schedule *models.Schedule, opts ...Option) {
+	cfg := newConfig(opts...)
+	runID := ""
+	if schedule.Metadata != nil {
+		runID = schedule.Metadata.RunID
+	}
+	metrics.PublishRun(runID, func() {
+		computeScheduleMetrics(schedule, cfg)
+	})
+}
+
+// computeScheduleMetrics computes aggregate metrics from the final schedule.
+// This should be called after schedule generation is complete, from within
+// metrics.PublishRun.
+// cfg carries the capacity constraints the schedule was generated with.
+func computeScheduleMetrics(schedule *models.Schedule, cfg config) {
+	var totalAllocated, totalUnmet float64
+	var highPriorityAllocated, highPriorityUnmet float64
+	customers := make(map[string]metrics.CustomerAgents)
+	locations := make(map[string]metrics.CustomerAgents)
+	for e := range schedule.Entries() {
+		customers[e.Customer] = addEntry(customers[e.Customer], e)
+		locations[e.Location.String()] = addEntry(locations[e.Location.String()], e)
+	}
+
+	// Index unmet demand by slot for the per-hour curve
+	unmetByHour := make(map[int]int, len(schedule.UnmetDemands))
+	for _, unmet := range schedule.UnmetDemands {
+		unmetByHour[unmet.Hour] += unmet.UnmetAgents
+	}
+
+	// Worst-hour fulfillment only considers slots with demand
+	worstHourPercent := 100.0
+
+	// Capacity is only consumed in slots that have a limit
+	var capacityUsed float64
+
+	// The per-hour curve shows each hour's busiest slot when slots are
+	// shorter than an hour
+	var peakAllocated, peakUnmet, peakDemanded [24]int
+
+	// Sum up all slot requirements (this is what was allocated)
+	for h, reqs := range schedule.HourlyRequirements {
+		hourAllocated := 0
+		for _, req := range reqs {
+			hourAllocated += req.AgentsNeeded
+			totalAllocated += float64(req.AgentsNeeded)
+			if req.Priority == models.PriorityCritical {
+				highPriorityAllocated += float64(req.AgentsNeeded)
+			}
+		}
+
+		if cfg.capacityForSlot(h) > 0 {
+			capacityUsed += float64(hourAllocated)
+		}
+
+		hourDemanded := hourAllocated + unmetByHour[h]
+		hour := cfg.slotHour(h)
+		peakAllocated[hour] = max(peakAllocated[hour], hourAllocated)
+		peakUnmet[hour] = max(peakUnmet[hour], unmetByHour[h])
+		peakDemanded[hour] = max(peakDemanded[hour], hourDemanded)
+
+		if hourDemanded > 0 {
+			worstHourPercent = math.Min(worstHourPercent, fulfillmentPercent(float64(hourAllocated), float64(hourDemanded)))
+		}
+	}
+
+	// Publish every hour, including empty ones, so the curve has no gaps
+	for h := range 24 {
+		hourLabel := strconv.Itoa(h)
+		metrics.HourlyAgentsAllocated.WithLabelValues(hourLabel).Set(float64(peakAllocated[h]))
+		metrics.HourlyAgentsUnmet.WithLabelValues(hourLabel).Set(float64(peakUnmet[h]))
+		metrics.HourlyAgentsDemanded.WithLabelValues(hourLabel).Set(float64(peakDemanded[h]))
+	}
+
+	// Process unmet demands
+	metrics.HoursWithUnmetDemand.Set(float64(len(schedule.HoursWithShortfall())))
+
+	unmetBySkill := make(map[string]int)
+	ceilingUnmet := 0
+	for _, unmet := range schedule.UnmetDemands {
+		totalUnmet += float64(unmet.UnmetAgents)
+
+		// Track unmet demand by priority and skill
+		for _, client := range unmet.ImpactedClients {
+			if client.Skill != "" {
+				unmetBySkill[client.Skill] += client.UnmetAgents
+			}
+			if client.Capped() {
+				ceilingUnmet += client.RequestedAgents - client.CeilingAgents
+			}
+			priorityLabel := fmt.Sprintf("%d", client.Priority)
+			metrics.UnmetDemandByPriority.WithLabelValues(priorityLabel).Add(float64(client.UnmetAgents))
+			if client.Priority == models.PriorityCritical {
+				highPriorityUnmet += float64(client.UnmetAgents)
+			}
+		}
+	}
+
+	// Every demanded agent was either allocated or left unmet
+	totalDemanded := totalAllocated + totalUnmet
+
+	metrics.AgentsDemandedTotal.Set(totalDemanded)
+	metrics.AgentsAllocatedTotal.Set(totalAllocated)
+	metrics.AgentsUnmetTotal.Set(totalUnmet)
+	if cfg.labeledMetrics {
+		metrics.RecordPerCustomer(customers)
+		for name, l := range locations {
+			metrics.LocationAgentsDemanded.WithLabelValues(name).Set(l.Demanded)
+			metrics.LocationAgentsAllocated.WithLabelValues(name).Set(l.Allocated)
+			metrics.LocationAgentsUnmet.WithLabelValues(name).Set(l.Unmet)
+		}
+		for skill, unmet := range unmetBySkill {
+			metrics.SkillAgentsUnmet.WithLabelValues(skill).Set(float64(unmet))
+		}
+	}
+	metrics.CeilingAgentsUnmet.Set(float64(ceilingUnmet))
+
+	// SLO ratios, computed here so they are consistent with the totals above
+	metrics.DemandFulfillmentPercent.Set(fulfillmentPercent(totalAllocated, totalDemanded))
+	metrics.HighPriorityFulfillmentPercent.Set(fulfillmentPercent(highPriorityAllocated, highPriorityAllocated+highPriorityUnmet))
+	metrics.WorstHourFulfillmentPercent.Set(worstHourPercent)
+
+	// PeakHour stays at -1, as reset, without demand
+	peakSlot, peakSlotDemanded := schedule.PeakHour()
+	metrics.PeakHourAgentsDemanded.Set(float64(peakSlotDemanded))
+	if peakSlot >= 0 {
+		metrics.PeakHour.Set(float64(cfg.slotHour(peakSlot)))
+		metrics.PeakHourAgentsUnmet.Set(float64(unmetByHour[peakSlot]))
+	}
+
+	if cfg.constrained() {
+		metrics.SchedulerCapacityUsed.Set(capacityUsed)
+	}
+}
+
+// publishPoolUsage publishes the agents drawn from each of cfg's pools, by
+// slot as poolAgents holds them, with their utilization of the headcount
+// open and their cost, unless labeled metrics are off.
+func publishPoolUsage(schedule *models.Schedule, cfg config, poolAgents [][]int) {
+	if poolAgents == nil || !cfg.labeledMetrics {
+		return
+	}
+	allocated := make(map[string]float64)
+	open := make(map[string]float64)
+	cost := make(map[string]float64)
+	for h, used := range poolAgents {
+		slot := schedule.SlotAt(h)
+		for i, pool := range cfg.openPools(slot) {
+			open[pool.Name] += float64(pool.Headcount)
+			if used != nil {
+				allocated[pool.Name] += float64(used[i])
+				cost[pool.Name] += float64(used[i]) * slot.Duration.Hours() * pool.HourlyCost
+			}
+		}
+	}
+	for name, headcount := range open {
+		utilization := 0.0
+		if headcount > 0 {
+			utilization = allocated[name] / headcount * 100
+		}
+		metrics.PoolAgentsAllocated.WithLabelValues(name).Set(allocated[name])
+		metrics.PoolUtilizationPercent.WithLabelValues(name).Set(utilization)
+		metrics.PoolCost.WithLabelValues(name).Set(cost[name])
+	}
+}
+
+// addEntry adds a schedule entry's agents to a per-customer or per-location
+// total.
+func addEntry(total metrics.CustomerAgents, e models.Entry) metrics.CustomerAgents {
+	total.Allocated += float64(e.Agents)
+	total.Unmet += float64(e.Unmet)
+	total.Demanded += float64(e.Agents + e.Unmet)
+	return total
+}
+
+// fulfillmentPercent returns allocated as a percentage of demanded.
+// No demand counts as fully met.
+func fulfillmentPercent(allocated, demanded float64) float64 {
+	if demanded <= 0 {
+		return 100
+	}
+	return allocated / demanded * 100
+}
@@ -0,0 +1,2048 @@
+package scheduler_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/karthikrao-23/agentscheduler/internal/benchdata"
+	customerrors "github.com/karthikrao-23/agentscheduler/pkg/errors"
+	"github.com/karthikrao-23/agentscheduler/pkg/metrics"
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+	"github.com/karthikrao-23/agentscheduler/pkg/scheduler"
+	"github.com/karthikrao-23/agentscheduler/pkg/tracing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestGenerateSchedule(t *testing.T) {
+	// Helper to create time in a specific location
+	makeTime := func(hour int, locName string) time.Time {
+		loc, err := time.LoadLocation(locName)
+		if err != nil {
+			panic(err)
+		}
+		now := time.Now().In(loc)
+		return time.Date(now.Year(), now.Month(), now.Day(), hour, 0, 0, 0, loc)
+	}
+
+	// Helper to create time with minutes in a specific location
+	makeTimeWithMinutes := func(hour, minute int, locName string) time.Time {
+		loc, err := time.LoadLocation(locName)
+		if err != nil {
+			panic(err)
+		}
+		now := time.Now().In(loc)
+		return time.Date(now.Year(), now.Month(), now.Day(), hour, minute, 0, 0, loc)
+	}
+
+	// Helper to load a location
+	mustLoadLocation := func(locName string) models.Location {
+		loc, err := models.LoadLocation(locName)
+		if err != nil {
+			panic(err)
+		}
+		return loc
+	}
+
+	tests := map[string]struct {
+		input    []models.CallData
+		expected map[int]int // map[hourLocal]totalAgents (in customer's local time)
+	}{
+		"Simple_SameDay_UTC": {
+			input: []models.CallData{
+				{
+					CustomerName:               "Cust1",
+					AverageCallDurationSeconds: 3600,                // 1 hour
+					StartTime:                  makeTime(10, "UTC"), // 10:00 UTC
+					EndTime:                    makeTime(12, "UTC"), // 12:00 UTC
+					Location:                   models.NewLocation(time.UTC),
+					NumberOfCalls:              10,
+					Priority:                   1,
+				},
+			},
+			// Duration = 2 hours. Calls/hr = 5.
+			// Agents = ceil(5 * 3600 / 3600) = 5.
+			// Hours (local UTC): 10, 11.
+			expected: map[int]int{
+				10: 5,
+				11: 5,
+			},
+		},
+		"WrapUp": {
+			input: []models.CallData{
+				{
+					CustomerName:               "Cust1",
+					AverageCallDurationSeconds: 1800,
+					StartTime:                  makeTime(10, "UTC"),
+					EndTime:                    makeTime(12, "UTC"),
+					Location:                   models.NewLocation(time.UTC),
+					NumberOfCalls:              10,
+					Priority:                   1,
+					WrapUpSeconds:              1800,
+				},
+			},
+			// Each call takes an agent an hour with its wrap-up, so
+			// agents = ceil(5 * 3600 / 3600) = 5, not the 3 the calls alone need
+			expected: map[int]int{
+				10: 5,
+				11: 5,
+			},
+		},
+		"Overnight_PST": {
+			input: []models.CallData{
+				{
+					CustomerName:               "Cust2",
+					AverageCallDurationSeconds: 3600,
+					StartTime:                  makeTime(22, "America/Los_Angeles"), // 10 PM PST
+					EndTime:                    makeTime(2, "America/Los_Angeles"),  // 2 AM PST (next day)
+					Location:                   mustLoadLocation("America/Los_Angeles"),
+					NumberOfCalls:              20,
+					Priority:                   1,
+				},
+			},
+			// Duration = 4 hours. Calls/hr = 5. Agents = 5.
+			// Hours (local PST): 22, 23, 0, 1.
+			expected: map[int]int{
+				22: 5,
+				23: 5,
+				0:  5,
+				1:  5,
+			},
+		},
+		"Mixed_Timezones": {
+			input: []models.CallData{
+				{
+					CustomerName:               "CustPST",
+					AverageCallDurationSeconds: 3600,
+					StartTime:                  makeTime(9, "America/Los_Angeles"),  // 9 AM PST
+					EndTime:                    makeTime(11, "America/Los_Angeles"), // 11 AM PST
+					Location:                   mustLoadLocation("America/Los_Angeles"),
+					NumberOfCalls:              10,
+				},
+				{
+					CustomerName:               "CustEST",
+					AverageCallDurationSeconds: 3600,
+					StartTime:                  makeTime(12, "America/New_York"), // 12 PM EST
+					EndTime:                    makeTime(14, "America/New_York"), // 2 PM EST
+					Location:                   mustLoadLocation("America/New_York"),
+					NumberOfCalls:              10,
+				},
+			},
+			// CustPST: local hours 9, 10 (5 agents each)
+			// CustEST: local hours 12, 13 (5 agents each)
+			// These are different local hours, so no overlap
+			expected: map[int]int{
+				9:  5,
+				10: 5,
+				12: 5,
+				13: 5,
+			},
+		},
+		"NonHourBoundary_PartialHours": {
+			input: []models.CallData{
+				{
+					CustomerName:               "PartialHourCustomer",
+					AverageCallDurationSeconds: 1800,                                            // 30 minutes per call
+					StartTime:                  makeTimeWithMinutes(9, 30, "America/New_York"),  // 9:30 AM EST
+					EndTime:                    makeTimeWithMinutes(15, 45, "America/New_York"), // 3:45 PM EST
+					Location:                   mustLoadLocation("America/New_York"),
+					NumberOfCalls:              100,
+					Priority:                   1,
+				},
+			},
+			// This means:
+			// - Hour 9: covers 9:30-10:00 (partial hour - 30 min)
+			// - Hours 10-14: full hours within the time window
+			// - Hour 15: covers 15:00-15:45 (partial hour - 45 min)
+			expected: map[int]int{
+				9:  4,
+				10: 8,
+				11: 8,
+				12: 8,
+				13: 8,
+				14: 8,
+				15: 6,
+			},
+		},
+		"DST_SpringForward": {
+			input: []models.CallData{
+				{
+					CustomerName:               "SpringForwardTest",
+					AverageCallDurationSeconds: 3600,
+					// March 10, 2024 - DST spring forward: 1:00 AM to 4:00 AM
+					// Actual elapsed time: 2 hours (hour 2:00-2:59 doesn't exist)
+					StartTime:     time.Date(2024, 3, 10, 1, 0, 0, 0, mustLoadLocation("America/New_York").Time()),
+					EndTime:       time.Date(2024, 3, 10, 4, 0, 0, 0, mustLoadLocation("America/New_York").Time()),
+					Location:      mustLoadLocation("America/New_York"),
+					NumberOfCalls: 6, // 6 calls / 2 hours = 3 calls/hour
+					Priority:      1,
+				},
+			},
+			// Calls per hour = 3, Agents per hour = 3
+			// Hours scheduled: 1, 3 (hour 2 doesn't exist due to DST)
+			expected: map[int]int{
+				1: 3, // 1:00-3:00 (skips to 3:00 due to DST)
+				3: 3, // 3:00-4:00
+			},
+		},
+		"DST_FallBack": {
+			input: []models.CallData{
+				{
+					CustomerName:               "FallBackTest",
+					AverageCallDurationSeconds: 3600,
+					// November 3, 2024 - DST fall back: 12:00 AM to 3:00 AM
+					// Actual elapsed time: 4 hours (hour 1:00-1:59 repeats)
+					StartTime:     time.Date(2024, 11, 3, 0, 0, 0, 0, mustLoadLocation("America/New_York").Time()),
+					EndTime:       time.Date(2024, 11, 3, 3, 0, 0, 0, mustLoadLocation("America/New_York").Time()),
+					Location:      mustLoadLocation("America/New_York"),
+					NumberOfCalls: 12, // 12 calls / 4 hours = 3 calls/hour
+					Priority:      1,
+				},
+			},
+			// Calls per hour = 3, Agents per hour = 3
+			// Hours scheduled: 0, 1, 2
+			// Hour 1 occurs twice during fall back, so gets 2 hours worth of agents
+			expected: map[int]int{
+				0: 3, // 0:00-1:00
+				1: 6, // 1:00-2:00 (happens twice = 2 hours × 3 agents/hour)
+				2: 3, // 2:00-3:00
+			},
+		},
+		"DST_PartialHoursAcrossSpringForward": {
+			input: []models.CallData{
+				{
+					CustomerName:               "PartialDSTTest",
+					AverageCallDurationSeconds: 1800, // 30 min per call
+					// March 10, 2024: 1:30 AM to 3:30 AM (spans DST)
+					// Actual elapsed time: 1 hour (not 2!)
+					StartTime:     time.Date(2024, 3, 10, 1, 30, 0, 0, mustLoadLocation("America/New_York").Time()),
+					EndTime:       time.Date(2024, 3, 10, 3, 30, 0, 0, mustLoadLocation("America/New_York").Time()),
+					Location:      mustLoadLocation("America/New_York"),
+					NumberOfCalls: 10, // 10 calls / 1 hour = 10 calls/hour
+					Priority:      1,
+				},
+			},
+			// Hour 1: 1:30-3:00 (jumps to 3:00, actual 0.5 hours) = 5 calls = 3 agents
+			// Hour 3: 3:00-3:30 (0.5 hours) = 5 calls = 3 agents
+			expected: map[int]int{
+				1: 3, // Partial hour before DST jump
+				2: 0, // Hour 2 doesn't exist due to DST
+				3: 3, // Partial hour after DST jump
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			sched, err := scheduler.GenerateSchedule(tt.input)
+			require.NoError(t, err)
+
+			for h, reqs := range sched.HourlyRequirements {
+				total := 0
+				for _, r := range reqs {
+					total += r.AgentsNeeded
+				}
+
+				if expectedTotal, ok := tt.expected[h]; ok {
+					assert.Equal(t, expectedTotal, total, fmt.Sprintf("Hour %d agents mismatch", h))
+				} else {
+					assert.Equal(t, 0, total, fmt.Sprintf("Hour %d should be empty", h))
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateSchedule_PriorityAndCapacity(t *testing.T) {
+	// Helper to create time in UTC
+	makeTime := func(hour int) time.Time {
+		now := time.Now().UTC()
+		return time.Date(now.Year(), now.Month(), now.Day(), hour, 0, 0, 0, time.UTC)
+	}
+
+	input := []models.CallData{
+		{
+			CustomerName:               "HighPriority",
+			AverageCallDurationSeconds: 3600, // 1 hour
+			StartTime:                  makeTime(10),
+			EndTime:                    makeTime(11),
+			Location:                   models.NewLocation(time.UTC),
+			NumberOfCalls:              10, // Needs 10 agents
+			Priority:                   1,  // High priority
+		},
+		{
+			CustomerName:               "LowPriority",
+			AverageCallDurationSeconds: 3600, // 1 hour
+			StartTime:                  makeTime(10),
+			EndTime:                    makeTime(11),
+			Location:                   models.NewLocation(time.UTC),
+			NumberOfCalls:              10, // Needs 10 agents
+			Priority:                   2,  // Low priority
+		},
+	}
+
+	// Capacity 15. Total demand 20.
+	// HighPriority should get 10.
+	// LowPriority should get 5.
+	sched, err := scheduler.GenerateSchedule(input, scheduler.WithCapacity(15))
+	require.NoError(t, err)
+
+	// Check Hour 10
+	reqs := sched.HourlyRequirements[10]
+	assert.NotEmpty(t, reqs, "Hour 10 should have requirements")
+
+	// Verify allocations
+	var highPriorityAllocated, lowPriorityAllocated int
+	for _, r := range reqs {
+		switch r.Name {
+		case "HighPriority":
+			highPriorityAllocated = r.AgentsNeeded
+		case "LowPriority":
+			lowPriorityAllocated = r.AgentsNeeded
+		default:
+			panic("Unexpected customer name")
+		}
+	}
+
+	assert.Equal(t, 10, highPriorityAllocated, "High priority customer should get full allocation")
+	assert.Equal(t, 5, lowPriorityAllocated, "Low priority customer should get remaining capacity")
+
+	// Verify Unmet Demands
+	assert.NotEmpty(t, sched.UnmetDemands, "Should have unmet demands")
+
+	var foundUnmet bool
+	for _, unmet := range sched.UnmetDemands {
+		if unmet.Hour == 10 {
+			foundUnmet = true
+			assert.Equal(t, 20, unmet.TotalDemand, "Total demand mismatch")
+			assert.Equal(t, 15, unmet.AllocatedAgents, "Allocated agents mismatch")
+			assert.Equal(t, 5, unmet.UnmetAgents, "Unmet agents mismatch")
+
+			// Check impacted clients
+			assert.NotEmpty(t, unmet.ImpactedClients, "Should have impacted clients")
+			for _, client := range unmet.ImpactedClients {
+				if client.Name == "LowPriority" {
+					assert.Equal(t, 10, client.RequestedAgents, "LowPriority requested mismatch")
+					assert.Equal(t, 5, client.AllocatedAgents, "LowPriority allocated mismatch")
+					assert.Equal(t, 5, client.UnmetAgents, "LowPriority unmet mismatch")
+				}
+			}
+		}
+	}
+	assert.True(t, foundUnmet, "Should find unmet demand for hour 10")
+}
+
+func TestGenerateSchedule_CapacityProfile(t *testing.T) {
+	makeTime := func(hour int) time.Time {
+		now := time.Now().UTC()
+		return time.Date(now.Year(), now.Month(), now.Day(), hour, 0, 0, 0, time.UTC)
+	}
+
+	input := []models.CallData{
+		{
+			CustomerName:               "ProfileTest",
+			AverageCallDurationSeconds: 3600,
+			StartTime:                  makeTime(9),
+			EndTime:                    makeTime(12),
+			Location:                   models.NewLocation(time.UTC),
+			NumberOfCalls:              30, // 10 agents per hour
+			Priority:                   1,
+		},
+	}
+
+	// Hour 9 is unlimited, hour 10 is capped at 4, hour 11 falls back to
+	// the flat capacity of 6 because the profile is shorter.
+	profile := make([]int, 11)
+	profile[10] = 4
+	sched, err := scheduler.GenerateSchedule(input,
+		scheduler.WithCapacity(6),
+		scheduler.WithCapacityProfile(profile),
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, 10, sched.HourlyRequirements[9][0].AgentsNeeded)
+	assert.Equal(t, 4, sched.HourlyRequirements[10][0].AgentsNeeded)
+	assert.Equal(t, 6, sched.HourlyRequirements[11][0].AgentsNeeded)
+	if assert.Len(t, sched.UnmetDemands, 2) {
+		assert.Equal(t, 10, sched.UnmetDemands[0].Hour)
+		assert.Equal(t, 11, sched.UnmetDemands[1].Hour)
+	}
+	assert.Equal(t, 10.0, testutil.ToFloat64(metrics.SchedulerCapacityUsed))
+}
+
+func TestGenerateSchedule_CarriesOptionalAttributes(t *testing.T) {
+	now := time.Now().UTC()
+	input := []models.CallData{
+		{
+			CustomerName:               "Chat",
+			AverageCallDurationSeconds: 3600,
+			StartTime:                  time.Date(now.Year(), now.Month(), now.Day(), 10, 0, 0, 0, time.UTC),
+			EndTime:                    time.Date(now.Year(), now.Month(), now.Day(), 11, 0, 0, 0, time.UTC),
+			Location:                   models.NewLocation(time.UTC),
+			NumberOfCalls:              10,
+			Priority:                   1,
+			SLATargetPercent:           80,
+			SLAThresholdSeconds:        20,
+			Skill:                      "chat",
+			Concurrency:                3,
+			HourlyCost:                 42.5,
+		},
+	}
+
+	// Capacity forces a partial allocation, which must keep the attributes too
+	sched, err := scheduler.GenerateSchedule(input, scheduler.WithCapacity(4))
+	require.NoError(t, err)
+	require.Len(t, sched.HourlyRequirements[10], 1)
+	req := sched.HourlyRequirements[10][0]
+	assert.Equal(t, 4, req.AgentsNeeded)
+	assert.Equal(t, 80.0, req.SLATargetPercent)
+	assert.Equal(t, 20, req.SLAThresholdSeconds)
+	assert.Equal(t, "chat", req.Skill)
+	assert.Equal(t, 3, req.Concurrency)
+	assert.Equal(t, 42.5, req.HourlyCost)
+}
+
+func TestGenerateSchedule_Metadata(t *testing.T) {
+	now := time.Now().UTC()
+	input := []models.CallData{
+		{
+			CustomerName:               "Acme",
+			AverageCallDurationSeconds: 3600,
+			StartTime:                  time.Date(now.Year(), now.Month(), now.Day(), 10, 0, 0, 0, time.UTC),
+			EndTime:                    time.Date(now.Year(), now.Month(), now.Day(), 11, 0, 0, 0, time.UTC),
+			Location:                   models.NewLocation(time.UTC),
+			NumberOfCalls:              10,
+			Priority:                   1,
+		},
+	}
+
+	before := time.Now().UTC()
+	sched, err := scheduler.GenerateSchedule(input, scheduler.WithCapacity(4), scheduler.WithUtilization(0.8))
+	require.NoError(t, err)
+	require.NotNil(t, sched.Metadata)
+	md := sched.Metadata
+	assert.False(t, md.GeneratedAt.Before(before.Truncate(time.Second)))
+	assert.Equal(t, time.UTC, md.GeneratedAt.Location())
+	assert.NotEmpty(t, md.RunID)
+	assert.NotEmpty(t, md.ToolVersion)
+	assert.Equal(t, scheduler.InputHash(input), md.InputHash)
+	assert.Len(t, md.InputHash, 64)
+	assert.Equal(t, map[string]string{
+		"utilization": "0.8",
+		"capacity":    "4",
+		"allocator":   "scheduler.PriorityAllocator",
+	}, md.Options)
+
+	// The same input hashes the same across runs; a different one does not
+	again, err := scheduler.GenerateSchedule(input)
+	require.NoError(t, err)
+	assert.Equal(t, md.InputHash, again.Metadata.InputHash)
+	assert.NotEqual(t, md.RunID, again.Metadata.RunID)
+	input[0].NumberOfCalls = 11
+	assert.NotEqual(t, md.InputHash, scheduler.InputHash(input))
+}
+
+func TestCacheKey(t *testing.T) {
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	input := []models.CallData{{
+		CustomerName:               "Cust1",
+		AverageCallDurationSeconds: 300,
+		StartTime:                  start,
+		EndTime:                    start.Add(time.Hour),
+		Location:                   models.NewLocation(time.UTC),
+		NumberOfCalls:              10,
+		Priority:                   1,
+	}}
+
+	key := scheduler.CacheKey(input, scheduler.WithCapacity(4))
+	assert.Len(t, key, 64)
+	assert.Equal(t, key, scheduler.CacheKey(input, scheduler.WithUtilization(1), scheduler.WithCapacity(4)), "defaults spelled out give the same key")
+	assert.NotEqual(t, key, scheduler.CacheKey(input, scheduler.WithCapacity(5)))
+	assert.NotEqual(t, key, scheduler.CacheKey(input[:0], scheduler.WithCapacity(4)))
+}
+
+func TestRunScenario(t *testing.T) {
+	now := time.Now().UTC()
+	input := []models.CallData{
+		{
+			CustomerName:               "Acme",
+			AverageCallDurationSeconds: 3600,
+			StartTime:                  time.Date(now.Year(), now.Month(), now.Day(), 10, 0, 0, 0, time.UTC),
+			EndTime:                    time.Date(now.Year(), now.Month(), now.Day(), 11, 0, 0, 0, time.UTC),
+			Location:                   models.NewLocation(time.UTC),
+			NumberOfCalls:              8,
+			Priority:                   models.PriorityNormal,
+		},
+	}
+
+	scenario := models.Scenario{Name: "lean", Utilization: 0.8, Capacity: 6}
+	result, err := scheduler.RunScenario(context.Background(), input, scenario)
+	require.NoError(t, err)
+	assert.Equal(t, scenario, result.Scenario)
+	assert.Equal(t, 10, result.Summary.TotalDemand)
+	assert.Equal(t, 6, result.Summary.TotalAgents)
+	assert.Equal(t, 4, result.Summary.TotalUnmet)
+	assert.Equal(t, "6", result.Schedule.Metadata.Options["capacity"])
+
+	// Later options override the scenario
+	result, err = scheduler.RunScenario(context.Background(), input, scenario, scheduler.WithCapacity(0))
+	require.NoError(t, err)
+	assert.Equal(t, 0, result.Summary.TotalUnmet)
+
+	_, err = scheduler.RunScenario(context.Background(), input, models.Scenario{Utilization: 2})
+	assert.ErrorIs(t, err, customerrors.ErrInvalidUtilization)
+}
+
+func TestGenerateSchedule_Utilization(t *testing.T) {
+	makeTime := func(hour int) time.Time {
+		now := time.Now().UTC()
+		return time.Date(now.Year(), now.Month(), now.Day(), hour, 0, 0, 0, time.UTC)
+	}
+
+	input := []models.CallData{
+		{
+			CustomerName:               "UtilizationTest",
+			AverageCallDurationSeconds: 3600,
+			StartTime:                  makeTime(10),
+			EndTime:                    makeTime(11),
+			Location:                   models.NewLocation(time.UTC),
+			NumberOfCalls:              10,
+			Priority:                   1,
+		},
+	}
+
+	// Base agents needed = 10 (10 calls * 1 hr / 1 hr)
+	// Utilization 0.8 -> Multiplier = 1/0.8 = 1.25
+	// Expected agents = ceil(10 * 1.25) = 13
+
+	sched, err := scheduler.GenerateSchedule(input, scheduler.WithUtilization(0.8))
+	require.NoError(t, err)
+
+	reqs := sched.HourlyRequirements[10]
+	assert.NotEmpty(t, reqs)
+	assert.Equal(t, 13, reqs[0].AgentsNeeded, "Should adjust agents based on utilization")
+}
+
+func TestGenerateSchedule_ErlangC(t *testing.T) {
+	makeTime := func(hour, minute int) time.Time {
+		now := time.Now().UTC()
+		return time.Date(now.Year(), now.Month(), now.Day(), hour, minute, 0, 0, time.UTC)
+	}
+	// 200 calls an hour of 3 minutes each is 10 Erlangs of offered load
+	row := models.CallData{
+		CustomerName:               "ErlangTest",
+		AverageCallDurationSeconds: 180,
+		StartTime:                  makeTime(10, 0),
+		EndTime:                    makeTime(11, 0),
+		Location:                   models.NewLocation(time.UTC),
+		NumberOfCalls:              200,
+		Priority:                   1,
+	}
+	withSLA := row
+	withSLA.SLATargetPercent, withSLA.SLAThresholdSeconds = 90, 20
+	halfHour := row
+	halfHour.EndTime, halfHour.NumberOfCalls = makeTime(10, 30), 100
+
+	tests := map[string]struct {
+		input []models.CallData
+		opts  []scheduler.Option
+		want  map[int]int
+	}{
+		"Workload": {
+			input: []models.CallData{row},
+			want:  map[int]int{10: 10},
+		},
+		"ServiceLevel": {
+			// 13 agents answer 79.6% of calls within 20 seconds, 14 answer 88.8%
+			input: []models.CallData{row},
+			opts:  []scheduler.Option{scheduler.WithErlangC(80, 20)},
+			want:  map[int]int{10: 14},
+		},
+		"RowSLAOverridesDefault": {
+			input: []models.CallData{withSLA},
+			opts:  []scheduler.Option{scheduler.WithErlangC(80, 20)},
+			want:  map[int]int{10: 15},
+		},
+		"PartialHourStaffsToRate": {
+			input: []models.CallData{halfHour},
+			opts:  []scheduler.Option{scheduler.WithErlangC(80, 20)},
+			want:  map[int]int{10: 14},
+		},
+		"Utilization": {
+			input: []models.CallData{row},
+			opts:  []scheduler.Option{scheduler.WithErlangC(80, 20), scheduler.WithUtilization(0.5)},
+			want:  map[int]int{10: 28},
+		},
+		"Poisson": {
+			// 10 Erlangs has 14 or fewer calls in progress 91.7% of the
+			// time, and 15 or fewer 95.1%
+			input: []models.CallData{row},
+			opts:  []scheduler.Option{scheduler.WithPoisson(95)},
+			want:  map[int]int{10: 15},
+		},
+		"PoissonMedian": {
+			input: []models.CallData{row},
+			opts:  []scheduler.Option{scheduler.WithPoisson(50)},
+			want:  map[int]int{10: 10},
+		},
+		"PoissonPartialHourStaffsToRate": {
+			input: []models.CallData{halfHour},
+			opts:  []scheduler.Option{scheduler.WithPoisson(95)},
+			want:  map[int]int{10: 15},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			sched, err := scheduler.GenerateSchedule(tt.input, tt.opts...)
+			require.NoError(t, err)
+			for h, reqs := range sched.HourlyRequirements {
+				agents := 0
+				for _, req := range reqs {
+					agents += req.AgentsNeeded
+				}
+				assert.Equal(t, tt.want[h], agents, "hour %d", h)
+			}
+		})
+	}
+}
+
+func TestGenerateSchedule_MaxOccupancy(t *testing.T) {
+	now := time.Now().UTC()
+	// 10 Erlangs of workload from 10:00 to 11:00
+	row := models.CallData{
+		CustomerName:               "OccupancyTest",
+		AverageCallDurationSeconds: 180,
+		StartTime:                  time.Date(now.Year(), now.Month(), now.Day(), 10, 0, 0, 0, time.UTC),
+		EndTime:                    time.Date(now.Year(), now.Month(), now.Day(), 11, 0, 0, 0, time.UTC),
+		Location:                   models.NewLocation(time.UTC),
+		NumberOfCalls:              200,
+		Priority:                   1,
+	}
+	nineErlangs := row
+	nineErlangs.NumberOfCalls = 180
+
+	tests := map[string]struct {
+		input     []models.CallData
+		opts      []scheduler.Option
+		want      int
+		wantAdded int
+	}{
+		"Workload": {
+			// 10 agents would be busy all hour; at 90% it takes 11.1
+			input:     []models.CallData{row},
+			opts:      []scheduler.Option{scheduler.WithMaxOccupancy(0.9)},
+			want:      12,
+			wantAdded: 2,
+		},
+		"ExactFit": {
+			input:     []models.CallData{nineErlangs},
+			opts:      []scheduler.Option{scheduler.WithMaxOccupancy(0.9)},
+			want:      10,
+			wantAdded: 1,
+		},
+		"Utilization": {
+			// 13 agents at 80%, then 26 after utilization rather than 20
+			input:     []models.CallData{row},
+			opts:      []scheduler.Option{scheduler.WithMaxOccupancy(0.8), scheduler.WithUtilization(0.5)},
+			want:      26,
+			wantAdded: 6,
+		},
+		"ErlangCUnderCap": {
+			// Erlang C's 14 agents are 71% occupied
+			input: []models.CallData{row},
+			opts:  []scheduler.Option{scheduler.WithMaxOccupancy(0.9), scheduler.WithErlangC(80, 20)},
+			want:  14,
+		},
+		"ErlangCOverCap": {
+			input:     []models.CallData{row},
+			opts:      []scheduler.Option{scheduler.WithMaxOccupancy(0.6), scheduler.WithErlangC(80, 20)},
+			want:      17,
+			wantAdded: 3,
+		},
+		"NoCap": {
+			input: []models.CallData{row},
+			want:  10,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			sched, err := scheduler.GenerateSchedule(tt.input, tt.opts...)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, sched.DemandForHour(10))
+			assert.Equal(t, float64(tt.wantAdded), testutil.ToFloat64(metrics.OccupancyAgentsAdded))
+		})
+	}
+}
+
+func TestGenerateSchedule_Peakedness(t *testing.T) {
+	now := time.Now().UTC()
+	// 10 Erlangs of workload from 10:00 to 11:00
+	row := func(name string) models.CallData {
+		return models.CallData{
+			CustomerName:               name,
+			AverageCallDurationSeconds: 180,
+			StartTime:                  time.Date(now.Year(), now.Month(), now.Day(), 10, 0, 0, 0, time.UTC),
+			EndTime:                    time.Date(now.Year(), now.Month(), now.Day(), 11, 0, 0, 0, time.UTC),
+			Location:                   models.NewLocation(time.UTC),
+			NumberOfCalls:              200,
+			Priority:                   1,
+		}
+	}
+	// Acme gets 30% of its calls in each middle quarter
+	curves := models.ArrivalCurves{"Acme": {0.2, 0.3, 0.3, 0.2}}
+
+	tests := map[string]struct {
+		opts []scheduler.Option
+		want map[string]int
+	}{
+		"Even": {
+			want: map[string]int{"Acme": 10, "Globex": 10},
+		},
+		"Factor": {
+			opts: []scheduler.Option{scheduler.WithPeakedness(1.25)},
+			want: map[string]int{"Acme": 13, "Globex": 13},
+		},
+		"Curve": {
+			// The curve overrides the factor for Acme
+			opts: []scheduler.Option{scheduler.WithPeakedness(1.25), scheduler.WithArrivalCurves(curves)},
+			want: map[string]int{"Acme": 12, "Globex": 13},
+		},
+		"ErlangC": {
+			// Erlang C staffs 14 agents for 10 Erlangs and 16 for 12
+			opts: []scheduler.Option{scheduler.WithArrivalCurves(curves), scheduler.WithErlangC(80, 20)},
+			want: map[string]int{"Acme": 16, "Globex": 14},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			sched, err := scheduler.GenerateSchedule([]models.CallData{row("Acme"), row("Globex")}, tt.opts...)
+			require.NoError(t, err)
+			got := make(map[string]int)
+			for _, req := range sched.HourlyRequirements[10] {
+				got[req.Name] = req.AgentsNeeded
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestGenerateSchedule_Smoothing(t *testing.T) {
+	utc := models.NewLocation(time.UTC)
+	at := func(hour int) time.Time {
+		now := time.Now().UTC()
+		return time.Date(now.Year(), now.Month(), now.Day(), hour, 0, 0, 0, time.UTC)
+	}
+	// 12 agents at 9:00 and 90 at 10:00, then nothing
+	input := []models.CallData{
+		{CustomerName: "Acme", AverageCallDurationSeconds: 3600, StartTime: at(9), EndTime: at(10), Location: utc, NumberOfCalls: 12, Priority: 2, Skill: "billing"},
+		{CustomerName: "Acme", AverageCallDurationSeconds: 3600, StartTime: at(10), EndTime: at(11), Location: utc, NumberOfCalls: 90, Priority: 2, Skill: "billing"},
+	}
+
+	tests := map[string]struct {
+		opts []scheduler.Option
+		want map[int]int
+	}{
+		"PerSlot": {
+			opts: []scheduler.Option{scheduler.WithSmoothing(1, 20)},
+			want: map[int]int{6: 10, 7: 30, 8: 50, 9: 70, 10: 90, 11: 70, 12: 50, 13: 30, 14: 10},
+		},
+		"Window": {
+			// At most 20 agents of change between slots up to two apart
+			opts: []scheduler.Option{scheduler.WithSmoothing(2, 20)},
+			want: map[int]int{2: 10, 3: 10, 4: 30, 5: 30, 6: 50, 7: 50, 8: 70, 9: 70, 10: 90, 11: 70, 12: 70, 13: 50, 14: 50, 15: 30, 16: 30, 17: 10, 18: 10},
+		},
+		"WithinLimit": {
+			opts: []scheduler.Option{scheduler.WithSmoothing(1, 100)},
+			want: map[int]int{9: 12, 10: 90},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			sched, err := scheduler.GenerateSchedule(input, tt.opts...)
+			require.NoError(t, err)
+			for h := range sched.NumSlots() {
+				assert.Equal(t, tt.want[h], sched.DemandForHour(h), "hour %d", h)
+				// Each slot keeps one requirement per customer, with its attributes
+				for _, req := range sched.HourlyRequirements[h] {
+					assert.Equal(t, "billing", req.Skill)
+					assert.Equal(t, models.Priority(2), req.Priority)
+				}
+				assert.LessOrEqual(t, len(sched.HourlyRequirements[h]), 1, "hour %d", h)
+			}
+		})
+	}
+
+	t.Run("BeforeCapacity", func(t *testing.T) {
+		sched, err := scheduler.GenerateSchedule(input, scheduler.WithSmoothing(1, 20), scheduler.WithCapacity(60))
+		require.NoError(t, err)
+		assert.Equal(t, 60, sched.AgentsForHour(9))
+		assert.Equal(t, 70, sched.DemandForHour(9))
+		assert.Equal(t, "1", sched.Metadata.Options["smoothing_window"])
+		assert.Equal(t, "20", sched.Metadata.Options["smoothing_max_delta"])
+	})
+}
+
+func TestGenerateSchedule_AgentLimits(t *testing.T) {
+	utc := models.NewLocation(time.UTC)
+	at := func(hour int) time.Time {
+		now := time.Now().UTC()
+		return time.Date(now.Year(), now.Month(), now.Day(), hour, 0, 0, 0, time.UTC)
+	}
+	// Acme needs 1 agent at 9:00 and 12 at 10:00, and is held to 3-8
+	input := []models.CallData{
+		{CustomerName: "Acme", AverageCallDurationSeconds: 3600, StartTime: at(9), EndTime: at(10), Location: utc, NumberOfCalls: 1, Priority: 1, FloorAgents: 3, CeilingAgents: 8},
+		{CustomerName: "Acme", AverageCallDurationSeconds: 3600, StartTime: at(10), EndTime: at(11), Location: utc, NumberOfCalls: 12, Priority: 1, FloorAgents: 3, CeilingAgents: 8},
+		{CustomerName: "Globex", AverageCallDurationSeconds: 3600, StartTime: at(10), EndTime: at(11), Location: utc, NumberOfCalls: 4, Priority: 2},
+	}
+
+	t.Run("Unconstrained", func(t *testing.T) {
+		sched, err := scheduler.GenerateSchedule(input)
+		require.NoError(t, err)
+		assert.Equal(t, 3, sched.AgentsForHour(9))
+		assert.Equal(t, 12, sched.AgentsForHour(10))
+		require.Len(t, sched.UnmetDemands, 1)
+		unmet := sched.UnmetDemands[0]
+		assert.Equal(t, 10, unmet.Hour)
+		assert.Equal(t, 16, unmet.TotalDemand)
+		assert.Equal(t, 12, unmet.AllocatedAgents)
+		assert.Equal(t, 4, unmet.UnmetAgents)
+		assert.Equal(t, []models.ImpactedClient{
+			{Name: "Acme", RequestedAgents: 12, AllocatedAgents: 8, UnmetAgents: 4, Priority: 1, Location: utc, CeilingAgents: 8},
+		}, unmet.ImpactedClients)
+		assert.Equal(t, 4.0, testutil.ToFloat64(metrics.CeilingAgentsUnmet))
+	})
+
+	t.Run("WithCapacity", func(t *testing.T) {
+		// Capacity cuts Acme below its ceiling too, and Globex entirely
+		sched, err := scheduler.GenerateSchedule(input, scheduler.WithCapacity(6))
+		require.NoError(t, err)
+		assert.Equal(t, 6, sched.AgentsForHour(10))
+		require.Len(t, sched.UnmetDemands, 1)
+		unmet := sched.UnmetDemands[0]
+		assert.Equal(t, 16, unmet.TotalDemand)
+		assert.Equal(t, 10, unmet.UnmetAgents)
+		assert.Equal(t, []models.ImpactedClient{
+			{Name: "Acme", RequestedAgents: 12, AllocatedAgents: 6, UnmetAgents: 6, Priority: 1, Location: utc, CeilingAgents: 8},
+			{Name: "Globex", RequestedAgents: 4, AllocatedAgents: 0, UnmetAgents: 4, Priority: 2, Location: utc},
+		}, unmet.ImpactedClients)
+		assert.Equal(t, 16, sched.DemandForHour(10))
+	})
+}
+
+func TestGenerateSchedule_FTE(t *testing.T) {
+	utc := models.NewLocation(time.UTC)
+	now := time.Now().UTC()
+	start := time.Date(now.Year(), now.Month(), now.Day(), 9, 0, 0, 0, time.UTC)
+	// Acme works 1.5 agents' worth of calls and Globex 0.25
+	input := []models.CallData{
+		{CustomerName: "Acme", AverageCallDurationSeconds: 360, StartTime: start, EndTime: start.Add(time.Hour), Location: utc, NumberOfCalls: 15, Priority: 1},
+		{CustomerName: "Globex", AverageCallDurationSeconds: 180, StartTime: start, EndTime: start.Add(time.Hour), Location: utc, NumberOfCalls: 5, Priority: 2},
+	}
+	fte := func(sched *models.Schedule) map[string]float64 {
+		byName := make(map[string]float64)
+		for _, req := range sched.HourlyRequirements[9] {
+			byName[req.Name] += req.AgentsFTE()
+		}
+		return byName
+	}
+
+	t.Run("Off", func(t *testing.T) {
+		sched, err := scheduler.GenerateSchedule(input)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]float64{"Acme": 2, "Globex": 1}, fte(sched), "without WithFTE, FTE is the whole agents")
+	})
+
+	t.Run("On", func(t *testing.T) {
+		sched, err := scheduler.GenerateSchedule(input, scheduler.WithFTE(true))
+		require.NoError(t, err)
+		assert.Equal(t, 3, sched.AgentsForHour(9), "whole agents are still rounded up")
+		assert.Equal(t, map[string]float64{"Acme": 1.5, "Globex": 0.25}, fte(sched))
+		assert.Equal(t, "true", sched.Metadata.Options["fte"])
+	})
+
+	t.Run("WithUtilization", func(t *testing.T) {
+		sched, err := scheduler.GenerateSchedule(input, scheduler.WithFTE(true), scheduler.WithUtilization(0.5))
+		require.NoError(t, err)
+		assert.Equal(t, map[string]float64{"Acme": 3, "Globex": 0.5}, fte(sched))
+	})
+
+	t.Run("WithCapacity", func(t *testing.T) {
+		// Acme keeps its 1.5 within 2 agents; Globex is left 0.0 of 0.25
+		sched, err := scheduler.GenerateSchedule(input, scheduler.WithFTE(true), scheduler.WithCapacity(2))
+		require.NoError(t, err)
+		assert.Equal(t, map[string]float64{"Acme": 1.5}, fte(sched))
+	})
+}
+
+func TestGenerateSchedule_Hooks(t *testing.T) {
+	utc := models.NewLocation(time.UTC)
+	now := time.Now().UTC()
+	start := time.Date(now.Year(), now.Month(), now.Day(), 9, 0, 0, 0, time.UTC)
+	// 3 agents each hour from 9 to 11, with capacity for 2
+	input := []models.CallData{
+		{CustomerName: "Acme", AverageCallDurationSeconds: 360, StartTime: start, EndTime: start.Add(2 * time.Hour), Location: utc, NumberOfCalls: 40, Priority: 1},
+		{CustomerName: "Globex", AverageCallDurationSeconds: 360, StartTime: start, EndTime: start.Add(2 * time.Hour), Location: utc, NumberOfCalls: 20, Priority: 2},
+	}
+
+	var events []string
+	_, err := scheduler.GenerateSchedule(input, scheduler.WithCapacity(2), scheduler.WithHooks(scheduler.Hooks{
+		OnAllocated: func(slot models.TimeSlot, req models.CustomerRequirement) {
+			events = append(events, fmt.Sprintf("%02d %s %d", slot.Start.Hour(), req.Name, req.AgentsNeeded))
+		},
+		OnUnmet: func(unmet models.UnmetDemand) {
+			events = append(events, fmt.Sprintf("%02d unmet %d", unmet.Hour, unmet.UnmetAgents))
+		},
+		OnHourComplete: func(h int, slot models.TimeSlot) {
+			if hour := slot.Start.Hour(); hour >= 9 && hour < 11 {
+				events = append(events, fmt.Sprintf("%02d done", h))
+			}
+		},
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"09 Acme 2", "09 unmet 1", "09 done",
+		"10 Acme 2", "10 unmet 1", "10 done",
+	}, events)
+
+	t.Run("Partial", func(t *testing.T) {
+		completed := 0
+		sched, err := scheduler.GenerateSchedule(input, scheduler.WithHooks(scheduler.Hooks{
+			OnHourComplete: func(int, models.TimeSlot) { completed++ },
+		}))
+		require.NoError(t, err)
+		assert.Equal(t, sched.NumSlots(), completed, "unset hooks are skipped")
+	})
+}
+
+func TestReallocate(t *testing.T) {
+	utc := models.NewLocation(time.UTC)
+	now := time.Now().UTC()
+	start := time.Date(now.Year(), now.Month(), now.Day(), 9, 0, 0, 0, time.UTC)
+	// From 9 to 11, Acme needs 4 agents, Globex 3, and Initech 4 held to 2
+	input := []models.CallData{
+		{CustomerName: "Acme", AverageCallDurationSeconds: 360, StartTime: start, EndTime: start.Add(2 * time.Hour), Location: utc, NumberOfCalls: 80, Priority: 1},
+		{CustomerName: "Globex", AverageCallDurationSeconds: 360, StartTime: start, EndTime: start.Add(2 * time.Hour), Location: utc, NumberOfCalls: 60, Priority: 2, Skill: "billing", MinAgents: 1},
+		{CustomerName: "Initech", AverageCallDurationSeconds: 360, StartTime: start, EndTime: start.Add(2 * time.Hour), Location: utc, NumberOfCalls: 80, Priority: 3, CeilingAgents: 2},
+	}
+	saved, err := scheduler.GenerateSchedule(input, scheduler.WithCapacity(4))
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	require.NoError(t, saved.Save(&buf))
+	loaded, err := models.Load(&buf)
+	require.NoError(t, err)
+
+	for name, opts := range map[string][]scheduler.Option{
+		"MoreCapacity": {scheduler.WithCapacity(8)},
+		"LessCapacity": {scheduler.WithCapacity(2)},
+		"Unlimited":    nil,
+		"Proportional": {scheduler.WithCapacity(6), scheduler.WithAllocator(scheduler.ProportionalAllocator{})},
+	} {
+		t.Run(name, func(t *testing.T) {
+			got, err := scheduler.Reallocate(context.Background(), loaded, opts...)
+			require.NoError(t, err)
+			want, err := scheduler.GenerateSchedule(input, opts...)
+			require.NoError(t, err)
+			require.Equal(t, want.NumSlots(), got.NumSlots())
+			for h := range want.NumSlots() {
+				assert.ElementsMatch(t, want.HourlyRequirements[h], got.HourlyRequirements[h], "slot %d", h)
+			}
+			require.Len(t, got.UnmetDemands, len(want.UnmetDemands))
+			for i, unmet := range want.UnmetDemands {
+				assert.Equal(t, unmet.Hour, got.UnmetDemands[i].Hour)
+				assert.Equal(t, unmet.UnmetAgents, got.UnmetDemands[i].UnmetAgents)
+				assert.ElementsMatch(t, unmet.ImpactedClients, got.UnmetDemands[i].ImpactedClients)
+			}
+			assert.Equal(t, saved.Metadata.InputHash, got.Metadata.InputHash)
+			assert.NotEqual(t, saved.Metadata.RunID, got.Metadata.RunID)
+		})
+	}
+
+	t.Run("Pools", func(t *testing.T) {
+		// No pool serves Globex's billing calls, so they are all unmet
+		saved, err := scheduler.GenerateSchedule(input, scheduler.WithAgentPools([]models.AgentPool{{Name: "Support", Skills: []string{"support"}, Headcount: 4}}))
+		require.NoError(t, err)
+		// One agent who takes billing, which Acme would take but for
+		// Globex's minimum
+		opts := []scheduler.Option{scheduler.WithAgentPools([]models.AgentPool{{Name: "Billing", Skills: []string{"billing"}, Headcount: 1}})}
+
+		got, err := scheduler.Reallocate(context.Background(), saved, opts...)
+		require.NoError(t, err)
+		want, err := scheduler.GenerateSchedule(input, opts...)
+		require.NoError(t, err)
+		for h := range want.NumSlots() {
+			assert.ElementsMatch(t, want.HourlyRequirements[h], got.HourlyRequirements[h], "slot %d", h)
+		}
+		assert.Equal(t, []models.CustomerRequirement{{Name: "Globex", AgentsNeeded: 1, Priority: 2, Location: utc, Skill: "billing", MinAgents: 1}}, got.HourlyRequirements[9])
+		require.Len(t, got.UnmetDemands, len(want.UnmetDemands))
+		for i, unmet := range want.UnmetDemands {
+			assert.ElementsMatch(t, unmet.ImpactedClients, got.UnmetDemands[i].ImpactedClients)
+		}
+	})
+
+	t.Run("Interval", func(t *testing.T) {
+		// 10 agents in each half hour from 9:00, held to 1 in hour 9
+		input := []models.CallData{
+			{CustomerName: "Acme", AverageCallDurationSeconds: 360, StartTime: start, EndTime: start.Add(time.Hour), Location: utc, NumberOfCalls: 100, Priority: 1},
+		}
+		saved, err := scheduler.GenerateSchedule(input, scheduler.WithInterval(30*time.Minute))
+		require.NoError(t, err)
+		profile := make([]int, 24)
+		for h := range profile {
+			profile[h] = 100
+		}
+		profile[9] = 1
+
+		got, err := scheduler.Reallocate(context.Background(), saved, scheduler.WithCapacityProfile(profile))
+		require.NoError(t, err)
+		want, err := scheduler.GenerateSchedule(input, scheduler.WithInterval(30*time.Minute), scheduler.WithCapacityProfile(profile))
+		require.NoError(t, err)
+		assert.Equal(t, 1, got.AgentsForHour(18))
+		assert.Equal(t, 1, got.AgentsForHour(19))
+		assert.Equal(t, want.HourlyRequirements, got.HourlyRequirements)
+		require.Len(t, got.UnmetDemands, 2)
+		assert.Equal(t, []int{18, 19}, []int{got.UnmetDemands[0].Hour, got.UnmetDemands[1].Hour})
+		assert.Equal(t, "30m0s", got.Metadata.Options["interval"])
+
+		_, err = scheduler.Reallocate(context.Background(), saved, scheduler.WithInterval(15*time.Minute))
+		var cv *customerrors.ConstraintViolationError
+		require.ErrorAs(t, err, &cv)
+		assert.Equal(t, "interval", cv.Constraint)
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		_, err := scheduler.Reallocate(context.Background(), &models.Schedule{})
+		assert.ErrorIs(t, err, customerrors.ErrEmptyInput)
+		_, err = scheduler.Reallocate(context.Background(), loaded, scheduler.WithCapacity(-1))
+		var cv *customerrors.ConstraintViolationError
+		assert.ErrorAs(t, err, &cv)
+	})
+}
+
+func TestGenerateSchedule_Interval(t *testing.T) {
+	now := time.Now().UTC()
+	// 60 calls from 9:10 to 10:00 arrive at 72 an hour, 18 every 15 minutes
+	input := []models.CallData{{
+		CustomerName:               "IntervalTest",
+		AverageCallDurationSeconds: 900,
+		StartTime:                  time.Date(now.Year(), now.Month(), now.Day(), 9, 10, 0, 0, time.UTC),
+		EndTime:                    time.Date(now.Year(), now.Month(), now.Day(), 10, 0, 0, 0, time.UTC),
+		Location:                   models.NewLocation(time.UTC),
+		NumberOfCalls:              60,
+		Priority:                   1,
+	}}
+
+	sched, err := scheduler.GenerateSchedule(input, scheduler.WithInterval(15*time.Minute), scheduler.WithCapacity(10))
+	require.NoError(t, err)
+	require.Len(t, sched.HourlyRequirements, 96)
+	assert.Equal(t, 96, sched.NumSlots())
+	assert.Equal(t, "09:00", sched.SlotAt(36).String())
+	assert.Equal(t, "15m0s", sched.Metadata.Options["interval"])
+
+	// 9:10-9:15 gets a third of a slot's calls
+	want := map[int]int{36: 6, 37: 18, 38: 18, 39: 18}
+	for h := range sched.NumSlots() {
+		assert.Equal(t, want[h], sched.DemandForHour(h), "slot %s", sched.SlotAt(h))
+	}
+	// The hourly capacity caps each slot in the hour
+	for _, unmet := range sched.UnmetDemands {
+		assert.Equal(t, 10, unmet.AllocatedAgents, "slot %s", unmet.Slot)
+	}
+	assert.Equal(t, []int{37, 38, 39}, sched.HoursWithShortfall())
+}
+
+func TestGenerateSchedule_MultiDay(t *testing.T) {
+	utc := models.NewLocation(time.UTC)
+	input := []models.CallData{
+		// Overnight into 2026-03-03, 10 agents an hour
+		{CustomerName: "Night Desk", AverageCallDurationSeconds: 360, StartTime: time.Date(2026, 3, 2, 21, 0, 0, 0, time.UTC), EndTime: time.Date(2026, 3, 3, 5, 0, 0, 0, time.UTC), Location: utc, NumberOfCalls: 800, Priority: 1},
+		// Two days later, leaving 2026-03-03 otherwise empty
+		{CustomerName: "Day Desk", AverageCallDurationSeconds: 3600, StartTime: time.Date(2026, 3, 4, 9, 0, 0, 0, time.UTC), EndTime: time.Date(2026, 3, 4, 10, 0, 0, 0, time.UTC), Location: utc, NumberOfCalls: 10, Priority: 1},
+	}
+
+	t.Run("ByDate", func(t *testing.T) {
+		sched, err := scheduler.GenerateSchedule(input, scheduler.WithMultiDay(true), scheduler.WithCapacity(8))
+		require.NoError(t, err)
+		require.Equal(t, 72, sched.NumSlots())
+		require.Len(t, sched.HourlyRequirements, 72)
+		assert.Equal(t, "2026-03-02 00:00", sched.SlotAt(0).String())
+		assert.Equal(t, "2026-03-04 23:00", sched.SlotAt(71).String())
+
+		want := map[int]int{21: 10, 22: 10, 23: 10, 24: 10, 25: 10, 26: 10, 27: 10, 28: 10, 57: 10}
+		for h := range sched.NumSlots() {
+			assert.Equal(t, want[h], sched.DemandForHour(h), "slot %s", sched.SlotAt(h))
+		}
+		// Capacity applies to every day's hours
+		require.Len(t, sched.UnmetDemands, len(want))
+		assert.Equal(t, "2026-03-03 00:00", sched.UnmetDemands[3].Slot.String())
+		assert.Equal(t, 2, sched.UnmetDemands[3].UnmetAgents)
+	})
+
+	t.Run("OneDay", func(t *testing.T) {
+		// Without it, every row folds into the same generic day
+		sched, err := scheduler.GenerateSchedule(input)
+		require.NoError(t, err)
+		require.Equal(t, 24, sched.NumSlots())
+		want := map[int]int{0: 10, 1: 10, 2: 10, 3: 10, 4: 10, 9: 10, 21: 10, 22: 10, 23: 10}
+		for h := range sched.NumSlots() {
+			assert.Equal(t, want[h], sched.DemandForHour(h), "hour %d", h)
+		}
+	})
+}
+
+func TestGenerateSchedule_UTC(t *testing.T) {
+	la, err := time.LoadLocation("America/Los_Angeles")
+	require.NoError(t, err)
+	ny, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	// 9AM in Los Angeles and noon in New York are both 17:00 UTC in March,
+	// each 10 agents
+	input := []models.CallData{
+		{CustomerName: "West", AverageCallDurationSeconds: 360, StartTime: time.Date(2026, 3, 2, 9, 0, 0, 0, la), EndTime: time.Date(2026, 3, 2, 10, 0, 0, 0, la), Location: models.NewLocation(la), NumberOfCalls: 100, Priority: 1},
+		{CustomerName: "East", AverageCallDurationSeconds: 360, StartTime: time.Date(2026, 3, 2, 12, 0, 0, 0, ny), EndTime: time.Date(2026, 3, 2, 13, 0, 0, 0, ny), Location: models.NewLocation(ny), NumberOfCalls: 100, Priority: 2},
+	}
+
+	t.Run("Local", func(t *testing.T) {
+		sched, err := scheduler.GenerateSchedule(input, scheduler.WithCapacity(10))
+		require.NoError(t, err)
+		assert.Equal(t, 10, sched.AgentsForHour(9))
+		assert.Equal(t, 10, sched.AgentsForHour(12))
+		assert.Empty(t, sched.UnmetDemands)
+	})
+
+	t.Run("UTC", func(t *testing.T) {
+		sched, err := scheduler.GenerateSchedule(input, scheduler.WithCapacity(10), scheduler.WithUTC(true))
+		require.NoError(t, err)
+		assert.Equal(t, "17:00 UTC", sched.SlotAt(17).String())
+		assert.Equal(t, "true", sched.Metadata.Options["utc"])
+		assert.Equal(t, 10, sched.AgentsForHour(17))
+		assert.Equal(t, 20, sched.DemandForHour(17), "both share the slot's capacity")
+		require.Len(t, sched.UnmetDemands, 1)
+		require.Len(t, sched.UnmetDemands[0].ImpactedClients, 1)
+		assert.Equal(t, "East", sched.UnmetDemands[0].ImpactedClients[0].Name)
+	})
+
+	t.Run("UTCByDate", func(t *testing.T) {
+		sched, err := scheduler.GenerateSchedule(input, scheduler.WithUTC(true), scheduler.WithMultiDay(true))
+		require.NoError(t, err)
+		require.Equal(t, 24, sched.NumSlots())
+		assert.Equal(t, "2026-03-02 17:00 UTC", sched.SlotAt(17).String())
+		assert.Equal(t, time.Date(2026, 3, 2, 17, 0, 0, 0, time.UTC), sched.SlotAt(17).Start)
+		assert.Equal(t, 20, sched.AgentsForHour(17))
+	})
+}
+
+func TestGenerateSchedule_WeeklyPattern(t *testing.T) {
+	utc := models.NewLocation(time.UTC)
+	// A Wednesday, 10 agents an hour before the pattern applies
+	input := []models.CallData{
+		{CustomerName: "Acme", AverageCallDurationSeconds: 360, StartTime: time.Date(2026, 3, 4, 9, 0, 0, 0, time.UTC), EndTime: time.Date(2026, 3, 4, 11, 0, 0, 0, time.UTC), Location: utc, NumberOfCalls: 200, Priority: 1},
+		{CustomerName: "Globex", AverageCallDurationSeconds: 3600, StartTime: time.Date(2026, 3, 4, 12, 0, 0, 0, time.UTC), EndTime: time.Date(2026, 3, 4, 13, 0, 0, 0, time.UTC), Location: utc, NumberOfCalls: 1, Priority: 1},
+	}
+	pattern := models.WeeklyPattern{
+		"Acme": {time.Monday: 2, time.Tuesday: 1, time.Wednesday: 1, time.Thursday: 1, time.Friday: 0.5, time.Saturday: 0.5},
+	}
+
+	sched, err := scheduler.GenerateSchedule(input, scheduler.WithWeeklyPattern(pattern))
+	require.NoError(t, err)
+	require.Equal(t, 7*24, sched.NumSlots())
+	assert.Equal(t, "2026-03-02 00:00", sched.SlotAt(0).String())
+	assert.Equal(t, "2026-03-08 23:00", sched.SlotAt(7*24-1).String())
+	assert.NotEmpty(t, sched.Metadata.Options["weekly_pattern"])
+
+	// Acme is scaled per weekday and Globex, not in the pattern, is not
+	agents := []int{20, 10, 10, 10, 5, 5, 0}
+	for day, acme := range agents {
+		assert.Equal(t, acme, sched.DemandForHour(day*24+9), "day %d", day)
+		assert.Equal(t, acme, sched.DemandForHour(day*24+10), "day %d", day)
+		assert.Equal(t, 1, sched.DemandForHour(day*24+12), "day %d", day)
+	}
+}
+
+func TestGenerateSchedule_Calendar(t *testing.T) {
+	utc := models.NewLocation(time.UTC)
+	// Across a Wednesday and Thursday, 10 agents an hour
+	input := []models.CallData{
+		{CustomerName: "Acme", AverageCallDurationSeconds: 360, StartTime: time.Date(2026, 3, 4, 9, 0, 0, 0, time.UTC), EndTime: time.Date(2026, 3, 4, 12, 0, 0, 0, time.UTC), Location: utc, NumberOfCalls: 300, Priority: 1, FloorAgents: 2},
+		{CustomerName: "Acme", AverageCallDurationSeconds: 360, StartTime: time.Date(2026, 3, 5, 9, 0, 0, 0, time.UTC), EndTime: time.Date(2026, 3, 5, 12, 0, 0, 0, time.UTC), Location: utc, NumberOfCalls: 300, Priority: 1, FloorAgents: 2},
+		{CustomerName: "Globex", AverageCallDurationSeconds: 3600, StartTime: time.Date(2026, 3, 4, 9, 0, 0, 0, time.UTC), EndTime: time.Date(2026, 3, 4, 10, 0, 0, 0, time.UTC), Location: utc, NumberOfCalls: 1, Priority: 1},
+	}
+	cal := models.Calendar{
+		{Name: "Holiday", Start: time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC), End: time.Date(2026, 3, 6, 0, 0, 0, 0, time.UTC), Multiplier: 0.5},
+		{Name: "Training", Customer: "Acme", Start: time.Date(2026, 3, 4, 10, 0, 0, 0, time.UTC), End: time.Date(2026, 3, 4, 11, 0, 0, 0, time.UTC)},
+	}
+
+	tests := map[string]struct {
+		opts []scheduler.Option
+		want map[int]int
+	}{
+		"Workload": {
+			// Training blacks Acme out, floor and all, and the holiday
+			// halves it
+			want: map[int]int{9: 11, 11: 10, 33: 5, 34: 5, 35: 5},
+		},
+		"ErlangC": {
+			// 5 Erlangs need 8 agents to answer 80% of calls within 20
+			// seconds, 10 Erlangs 14, and Globex's 1 Erlang 3
+			opts: []scheduler.Option{scheduler.WithErlangC(80, 20)},
+			want: map[int]int{9: 17, 11: 14, 33: 8, 34: 8, 35: 8},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			opts := append([]scheduler.Option{scheduler.WithMultiDay(true), scheduler.WithCalendar(cal)}, tt.opts...)
+			sched, err := scheduler.GenerateSchedule(input, opts...)
+			require.NoError(t, err)
+			require.Equal(t, 48, sched.NumSlots())
+			assert.NotEmpty(t, sched.Metadata.Options["calendar"])
+			for h := range sched.NumSlots() {
+				assert.Equal(t, tt.want[h], sched.DemandForHour(h), "slot %s", sched.SlotAt(h))
+			}
+		})
+	}
+}
+
+func TestGenerateSchedule_Seasonality(t *testing.T) {
+	utc := models.NewLocation(time.UTC)
+	// 10 agents an hour in the last week of November and the first two of
+	// December; 2026-12-10 is in ISO week 50
+	var input []models.CallData
+	for _, day := range []time.Time{time.Date(2026, 11, 26, 0, 0, 0, 0, time.UTC), time.Date(2026, 12, 3, 0, 0, 0, 0, time.UTC), time.Date(2026, 12, 10, 0, 0, 0, 0, time.UTC)} {
+		for _, customer := range []string{"Acme", "Globex"} {
+			input = append(input, models.CallData{CustomerName: customer, AverageCallDurationSeconds: 360, StartTime: day.Add(9 * time.Hour), EndTime: day.Add(10 * time.Hour), Location: utc, NumberOfCalls: 100, Priority: 1})
+		}
+	}
+	seasonality := models.Seasonality{
+		{Month: time.December, Multiplier: 1.5},
+		{Customer: "Acme", Month: time.December, Multiplier: 2},
+		{Week: 50, Multiplier: 2},
+	}
+
+	sched, err := scheduler.GenerateSchedule(input, scheduler.WithMultiDay(true), scheduler.WithSeasonality(seasonality))
+	require.NoError(t, err)
+	assert.NotEmpty(t, sched.Metadata.Options["seasonality"])
+	agents := func(day, customer string) int {
+		for h := range sched.NumSlots() {
+			if sched.SlotAt(h).String() != day+" 09:00" {
+				continue
+			}
+			for _, req := range sched.HourlyRequirements[h] {
+				if req.Name == customer {
+					return req.AgentsNeeded
+				}
+			}
+		}
+		return 0
+	}
+	assert.Equal(t, 10, agents("2026-11-26", "Acme"))
+	assert.Equal(t, 10, agents("2026-11-26", "Globex"))
+	assert.Equal(t, 20, agents("2026-12-03", "Acme"), "Acme's own December")
+	assert.Equal(t, 15, agents("2026-12-03", "Globex"))
+	assert.Equal(t, 40, agents("2026-12-10", "Acme"), "December and week 50 multiply")
+	assert.Equal(t, 30, agents("2026-12-10", "Globex"))
+}
+
+func TestGenerateSchedule_CapacityUsedMetric(t *testing.T) {
+	makeTime := func(hour int) time.Time {
+		now := time.Now().UTC()
+		return time.Date(now.Year(), now.Month(), now.Day(), hour, 0, 0, 0, time.UTC)
+	}
+
+	input := []models.CallData{
+		{
+			CustomerName:               "CapacityTest",
+			AverageCallDurationSeconds: 3600,
+			StartTime:                  makeTime(10),
+			EndTime:                    makeTime(12),
+			Location:                   models.NewLocation(time.UTC),
+			NumberOfCalls:              20, // 10 agents per hour
+			Priority:                   1,
+		},
+	}
+
+	// Capacity 8 per hour across two hours -> 16 agents used
+	_, err := scheduler.GenerateSchedule(input, scheduler.WithCapacity(8))
+	require.NoError(t, err)
+	assert.Equal(t, 16.0, testutil.ToFloat64(metrics.SchedulerCapacityUsed))
+
+	// Unconstrained runs leave the gauge at zero
+	_, err = scheduler.GenerateSchedule(input)
+	require.NoError(t, err)
+	assert.Equal(t, 0.0, testutil.ToFloat64(metrics.SchedulerCapacityUsed))
+}
+
+func TestGenerateSchedule_PerCustomerMetrics(t *testing.T) {
+	makeTime := func(hour int) time.Time {
+		now := time.Now().UTC()
+		return time.Date(now.Year(), now.Month(), now.Day(), hour, 0, 0, 0, time.UTC)
+	}
+
+	input := []models.CallData{
+		{
+			CustomerName:               "Big",
+			AverageCallDurationSeconds: 3600,
+			StartTime:                  makeTime(10),
+			EndTime:                    makeTime(11),
+			Location:                   models.NewLocation(time.UTC),
+			NumberOfCalls:              10,
+			Priority:                   1,
+		},
+		{
+			CustomerName:               "Small",
+			AverageCallDurationSeconds: 3600,
+			StartTime:                  makeTime(10),
+			EndTime:                    makeTime(11),
+			Location:                   models.NewLocation(time.UTC),
+			NumberOfCalls:              4,
+			Priority:                   2,
+		},
+	}
+
+	metrics.EnablePerCustomerMetrics(1)
+	defer metrics.EnablePerCustomerMetrics(0)
+
+	// Capacity 12: Big gets 10, Small gets 2 of 4
+	_, err := scheduler.GenerateSchedule(input, scheduler.WithCapacity(12))
+	require.NoError(t, err)
+
+	assert.Equal(t, 10.0, testutil.ToFloat64(metrics.CustomerAgentsDemanded.WithLabelValues("Big")))
+	assert.Equal(t, 10.0, testutil.ToFloat64(metrics.CustomerAgentsAllocated.WithLabelValues("Big")))
+
+	// Small is beyond the cap of 1 and is reported under the overflow label
+	other := metrics.OtherCustomersLabel
+	assert.Equal(t, 4.0, testutil.ToFloat64(metrics.CustomerAgentsDemanded.WithLabelValues(other)))
+	assert.Equal(t, 2.0, testutil.ToFloat64(metrics.CustomerAgentsAllocated.WithLabelValues(other)))
+	assert.Equal(t, 2.0, testutil.ToFloat64(metrics.CustomerAgentsUnmet.WithLabelValues(other)))
+	assert.Equal(t, 2, testutil.CollectAndCount(metrics.CustomerAgentsDemanded))
+}
+
+func TestGenerateSchedule_HourlyMetrics(t *testing.T) {
+	makeTime := func(hour int) time.Time {
+		now := time.Now().UTC()
+		return time.Date(now.Year(), now.Month(), now.Day(), hour, 0, 0, 0, time.UTC)
+	}
+
+	input := []models.CallData{
+		{
+			CustomerName:               "HourlyTest",
+			AverageCallDurationSeconds: 3600,
+			StartTime:                  makeTime(10),
+			EndTime:                    makeTime(11),
+			Location:                   models.NewLocation(time.UTC),
+			NumberOfCalls:              10,
+			Priority:                   1,
+		},
+	}
+
+	_, err := scheduler.GenerateSchedule(input, scheduler.WithCapacity(6))
+	require.NoError(t, err)
+
+	assert.Equal(t, 10.0, testutil.ToFloat64(metrics.HourlyAgentsDemanded.WithLabelValues("10")))
+	assert.Equal(t, 6.0, testutil.ToFloat64(metrics.HourlyAgentsAllocated.WithLabelValues("10")))
+	assert.Equal(t, 4.0, testutil.ToFloat64(metrics.HourlyAgentsUnmet.WithLabelValues("10")))
+	assert.Equal(t, 0.0, testutil.ToFloat64(metrics.HourlyAgentsDemanded.WithLabelValues("11")))
+	assert.Equal(t, 24, testutil.CollectAndCount(metrics.HourlyAgentsDemanded))
+}
+
+func TestGenerateScheduleContext_Spans(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	makeTime := func(hour int) time.Time {
+		now := time.Now().UTC()
+		return time.Date(now.Year(), now.Month(), now.Day(), hour, 0, 0, 0, time.UTC)
+	}
+
+	input := []models.CallData{
+		{CustomerName: "A", AverageCallDurationSeconds: 3600, StartTime: makeTime(9), EndTime: makeTime(10), Location: models.NewLocation(time.UTC), NumberOfCalls: 1, Priority: 1},
+		{CustomerName: "B", AverageCallDurationSeconds: 3600, StartTime: makeTime(9), EndTime: makeTime(10), Location: models.NewLocation(time.UTC), NumberOfCalls: 1, Priority: 2},
+	}
+
+	tracing.SetDebug(true)
+	defer tracing.SetDebug(false)
+	_, err := scheduler.GenerateScheduleContext(context.Background(), input, scheduler.WithCapacity(1))
+	assert.NoError(t, err)
+
+	names := make(map[string]int)
+	for _, s := range recorder.Ended() {
+		names[s.Name()]++
+	}
+	assert.Equal(t, 1, names["scheduler.GenerateSchedule"])
+	assert.Equal(t, 2, names["scheduler.expandCustomer"], "one child span per customer in debug mode")
+	assert.Equal(t, 1, names["scheduler.allocate"])
+}
+
+func TestGenerateScheduleContext_Cancelled(t *testing.T) {
+	input := []models.CallData{
+		{CustomerName: "A", AverageCallDurationSeconds: 3600, StartTime: time.Now(), EndTime: time.Now().Add(time.Hour), Location: models.NewLocation(time.UTC), NumberOfCalls: 1, Priority: 1},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	sched, err := scheduler.GenerateScheduleContext(ctx, input)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Nil(t, sched)
+}
+
+func TestGenerateScheduleContext_Errors(t *testing.T) {
+	input := []models.CallData{
+		{CustomerName: "A", AverageCallDurationSeconds: 3600, StartTime: time.Now(), EndTime: time.Now().Add(time.Hour), Location: models.NewLocation(time.UTC), NumberOfCalls: 1, Priority: 1},
+	}
+
+	tests := map[string]struct {
+		input          []models.CallData
+		opts           []scheduler.Option
+		wantErr        error
+		wantConstraint string
+	}{
+		"EmptyInput": {
+			wantErr: customerrors.ErrEmptyInput,
+		},
+		"ZeroDuration": {
+			input:   []models.CallData{input[0], {CustomerName: "B", StartTime: input[0].StartTime, EndTime: input[0].EndTime, Location: input[0].Location, NumberOfCalls: 1, Priority: 1}},
+			wantErr: customerrors.ErrInvalidDuration,
+		},
+		"EmptyWindow": {
+			input:   []models.CallData{{CustomerName: "B", AverageCallDurationSeconds: 60, StartTime: input[0].StartTime, EndTime: input[0].StartTime, Location: input[0].Location, NumberOfCalls: 1, Priority: 1}},
+			wantErr: customerrors.ErrInvalidWindow,
+		},
+		"RowSLATargetAbove100": {
+			input:   []models.CallData{{CustomerName: "B", AverageCallDurationSeconds: 60, StartTime: input[0].StartTime, EndTime: input[0].EndTime, Location: input[0].Location, NumberOfCalls: 1, Priority: 1, SLATargetPercent: 150, SLAThresholdSeconds: 20}},
+			opts:    []scheduler.Option{scheduler.WithErlangC(80, 20)},
+			wantErr: customerrors.ErrInvalidSLA,
+		},
+		"RowSLATargetNaN": {
+			input:   []models.CallData{{CustomerName: "B", AverageCallDurationSeconds: 60, StartTime: input[0].StartTime, EndTime: input[0].EndTime, Location: input[0].Location, NumberOfCalls: 1, Priority: 1, SLATargetPercent: math.NaN(), SLAThresholdSeconds: 20}},
+			opts:    []scheduler.Option{scheduler.WithErlangC(80, 20)},
+			wantErr: customerrors.ErrInvalidSLA,
+		},
+		"ZeroUtilization": {
+			input:          input,
+			opts:           []scheduler.Option{scheduler.WithUtilization(0)},
+			wantErr:        customerrors.ErrInvalidUtilization,
+			wantConstraint: "utilization",
+		},
+		"NaNUtilization": {
+			input:          input,
+			opts:           []scheduler.Option{scheduler.WithUtilization(math.NaN())},
+			wantErr:        customerrors.ErrInvalidUtilization,
+			wantConstraint: "utilization",
+		},
+		"UtilizationAboveOne": {
+			input:          input,
+			opts:           []scheduler.Option{scheduler.WithUtilization(1.5)},
+			wantErr:        customerrors.ErrInvalidUtilization,
+			wantConstraint: "utilization",
+		},
+		"NegativeCapacity": {
+			input:          input,
+			opts:           []scheduler.Option{scheduler.WithCapacity(-1)},
+			wantErr:        customerrors.ErrInfeasibleConstraints,
+			wantConstraint: "capacity",
+		},
+		"NegativeProfileHour": {
+			input:          input,
+			opts:           []scheduler.Option{scheduler.WithCapacityProfile([]int{5, -2})},
+			wantErr:        customerrors.ErrInfeasibleConstraints,
+			wantConstraint: "capacity_profile[1]",
+		},
+		"ProfileTooLong": {
+			input:          input,
+			opts:           []scheduler.Option{scheduler.WithCapacityProfile(make([]int, 25))},
+			wantErr:        customerrors.ErrInfeasibleConstraints,
+			wantConstraint: "capacity_profile",
+		},
+		"MaxOccupancyAboveOne": {
+			input:          input,
+			opts:           []scheduler.Option{scheduler.WithMaxOccupancy(90)},
+			wantErr:        customerrors.ErrInfeasibleConstraints,
+			wantConstraint: "max_occupancy",
+		},
+		"NaNMaxOccupancy": {
+			input:          input,
+			opts:           []scheduler.Option{scheduler.WithMaxOccupancy(math.NaN())},
+			wantErr:        customerrors.ErrInfeasibleConstraints,
+			wantConstraint: "max_occupancy",
+		},
+		"SmoothingWindowZero": {
+			input:          input,
+			opts:           []scheduler.Option{scheduler.WithSmoothing(0, 10)},
+			wantErr:        customerrors.ErrInfeasibleConstraints,
+			wantConstraint: "smoothing_window",
+		},
+		"IntervalNotDividingHour": {
+			input:          input,
+			opts:           []scheduler.Option{scheduler.WithInterval(25 * time.Minute)},
+			wantErr:        customerrors.ErrInfeasibleConstraints,
+			wantConstraint: "interval",
+		},
+		"SubMinuteInterval": {
+			input:          input,
+			opts:           []scheduler.Option{scheduler.WithInterval(30 * time.Second)},
+			wantErr:        customerrors.ErrInfeasibleConstraints,
+			wantConstraint: "interval",
+		},
+		"FullSLATarget": {
+			input:          input,
+			opts:           []scheduler.Option{scheduler.WithErlangC(100, 20)},
+			wantErr:        customerrors.ErrInvalidSLA,
+			wantConstraint: "sla_target",
+		},
+		"NaNSLATarget": {
+			input:          input,
+			opts:           []scheduler.Option{scheduler.WithErlangC(math.NaN(), 20)},
+			wantErr:        customerrors.ErrInvalidSLA,
+			wantConstraint: "sla_target",
+		},
+		"ZeroSLAThreshold": {
+			input:          input,
+			opts:           []scheduler.Option{scheduler.WithErlangC(80, 0)},
+			wantErr:        customerrors.ErrInvalidSLA,
+			wantConstraint: "sla_threshold_seconds",
+		},
+		"CalendarWithoutDates": {
+			input:          input,
+			opts:           []scheduler.Option{scheduler.WithCalendar(models.Calendar{{Name: "Holiday"}})},
+			wantErr:        customerrors.ErrInfeasibleConstraints,
+			wantConstraint: "calendar",
+		},
+		"PoissonFullPercentile": {
+			input:          input,
+			opts:           []scheduler.Option{scheduler.WithPoisson(100)},
+			wantErr:        customerrors.ErrInfeasibleConstraints,
+			wantConstraint: "poisson_percentile",
+		},
+		"NaNPoissonPercentile": {
+			input:          input,
+			opts:           []scheduler.Option{scheduler.WithPoisson(math.NaN())},
+			wantErr:        customerrors.ErrInfeasibleConstraints,
+			wantConstraint: "poisson_percentile",
+		},
+		"PoissonWithErlangC": {
+			input:          input,
+			opts:           []scheduler.Option{scheduler.WithPoisson(95), scheduler.WithErlangC(80, 20)},
+			wantErr:        customerrors.ErrInfeasibleConstraints,
+			wantConstraint: "staffing_model",
+		},
+		"AgentPoolsWithCapacity": {
+			input:          input,
+			opts:           []scheduler.Option{scheduler.WithAgentPools([]models.AgentPool{{Name: "All", Headcount: 5}}), scheduler.WithCapacity(5)},
+			wantErr:        customerrors.ErrInfeasibleConstraints,
+			wantConstraint: "agent_pools",
+		},
+		"AgentPoolsWithProportional": {
+			input:          input,
+			opts:           []scheduler.Option{scheduler.WithAllocator(scheduler.ProportionalAllocator{}), scheduler.WithAgentPools([]models.AgentPool{{Name: "All", Headcount: 5}})},
+			wantErr:        customerrors.ErrInfeasibleConstraints,
+			wantConstraint: "allocator",
+		},
+		"ReservedOverFull": {
+			input:          input,
+			opts:           []scheduler.Option{scheduler.WithReservedCapacity(map[models.Priority]float64{1: 60, 2: 50})},
+			wantErr:        customerrors.ErrInfeasibleConstraints,
+			wantConstraint: "reserved_capacity",
+		},
+		"ReservedNaN": {
+			input:          input,
+			opts:           []scheduler.Option{scheduler.WithReservedCapacity(map[models.Priority]float64{1: math.NaN()})},
+			wantErr:        customerrors.ErrInfeasibleConstraints,
+			wantConstraint: "reserved_capacity[1]",
+		},
+		"ReservedInvalidPriority": {
+			input:          input,
+			opts:           []scheduler.Option{scheduler.WithReservedCapacity(map[models.Priority]float64{11: 10})},
+			wantErr:        customerrors.ErrInfeasibleConstraints,
+			wantConstraint: "reserved_capacity[11]",
+		},
+		"OptimalWeightZero": {
+			input:          input,
+			opts:           []scheduler.Option{scheduler.WithAllocator(scheduler.OptimalAllocator{Weights: map[models.Priority]float64{2: 0}})},
+			wantErr:        customerrors.ErrInfeasibleConstraints,
+			wantConstraint: "allocator_weights[2]",
+		},
+		"OptimalWeightInfinite": {
+			input:          input,
+			opts:           []scheduler.Option{scheduler.WithAllocator(scheduler.OptimalAllocator{Weights: map[models.Priority]float64{1: math.Inf(1)}})},
+			wantErr:        customerrors.ErrInfeasibleConstraints,
+			wantConstraint: "allocator_weights[1]",
+		},
+		"NaNPeakedness": {
+			input:          input,
+			opts:           []scheduler.Option{scheduler.WithPeakedness(math.NaN())},
+			wantErr:        customerrors.ErrInfeasibleConstraints,
+			wantConstraint: "peakedness",
+		},
+		"InfinitePeakedness": {
+			input:          input,
+			opts:           []scheduler.Option{scheduler.WithPeakedness(math.Inf(1))},
+			wantErr:        customerrors.ErrInfeasibleConstraints,
+			wantConstraint: "peakedness",
+		},
+		"PeakednessBelowOne": {
+			input:          input,
+			opts:           []scheduler.Option{scheduler.WithPeakedness(0.8)},
+			wantErr:        customerrors.ErrInfeasibleConstraints,
+			wantConstraint: "peakedness",
+		},
+		"FlatArrivalCurve": {
+			input:          input,
+			opts:           []scheduler.Option{scheduler.WithArrivalCurves(models.ArrivalCurves{"Acme": {0, 0}})},
+			wantErr:        customerrors.ErrInfeasibleConstraints,
+			wantConstraint: "arrival_curves[Acme]",
+		},
+		"NaNArrivalShare": {
+			input:          input,
+			opts:           []scheduler.Option{scheduler.WithArrivalCurves(models.ArrivalCurves{"Acme": {1, math.NaN()}})},
+			wantErr:        customerrors.ErrInfeasibleConstraints,
+			wantConstraint: "arrival_curves[Acme]",
+		},
+		"EmptyAgentPools": {
+			input:          input,
+			opts:           []scheduler.Option{scheduler.WithAgentPools([]models.AgentPool{{Name: "All"}})},
+			wantErr:        customerrors.ErrInfeasibleConstraints,
+			wantConstraint: "agent_pools",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			sched, err := scheduler.GenerateScheduleContext(context.Background(), tt.input, tt.opts...)
+			assert.Nil(t, sched)
+			assert.ErrorIs(t, err, tt.wantErr)
+
+			var cve *customerrors.ConstraintViolationError
+			if tt.wantConstraint == "" {
+				assert.False(t, errors.As(err, &cve))
+				return
+			}
+			if assert.True(t, errors.As(err, &cve)) {
+				assert.Equal(t, tt.wantConstraint, cve.Constraint)
+			}
+		})
+	}
+}
+
+func TestGenerateScheduleContext_Concurrent(t *testing.T) {
+	makeInput := func(calls int) []models.CallData {
+		now := time.Now().UTC()
+		return []models.CallData{{
+			CustomerName:               fmt.Sprintf("Calls%d", calls),
+			AverageCallDurationSeconds: 3600,
+			StartTime:                  time.Date(now.Year(), now.Month(), now.Day(), 9, 0, 0, 0, time.UTC),
+			EndTime:                    time.Date(now.Year(), now.Month(), now.Day(), 10, 0, 0, 0, time.UTC),
+			Location:                   models.NewLocation(time.UTC),
+			NumberOfCalls:              calls,
+			Priority:                   1,
+		}}
+	}
+
+	var wg sync.WaitGroup
+	for i := range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			calls := 10 + i%2*10 // alternate between 10 and 20 agents
+			sched, err := scheduler.GenerateScheduleContext(context.Background(), makeInput(calls), scheduler.WithCapacity(15))
+			if assert.NoError(t, err) {
+				assert.Equal(t, min(calls, 15), sched.TotalAgents())
+			}
+		}()
+	}
+	wg.Wait()
+
+	// The gauges must describe one complete run, not a mix of several
+	demanded := testutil.ToFloat64(metrics.AgentsDemandedTotal)
+	assert.Contains(t, []float64{10, 20}, demanded)
+	assert.Equal(t, demanded, testutil.ToFloat64(metrics.AgentsAllocatedTotal)+testutil.ToFloat64(metrics.AgentsUnmetTotal))
+	assert.Equal(t, 1, testutil.CollectAndCount(metrics.RunInfo))
+}
+
+func TestGenerateSchedule_RunResetPolicy(t *testing.T) {
+	makeTime := func(hour int) time.Time {
+		now := time.Now().UTC()
+		return time.Date(now.Year(), now.Month(), now.Day(), hour, 0, 0, 0, time.UTC)
+	}
+
+	input := []models.CallData{
+		{CustomerName: "VIP", AverageCallDurationSeconds: 3600, StartTime: makeTime(9), EndTime: makeTime(10), Location: models.NewLocation(time.UTC), NumberOfCalls: 1, Priority: 1},
+	}
+
+	tests := map[string]struct {
+		policy         metrics.ResetPolicy
+		expectedSeries int
+	}{
+		"PerRun_KeepsLatestOnly": {policy: metrics.ResetPerRun, expectedSeries: 1},
+		"Never_KeepsEveryRun":    {policy: metrics.ResetNever, expectedSeries: 2},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			metrics.RequestsSatisfaction.Reset()
+			metrics.SetResetPolicy(tt.policy)
+			defer metrics.SetResetPolicy(metrics.ResetPerRun)
+
+			_, err := scheduler.GenerateSchedule(input, scheduler.WithCapacity(5))
+			require.NoError(t, err)
+			_, err = scheduler.GenerateSchedule(input, scheduler.WithCapacity(5))
+			require.NoError(t, err)
+
+			runID := metrics.CurrentRunID()
+			assert.NotEmpty(t, runID)
+			assert.Equal(t, tt.expectedSeries, testutil.CollectAndCount(metrics.RequestsSatisfaction))
+			assert.Equal(t, 1.0, testutil.ToFloat64(metrics.RequestsSatisfaction.WithLabelValues("1", metrics.OutcomeFull, runID)))
+			assert.Equal(t, 1, testutil.CollectAndCount(metrics.RunInfo))
+		})
+	}
+}
+
+func TestGenerateSchedule_FulfillmentMetrics(t *testing.T) {
+	makeTime := func(hour int) time.Time {
+		now := time.Now().UTC()
+		return time.Date(now.Year(), now.Month(), now.Day(), hour, 0, 0, 0, time.UTC)
+	}
+
+	input := []models.CallData{
+		// 10 agents at hours 9 and 10
+		{CustomerName: "VIP", AverageCallDurationSeconds: 3600, StartTime: makeTime(9), EndTime: makeTime(11), Location: models.NewLocation(time.UTC), NumberOfCalls: 20, Priority: 1},
+		// 10 more agents at hour 10 only
+		{CustomerName: "Basic", AverageCallDurationSeconds: 3600, StartTime: makeTime(10), EndTime: makeTime(11), Location: models.NewLocation(time.UTC), NumberOfCalls: 10, Priority: 2},
+	}
+
+	// Capacity 10: hour 9 fully met (10/10), hour 10 half met (10/20)
+	_, err := scheduler.GenerateSchedule(input, scheduler.WithCapacity(10))
+	require.NoError(t, err)
+
+	assert.Equal(t, 30.0, testutil.ToFloat64(metrics.AgentsDemandedTotal))
+	assert.InDelta(t, 20.0/30.0*100, testutil.ToFloat64(metrics.DemandFulfillmentPercent), 0.001)
+	assert.Equal(t, 100.0, testutil.ToFloat64(metrics.HighPriorityFulfillmentPercent))
+	assert.Equal(t, 50.0, testutil.ToFloat64(metrics.WorstHourFulfillmentPercent))
+
+	// Hour 10 is the peak with 20 demanded, 10 unmet
+	assert.Equal(t, 10.0, testutil.ToFloat64(metrics.PeakHour))
+	assert.Equal(t, 20.0, testutil.ToFloat64(metrics.PeakHourAgentsDemanded))
+	assert.Equal(t, 10.0, testutil.ToFloat64(metrics.PeakHourAgentsUnmet))
+
+	// Without demand there is no peak
+	_, err = scheduler.EmptySchedule(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, -1.0, testutil.ToFloat64(metrics.PeakHour))
+	assert.Zero(t, testutil.ToFloat64(metrics.PeakHourAgentsDemanded))
+}
+
+func TestGenerateSchedule_SatisfactionByPriority(t *testing.T) {
+	makeTime := func(hour int) time.Time {
+		now := time.Now().UTC()
+		return time.Date(now.Year(), now.Month(), now.Day(), hour, 0, 0, 0, time.UTC)
+	}
+
+	input := []models.CallData{
+		{CustomerName: "P1", AverageCallDurationSeconds: 3600, StartTime: makeTime(9), EndTime: makeTime(10), Location: models.NewLocation(time.UTC), NumberOfCalls: 4, Priority: 1},
+		{CustomerName: "P2", AverageCallDurationSeconds: 3600, StartTime: makeTime(9), EndTime: makeTime(10), Location: models.NewLocation(time.UTC), NumberOfCalls: 4, Priority: 2},
+		{CustomerName: "P3", AverageCallDurationSeconds: 3600, StartTime: makeTime(9), EndTime: makeTime(10), Location: models.NewLocation(time.UTC), NumberOfCalls: 4, Priority: 3},
+	}
+
+	// Capacity 6: P1 full, P2 partial (2 of 4), P3 none
+	_, err := scheduler.GenerateSchedule(input, scheduler.WithCapacity(6))
+	require.NoError(t, err)
+	runID := metrics.CurrentRunID()
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(metrics.RequestsSatisfaction.WithLabelValues("1", metrics.OutcomeFull, runID)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(metrics.RequestsSatisfaction.WithLabelValues("2", metrics.OutcomePartial, runID)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(metrics.RequestsSatisfaction.WithLabelValues("3", metrics.OutcomeNone, runID)))
+}
+
+func TestGenerateSchedule_LocationMetrics(t *testing.T) {
+	ny, _ := time.LoadLocation("America/New_York")
+	la, _ := time.LoadLocation("America/Los_Angeles")
+	makeTime := func(hour int, loc *time.Location) time.Time {
+		now := time.Now().In(loc)
+		return time.Date(now.Year(), now.Month(), now.Day(), hour, 0, 0, 0, loc)
+	}
+
+	input := []models.CallData{
+		{CustomerName: "East", AverageCallDurationSeconds: 3600, StartTime: makeTime(9, ny), EndTime: makeTime(10, ny), Location: models.NewLocation(ny), NumberOfCalls: 6, Priority: 1},
+		{CustomerName: "West", AverageCallDurationSeconds: 3600, StartTime: makeTime(9, la), EndTime: makeTime(10, la), Location: models.NewLocation(la), NumberOfCalls: 6, Priority: 2},
+	}
+
+	// Both land in local hour 9; capacity 8 leaves West 4 short
+	_, err := scheduler.GenerateSchedule(input, scheduler.WithCapacity(8))
+	require.NoError(t, err)
+
+	assert.Equal(t, 6.0, testutil.ToFloat64(metrics.LocationAgentsAllocated.WithLabelValues("America/New_York")))
+	assert.Equal(t, 0.0, testutil.ToFloat64(metrics.LocationAgentsUnmet.WithLabelValues("America/New_York")))
+	assert.Equal(t, 6.0, testutil.ToFloat64(metrics.LocationAgentsDemanded.WithLabelValues("America/Los_Angeles")))
+	assert.Equal(t, 2.0, testutil.ToFloat64(metrics.LocationAgentsAllocated.WithLabelValues("America/Los_Angeles")))
+	assert.Equal(t, 4.0, testutil.ToFloat64(metrics.LocationAgentsUnmet.WithLabelValues("America/Los_Angeles")))
+
+	// Without labeled metrics only the totals are published
+	metrics.EnablePerCustomerMetrics(10)
+	defer metrics.EnablePerCustomerMetrics(0)
+	_, err = scheduler.GenerateSchedule(input, scheduler.WithCapacity(8), scheduler.WithLabeledMetrics(false))
+	require.NoError(t, err)
+	assert.Equal(t, 8.0, testutil.ToFloat64(metrics.AgentsAllocatedTotal))
+	assert.Zero(t, testutil.CollectAndCount(metrics.LocationAgentsAllocated))
+	assert.Zero(t, testutil.CollectAndCount(metrics.CustomerAgentsDemanded))
+	assert.Zero(t, testutil.CollectAndCount(metrics.SkillAgentsUnmet))
+}
+
+func TestBuilder(t *testing.T) {
+	start := time.Date(2026, 3, 2, 6, 0, 0, 0, time.UTC)
+	var input []models.CallData
+	for i := range 40 {
+		input = append(input, models.CallData{
+			CustomerName:               fmt.Sprintf("Cust%02d", i),
+			AverageCallDurationSeconds: 300,
+			StartTime:                  start.Add(time.Duration(i%8) * time.Hour),
+			EndTime:                    start.Add(time.Duration(i%8+4) * time.Hour),
+			Location:                   models.NewLocation(time.UTC),
+			NumberOfCalls:              50 + i,
+			Priority:                   models.Priority(i%3 + 1),
+		})
+	}
+	opts := []scheduler.Option{scheduler.WithCapacity(12), scheduler.WithUtilization(0.8)}
+	want, err := scheduler.GenerateScheduleContext(context.Background(), input, opts...)
+	require.NoError(t, err)
+
+	for _, workers := range []int{1, 4} {
+		t.Run(fmt.Sprintf("Workers%d", workers), func(t *testing.T) {
+			b, err := scheduler.NewBuilder(context.Background(), append(opts, scheduler.WithWorkers(workers))...)
+			require.NoError(t, err)
+			for i := 0; i < len(input); i += 6 {
+				require.NoError(t, b.Add(input[i:min(i+6, len(input))]))
+			}
+			require.NoError(t, b.Add(nil))
+			got, err := b.Build()
+			require.NoError(t, err)
+
+			assert.Equal(t, want.HourlyRequirements, got.HourlyRequirements)
+			assert.Equal(t, want.UnmetDemands, got.UnmetDemands)
+			assert.Equal(t, scheduler.InputHash(input), got.Metadata.InputHash)
+			assert.Equal(t, want.Metadata.Options, got.Metadata.Options, "workers do not change the schedule")
+		})
+	}
+
+	t.Run("InvalidRow", func(t *testing.T) {
+		bad := slices.Clone(input)
+		bad[9].AverageCallDurationSeconds = 0
+		b, err := scheduler.NewBuilder(context.Background(), opts...)
+		require.NoError(t, err)
+		for i := 0; i < len(bad); i += 6 {
+			if err = b.Add(bad[i:min(i+6, len(bad))]); err != nil {
+				break
+			}
+		}
+		_, err = b.Build()
+		var rowErr *customerrors.RowError
+		require.ErrorAs(t, err, &rowErr)
+		assert.Equal(t, 9, rowErr.Row)
+		assert.Equal(t, "Cust09", rowErr.Customer)
+		assert.ErrorIs(t, err, customerrors.ErrInvalidDuration)
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		b, err := scheduler.NewBuilder(context.Background())
+		require.NoError(t, err)
+		_, err = b.Build()
+		assert.ErrorIs(t, err, customerrors.ErrEmptyInput)
+	})
+
+	t.Run("EmptySchedule", func(t *testing.T) {
+		got, err := scheduler.EmptySchedule(context.Background(), scheduler.WithCapacity(12), scheduler.WithInterval(30*time.Minute))
+		require.NoError(t, err)
+		assert.Equal(t, 48, got.NumSlots())
+		assert.Len(t, got.HourlyRequirements, 48)
+		assert.Zero(t, got.TotalDemand())
+		assert.Empty(t, got.UnmetDemands)
+		assert.NotEmpty(t, got.Metadata.RunID)
+		assert.Equal(t, "12", got.Metadata.Options["capacity"])
+
+		_, err = scheduler.EmptySchedule(context.Background(), scheduler.WithUtilization(2))
+		assert.ErrorIs(t, err, customerrors.ErrInvalidUtilization)
+	})
+
+	t.Run("Cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		b, err := scheduler.NewBuilder(ctx, scheduler.WithWorkers(2))
+		require.NoError(t, err)
+		cancel()
+		_ = b.Add(input)
+		_, err = b.Build()
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.ErrorIs(t, b.Add(input), context.Canceled, "failures are sticky")
+	})
+}
+
+func TestReplanner(t *testing.T) {
+	start := time.Date(2026, 3, 2, 6, 0, 0, 0, time.UTC)
+	var input []models.CallData
+	for i := range 40 {
+		input = append(input, models.CallData{
+			CustomerName:               fmt.Sprintf("Cust%02d", i),
+			AverageCallDurationSeconds: 300,
+			StartTime:                  start.Add(time.Duration(i%8) * time.Hour),
+			EndTime:                    start.Add(time.Duration(i%8+2) * time.Hour),
+			Location:                   models.NewLocation(time.UTC),
+			NumberOfCalls:              50 + i,
+			Priority:                   models.Priority(i%3 + 1),
+		})
+	}
+	opts := []scheduler.Option{scheduler.WithCapacity(12), scheduler.WithUtilization(0.8)}
+	r, err := scheduler.NewReplanner(opts...)
+	require.NoError(t, err)
+
+	check := func(input []models.CallData) scheduler.ReplanStats {
+		t.Helper()
+		got, err := r.Schedule(context.Background(), input)
+		require.NoError(t, err)
+		want, err := scheduler.GenerateSchedule(input, opts...)
+		require.NoError(t, err)
+		assert.True(t, want.Equal(got), "the schedule is the one generated from scratch")
+		assert.Equal(t, want.Metadata.InputHash, got.Metadata.InputHash)
+		return r.Stats()
+	}
+
+	stats := check(input)
+	assert.Equal(t, scheduler.ReplanStats{Rows: 40, Expanded: 40, Slots: 9, Reallocated: 9}, stats)
+
+	// One customer's forecast is revised: only its row is expanded, and only
+	// the two slots it covers are allocated
+	revised := slices.Clone(input)
+	revised[3].NumberOfCalls = 500
+	stats = check(revised)
+	assert.Equal(t, scheduler.ReplanStats{Rows: 40, Expanded: 1, Slots: 9, Reallocated: 2}, stats)
+
+	stats = check(revised)
+	assert.Equal(t, scheduler.ReplanStats{Rows: 40, Expanded: 0, Slots: 9, Reallocated: 0}, stats)
+
+	// Dropping a customer reallocates its slots too
+	stats = check(revised[1:])
+	assert.Equal(t, 0, stats.Expanded)
+	assert.Equal(t, 2, stats.Reallocated)
+
+	_, err = r.Schedule(context.Background(), nil)
+	assert.ErrorIs(t, err, customerrors.ErrEmptyInput)
+	_, err = scheduler.NewReplanner(scheduler.WithUtilization(2))
+	assert.Error(t, err)
+}
+
+func TestGenerateSchedule_Workers(t *testing.T) {
+	data := benchdata.CallData(20_000)
+	for name, opts := range map[string][]scheduler.Option{
+		"Unconstrained": nil,
+		"Capacity":      {scheduler.WithCapacity(500)},
+		"NoAggregation": {scheduler.WithAggregation(false), scheduler.WithCapacity(500)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			want, err := scheduler.GenerateSchedule(data, opts...)
+			require.NoError(t, err)
+			got, err := scheduler.GenerateSchedule(data, append(opts, scheduler.WithWorkers(8))...)
+			require.NoError(t, err)
+			assert.True(t, want.Equal(got), "workers do not change the schedule")
+			assert.Equal(t, want.Metadata.InputHash, got.Metadata.InputHash)
+		})
+	}
+}
+
+func TestGenerateSchedule_Aggregation(t *testing.T) {
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	row := func(name string, priority models.Priority, calls int) models.CallData {
+		return models.CallData{
+			CustomerName:               name,
+			AverageCallDurationSeconds: 360,
+			StartTime:                  start,
+			EndTime:                    start.Add(2 * time.Hour),
+			Location:                   models.NewLocation(time.UTC),
+			NumberOfCalls:              calls,
+			Priority:                   priority,
+		}
+	}
+	// Acme's priority-1 rows repeat across the feed
+	input := []models.CallData{row("Acme", 1, 100), row("Globex", 2, 40), row("Acme", 1, 60), row("Acme", 2, 20)}
+
+	separate, err := scheduler.GenerateSchedule(input, scheduler.WithAggregation(false))
+	require.NoError(t, err)
+	merged, err := scheduler.GenerateSchedule(input)
+	require.NoError(t, err)
+	assert.Equal(t, separate.TotalAgents(), merged.TotalAgents())
+	assert.Len(t, separate.HourlyRequirements[9], 4)
+	require.Len(t, merged.HourlyRequirements[9], 3, "rows differing only in agents are merged")
+	assert.Equal(t, "Acme", merged.HourlyRequirements[9][0].Name)
+	assert.Equal(t, 5+3, merged.HourlyRequirements[9][0].AgentsNeeded)
+	assert.NotContains(t, merged.Metadata.Options, "aggregation", "aggregation is the default")
+	assert.Equal(t, "false", separate.Metadata.Options["aggregation"])
+	assert.Equal(t, scheduler.InputHash(input), merged.Metadata.InputHash)
+
+	// The allocator sees Acme's merged request as one
+	capped, err := scheduler.GenerateSchedule(input, scheduler.WithCapacity(7))
+	require.NoError(t, err)
+	require.Len(t, capped.UnmetDemands, 2)
+	impacted := capped.UnmetDemands[0].ImpactedClients
+	require.Len(t, impacted, 3)
+	assert.Equal(t, 8, impacted[0].RequestedAgents)
+	assert.Equal(t, 7, impacted[0].AllocatedAgents)
+}
+
+func TestGenerateSchedule_BackToBack(t *testing.T) {
+	// Runs reuse pooled buffers; none may be shared with a returned schedule
+	// Some hours have capacity to spare and some fall short
+	profile := make([]int, 24)
+	for h := range profile {
+		profile[h] = 100 + h%2*1_000_000
+	}
+	first, err := scheduler.GenerateSchedule(benchdata.CallData(2_000), scheduler.WithCapacityProfile(profile), scheduler.WithWorkers(4))
+	require.NoError(t, err)
+	require.NotEmpty(t, first.UnmetDemands)
+	snapshot, err := json.Marshal(first)
+	require.NoError(t, err)
+
+	for _, n := range []int{500, 3_000, 2_000} {
+		for _, opts := range [][]scheduler.Option{
+			{scheduler.WithCapacity(50)},
+			{scheduler.WithCapacity(50), scheduler.WithWorkers(4)},
+			{scheduler.WithAggregation(false)},
+		} {
+			_, err := scheduler.GenerateSchedule(benchdata.CallData(n), opts...)
+			require.NoError(t, err)
+		}
+	}
+	after, err := json.Marshal(first)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(snapshot), string(after))
+	for _, reqs := range first.HourlyRequirements {
+		for _, req := range reqs {
+			require.NotEmpty(t, req.Name, "requirement recycled while still in the schedule")
+		}
+	}
+}
+
+func BenchmarkGenerateSchedule(b *testing.B) {
+	for _, n := range benchdata.Sizes {
+		b.Run(benchdata.Name(n), func(b *testing.B) {
+			data := benchdata.CallData(n)
+			b.ReportAllocs()
+			for b.Loop() {
+				if _, err := scheduler.GenerateScheduleContext(context.Background(), data, scheduler.WithCapacity(n/10)); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
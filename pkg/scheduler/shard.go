@@ -0,0 +1,195 @@
+package scheduler
+
+import (
+	"cmp"
+	"maps"
+	"runtime"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// wideHour is how many requests an hour needs before PriorityAllocator
+// shards its work across GOMAXPROCS goroutines; narrower hours are quicker
+// on one.
+const wideHour = 10_000
+
+// sharded reports whether requests are worth splitting across goroutines.
+// With a single P the shards would only run one after another, so the
+// merging is pure overhead.
+func sharded(requests []models.CustomerRequirement) bool {
+	return len(requests) >= wideHour && runtime.GOMAXPROCS(0) > 1
+}
+
+// compareRequests orders requests for allocation: by priority (1 = highest),
+// then alphabetically by name.
+func compareRequests(a, b *models.CustomerRequirement) int {
+	if a.Priority != b.Priority {
+		return cmp.Compare(a.Priority, b.Priority)
+	}
+	return compareByName(a, b)
+}
+
+// compareByName orders requests by name, breaking ties on every other field
+// so that only identical requests compare equal and any sort puts them in
+// the same order.
+func compareByName(a, b *models.CustomerRequirement) int {
+	if c := strings.Compare(a.Name, b.Name); c != 0 {
+		return c
+	}
+	// Ties are rare, so the remaining fields are compared all at once
+	return cmp.Or(
+		strings.Compare(a.Location.Name(), b.Location.Name()),
+		cmp.Compare(a.AgentsNeeded, b.AgentsNeeded),
+		cmp.Compare(a.SLATargetPercent, b.SLATargetPercent),
+		cmp.Compare(a.SLAThresholdSeconds, b.SLAThresholdSeconds),
+		strings.Compare(a.Skill, b.Skill),
+		cmp.Compare(a.Concurrency, b.Concurrency),
+		cmp.Compare(a.HourlyCost, b.HourlyCost),
+		cmp.Compare(a.MinAgents, b.MinAgents),
+		cmp.Compare(a.CeilingAgents, b.CeilingAgents),
+		cmp.Compare(a.FTE, b.FTE),
+	)
+}
+
+// sumDemand totals the agents requests need.
+func sumDemand(requests []models.CustomerRequirement) int {
+	if !sharded(requests) {
+		total := 0
+		for _, req := range requests {
+			total += req.AgentsNeeded
+		}
+		return total
+	}
+
+	shards := split(span{0, len(requests)}, runtime.GOMAXPROCS(0))
+	sums := make([]int, len(shards))
+	var g errgroup.Group
+	for i, s := range shards {
+		g.Go(func() error {
+			for _, req := range requests[s.lo:s.hi] {
+				sums[i] += req.AgentsNeeded
+			}
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	total := 0
+	for _, sum := range sums {
+		total += sum
+	}
+	return total
+}
+
+// sortRequests sorts requests by compareRequests.
+//
+// Wide hours are first bucketed by priority in one linear, stable pass,
+// which leaves only names to compare. The buckets are cut into shards that
+// are sorted concurrently and then merged pairwise, also concurrently.
+func sortRequests(requests []models.CustomerRequirement) {
+	if !sharded(requests) {
+		sort.Slice(requests, func(i, j int) bool {
+			return compareRequests(&requests[i], &requests[j]) < 0
+		})
+		return
+	}
+	workers := runtime.GOMAXPROCS(0)
+
+	counts := make(map[models.Priority]int)
+	for _, req := range requests {
+		counts[req.Priority]++
+	}
+	next := make(map[models.Priority]int, len(counts))
+	var shards []span
+	at := 0
+	for _, p := range slices.Sorted(maps.Keys(counts)) {
+		next[p] = at
+		shards = append(shards, split(span{at, at + counts[p]}, workers)...)
+		at += counts[p]
+	}
+	src := make([]models.CustomerRequirement, len(requests))
+	for _, req := range requests {
+		src[next[req.Priority]] = req
+		next[req.Priority]++
+	}
+
+	var g errgroup.Group
+	for _, s := range shards {
+		g.Go(func() error {
+			shard := src[s.lo:s.hi]
+			sort.Slice(shard, func(i, j int) bool {
+				return compareByName(&shard[i], &shard[j]) < 0
+			})
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	// Merge neighbouring shards of the same priority until each priority is
+	// one sorted run, alternating between the two buffers
+	dst := requests
+	for len(shards) > len(counts) {
+		var merged []span
+		for i := 0; i < len(shards); i++ {
+			a := shards[i]
+			if i+1 == len(shards) || src[shards[i+1].lo].Priority != src[a.lo].Priority {
+				g.Go(func() error {
+					copy(dst[a.lo:a.hi], src[a.lo:a.hi])
+					return nil
+				})
+				merged = append(merged, a)
+				continue
+			}
+			b := shards[i+1]
+			g.Go(func() error {
+				mergeByName(dst[a.lo:b.hi], src[a.lo:a.hi], src[b.lo:b.hi])
+				return nil
+			})
+			merged = append(merged, span{a.lo, b.hi})
+			i++
+		}
+		_ = g.Wait()
+		shards = merged
+		src, dst = dst, src
+	}
+	if &src[0] != &requests[0] {
+		copy(requests, src)
+	}
+}
+
+// mergeByName merges a and b, each sorted by compareByName, into dst.
+func mergeByName(dst, a, b []models.CustomerRequirement) {
+	i, j, k := 0, 0, 0
+	for i < len(a) && j < len(b) {
+		if compareByName(&b[j], &a[i]) < 0 {
+			dst[k] = b[j]
+			j++
+		} else {
+			dst[k] = a[i]
+			i++
+		}
+		k++
+	}
+	k += copy(dst[k:], a[i:])
+	copy(dst[k:], b[j:])
+}
+
+// span is the half-open index range [lo, hi).
+type span struct {
+	lo, hi int
+}
+
+// split cuts s into at most n near-equal, non-empty spans.
+func split(s span, n int) []span {
+	size := max((s.hi-s.lo+n-1)/n, 1)
+	var spans []span
+	for lo := s.lo; lo < s.hi; lo += size {
+		spans = append(spans, span{lo, min(lo+size, s.hi)})
+	}
+	return spans
+}
@@ -0,0 +1,117 @@
+package scheduler
+
+import (
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+)
+
+// ramp limits how fast a customer's agents may change: by at most maxDelta
+// between any two slots window or fewer slots apart.
+type ramp struct {
+	window   int
+	maxDelta int
+}
+
+// WithSmoothing limits how fast each customer's agents ramp up and down, as
+// real staffing cannot swing from 12 agents to 90 in one hour: between any
+// two slots at most window slots apart, its agents change by at most
+// maxDelta. Slots are topped up so that customers ramp into a peak ahead of
+// it and out of it gradually; no slot gets fewer agents than it needs, nor
+// more than the customer's CeilingAgents. The days of a schedule laid out by
+// date are smoothed as one run of slots. Capacity is shared out after
+// smoothing, so the extra agents compete for it like any others.
+func WithSmoothing(window, maxDelta int) Option {
+	return func(c *config) {
+		c.smoothing = &ramp{window: window, maxDelta: maxDelta}
+	}
+}
+
+// smooth tops up hourly, the schedule's requirements by slot, so that every
+// customer's agents keep to r. Requirements are told apart as with
+// WithAggregation; a slot where a customer has none gets a new one. With
+// fte, the agents added are added to FTE too.
+func (r ramp) smooth(hourly [][]models.CustomerRequirement, fte bool) {
+	// Each customer's agents in every slot, and where in the slot its first
+	// requirement is, plus one; keys are kept in the order first seen so the
+	// schedule comes out the same every run
+	demand := make(map[requirementKey][]int)
+	at := make(map[requirementKey][]int)
+	first := make(map[requirementKey]models.CustomerRequirement)
+	var keys []requirementKey
+	for h, reqs := range hourly {
+		for i, req := range reqs {
+			k := requirementKeyOf(req)
+			if _, ok := demand[k]; !ok {
+				demand[k] = make([]int, len(hourly))
+				at[k] = make([]int, len(hourly))
+				first[k] = req
+				keys = append(keys, k)
+			}
+			demand[k][h] += req.AgentsNeeded
+			if at[k][h] == 0 {
+				at[k][h] = i + 1
+			}
+		}
+	}
+
+	for _, k := range keys {
+		for h, agents := range r.envelope(demand[k]) {
+			if k.ceilingAgents > 0 {
+				agents = min(agents, k.ceilingAgents)
+			}
+			extra := agents - demand[k][h]
+			if extra <= 0 {
+				continue
+			}
+			if i := at[k][h]; i > 0 {
+				hourly[h][i-1].AgentsNeeded += extra
+				if fte {
+					hourly[h][i-1].FTE += float64(extra)
+				}
+				continue
+			}
+			req := first[k]
+			req.AgentsNeeded = extra
+			req.FTE = 0
+			if fte {
+				req.FTE = float64(extra)
+			}
+			hourly[h] = append(hourly[h], req)
+		}
+	}
+}
+
+// envelope returns the fewest agents per slot, no fewer than demand, that
+// keep to r: the most any slot's demand asks of it, less maxDelta for every
+// window slots or part of them in between.
+func (r ramp) envelope(demand []int) []int {
+	n := len(demand)
+	// forward[t] covers the slots up to t and backward[t] those from t on.
+	// Slots further than window away are reached through the slot window
+	// away, which already covers them
+	forward := make([]int, n)
+	for t := range n {
+		agents := demand[t]
+		for s := max(t-r.window+1, 0); s < t; s++ {
+			agents = max(agents, demand[s]-r.maxDelta)
+		}
+		if t >= r.window {
+			agents = max(agents, forward[t-r.window]-r.maxDelta)
+		}
+		forward[t] = agents
+	}
+	backward := make([]int, n)
+	for t := n - 1; t >= 0; t-- {
+		agents := demand[t]
+		for s := t + 1; s < min(t+r.window, n); s++ {
+			agents = max(agents, demand[s]-r.maxDelta)
+		}
+		if t+r.window < n {
+			agents = max(agents, backward[t+r.window]-r.maxDelta)
+		}
+		backward[t] = agents
+	}
+	for t := range n {
+		forward[t] = max(forward[t], backward[t])
+	}
+	return forward
+}
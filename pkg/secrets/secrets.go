@@ -0,0 +1,220 @@
+// Package secrets resolves credentials stored in HashiCorp Vault or Azure
+// Key Vault, so integrations can be configured with a reference to a secret
+// instead of the secret itself.
+//
+// References are URIs:
+//
+//   - vault://<mount>/<path>#<key>, a key of a KV version 2 secret, e.g.
+//     vault://secret/agent-scheduler/twilio#auth_token
+//   - azkv://<vault>/<secret>[/<version>], a Key Vault secret, where vault is
+//     a vault name in the public Azure cloud or a full host name, e.g.
+//     azkv://staffing-kv/twilio-auth-token
+//
+// Any other value is not a reference and resolves to itself.
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+)
+
+// keyVaultAPIVersion is the Key Vault REST API version used to read secrets.
+const keyVaultAPIVersion = "7.4"
+
+// IsReference reports whether value refers to a secret to resolve.
+func IsReference(value string) bool {
+	return strings.HasPrefix(value, "vault://") || strings.HasPrefix(value, "azkv://")
+}
+
+// Resolver resolves secret references, caching each resolved secret for the
+// life of the Resolver.
+type Resolver struct {
+	// VaultAddr and VaultToken locate and authenticate to Vault, e.g.
+	// https://vault.example.com:8200
+	VaultAddr  string
+	VaultToken string
+	// VaultNamespace is the Vault Enterprise namespace, if any
+	VaultNamespace string
+	// AzureCredential authenticates to Key Vault; defaults to the default
+	// Azure credential chain on first use
+	AzureCredential azcore.TokenCredential
+	// Client sends the requests; defaults to http.DefaultClient
+	Client *http.Client
+
+	mu    sync.Mutex
+	cache map[string]string
+}
+
+// NewResolver returns a Resolver configured from the standard VAULT_ADDR,
+// VAULT_TOKEN, and VAULT_NAMESPACE environment variables.
+func NewResolver() *Resolver {
+	return &Resolver{
+		VaultAddr:      os.Getenv("VAULT_ADDR"),
+		VaultToken:     os.Getenv("VAULT_TOKEN"),
+		VaultNamespace: os.Getenv("VAULT_NAMESPACE"),
+	}
+}
+
+// Resolve returns the secret value refers to, or value itself when it is not
+// a reference.
+func (r *Resolver) Resolve(ctx context.Context, value string) (string, error) {
+	if !IsReference(value) {
+		return value, nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if secret, ok := r.cache[value]; ok {
+		return secret, nil
+	}
+
+	u, err := url.Parse(value)
+	if err != nil {
+		return "", fmt.Errorf("invalid secret reference: %w", err)
+	}
+	var secret string
+	switch u.Scheme {
+	case "vault":
+		secret, err = r.vault(ctx, u)
+	case "azkv":
+		secret, err = r.keyVault(ctx, u)
+	}
+	if err != nil {
+		return "", err
+	}
+	if r.cache == nil {
+		r.cache = make(map[string]string)
+	}
+	r.cache[value] = secret
+	return secret, nil
+}
+
+// ResolveEnv replaces every environment variable holding a reference with
+// the secret it refers to, so code reading credentials from the environment
+// gets the secret.
+func (r *Resolver) ResolveEnv(ctx context.Context) error {
+	for _, kv := range os.Environ() {
+		name, value, _ := strings.Cut(kv, "=")
+		if !IsReference(value) {
+			continue
+		}
+		secret, err := r.Resolve(ctx, value)
+		if err != nil {
+			return fmt.Errorf("resolving %s: %w", name, err)
+		}
+		if err := os.Setenv(name, secret); err != nil {
+			return fmt.Errorf("resolving %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// vault reads a key of a KV version 2 secret.
+func (r *Resolver) vault(ctx context.Context, u *url.URL) (string, error) {
+	path := strings.Trim(u.Path, "/")
+	if u.Host == "" || path == "" || u.Fragment == "" {
+		return "", fmt.Errorf("invalid Vault reference %q: want vault://mount/path#key", u.Redacted())
+	}
+	if r.VaultAddr == "" {
+		return "", fmt.Errorf("resolving %s: VAULT_ADDR is not set", u.Redacted())
+	}
+	endpoint := strings.TrimSuffix(r.VaultAddr, "/") + "/v1/" + u.Host + "/data/" + path
+
+	var body struct {
+		Data struct {
+			Data map[string]any `json:"data"`
+		} `json:"data"`
+	}
+	err := r.getJSON(ctx, endpoint, func(req *http.Request) {
+		req.Header.Set("X-Vault-Token", r.VaultToken)
+		if r.VaultNamespace != "" {
+			req.Header.Set("X-Vault-Namespace", r.VaultNamespace)
+		}
+	}, &body)
+	if err != nil {
+		return "", fmt.Errorf("vault: reading %s/%s: %w", u.Host, path, err)
+	}
+	v, ok := body.Data.Data[u.Fragment].(string)
+	if !ok {
+		return "", fmt.Errorf("vault: secret %s/%s has no string key %q", u.Host, path, u.Fragment)
+	}
+	return v, nil
+}
+
+// keyVault reads a Key Vault secret, at its latest version unless one is
+// given.
+func (r *Resolver) keyVault(ctx context.Context, u *url.URL) (string, error) {
+	secret := strings.Trim(u.Path, "/")
+	if u.Host == "" || secret == "" {
+		return "", fmt.Errorf("invalid Key Vault reference %q: want azkv://vault/secret[/version]", u.Redacted())
+	}
+	host := u.Host
+	if !strings.Contains(host, ".") {
+		host += ".vault.azure.net"
+	}
+	// The token audience is the vault's DNS suffix, e.g. vault.azure.net
+	_, suffix, _ := strings.Cut(host, ".")
+
+	cred := r.AzureCredential
+	if cred == nil {
+		var err error
+		if cred, err = azidentity.NewDefaultAzureCredential(nil); err != nil {
+			return "", fmt.Errorf("azure credential: %w", err)
+		}
+		r.AzureCredential = cred
+	}
+	token, err := cred.GetToken(ctx, policy.TokenRequestOptions{Scopes: []string{"https://" + suffix + "/.default"}})
+	if err != nil {
+		return "", fmt.Errorf("key vault: getting token: %w", err)
+	}
+
+	endpoint := "https://" + host + "/secrets/" + secret + "?api-version=" + keyVaultAPIVersion
+	var body struct {
+		Value string `json:"value"`
+	}
+	err = r.getJSON(ctx, endpoint, func(req *http.Request) {
+		req.Header.Set("Authorization", "Bearer "+token.Token)
+	}, &body)
+	if err != nil {
+		return "", fmt.Errorf("key vault: reading %s from %s: %w", secret, host, err)
+	}
+	return body.Value, nil
+}
+
+// getJSON sends an authorized GET request and decodes the JSON response.
+func (r *Resolver) getJSON(ctx context.Context, endpoint string, authorize func(*http.Request), out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	authorize(req)
+
+	client := r.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("unexpected status %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decoding response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package secrets_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/karthikrao-23/agentscheduler/pkg/secrets"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newVault(t *testing.T) (*httptest.Server, *int) {
+	t.Helper()
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("X-Vault-Token") != "root" {
+			http.Error(w, `{"errors":["permission denied"]}`, http.StatusForbidden)
+			return
+		}
+		if r.URL.Path != "/v1/secret/data/agent-scheduler/twilio" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, `{"data":{"data":{"auth_token":"tw-token","account_sid":"AC123"},"metadata":{"version":3}}}`)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &requests
+}
+
+func TestResolveVault(t *testing.T) {
+	srv, requests := newVault(t)
+	r := &secrets.Resolver{VaultAddr: srv.URL, VaultToken: "root"}
+	ctx := context.Background()
+
+	tests := map[string]struct {
+		value string
+		want  string
+		err   string
+	}{
+		"Key":        {value: "vault://secret/agent-scheduler/twilio#auth_token", want: "tw-token"},
+		"OtherKey":   {value: "vault://secret/agent-scheduler/twilio#account_sid", want: "AC123"},
+		"Plain":      {value: "not-a-reference", want: "not-a-reference"},
+		"MissingKey": {value: "vault://secret/agent-scheduler/twilio#password", err: `no string key "password"`},
+		"NoFragment": {value: "vault://secret/agent-scheduler/twilio", err: "want vault://mount/path#key"},
+		"NotFound":   {value: "vault://secret/other#key", err: "404"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := r.Resolve(ctx, tt.value)
+			if tt.err != "" {
+				assert.ErrorContains(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	before := *requests
+	_, err := r.Resolve(ctx, "vault://secret/agent-scheduler/twilio#auth_token")
+	require.NoError(t, err)
+	assert.Equal(t, before, *requests, "resolved secrets are cached")
+
+	_, err = (&secrets.Resolver{VaultAddr: srv.URL, VaultToken: "wrong"}).Resolve(ctx, "vault://secret/agent-scheduler/twilio#auth_token")
+	assert.ErrorContains(t, err, "permission denied")
+}
+
+type fakeCredential struct{}
+
+func (fakeCredential) GetToken(context.Context, policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	return azcore.AccessToken{Token: "kv-token", ExpiresOn: time.Now().Add(time.Hour)}, nil
+}
+
+func TestResolveKeyVault(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer kv-token", r.Header.Get("Authorization"))
+		assert.Equal(t, "7.4", r.URL.Query().Get("api-version"))
+		switch r.URL.Path {
+		case "/secrets/grafana-token":
+			fmt.Fprint(w, `{"value":"latest","id":"https://kv/secrets/grafana-token/abc"}`)
+		case "/secrets/grafana-token/v1":
+			fmt.Fprint(w, `{"value":"pinned"}`)
+		default:
+			http.Error(w, `{"error":{"code":"SecretNotFound"}}`, http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	r := &secrets.Resolver{AzureCredential: fakeCredential{}, Client: srv.Client()}
+	host := strings.TrimPrefix(srv.URL, "https://")
+	ctx := context.Background()
+
+	got, err := r.Resolve(ctx, "azkv://"+host+"/grafana-token")
+	require.NoError(t, err)
+	assert.Equal(t, "latest", got)
+	got, err = r.Resolve(ctx, "azkv://"+host+"/grafana-token/v1")
+	require.NoError(t, err)
+	assert.Equal(t, "pinned", got)
+	_, err = r.Resolve(ctx, "azkv://"+host+"/missing")
+	assert.ErrorContains(t, err, "SecretNotFound")
+}
+
+func TestResolveEnv(t *testing.T) {
+	srv, _ := newVault(t)
+	t.Setenv("TWILIO_AUTH_TOKEN", "vault://secret/agent-scheduler/twilio#auth_token")
+	t.Setenv("TWILIO_ACCOUNT_SID", "AC-plain")
+
+	r := &secrets.Resolver{VaultAddr: srv.URL, VaultToken: "root"}
+	require.NoError(t, r.ResolveEnv(context.Background()))
+	assert.Equal(t, "tw-token", os.Getenv("TWILIO_AUTH_TOKEN"))
+	assert.Equal(t, "AC-plain", os.Getenv("TWILIO_ACCOUNT_SID"))
+
+	t.Setenv("GRAFANA_TOKEN", "vault://secret/missing#token")
+	assert.ErrorContains(t, r.ResolveEnv(context.Background()), "resolving GRAFANA_TOKEN")
+}
@@ -0,0 +1,419 @@
+// Package server exposes the scheduler over HTTP ("serve mode"), so other
+// services can request schedules without shelling out to the command line.
+// The API is described by the OpenAPI document served at /openapi.yaml; the
+// client package is a typed Go client for it.
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/karthikrao-23/agentscheduler/pkg/formatter"
+	"github.com/karthikrao-23/agentscheduler/pkg/ics"
+	"github.com/karthikrao-23/agentscheduler/pkg/metrics"
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+	"github.com/karthikrao-23/agentscheduler/pkg/parser"
+	"github.com/karthikrao-23/agentscheduler/pkg/scheduler"
+)
+
+// OpenAPI is the OpenAPI 3 document describing the server's API.
+//
+//go:embed openapi.yaml
+var OpenAPI []byte
+
+// DefaultMaxBodyBytes bounds request bodies unless WithMaxBodyBytes is given.
+const DefaultMaxBodyBytes = 10 << 20
+
+// ScheduleRequest is the JSON body of POST /v1/schedules. Zero options leave
+// the server's defaults in place.
+type ScheduleRequest struct {
+	CallData        []models.CallData `json:"call_data"`
+	Utilization     float64           `json:"utilization,omitempty"`
+	Capacity        int               `json:"capacity,omitempty"`
+	CapacityProfile []int             `json:"capacity_profile,omitempty"`
+}
+
+// Error is the JSON body of a failed request.
+type Error struct {
+	Error string `json:"error"`
+}
+
+// Option configures a Server.
+type Option func(*config)
+
+type config struct {
+	scheduleOptions []scheduler.Option
+	maxBodyBytes    int64
+	logger          *slog.Logger
+	cache           Cache
+	onPublish       func(*models.Schedule)
+	trigger         *TriggerConfig
+	tenants         []Tenant
+}
+
+// Cache stores generated schedules by scheduler.CacheKey, so repeated
+// requests for the same input and options skip generation. Get returns nil
+// without an error on a miss.
+type Cache interface {
+	Get(ctx context.Context, key string) (*models.Schedule, error)
+	Set(ctx context.Context, key string, schedule *models.Schedule) error
+}
+
+// WithScheduleOptions sets the defaults every schedule is generated with.
+// Options in a request override them.
+func WithScheduleOptions(opts ...scheduler.Option) Option {
+	return func(c *config) {
+		c.scheduleOptions = opts
+	}
+}
+
+// WithMaxBodyBytes bounds the size of request bodies. Defaults to
+// DefaultMaxBodyBytes.
+func WithMaxBodyBytes(n int64) Option {
+	return func(c *config) {
+		c.maxBodyBytes = n
+	}
+}
+
+// WithCache serves repeated schedule requests from cache. Cache failures are
+// logged and the schedule is generated as if there were no cache.
+func WithCache(cache Cache) Option {
+	return func(c *config) {
+		c.cache = cache
+	}
+}
+
+// WithOnPublish calls fn with every schedule the server publishes as its
+// latest, e.g. to forward it to other systems. fn runs before the request
+// that produced the schedule is answered, so it should be quick.
+func WithOnPublish(fn func(*models.Schedule)) Option {
+	return func(c *config) {
+		c.onPublish = fn
+	}
+}
+
+// WithLogger sets the logger for failed requests. Defaults to slog.Default().
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *config) {
+		c.logger = logger
+	}
+}
+
+// Server serves the scheduling API. The zero value is not usable; create one
+// with New.
+type Server struct {
+	cfg config
+	mux *http.ServeMux
+
+	mu sync.RWMutex
+	// latest holds each tenant's latest schedule by name; a single-tenant
+	// server's is under ""
+	latest map[string]*models.Schedule
+
+	replanMu sync.Mutex
+	// replanners holds each profile's Replanner by tenant and profile name,
+	// so a profile triggered again only redoes what its input changed
+	replanners map[string]*replanner
+}
+
+// New returns a Server with its routes registered.
+func New(opts ...Option) *Server {
+	cfg := config{maxBodyBytes: DefaultMaxBodyBytes, logger: slog.Default()}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	s := &Server{cfg: cfg, mux: http.NewServeMux(), latest: make(map[string]*models.Schedule), replanners: make(map[string]*replanner)}
+	s.handle("POST /v1/schedules", "/v1/schedules", s.tenanted("/v1/schedules", s.createSchedule))
+	s.handle("GET /v1/schedules/latest", "/v1/schedules/latest", s.tenanted("/v1/schedules/latest", s.getLatestSchedule))
+	s.handle("POST /trigger", "/trigger", s.tenanted("/trigger", s.trigger))
+	s.handle("GET /calendar/{site...}", "/calendar/{site}.ics", s.tenanted("/calendar/{site}.ics", s.getCalendar))
+	s.handle("GET /openapi.yaml", "/openapi.yaml", s.getOpenAPI)
+	s.handle("GET /healthz", "/healthz", s.getHealth)
+	return s
+}
+
+// Handle registers an additional handler, e.g. /metrics, on the server's mux.
+func (s *Server) Handle(pattern string, h http.Handler) {
+	s.mux.Handle(pattern, h)
+}
+
+// HandleWithToken is like Handle, but requests must carry token as a bearer
+// token. Use it for /metrics on a multi-tenant server, whose process-wide
+// metrics describe whichever tenant ran last.
+func (s *Server) HandleWithToken(pattern, token string, h http.Handler) {
+	s.mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+		bearer, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || token == "" || subtle.ConstantTimeCompare([]byte(bearer), []byte(token)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="agent-scheduler"`)
+			s.writeError(w, http.StatusUnauthorized, errors.New("missing or invalid bearer token"))
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// Latest returns the most recently published schedule, or nil. On a
+// multi-tenant server, use LatestFor.
+func (s *Server) Latest() *models.Schedule {
+	return s.LatestFor("")
+}
+
+// LatestFor returns the named tenant's most recently published schedule, or
+// nil.
+func (s *Server) LatestFor(tenant string) *models.Schedule {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.latest[tenant]
+}
+
+// Publish makes schedule the latest schedule.
+func (s *Server) Publish(schedule *models.Schedule) {
+	s.publish(context.Background(), schedule)
+}
+
+// publish makes schedule the latest schedule of ctx's tenant.
+func (s *Server) publish(ctx context.Context, schedule *models.Schedule) {
+	tenant := tenantName(ctx)
+	s.mu.Lock()
+	s.latest[tenant] = schedule
+	s.mu.Unlock()
+	if tenant != "" {
+		metrics.RecordTenantSchedule(tenant, schedule.TotalAgents(), schedule.TotalUnmet())
+		return
+	}
+	if s.cfg.onPublish != nil {
+		s.cfg.onPublish(schedule)
+	}
+}
+
+// defaults returns the schedule options requests from ctx's tenant start
+// from: the server's, overridden by the tenant's.
+func (s *Server) defaults(ctx context.Context) []scheduler.Option {
+	t := tenantFromContext(ctx)
+	if t == nil {
+		return s.cfg.scheduleOptions
+	}
+	// The labeled gauges carry client names but no tenant, and /metrics is
+	// shared by every tenant
+	return slices.Concat(s.cfg.scheduleOptions, []scheduler.Option{scheduler.WithScenario(t.Defaults), scheduler.WithLabeledMetrics(false)})
+}
+
+func (s *Server) handle(pattern, route string, h http.HandlerFunc) {
+	s.mux.Handle(pattern, metrics.InstrumentHandler(route, h))
+}
+
+func (s *Server) createSchedule(w http.ResponseWriter, r *http.Request) {
+	format, ok := s.format(w, r)
+	if !ok {
+		return
+	}
+	data, opts, err := s.decodeRequest(r)
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	opts = slices.Concat(s.defaults(r.Context()), opts)
+
+	var key string
+	if s.cfg.cache != nil {
+		key = scheduler.CacheKey(data, opts...)
+		if tenant := tenantName(r.Context()); tenant != "" {
+			// Tenants never share cache entries, even for equal requests
+			key = tenant + "/" + key
+		}
+		schedule, err := s.cfg.cache.Get(r.Context(), key)
+		if err != nil {
+			s.cfg.logger.Warn("reading schedule cache", slog.Any("error", err))
+		}
+		if schedule != nil {
+			// The schedule keeps the metadata of the run that generated
+			// it, and the metrics are published again to match
+			w.Header().Set("X-Cache", "HIT")
+			scheduler.PublishMetrics(schedule, opts...)
+			s.publish(r.Context(), schedule)
+			s.writeSchedule(w, r, format, schedule)
+			return
+		}
+		w.Header().Set("X-Cache", "MISS")
+	}
+
+	schedule, err := scheduler.GenerateScheduleContext(r.Context(), data, opts...)
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if s.cfg.cache != nil {
+		if err := s.cfg.cache.Set(r.Context(), key, schedule); err != nil {
+			s.cfg.logger.Warn("writing schedule cache", slog.Any("error", err))
+		}
+	}
+	s.publish(r.Context(), schedule)
+	s.writeSchedule(w, r, format, schedule)
+}
+
+func (s *Server) getLatestSchedule(w http.ResponseWriter, r *http.Request) {
+	format, ok := s.format(w, r)
+	if !ok {
+		return
+	}
+	schedule := s.LatestFor(tenantName(r.Context()))
+	if schedule == nil {
+		s.writeError(w, http.StatusNotFound, fmt.Errorf("no schedule has been published yet"))
+		return
+	}
+	s.writeSchedule(w, r, format, schedule)
+}
+
+// getCalendar serves the latest schedule's staffing for a site as an
+// iCalendar feed. Sites may contain slashes, as IANA time zone names do.
+func (s *Server) getCalendar(w http.ResponseWriter, r *http.Request) {
+	site, ok := strings.CutSuffix(r.PathValue("site"), ".ics")
+	if !ok || site == "" {
+		s.writeError(w, http.StatusNotFound, fmt.Errorf("calendar must be requested as /calendar/{site}.ics"))
+		return
+	}
+	schedule := s.LatestFor(tenantName(r.Context()))
+	if schedule == nil {
+		s.writeError(w, http.StatusNotFound, fmt.Errorf("no schedule has been published yet"))
+		return
+	}
+	var buf bytes.Buffer
+	if err := ics.Write(&buf, schedule, site); err != nil {
+		s.writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	_, _ = w.Write(buf.Bytes())
+}
+
+func (s *Server) getOpenAPI(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/yaml")
+	_, _ = w.Write(OpenAPI)
+}
+
+func (s *Server) getHealth(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain")
+	_, _ = io.WriteString(w, "ok\n")
+}
+
+// decodeRequest reads call data and per-request scheduling options from a
+// JSON ScheduleRequest or a CSV body with options in the query string.
+func (s *Server) decodeRequest(r *http.Request) ([]models.CallData, []scheduler.Option, error) {
+	body := http.MaxBytesReader(nil, r.Body, s.cfg.maxBodyBytes)
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+
+	switch mediaType {
+	case "application/json":
+		var req ScheduleRequest
+		if err := json.NewDecoder(body).Decode(&req); err != nil {
+			return nil, nil, fmt.Errorf("decoding request: %w", err)
+		}
+		for i, cd := range req.CallData {
+			if err := cd.Validate(); err != nil {
+				return nil, nil, fmt.Errorf("call_data[%d]: %w", i, err)
+			}
+		}
+		return req.CallData, []scheduler.Option{scheduler.WithScenario(models.Scenario{
+			Utilization:     req.Utilization,
+			Capacity:        req.Capacity,
+			CapacityProfile: req.CapacityProfile,
+		})}, nil
+	case "text/csv":
+		query := r.URL.Query()
+		var scenario models.Scenario
+		var lenient bool
+		var err error
+		if v := query.Get("utilization"); v != "" {
+			if scenario.Utilization, err = strconv.ParseFloat(v, 64); err != nil {
+				return nil, nil, fmt.Errorf("invalid utilization %q", v)
+			}
+		}
+		if v := query.Get("capacity"); v != "" {
+			if scenario.Capacity, err = strconv.Atoi(v); err != nil {
+				return nil, nil, fmt.Errorf("invalid capacity %q", v)
+			}
+		}
+		if v := query.Get("lenient"); v != "" {
+			if lenient, err = strconv.ParseBool(v); err != nil {
+				return nil, nil, fmt.Errorf("invalid lenient %q", v)
+			}
+		}
+		data, err := parser.ParseContext(r.Context(), body, parser.WithLenient(lenient))
+		if err != nil {
+			return nil, nil, err
+		}
+		return data, []scheduler.Option{scheduler.WithScenario(scenario)}, nil
+	default:
+		return nil, nil, fmt.Errorf("unsupported content type %q: use application/json or text/csv", mediaType)
+	}
+}
+
+// format returns the requested response format, writing a 400 response and
+// reporting false if it is unknown.
+func (s *Server) format(w http.ResponseWriter, r *http.Request) (string, bool) {
+	format := r.URL.Query().Get("format")
+	switch format {
+	case "":
+		return "json", true
+	case "json", "text", "csv":
+		return format, true
+	default:
+		s.writeError(w, http.StatusBadRequest, fmt.Errorf("format must be one of: json, text, csv (got: %s)", format))
+		return "", false
+	}
+}
+
+// writeSchedule renders schedule as its JSON form, or as the command line's
+// text or CSV output.
+func (s *Server) writeSchedule(w http.ResponseWriter, r *http.Request, format string, schedule *models.Schedule) {
+	if format == "json" {
+		s.writeJSON(w, http.StatusOK, schedule)
+		return
+	}
+	out, err := formatter.Format(r.Context(), format, schedule)
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	contentType := "text/plain; charset=utf-8"
+	if format == "csv" {
+		contentType = "text/csv"
+	}
+	w.Header().Set("Content-Type", contentType)
+	_, _ = io.WriteString(w, out)
+}
+
+func (s *Server) writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		s.cfg.logger.Error("writing response", slog.Any("error", err))
+	}
+}
+
+func (s *Server) writeError(w http.ResponseWriter, status int, err error) {
+	if status >= http.StatusInternalServerError {
+		s.cfg.logger.Error("request failed", slog.Any("error", err))
+	}
+	s.writeJSON(w, status, Error{Error: err.Error()})
+}
@@ -0,0 +1,204 @@
+package server_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/karthikrao-23/agentscheduler/pkg/metrics"
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+	"github.com/karthikrao-23/agentscheduler/pkg/scheduler"
+	"github.com/karthikrao-23/agentscheduler/pkg/server"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.yaml.in/yaml/v3"
+)
+
+const csvInput = `#CustomerName, AverageCallDurationSeconds, StartTimeUTC, EndTimeUTC, NumberOfCalls, Priority
+Acme, 300, 9AM, 11AM, 240, 1
+`
+
+const jsonInput = `{"call_data":[{"customer_name":"Acme","average_call_duration_seconds":300,` +
+	`"start_time":"2026-03-02T09:00:00Z","end_time":"2026-03-02T11:00:00Z",` +
+	`"location":"UTC","number_of_calls":240,"priority":1}],"capacity":5}`
+
+func do(t *testing.T, s *server.Server, method, target, contentType, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestCreateSchedule(t *testing.T) {
+	tests := map[string]struct {
+		target      string
+		contentType string
+		body        string
+		status      int
+		agents      int
+		unmet       int
+		contains    string
+	}{
+		"CSV":                {target: "/v1/schedules", contentType: "text/csv", body: csvInput, status: http.StatusOK, agents: 20},
+		"CSVWithCapacity":    {target: "/v1/schedules?capacity=8", contentType: "text/csv", body: csvInput, status: http.StatusOK, agents: 16, unmet: 4},
+		"JSON":               {target: "/v1/schedules", contentType: "application/json", body: jsonInput, status: http.StatusOK, agents: 10, unmet: 10},
+		"TextFormat":         {target: "/v1/schedules?format=text", contentType: "text/csv", body: csvInput, status: http.StatusOK, contains: "09:00 : total=10"},
+		"UnknownFormat":      {target: "/v1/schedules?format=xml", contentType: "text/csv", body: csvInput, status: http.StatusBadRequest, contains: "format must be one of"},
+		"BadCSV":             {target: "/v1/schedules", contentType: "text/csv", body: "Acme, x\n", status: http.StatusBadRequest},
+		"BadOption":          {target: "/v1/schedules?capacity=lots", contentType: "text/csv", body: csvInput, status: http.StatusBadRequest, contains: "invalid capacity"},
+		"InvalidCallData":    {target: "/v1/schedules", contentType: "application/json", body: `{"call_data":[{"customer_name":"Acme"}]}`, status: http.StatusBadRequest, contains: "call_data[0]"},
+		"UnsupportedContent": {target: "/v1/schedules", contentType: "text/plain", body: csvInput, status: http.StatusBadRequest, contains: "unsupported content type"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			s := server.New()
+			rec := do(t, s, http.MethodPost, tt.target, tt.contentType, tt.body)
+			require.Equal(t, tt.status, rec.Code, rec.Body.String())
+			if tt.contains != "" {
+				assert.Contains(t, rec.Body.String(), tt.contains)
+			}
+			if tt.status != http.StatusOK {
+				assert.Nil(t, s.Latest())
+				return
+			}
+			require.NotNil(t, s.Latest())
+			if tt.agents == 0 {
+				return
+			}
+
+			var schedule models.Schedule
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &schedule))
+			assert.Equal(t, tt.agents, schedule.TotalAgents())
+			assert.Equal(t, tt.unmet, schedule.TotalUnmet())
+			assert.NotNil(t, schedule.Metadata)
+		})
+	}
+}
+
+func TestGetLatestSchedule(t *testing.T) {
+	s := server.New(server.WithScheduleOptions(scheduler.WithCapacity(8)))
+
+	rec := do(t, s, http.MethodGet, "/v1/schedules/latest", "", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.JSONEq(t, `{"error":"no schedule has been published yet"}`, rec.Body.String())
+
+	require.Equal(t, http.StatusOK, do(t, s, http.MethodPost, "/v1/schedules", "text/csv", csvInput).Code)
+
+	rec = do(t, s, http.MethodGet, "/v1/schedules/latest?format=csv", "", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "text/csv", rec.Header().Get("Content-Type"))
+	assert.Contains(t, rec.Body.String(), "09:00,8,UTC")
+}
+
+func TestGetCalendar(t *testing.T) {
+	s := server.New()
+
+	rec := do(t, s, http.MethodGet, "/calendar/UTC.ics", "", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code, "nothing published yet")
+
+	require.Equal(t, http.StatusOK, do(t, s, http.MethodPost, "/v1/schedules", "application/json", jsonInput).Code)
+
+	rec = do(t, s, http.MethodGet, "/calendar/UTC.ics", "", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "text/calendar; charset=utf-8", rec.Header().Get("Content-Type"))
+	assert.Equal(t, 2, strings.Count(rec.Body.String(), "BEGIN:VEVENT"))
+	assert.Contains(t, rec.Body.String(), "SUMMARY:5 agents (5 unmet)")
+
+	rec = do(t, s, http.MethodGet, "/calendar/America/New_York.ics", "", "")
+	require.Equal(t, http.StatusOK, rec.Code, "sites may contain slashes")
+	assert.NotContains(t, rec.Body.String(), "BEGIN:VEVENT")
+
+	assert.Equal(t, http.StatusNotFound, do(t, s, http.MethodGet, "/calendar/UTC", "", "").Code)
+}
+
+func TestOnPublish(t *testing.T) {
+	var published []*models.Schedule
+	s := server.New(server.WithOnPublish(func(schedule *models.Schedule) {
+		published = append(published, schedule)
+	}))
+
+	require.Equal(t, http.StatusOK, do(t, s, http.MethodPost, "/v1/schedules", "text/csv", csvInput).Code)
+	require.Equal(t, http.StatusBadRequest, do(t, s, http.MethodPost, "/v1/schedules", "text/csv", "Acme, x\n").Code)
+	require.Len(t, published, 1, "failed requests publish nothing")
+	assert.Same(t, s.Latest(), published[0])
+}
+
+type mapCache map[string]*models.Schedule
+
+func (c mapCache) Get(_ context.Context, key string) (*models.Schedule, error) {
+	return c[key], nil
+}
+
+func (c mapCache) Set(_ context.Context, key string, schedule *models.Schedule) error {
+	c[key] = schedule
+	return nil
+}
+
+func TestCreateScheduleCache(t *testing.T) {
+	c := mapCache{}
+	s := server.New(server.WithCache(c))
+
+	first := do(t, s, http.MethodPost, "/v1/schedules", "text/csv", csvInput)
+	require.Equal(t, http.StatusOK, first.Code)
+	assert.Equal(t, "MISS", first.Header().Get("X-Cache"))
+	assert.Len(t, c, 1)
+
+	second := do(t, s, http.MethodPost, "/v1/schedules", "text/csv", csvInput)
+	require.Equal(t, http.StatusOK, second.Code)
+	assert.Equal(t, "HIT", second.Header().Get("X-Cache"))
+	assert.Equal(t, first.Body.String(), second.Body.String(), "the cached schedule keeps its run metadata")
+
+	// Different options are cached separately
+	third := do(t, s, http.MethodPost, "/v1/schedules?capacity=8", "text/csv", csvInput)
+	assert.Equal(t, "MISS", third.Header().Get("X-Cache"))
+	assert.Len(t, c, 2)
+	assert.Equal(t, 4.0, testutil.ToFloat64(metrics.AgentsUnmetTotal))
+
+	// A hit publishes the metrics of the schedule it serves, under its run
+	fourth := do(t, s, http.MethodPost, "/v1/schedules", "text/csv", csvInput)
+	assert.Equal(t, "HIT", fourth.Header().Get("X-Cache"))
+	assert.Equal(t, 20.0, testutil.ToFloat64(metrics.AgentsAllocatedTotal))
+	assert.Zero(t, testutil.ToFloat64(metrics.AgentsUnmetTotal))
+	assert.Equal(t, s.Latest().Metadata.RunID, metrics.CurrentRunID())
+	assert.Equal(t, 1.0, testutil.ToFloat64(metrics.RunInfo.WithLabelValues(s.Latest().Metadata.RunID)))
+}
+
+func TestOpenAPI(t *testing.T) {
+	s := server.New()
+	rec := do(t, s, http.MethodGet, "/openapi.yaml", "", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var spec struct {
+		OpenAPI string                               `yaml:"openapi"`
+		Paths   map[string]map[string]map[string]any `yaml:"paths"`
+	}
+	require.NoError(t, yaml.Unmarshal(rec.Body.Bytes(), &spec))
+	assert.Equal(t, "3.0.3", spec.OpenAPI)
+
+	// Every documented operation is routed
+	for path, ops := range spec.Paths {
+		for method := range ops {
+			rec := do(t, s, strings.ToUpper(method), path, "application/json", "{}")
+			// The mux's own 404 and 405 responses are plain text, unlike the API's errors
+			if rec.Code == http.StatusNotFound || rec.Code == http.StatusMethodNotAllowed {
+				assert.Equal(t, "application/json", rec.Header().Get("Content-Type"), "%s %s is not routed", method, path)
+			}
+		}
+	}
+}
+
+func TestHealth(t *testing.T) {
+	rec := do(t, server.New(), http.MethodGet, "/healthz", "", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "ok\n", rec.Body.String())
+}
@@ -0,0 +1,120 @@
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/karthikrao-23/agentscheduler/pkg/metrics"
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+)
+
+// Tenant is a business unit sharing a multi-tenant server. Each tenant has
+// its own API key, scheduling defaults, trigger profiles, and latest
+// schedule; no tenant can read another's schedules or run its profiles.
+type Tenant struct {
+	// Name identifies the tenant in logs and in the tenant metrics label
+	Name string `json:"name"`
+	// APIKey is the bearer token the tenant's requests carry. The serve
+	// command resolves Vault and Key Vault references in it
+	APIKey string `json:"api_key"`
+	// Defaults override the server's schedule options for the tenant, and
+	// are in turn overridden by options in a request. Its Name is unused
+	Defaults models.Scenario `json:"defaults"`
+	// Profiles are the tenant's POST /trigger profiles
+	Profiles []Profile `json:"profiles,omitempty"`
+}
+
+// LoadTenants reads a JSON array of tenants. Names and API keys must be
+// unique.
+func LoadTenants(r io.Reader) ([]Tenant, error) {
+	var tenants []Tenant
+	if err := json.NewDecoder(r).Decode(&tenants); err != nil {
+		return nil, fmt.Errorf("decoding tenants: %w", err)
+	}
+	names := make(map[string]bool, len(tenants))
+	keys := make(map[string]bool, len(tenants))
+	for i, t := range tenants {
+		switch {
+		case t.Name == "":
+			return nil, fmt.Errorf("tenant %d has no name", i)
+		case names[t.Name]:
+			return nil, fmt.Errorf("tenant %q is defined twice", t.Name)
+		case t.APIKey == "":
+			return nil, fmt.Errorf("tenant %q has no api_key", t.Name)
+		case keys[t.APIKey]:
+			return nil, fmt.Errorf("tenant %q shares its api_key with another tenant", t.Name)
+		}
+		if err := validateProfiles(t.Profiles); err != nil {
+			return nil, fmt.Errorf("tenant %q: %w", t.Name, err)
+		}
+		names[t.Name] = true
+		keys[t.APIKey] = true
+	}
+	return tenants, nil
+}
+
+// WithTenants makes the server multi-tenant: every API request must carry
+// one of the tenants' keys as a bearer token, and is served from that
+// tenant's defaults, profiles, and latest schedule. /healthz and
+// /openapi.yaml stay public. Schedules published by tenants are not passed
+// to WithOnPublish, which has no way to keep them apart.
+func WithTenants(tenants ...Tenant) Option {
+	return func(c *config) {
+		c.tenants = tenants
+	}
+}
+
+type tenantKey struct{}
+
+// tenantFromContext returns the tenant a request authenticated as, or nil
+// on a single-tenant server.
+func tenantFromContext(ctx context.Context) *Tenant {
+	t, _ := ctx.Value(tenantKey{}).(*Tenant)
+	return t
+}
+
+// tenantName returns the name schedules are stored under for ctx's tenant;
+// a single-tenant server stores its schedule under "".
+func tenantName(ctx context.Context) string {
+	if t := tenantFromContext(ctx); t != nil {
+		return t.Name
+	}
+	return ""
+}
+
+// tenanted requires requests to h to bear a tenant's API key, identifying
+// the tenant to h through the request context and counting the request
+// under the tenant's name. It returns h unchanged on a single-tenant server.
+func (s *Server) tenanted(route string, h http.HandlerFunc) http.HandlerFunc {
+	if len(s.cfg.tenants) == 0 {
+		return h
+	}
+	handlers := make([]http.Handler, len(s.cfg.tenants))
+	for i, t := range s.cfg.tenants {
+		handlers[i] = metrics.InstrumentTenantHandler(t.Name, route, h)
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		// Compare against every key so the time taken does not reveal
+		// which tenant, if any, a key belongs to
+		tenant := -1
+		for i, t := range s.cfg.tenants {
+			if subtle.ConstantTimeCompare([]byte(token), []byte(t.APIKey)) == 1 && ok {
+				tenant = i
+			}
+		}
+		if tenant < 0 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="agent-scheduler"`)
+			s.writeError(w, http.StatusUnauthorized, errors.New("missing or invalid API key"))
+			return
+		}
+		ctx := context.WithValue(r.Context(), tenantKey{}, &s.cfg.tenants[tenant])
+		handlers[tenant].ServeHTTP(w, r.WithContext(ctx))
+	}
+}
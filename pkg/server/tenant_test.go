@@ -0,0 +1,121 @@
+package server_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/karthikrao-23/agentscheduler/pkg/metrics"
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+	"github.com/karthikrao-23/agentscheduler/pkg/scheduler"
+	"github.com/karthikrao-23/agentscheduler/pkg/server"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadTenants(t *testing.T) {
+	tests := map[string]struct {
+		input string
+		err   string
+	}{
+		"Valid":          {input: `[{"name":"emea","api_key":"k1","defaults":{"capacity":8}},{"name":"apac","api_key":"k2"}]`},
+		"MissingName":    {input: `[{"api_key":"k1"}]`, err: "has no name"},
+		"MissingKey":     {input: `[{"name":"emea"}]`, err: "has no api_key"},
+		"DuplicateName":  {input: `[{"name":"emea","api_key":"k1"},{"name":"emea","api_key":"k2"}]`, err: "defined twice"},
+		"SharedKey":      {input: `[{"name":"emea","api_key":"k1"},{"name":"apac","api_key":"k1"}]`, err: "shares its api_key"},
+		"InvalidProfile": {input: `[{"name":"emea","api_key":"k1","profiles":[{"name":"nightly"}]}]`, err: `tenant "emea": profile "nightly" has no input`},
+		"NotJSON":        {input: `tenants:`, err: "decoding tenants"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			tenants, err := server.LoadTenants(strings.NewReader(tt.input))
+			if tt.err != "" {
+				assert.ErrorContains(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, tenants, 2)
+			assert.Equal(t, "emea", tenants[0].Name)
+			assert.Equal(t, 8, tenants[0].Defaults.Capacity)
+		})
+	}
+}
+
+func TestTenants(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "forecast.csv")
+	require.NoError(t, os.WriteFile(input, []byte(csvInput), 0o600))
+
+	c := mapCache{}
+	s := server.New(
+		server.WithScheduleOptions(scheduler.WithCapacity(20)),
+		server.WithCache(c),
+		server.WithTrigger(server.TriggerConfig{}),
+		server.WithTenants(
+			server.Tenant{Name: "emea", APIKey: "emea-key", Defaults: models.Scenario{Capacity: 8},
+				Profiles: []server.Profile{{Scenario: models.Scenario{Name: "nightly"}, Input: input}}},
+			server.Tenant{Name: "apac", APIKey: "apac-key"},
+		),
+	)
+	as := func(key, method, target, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set("Content-Type", "text/csv")
+		if key != "" {
+			req.Header.Set("Authorization", "Bearer "+key)
+		}
+		rec := httptest.NewRecorder()
+		s.ServeHTTP(rec, req)
+		return rec
+	}
+	agents := func(rec *httptest.ResponseRecorder) int {
+		t.Helper()
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		var schedule models.Schedule
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &schedule))
+		return schedule.TotalAgents()
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, as("", http.MethodPost, "/v1/schedules", csvInput).Code)
+	assert.Equal(t, http.StatusUnauthorized, as("guess", http.MethodGet, "/v1/schedules/latest", "").Code)
+	assert.Equal(t, http.StatusOK, as("", http.MethodGet, "/healthz", "").Code, "health checks stay public")
+	s.HandleWithToken("GET /metrics", "ops-key", http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	assert.Equal(t, http.StatusUnauthorized, as("", http.MethodGet, "/metrics", "").Code)
+	assert.Equal(t, http.StatusUnauthorized, as("emea-key", http.MethodGet, "/metrics", "").Code, "tenant keys do not open /metrics")
+	assert.Equal(t, http.StatusOK, as("ops-key", http.MethodGet, "/metrics", "").Code)
+
+	// Tenant defaults override the server's; request options override both
+	assert.Equal(t, 16, agents(as("emea-key", http.MethodPost, "/v1/schedules", csvInput)))
+	assert.Zero(t, testutil.CollectAndCount(metrics.LocationAgentsAllocated), "tenant runs publish no client names")
+	assert.Equal(t, 16.0, testutil.ToFloat64(metrics.AgentsAllocatedTotal))
+	assert.Equal(t, 20, agents(as("apac-key", http.MethodPost, "/v1/schedules?capacity=10", csvInput)))
+	assert.Len(t, c, 2)
+
+	// Equal requests from different tenants are cached separately
+	rec := as("apac-key", http.MethodPost, "/v1/schedules?capacity=8", csvInput)
+	assert.Equal(t, "MISS", rec.Header().Get("X-Cache"))
+	assert.Equal(t, 16, agents(rec))
+
+	// Each tenant sees only its own latest schedule
+	emea := s.LatestFor("emea")
+	require.NotNil(t, emea)
+	assert.NotSame(t, emea, s.LatestFor("apac"))
+	assert.Nil(t, s.Latest())
+	rec = as("emea-key", http.MethodGet, "/v1/schedules/latest", "")
+	assert.Equal(t, 16, agents(rec))
+	assert.Contains(t, rec.Body.String(), emea.Metadata.RunID)
+
+	// Profiles belong to their tenant
+	assert.Equal(t, http.StatusNotFound, as("apac-key", http.MethodPost, "/trigger", `{"profile":"nightly"}`).Code)
+	rec = as("emea-key", http.MethodPost, "/trigger", `{"profile":"nightly"}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.NotSame(t, emea, s.LatestFor("emea"))
+	assert.Equal(t, 16, s.LatestFor("emea").TotalAgents())
+}
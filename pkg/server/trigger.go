@@ -0,0 +1,220 @@
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/karthikrao-23/agentscheduler/pkg/formatter"
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+	"github.com/karthikrao-23/agentscheduler/pkg/parser"
+	"github.com/karthikrao-23/agentscheduler/pkg/scheduler"
+)
+
+// Profile is a named run configuration that POST /trigger can start: where
+// to read call data from, the scenario to schedule it under, and optionally
+// where to deliver the formatted schedule. The scenario's Name names the
+// profile.
+type Profile struct {
+	models.Scenario
+	// Input is the call-data CSV, as a path or URI that TriggerConfig.Open
+	// understands
+	Input   string `json:"input"`
+	Lenient bool   `json:"lenient,omitempty"`
+	// Output, if set, receives the schedule in Format (default csv)
+	Output string `json:"output,omitempty"`
+	Format string `json:"format,omitempty"`
+}
+
+// LoadProfiles reads a JSON array of profiles.
+func LoadProfiles(r io.Reader) ([]Profile, error) {
+	var profiles []Profile
+	if err := json.NewDecoder(r).Decode(&profiles); err != nil {
+		return nil, fmt.Errorf("decoding profiles: %w", err)
+	}
+	if err := validateProfiles(profiles); err != nil {
+		return nil, err
+	}
+	return profiles, nil
+}
+
+func validateProfiles(profiles []Profile) error {
+	seen := make(map[string]bool, len(profiles))
+	for i, p := range profiles {
+		switch {
+		case p.Name == "":
+			return fmt.Errorf("profile %d has no name", i)
+		case seen[p.Name]:
+			return fmt.Errorf("profile %q is defined twice", p.Name)
+		case p.Input == "":
+			return fmt.Errorf("profile %q has no input", p.Name)
+		case !slices.Contains([]string{"", "text", "json", "csv"}, p.Format):
+			return fmt.Errorf("profile %q: format must be one of: text, json, csv (got: %s)", p.Name, p.Format)
+		}
+		seen[p.Name] = true
+	}
+	return nil
+}
+
+// TriggerConfig enables POST /trigger.
+type TriggerConfig struct {
+	// Token is the bearer token callers must present; required unless the
+	// server has tenants, which authenticate with their own keys and run
+	// their own profiles instead of Profiles
+	Token    string
+	Profiles []Profile
+	// Open opens profile inputs; defaults to local files
+	Open func(ctx context.Context, uri string) (io.ReadCloser, error)
+	// Create creates profile outputs; defaults to local files
+	Create func(ctx context.Context, uri string) (io.WriteCloser, error)
+}
+
+// TriggerRequest is the JSON body of POST /trigger.
+type TriggerRequest struct {
+	Profile string `json:"profile"`
+}
+
+// TriggerResult is the response to POST /trigger once the run completes.
+type TriggerResult struct {
+	Profile string         `json:"profile"`
+	RunID   string         `json:"run_id"`
+	Summary models.Summary `json:"summary"`
+	// Output is where the schedule was delivered, if the profile has one
+	Output string `json:"output,omitempty"`
+}
+
+// WithTrigger enables POST /trigger, which runs a named profile and
+// publishes the result as the latest schedule, for schedulers such as
+// Airflow to call when a new forecast lands.
+func WithTrigger(trigger TriggerConfig) Option {
+	return func(c *config) {
+		if trigger.Open == nil {
+			trigger.Open = func(_ context.Context, uri string) (io.ReadCloser, error) { return os.Open(uri) }
+		}
+		if trigger.Create == nil {
+			trigger.Create = func(_ context.Context, uri string) (io.WriteCloser, error) { return os.Create(uri) }
+		}
+		c.trigger = &trigger
+	}
+}
+
+func (s *Server) trigger(w http.ResponseWriter, r *http.Request) {
+	t := s.cfg.trigger
+	if t == nil {
+		s.writeError(w, http.StatusNotFound, errors.New("the trigger endpoint is not enabled"))
+		return
+	}
+	profiles := t.Profiles
+	if tenant := tenantFromContext(r.Context()); tenant != nil {
+		profiles = tenant.Profiles
+	} else {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || t.Token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(t.Token)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="agent-scheduler"`)
+			s.writeError(w, http.StatusUnauthorized, errors.New("missing or invalid bearer token"))
+			return
+		}
+	}
+
+	var req TriggerRequest
+	if err := json.NewDecoder(http.MaxBytesReader(nil, r.Body, s.cfg.maxBodyBytes)).Decode(&req); err != nil {
+		s.writeError(w, http.StatusBadRequest, fmt.Errorf("decoding request: %w", err))
+		return
+	}
+	var profile *Profile
+	for i := range profiles {
+		if profiles[i].Name == req.Profile {
+			profile = &profiles[i]
+		}
+	}
+	if profile == nil {
+		s.writeError(w, http.StatusNotFound, fmt.Errorf("unknown profile %q", req.Profile))
+		return
+	}
+
+	result, err := s.runProfile(r.Context(), *profile)
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, fmt.Errorf("profile %s: %w", profile.Name, err))
+		return
+	}
+	s.writeJSON(w, http.StatusOK, result)
+}
+
+// replanner is a profile's Replanner, which runs one trigger at a time.
+type replanner struct {
+	mu sync.Mutex
+	*scheduler.Replanner
+}
+
+// replanner returns the Replanner for profile p of ctx's tenant, creating it
+// on the profile's first run.
+func (s *Server) replanner(ctx context.Context, p Profile) (*replanner, error) {
+	key := tenantName(ctx) + "\x00" + p.Name
+	s.replanMu.Lock()
+	defer s.replanMu.Unlock()
+	if rp, ok := s.replanners[key]; ok {
+		return rp, nil
+	}
+	opts := slices.Concat(s.defaults(ctx), []scheduler.Option{scheduler.WithScenario(p.Scenario)})
+	r, err := scheduler.NewReplanner(opts...)
+	if err != nil {
+		return nil, err
+	}
+	rp := &replanner{Replanner: r}
+	s.replanners[key] = rp
+	return rp, nil
+}
+
+// runProfile schedules a profile's input, publishes the schedule, and
+// delivers it to the profile's output. Each profile's runs reuse what they
+// can of its last, so a revised forecast only redoes the customers it
+// changed.
+func (s *Server) runProfile(ctx context.Context, p Profile) (TriggerResult, error) {
+	t := s.cfg.trigger
+	in, err := t.Open(ctx, p.Input)
+	if err != nil {
+		return TriggerResult{}, fmt.Errorf("opening input: %w", err)
+	}
+	data, err := parser.ParseContext(ctx, in, parser.WithLenient(p.Lenient))
+	in.Close()
+	if err != nil {
+		return TriggerResult{}, fmt.Errorf("parsing input: %w", err)
+	}
+	rp, err := s.replanner(ctx, p)
+	if err != nil {
+		return TriggerResult{}, fmt.Errorf("generating schedule: %w", err)
+	}
+	rp.mu.Lock()
+	schedule, err := rp.Schedule(ctx, data)
+	rp.mu.Unlock()
+	if err != nil {
+		return TriggerResult{}, fmt.Errorf("generating schedule: %w", err)
+	}
+	s.publish(ctx, schedule)
+
+	result := TriggerResult{Profile: p.Name, RunID: schedule.Metadata.RunID, Summary: schedule.Summary(), Output: p.Output}
+	if p.Output == "" {
+		return result, nil
+	}
+	format := p.Format
+	if format == "" {
+		format = "csv"
+	}
+	out, err := t.Create(ctx, p.Output)
+	if err != nil {
+		return TriggerResult{}, fmt.Errorf("creating output: %w", err)
+	}
+	err = formatter.Write(ctx, out, format, schedule)
+	if err = errors.Join(err, out.Close()); err != nil {
+		return TriggerResult{}, fmt.Errorf("writing output: %w", err)
+	}
+	return result, nil
+}
@@ -0,0 +1,107 @@
+package server_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+	"github.com/karthikrao-23/agentscheduler/pkg/server"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadProfiles(t *testing.T) {
+	tests := map[string]struct {
+		input string
+		err   string
+	}{
+		"Valid":         {input: `[{"name":"nightly","input":"forecast.csv","capacity":50,"output":"out.csv"}]`},
+		"MissingName":   {input: `[{"input":"forecast.csv"}]`, err: "has no name"},
+		"MissingInput":  {input: `[{"name":"nightly"}]`, err: "has no input"},
+		"Duplicate":     {input: `[{"name":"a","input":"x"},{"name":"a","input":"y"}]`, err: "defined twice"},
+		"UnknownFormat": {input: `[{"name":"a","input":"x","format":"xml"}]`, err: "format must be one of"},
+		"NotJSON":       {input: `profiles:`, err: "decoding profiles"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			profiles, err := server.LoadProfiles(strings.NewReader(tt.input))
+			if tt.err != "" {
+				assert.ErrorContains(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, profiles, 1)
+			assert.Equal(t, "nightly", profiles[0].Name)
+			assert.Equal(t, 50, profiles[0].Capacity)
+		})
+	}
+}
+
+func TestTrigger(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "forecast.csv")
+	output := filepath.Join(dir, "schedule.csv")
+	require.NoError(t, os.WriteFile(input, []byte(csvInput), 0o600))
+
+	s := server.New(server.WithTrigger(server.TriggerConfig{
+		Token: "s3cret",
+		Profiles: []server.Profile{
+			{Scenario: models.Scenario{Name: "nightly", Capacity: 8}, Input: input, Output: output},
+			{Scenario: models.Scenario{Name: "broken"}, Input: filepath.Join(dir, "missing.csv")},
+		},
+	}))
+	trigger := func(token, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/trigger", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		s.ServeHTTP(rec, req)
+		return rec
+	}
+
+	tests := map[string]struct {
+		token  string
+		body   string
+		status int
+	}{
+		"NoToken":        {body: `{"profile":"nightly"}`, status: http.StatusUnauthorized},
+		"WrongToken":     {token: "guess", body: `{"profile":"nightly"}`, status: http.StatusUnauthorized},
+		"UnknownProfile": {token: "s3cret", body: `{"profile":"weekly"}`, status: http.StatusNotFound},
+		"BadBody":        {token: "s3cret", body: `profile=nightly`, status: http.StatusBadRequest},
+		"FailedRun":      {token: "s3cret", body: `{"profile":"broken"}`, status: http.StatusInternalServerError},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			rec := trigger(tt.token, tt.body)
+			assert.Equal(t, tt.status, rec.Code, rec.Body.String())
+			assert.Nil(t, s.Latest())
+		})
+	}
+
+	rec := trigger("s3cret", `{"profile":"nightly"}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var result server.TriggerResult
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &result))
+	assert.Equal(t, "nightly", result.Profile)
+	assert.Equal(t, 16, result.Summary.TotalAgents, "the profile's capacity applies")
+	assert.Equal(t, output, result.Output)
+
+	require.NotNil(t, s.Latest())
+	assert.Equal(t, result.RunID, s.Latest().Metadata.RunID)
+	b, err := os.ReadFile(output)
+	require.NoError(t, err)
+	assert.Contains(t, string(b), "09:00,8,UTC")
+}
+
+func TestTriggerDisabled(t *testing.T) {
+	rec := do(t, server.New(), http.MethodPost, "/trigger", "application/json", `{"profile":"nightly"}`)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
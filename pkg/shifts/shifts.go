@@ -0,0 +1,356 @@
+// Package shifts turns a schedule's agents per slot into shifts: how many
+// agents start work at each allowed time of day, for a shift of fixed
+// length, which is what a staffing team hires and rosters against.
+package shifts
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+)
+
+const day = 24 * time.Hour
+
+// Config describes the shifts agents can work.
+type Config struct {
+	// Length is how long every shift runs, e.g. 8 hours
+	Length time.Duration
+	// Starts are the times of day a shift may start, as offsets from
+	// midnight; nil allows a start at every slot
+	Starts []time.Duration
+	// Breaks are taken off the phones in every shift
+	Breaks []Break
+}
+
+// Break is time every agent on a shift spends off the phones, e.g. a 15
+// minute break every 2 hours or a 30 minute lunch.
+type Break struct {
+	Length time.Duration
+	// Every takes the break this long into the shift and again this long
+	// after each, while it fits; 0 takes it once, in the middle of the
+	// shift
+	Every time.Duration
+}
+
+// Shift is a number of agents starting work together.
+type Shift struct {
+	// Start is wall-clock time in the schedule's location, on year 0 for
+	// a generic day
+	Start  time.Time
+	Length time.Duration
+	Agents int
+	// Breaks are when the shift's agents are off the phones, in order
+	Breaks []Window
+}
+
+// Window is a span of wall-clock time.
+type Window struct {
+	Start, End time.Time
+}
+
+// End returns when the shift finishes.
+func (s Shift) End() time.Time {
+	return s.Start.Add(s.Length)
+}
+
+// Plan is the shifts that staff a schedule.
+type Plan struct {
+	// Shifts are in order of start, each with at least one agent
+	Shifts []Shift
+	// Required is the agents the schedule needs in each of its slots, and
+	// Covered the agents on shift then, less the share of the slot they
+	// spend on breaks
+	Required []int
+	Covered  []float64
+}
+
+// Agents returns the agents across all shifts.
+func (p *Plan) Agents() int {
+	total := 0
+	for _, s := range p.Shifts {
+		total += s.Agents
+	}
+	return total
+}
+
+// Short returns the agents each slot is short of what it requires, which is
+// only ever above 0 where no allowed start reaches the slot; nil when every
+// slot is covered.
+func (p *Plan) Short() []int {
+	var short []int
+	for t, required := range p.Required {
+		missing := int(math.Ceil(float64(required) - p.Covered[t] - eps))
+		if missing <= 0 {
+			continue
+		}
+		if short == nil {
+			short = make([]int, len(p.Required))
+		}
+		short[t] = missing
+	}
+	return short
+}
+
+// eps absorbs rounding in coverage, which breaks make fractional.
+const eps = 1e-9
+
+// candidate is a shift that could be worked: a start and the slots it
+// covers, with how long after its start each one begins and the share of
+// it an agent on the shift is on the phones.
+type candidate struct {
+	start   time.Time
+	slots   []int
+	elapsed []time.Duration
+	on      []float64
+}
+
+// Generate staffs every slot of schedule with the agents allocated to it,
+// using as few agents on shifts as it can. Slots are filled in time order:
+// a slot still short gets its missing agents on the latest-starting shift
+// that covers it, which carries them furthest into the slots after. Agents
+// that later shifts made surplus are then dropped. Shifts of a generic day
+// run on past midnight into its start, as the day repeats; a schedule laid
+// out by date gets shifts on every date, including ones starting the day
+// before its first.
+//
+// Breaks count against coverage: an agent on a 15 minute break for part of
+// an hour slot covers only 0.75 of it, so slots a shift's breaks fall in
+// get extra agents. Breaks taken mid-shift are placed first, and the rest
+// are moved past any break they would overlap.
+func Generate(schedule *models.Schedule, cfg Config) (*Plan, error) {
+	if cfg.Length <= 0 || cfg.Length > day {
+		return nil, fmt.Errorf("shift length must be greater than 0 and at most 24h (got: %s)", cfg.Length)
+	}
+	breaks, err := place(cfg.Breaks, cfg.Length)
+	if err != nil {
+		return nil, err
+	}
+	n := schedule.NumSlots()
+	walls := make([]time.Time, n)
+	lengths := make([]time.Duration, n)
+	required := make([]int, n)
+	for t := range n {
+		walls[t] = wallClock(schedule.SlotAt(t))
+		lengths[t] = schedule.SlotAt(t).Duration
+		required[t] = schedule.AgentsForHour(t)
+	}
+	starts := slices.Clone(cfg.Starts)
+	if starts == nil {
+		for _, wall := range walls {
+			starts = append(starts, sinceMidnight(wall))
+		}
+	}
+	slices.Sort(starts)
+	starts = slices.Compact(starts)
+	for _, s := range starts {
+		if s < 0 || s >= day {
+			return nil, fmt.Errorf("shift start must be within the day (got: %s)", s)
+		}
+	}
+
+	candidates := enumerate(walls, lengths, starts, cfg.Length, breaks, !schedule.SlotAt(0).HasDate())
+	// The latest-starting candidate with agents on the phones in each slot,
+	// and their share of the slot
+	best := make([]int, n)
+	bestElapsed := make([]time.Duration, n)
+	bestOn := make([]float64, n)
+	for t := range best {
+		best[t] = -1
+	}
+	for c, cand := range candidates {
+		for i, t := range cand.slots {
+			if cand.on[i] > eps && (best[t] < 0 || cand.elapsed[i] < bestElapsed[t]) {
+				best[t], bestElapsed[t], bestOn[t] = c, cand.elapsed[i], cand.on[i]
+			}
+		}
+	}
+
+	agents := make([]int, len(candidates))
+	covered := make([]float64, n)
+	add := func(c, k int) {
+		agents[c] += k
+		for i, t := range candidates[c].slots {
+			covered[t] += float64(k) * candidates[c].on[i]
+		}
+	}
+	for t := range n {
+		short := float64(required[t]) - covered[t]
+		if short <= eps || best[t] < 0 {
+			continue
+		}
+		add(best[t], int(math.Ceil(short/bestOn[t]-eps)))
+	}
+	// Latest first, as those were added last
+	for c := len(candidates) - 1; c >= 0; c-- {
+		surplus := agents[c]
+		for i, t := range candidates[c].slots {
+			if on := candidates[c].on[i]; on > eps {
+				surplus = min(surplus, int(math.Floor((covered[t]-float64(required[t]))/on+eps)))
+			}
+		}
+		if surplus > 0 {
+			add(c, -surplus)
+		}
+	}
+
+	plan := &Plan{Required: required, Covered: covered}
+	for c, cand := range candidates {
+		if agents[c] == 0 {
+			continue
+		}
+		shift := Shift{Start: cand.start, Length: cfg.Length, Agents: agents[c]}
+		for _, b := range breaks {
+			shift.Breaks = append(shift.Breaks, Window{Start: cand.start.Add(b.start), End: cand.start.Add(b.end)})
+		}
+		plan.Shifts = append(plan.Shifts, shift)
+	}
+	return plan, nil
+}
+
+// offset is a span of a shift, from its start.
+type offset struct {
+	start, end time.Duration
+}
+
+// place lays out breaks over a shift of length, in order.
+func place(breaks []Break, length time.Duration) ([]offset, error) {
+	var placed []offset
+	for _, b := range breaks {
+		if b.Length <= 0 || b.Length >= length || b.Every < 0 || (b.Every > 0 && b.Every <= b.Length) {
+			return nil, fmt.Errorf("break of %s every %s does not fit a %s shift: it must be shorter than the shift and than how often it is taken", b.Length, b.Every, length)
+		}
+		if b.Every == 0 {
+			placed = append(placed, offset{length/2 - b.Length/2, length/2 - b.Length/2 + b.Length})
+		}
+	}
+	for _, b := range breaks {
+		if b.Every == 0 {
+			continue
+		}
+		for at := b.Every; ; at += b.Every {
+			// Move past any break already placed there, until none is
+			for moved := true; moved; {
+				moved = false
+				for _, p := range placed {
+					if at < p.end && p.start < at+b.Length {
+						at, moved = p.end, true
+					}
+				}
+			}
+			if at+b.Length > length {
+				break
+			}
+			placed = append(placed, offset{at, at + b.Length})
+		}
+	}
+	slices.SortFunc(placed, func(a, b offset) int {
+		return int(a.start - b.start)
+	})
+	return placed, nil
+}
+
+// enumerate returns the shifts of length, with breaks, that could be worked
+// over the slots starting at walls and lasting lengths, in order of start,
+// leaving out any that cover no slot. generic wraps them around one day.
+func enumerate(walls []time.Time, lengths []time.Duration, starts []time.Duration, length time.Duration, breaks []offset, generic bool) []candidate {
+	var days []time.Time
+	if generic {
+		days = []time.Time{time.Date(0, 1, 1, 0, 0, 0, 0, time.UTC)}
+	} else {
+		// Shifts from the day before a slot's can run into it
+		for _, wall := range walls {
+			midnight := wall.Add(-sinceMidnight(wall))
+			days = append(days, midnight.AddDate(0, 0, -1), midnight)
+		}
+		slices.SortFunc(days, time.Time.Compare)
+		days = slices.CompactFunc(days, time.Time.Equal)
+	}
+
+	var candidates []candidate
+	for _, midnight := range days {
+		for _, s := range starts {
+			cand := candidate{start: midnight.Add(s)}
+			for t, wall := range walls {
+				elapsed := wall.Sub(cand.start)
+				if generic {
+					elapsed = (elapsed%day + day) % day
+				}
+				if elapsed < 0 || elapsed >= length {
+					continue
+				}
+				// The slot's share on the phones: within the shift and
+				// not on a break
+				slot := offset{elapsed, elapsed + lengths[t]}
+				on := overlap(slot, offset{0, length})
+				for _, b := range breaks {
+					on -= overlap(slot, b)
+				}
+				cand.slots = append(cand.slots, t)
+				cand.elapsed = append(cand.elapsed, elapsed)
+				cand.on = append(cand.on, float64(on)/float64(lengths[t]))
+			}
+			if len(cand.slots) > 0 {
+				candidates = append(candidates, cand)
+			}
+		}
+	}
+	return candidates
+}
+
+// overlap returns how long a and b overlap.
+func overlap(a, b offset) time.Duration {
+	return max(min(a.end, b.end)-max(a.start, b.start), 0)
+}
+
+// wallClock returns the slot's start as wall-clock time in its location,
+// in UTC so that times compare without regard to DST.
+func wallClock(slot models.TimeSlot) time.Time {
+	local := slot.Location.In(slot.Start)
+	return time.Date(local.Year(), local.Month(), local.Day(), local.Hour(), local.Minute(), 0, 0, time.UTC)
+}
+
+func sinceMidnight(t time.Time) time.Duration {
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+}
+
+// Write writes plan's shifts as CSV, one row per shift with its start, end,
+// and agents, and when shifts have breaks, the breaks as "start-end" times
+// separated by ";". Times are "15:04" for a generic day and
+// "2006-01-02 15:04" otherwise.
+func Write(w io.Writer, plan *Plan) error {
+	withBreaks := slices.ContainsFunc(plan.Shifts, func(s Shift) bool { return len(s.Breaks) > 0 })
+	header := []string{"Start", "End", "Agents"}
+	if withBreaks {
+		header = append(header, "Breaks")
+	}
+	cw := csv.NewWriter(w)
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	for _, s := range plan.Shifts {
+		layout := "2006-01-02 15:04"
+		if s.Start.Year() == 0 {
+			layout = "15:04"
+		}
+		record := []string{s.Start.Format(layout), s.End().Format(layout), strconv.Itoa(s.Agents)}
+		if withBreaks {
+			breaks := make([]string, len(s.Breaks))
+			for i, b := range s.Breaks {
+				breaks[i] = b.Start.Format(layout) + "-" + b.End.Format(layout)
+			}
+			record = append(record, strings.Join(breaks, ";"))
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,144 @@
+package shifts_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+	"github.com/karthikrao-23/agentscheduler/pkg/shifts"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newSchedule returns a schedule over slots that needs agents[t] in slot t.
+func newSchedule(slots []models.TimeSlot, agents map[int]int) *models.Schedule {
+	reqs := make([][]models.CustomerRequirement, max(len(slots), 24))
+	for t, n := range agents {
+		reqs[t] = []models.CustomerRequirement{{Name: "Acme", AgentsNeeded: n, Priority: 1}}
+	}
+	return &models.Schedule{HourlyRequirements: reqs, Slots: slots}
+}
+
+// hours returns agents needed in each hour from start to end (exclusive).
+func hours(agents map[int]int, start, end, n int) map[int]int {
+	for h := start; h < end; h++ {
+		agents[h%24] += n
+	}
+	return agents
+}
+
+func TestGenerate(t *testing.T) {
+	at := func(hour int) time.Time {
+		return time.Date(0, 1, 1, hour, 0, 0, 0, time.UTC)
+	}
+	tests := map[string]struct {
+		agents     map[int]int
+		cfg        shifts.Config
+		wantShifts []shifts.Shift
+		wantShort  []int
+	}{
+		"OneShift": {
+			agents:     hours(map[int]int{}, 9, 17, 10),
+			cfg:        shifts.Config{Length: 8 * time.Hour},
+			wantShifts: []shifts.Shift{{Start: at(9), Length: 8 * time.Hour, Agents: 10}},
+		},
+		"Staggered": {
+			agents: hours(hours(map[int]int{}, 9, 17, 4), 13, 21, 2),
+			cfg:    shifts.Config{Length: 8 * time.Hour},
+			wantShifts: []shifts.Shift{
+				{Start: at(9), Length: 8 * time.Hour, Agents: 4},
+				{Start: at(13), Length: 8 * time.Hour, Agents: 2},
+			},
+		},
+		"AllowedStarts": {
+			agents: hours(map[int]int{}, 9, 18, 3),
+			cfg:    shifts.Config{Length: 8 * time.Hour, Starts: []time.Duration{14 * time.Hour, 6 * time.Hour}},
+			wantShifts: []shifts.Shift{
+				{Start: at(6), Length: 8 * time.Hour, Agents: 3},
+				{Start: at(14), Length: 8 * time.Hour, Agents: 3},
+			},
+		},
+		"PastMidnight": {
+			// The midnight shift first filled in is made surplus by the
+			// overnight one
+			agents:     hours(map[int]int{}, 22, 26, 2),
+			cfg:        shifts.Config{Length: 4 * time.Hour},
+			wantShifts: []shifts.Shift{{Start: at(22), Length: 4 * time.Hour, Agents: 2}},
+		},
+		"Unreachable": {
+			agents:    map[int]int{20: 1},
+			cfg:       shifts.Config{Length: 4 * time.Hour, Starts: []time.Duration{9 * time.Hour}},
+			wantShort: func() []int { short := make([]int, 24); short[20] = 1; return short }(),
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			plan, err := shifts.Generate(newSchedule(nil, tt.agents), tt.cfg)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantShifts, plan.Shifts)
+			assert.Equal(t, tt.wantShort, plan.Short())
+		})
+	}
+}
+
+func TestGenerate_Breaks(t *testing.T) {
+	at := func(hour, minute int) time.Time {
+		return time.Date(0, 1, 1, hour, minute, 0, 0, time.UTC)
+	}
+	cfg := shifts.Config{
+		Length: 8 * time.Hour,
+		Starts: []time.Duration{9 * time.Hour},
+		Breaks: []shifts.Break{{Length: 15 * time.Minute, Every: 2 * time.Hour}, {Length: 30 * time.Minute}},
+	}
+	plan, err := shifts.Generate(newSchedule(nil, hours(map[int]int{}, 9, 17, 10)), cfg)
+	require.NoError(t, err)
+
+	// Lunch runs 12:45 to 13:15, so the 13:00 break moves to 13:15 and
+	// half of 13:00 is spent off the phones; the next is 2 hours after it
+	require.Len(t, plan.Shifts, 1)
+	assert.Equal(t, 20, plan.Shifts[0].Agents)
+	assert.Equal(t, []shifts.Window{
+		{Start: at(11, 0), End: at(11, 15)},
+		{Start: at(12, 45), End: at(13, 15)},
+		{Start: at(13, 15), End: at(13, 30)},
+		{Start: at(15, 15), End: at(15, 30)},
+	}, plan.Shifts[0].Breaks)
+	assert.Equal(t, 10.0, plan.Covered[13])
+	assert.Equal(t, 15.0, plan.Covered[11])
+	assert.Nil(t, plan.Short())
+
+	var buf bytes.Buffer
+	require.NoError(t, shifts.Write(&buf, plan))
+	assert.Equal(t, "Start,End,Agents,Breaks\n09:00,17:00,20,11:00-11:15;12:45-13:15;13:15-13:30;15:15-15:30\n", buf.String())
+
+	_, err = shifts.Generate(newSchedule(nil, nil), shifts.Config{Length: time.Hour, Breaks: []shifts.Break{{Length: 15 * time.Minute, Every: 10 * time.Minute}}})
+	assert.Error(t, err)
+}
+
+func TestGenerate_ByDate(t *testing.T) {
+	slots := append(models.DaySlots(2026, 3, 2, time.Hour), models.DaySlots(2026, 3, 3, time.Hour)...)
+	// Overnight from 22:00 on the 2nd to 06:00 on the 3rd
+	agents := make(map[int]int)
+	for h := 22; h < 30; h++ {
+		agents[h] = 2
+	}
+	plan, err := shifts.Generate(newSchedule(slots, agents), shifts.Config{Length: 8 * time.Hour})
+	require.NoError(t, err)
+	assert.Equal(t, []shifts.Shift{{Start: time.Date(2026, 3, 2, 22, 0, 0, 0, time.UTC), Length: 8 * time.Hour, Agents: 2}}, plan.Shifts)
+	assert.Equal(t, 2, plan.Agents())
+
+	var buf bytes.Buffer
+	require.NoError(t, shifts.Write(&buf, plan))
+	assert.Equal(t, "Start,End,Agents\n2026-03-02 22:00,2026-03-03 06:00,2\n", buf.String())
+}
+
+func TestGenerate_Invalid(t *testing.T) {
+	_, err := shifts.Generate(newSchedule(nil, nil), shifts.Config{})
+	assert.EqualError(t, err, "shift length must be greater than 0 and at most 24h (got: 0s)")
+
+	_, err = shifts.Generate(newSchedule(nil, nil), shifts.Config{Length: time.Hour, Starts: []time.Duration{25 * time.Hour}})
+	assert.EqualError(t, err, "shift start must be within the day (got: 25h0m0s)")
+}
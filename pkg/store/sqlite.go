@@ -0,0 +1,218 @@
+// Package store persists generated schedules so past runs can be listed and
+// retrieved later, and records which run is currently published.
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/karthikrao-23/agentscheduler/pkg/errors"
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+
+	// Registers the "sqlite3" database/sql driver
+	_ "github.com/mattn/go-sqlite3"
+)
+
+const schema = `
+CREATE TABLE IF NOT EXISTS runs (
+	run_id       TEXT PRIMARY KEY,
+	generated_at TEXT NOT NULL,
+	input_hash   TEXT NOT NULL,
+	options      TEXT NOT NULL,
+	tool_version TEXT NOT NULL,
+	total_demand INTEGER NOT NULL,
+	total_agents INTEGER NOT NULL,
+	total_unmet  INTEGER NOT NULL,
+	schedule     TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS runs_generated_at ON runs (generated_at);
+CREATE TABLE IF NOT EXISTS unmet_demand (
+	run_id           TEXT NOT NULL REFERENCES runs (run_id) ON DELETE CASCADE,
+	hour             INTEGER NOT NULL,
+	slot             TEXT NOT NULL,
+	total_demand     INTEGER NOT NULL,
+	allocated_agents INTEGER NOT NULL,
+	unmet_agents     INTEGER NOT NULL,
+	PRIMARY KEY (run_id, hour)
+);
+CREATE TABLE IF NOT EXISTS publications (
+	id           INTEGER PRIMARY KEY AUTOINCREMENT,
+	run_id       TEXT NOT NULL REFERENCES runs (run_id),
+	published_at TEXT NOT NULL
+);
+`
+
+// Run summarizes one stored schedule.
+type Run struct {
+	RunID       string
+	GeneratedAt time.Time
+	InputHash   string
+	Options     map[string]string
+	ToolVersion string
+	TotalDemand int
+	TotalAgents int
+	TotalUnmet  int
+}
+
+// SQLite stores schedules in an embedded SQLite database file. Each run's
+// provenance and totals are kept in columns for listing, alongside the full
+// schedule as JSON and its unmet demand as rows for ad-hoc queries.
+type SQLite struct {
+	db *sql.DB
+}
+
+// OpenSQLite opens the database at path, creating it and its tables if
+// needed. The path is escaped into a file: URI, so names containing ? or #
+// open the file they name.
+func OpenSQLite(ctx context.Context, path string) (*SQLite, error) {
+	dsn := url.URL{Scheme: "file", Path: path, RawQuery: "_foreign_keys=on&_busy_timeout=5000"}
+	db, err := sql.Open("sqlite3", dsn.String())
+	if err != nil {
+		return nil, fmt.Errorf("opening store: %w", err)
+	}
+	if _, err := db.ExecContext(ctx, schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("creating store schema: %w", err)
+	}
+	return &SQLite{db: db}, nil
+}
+
+// Close closes the database.
+func (s *SQLite) Close() error {
+	return s.db.Close()
+}
+
+// Save records a generated schedule under its Metadata.RunID. Schedules
+// without metadata, e.g. the result of Merge, cannot be saved.
+func (s *SQLite) Save(ctx context.Context, schedule *models.Schedule) error {
+	md := schedule.Metadata
+	if md == nil || md.RunID == "" {
+		return fmt.Errorf("saving schedule: no run metadata")
+	}
+	options, err := json.Marshal(md.Options)
+	if err != nil {
+		return fmt.Errorf("saving schedule: %w", err)
+	}
+	body, err := json.Marshal(schedule)
+	if err != nil {
+		return fmt.Errorf("saving schedule: %w", err)
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("saving schedule: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `INSERT INTO runs
+		(run_id, generated_at, input_hash, options, tool_version, total_demand, total_agents, total_unmet, schedule)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		md.RunID, md.GeneratedAt.UTC().Format(time.RFC3339Nano), md.InputHash, string(options), md.ToolVersion,
+		schedule.TotalDemand(), schedule.TotalAgents(), schedule.TotalUnmet(), string(body))
+	if err != nil {
+		return fmt.Errorf("saving run %s: %w", md.RunID, err)
+	}
+	for _, unmet := range schedule.UnmetDemands {
+		_, err = tx.ExecContext(ctx, `INSERT INTO unmet_demand
+			(run_id, hour, slot, total_demand, allocated_agents, unmet_agents)
+			VALUES (?, ?, ?, ?, ?, ?)`,
+			md.RunID, unmet.Hour, unmet.Slot.String(), unmet.TotalDemand, unmet.AllocatedAgents, unmet.UnmetAgents)
+		if err != nil {
+			return fmt.Errorf("saving unmet demand for run %s: %w", md.RunID, err)
+		}
+	}
+	return tx.Commit()
+}
+
+// List returns up to limit stored runs, newest first. A limit of 0 or less
+// returns every run.
+func (s *SQLite) List(ctx context.Context, limit int) ([]Run, error) {
+	if limit <= 0 {
+		limit = -1
+	}
+	rows, err := s.db.QueryContext(ctx, `SELECT
+		run_id, generated_at, input_hash, options, tool_version, total_demand, total_agents, total_unmet
+		FROM runs ORDER BY generated_at DESC, run_id LIMIT ?`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("listing runs: %w", err)
+	}
+	defer rows.Close()
+
+	var runs []Run
+	for rows.Next() {
+		var r Run
+		var generatedAt, options string
+		if err := rows.Scan(&r.RunID, &generatedAt, &r.InputHash, &options, &r.ToolVersion,
+			&r.TotalDemand, &r.TotalAgents, &r.TotalUnmet); err != nil {
+			return nil, fmt.Errorf("listing runs: %w", err)
+		}
+		if r.GeneratedAt, err = time.Parse(time.RFC3339Nano, generatedAt); err != nil {
+			return nil, fmt.Errorf("run %s: invalid generated_at %q: %w", r.RunID, generatedAt, err)
+		}
+		if err := json.Unmarshal([]byte(options), &r.Options); err != nil {
+			return nil, fmt.Errorf("run %s: invalid options: %w", r.RunID, err)
+		}
+		runs = append(runs, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("listing runs: %w", err)
+	}
+	return runs, nil
+}
+
+// Get returns the schedule stored for runID. It returns an error wrapping
+// errors.ErrScheduleNotFound if there is none.
+func (s *SQLite) Get(ctx context.Context, runID string) (*models.Schedule, error) {
+	var body string
+	err := s.db.QueryRowContext(ctx, `SELECT schedule FROM runs WHERE run_id = ?`, runID).Scan(&body)
+	if stderrors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: %s", errors.ErrScheduleNotFound, runID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("loading run %s: %w", runID, err)
+	}
+	var schedule models.Schedule
+	if err := json.Unmarshal([]byte(body), &schedule); err != nil {
+		return nil, fmt.Errorf("loading run %s: %w", runID, err)
+	}
+	return &schedule, nil
+}
+
+// Publish makes the stored run runID the published schedule. Earlier
+// publications are kept, so the published schedule can be rolled back by
+// publishing an earlier run again.
+func (s *SQLite) Publish(ctx context.Context, runID string) error {
+	var exists bool
+	err := s.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM runs WHERE run_id = ?)`, runID).Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("publishing run %s: %w", runID, err)
+	}
+	if !exists {
+		return fmt.Errorf("%w: %s", errors.ErrScheduleNotFound, runID)
+	}
+	_, err = s.db.ExecContext(ctx, `INSERT INTO publications (run_id, published_at) VALUES (?, ?)`,
+		runID, time.Now().UTC().Format(time.RFC3339Nano))
+	if err != nil {
+		return fmt.Errorf("publishing run %s: %w", runID, err)
+	}
+	return nil
+}
+
+// Published returns the most recently published schedule. It returns an
+// error wrapping errors.ErrScheduleNotFound if nothing has been published.
+func (s *SQLite) Published(ctx context.Context) (*models.Schedule, error) {
+	var runID string
+	err := s.db.QueryRowContext(ctx, `SELECT run_id FROM publications ORDER BY id DESC LIMIT 1`).Scan(&runID)
+	if stderrors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: nothing published", errors.ErrScheduleNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("loading published run: %w", err)
+	}
+	return s.Get(ctx, runID)
+}
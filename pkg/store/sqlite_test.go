@@ -0,0 +1,124 @@
+package store_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	customerrors "github.com/karthikrao-23/agentscheduler/pkg/errors"
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+	"github.com/karthikrao-23/agentscheduler/pkg/scheduler"
+	"github.com/karthikrao-23/agentscheduler/pkg/store"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newSchedule(t *testing.T, calls int) *models.Schedule {
+	t.Helper()
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	cd, err := models.NewCallData("Acme", start, start.Add(time.Hour), calls, 3600)
+	require.NoError(t, err)
+	schedule, err := scheduler.GenerateScheduleContext(context.Background(), []models.CallData{cd}, scheduler.WithCapacity(5))
+	require.NoError(t, err)
+	return schedule
+}
+
+func TestSQLite_PathNeedsEscaping(t *testing.T) {
+	ctx := context.Background()
+	dir := filepath.Join(t.TempDir(), "runs?mode=ro#1 %41")
+	require.NoError(t, os.Mkdir(dir, 0o755))
+	path := filepath.Join(dir, "schedules.db")
+	s, err := store.OpenSQLite(ctx, path)
+	require.NoError(t, err)
+	defer s.Close()
+
+	require.NoError(t, s.Save(ctx, newSchedule(t, 3)))
+	assert.FileExists(t, path)
+}
+
+func TestSQLite_SaveListGet(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "schedules.db")
+	s, err := store.OpenSQLite(ctx, path)
+	require.NoError(t, err)
+
+	first := newSchedule(t, 3)
+	second := newSchedule(t, 8)
+	second.Metadata.GeneratedAt = first.Metadata.GeneratedAt.Add(time.Minute)
+	require.NoError(t, s.Save(ctx, first))
+	require.NoError(t, s.Save(ctx, second))
+	assert.Error(t, s.Save(ctx, first), "run IDs are unique")
+	require.NoError(t, s.Close())
+
+	// Runs survive reopening the file
+	s, err = store.OpenSQLite(ctx, path)
+	require.NoError(t, err)
+	defer s.Close()
+
+	runs, err := s.List(ctx, 0)
+	require.NoError(t, err)
+	require.Len(t, runs, 2)
+	assert.Equal(t, second.Metadata.RunID, runs[0].RunID, "newest first")
+	assert.Equal(t, 8, runs[0].TotalDemand)
+	assert.Equal(t, 5, runs[0].TotalAgents)
+	assert.Equal(t, 3, runs[0].TotalUnmet)
+	assert.Equal(t, second.Metadata.InputHash, runs[0].InputHash)
+	assert.Equal(t, second.Metadata.Options, runs[0].Options)
+	assert.True(t, second.Metadata.GeneratedAt.Equal(runs[0].GeneratedAt))
+
+	runs, err = s.List(ctx, 1)
+	require.NoError(t, err)
+	assert.Len(t, runs, 1)
+
+	got, err := s.Get(ctx, second.Metadata.RunID)
+	require.NoError(t, err)
+	assert.True(t, second.Equal(got))
+	assert.Equal(t, second.Metadata.RunID, got.Metadata.RunID)
+
+	_, err = s.Get(ctx, "missing")
+	assert.ErrorIs(t, err, customerrors.ErrScheduleNotFound)
+}
+
+func TestSQLite_SaveWithoutMetadata(t *testing.T) {
+	ctx := context.Background()
+	s, err := store.OpenSQLite(ctx, filepath.Join(t.TempDir(), "schedules.db"))
+	require.NoError(t, err)
+	defer s.Close()
+
+	assert.Error(t, s.Save(ctx, &models.Schedule{}))
+}
+
+func TestSQLite_Publish(t *testing.T) {
+	ctx := context.Background()
+	s, err := store.OpenSQLite(ctx, filepath.Join(t.TempDir(), "schedules.db"))
+	require.NoError(t, err)
+	defer s.Close()
+
+	_, err = s.Published(ctx)
+	assert.ErrorIs(t, err, customerrors.ErrScheduleNotFound, "nothing published yet")
+	assert.ErrorIs(t, s.Publish(ctx, "missing"), customerrors.ErrScheduleNotFound)
+
+	first := newSchedule(t, 3)
+	second := newSchedule(t, 8)
+	require.NoError(t, s.Save(ctx, first))
+	require.NoError(t, s.Save(ctx, second))
+
+	require.NoError(t, s.Publish(ctx, first.Metadata.RunID))
+	got, err := s.Published(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, first.Metadata.RunID, got.Metadata.RunID)
+
+	require.NoError(t, s.Publish(ctx, second.Metadata.RunID))
+	got, err = s.Published(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, second.Metadata.RunID, got.Metadata.RunID)
+
+	// Rolling back republishes the earlier run
+	require.NoError(t, s.Publish(ctx, first.Metadata.RunID))
+	got, err = s.Published(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, first.Metadata.RunID, got.Metadata.RunID)
+}
@@ -0,0 +1,73 @@
+// Package tracing provides OpenTelemetry spans for the parse, schedule, and
+// format phases of the agent scheduler.
+//
+// Spans are always created through the global tracer provider; until Setup is
+// called that provider is a no-op, so instrumented code pays almost nothing
+// when tracing is disabled.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.43.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ServiceName is reported as the service.name resource attribute.
+const ServiceName = "agent-scheduler"
+
+// debug enables fine-grained spans (e.g. one span per customer).
+var debug atomic.Bool
+
+// Tracer returns a named tracer from the global provider.
+func Tracer(name string) trace.Tracer {
+	return otel.Tracer(name)
+}
+
+// Debug reports whether fine-grained debug spans are enabled.
+func Debug() bool {
+	return debug.Load()
+}
+
+// SetDebug toggles fine-grained debug spans.
+func SetDebug(enabled bool) {
+	debug.Store(enabled)
+}
+
+// Setup installs an OTLP/HTTP exporter as the global tracer provider.
+// endpoint is a host:port such as "localhost:4318"; insecure disables TLS.
+// The returned shutdown function flushes pending spans and must be called
+// before the process exits.
+func Setup(ctx context.Context, endpoint string, insecure bool) (func(context.Context) error, error) {
+	opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(endpoint)}
+	if insecure {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	}
+
+	exporter, err := otlptracehttp.New(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("error creating trace exporter: %w", err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(ServiceName),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("error creating trace resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+
+	return provider.Shutdown, nil
+}
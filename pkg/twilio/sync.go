@@ -0,0 +1,84 @@
+package twilio
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// DefaultSyncURL is the base URL of the Twilio Sync API.
+const DefaultSyncURL = "https://sync.twilio.com/v1"
+
+// Client publishes staffing plans to a Twilio Sync document, where Flex
+// plugins and TaskRouter workflows can read them.
+type Client struct {
+	AccountSID string
+	AuthToken  string
+	// BaseURL overrides DefaultSyncURL, e.g. for tests
+	BaseURL string
+	// HTTPClient sends the requests; defaults to http.DefaultClient
+	HTTPClient *http.Client
+}
+
+// Publish stores plan as the data of the Sync document named document in the
+// Sync service serviceSID, creating the document if it does not exist yet.
+// Sync documents hold at most 16KiB, so very large plans are rejected by
+// Twilio.
+func (c *Client) Publish(ctx context.Context, serviceSID, document string, plan StaffingPlan) error {
+	data, err := json.Marshal(plan)
+	if err != nil {
+		return fmt.Errorf("encoding staffing plan: %w", err)
+	}
+
+	base := c.BaseURL
+	if base == "" {
+		base = DefaultSyncURL
+	}
+	documents := fmt.Sprintf("%s/Services/%s/Documents", strings.TrimSuffix(base, "/"), url.PathEscape(serviceSID))
+
+	status, err := c.post(ctx, documents+"/"+url.PathEscape(document), url.Values{"Data": {string(data)}})
+	if err != nil {
+		return err
+	}
+	if status != http.StatusNotFound {
+		return checkStatus("updating", status)
+	}
+	status, err = c.post(ctx, documents, url.Values{"UniqueName": {document}, "Data": {string(data)}})
+	if err != nil {
+		return err
+	}
+	return checkStatus("creating", status)
+}
+
+func (c *Client) post(ctx context.Context, endpoint string, form url.Values) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(c.AccountSID, c.AuthToken)
+
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("twilio sync request: %w", err)
+	}
+	defer resp.Body.Close()
+	// Drain so the connection can be reused
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return resp.StatusCode, nil
+}
+
+func checkStatus(action string, status int) error {
+	if status >= 200 && status < 300 {
+		return nil
+	}
+	return fmt.Errorf("%s twilio sync document: unexpected status %d %s", action, status, http.StatusText(status))
+}
@@ -0,0 +1,151 @@
+// Package twilio exports schedules as Twilio TaskRouter / Flex staffing plans
+// and publishes them to Twilio, so the routing platform sees the staffing the
+// planner expects.
+package twilio
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+)
+
+// DefaultTaskChannel is the TaskRouter task channel used when none is given.
+const DefaultTaskChannel = "voice"
+
+// StaffingPlan is a Flex-compatible staffing payload: per TaskRouter task
+// queue, the workers required in each slot and the channel capacity each
+// worker should be configured with.
+type StaffingPlan struct {
+	WorkspaceSID string      `json:"workspace_sid,omitempty"`
+	TaskChannel  string      `json:"task_channel"`
+	RunID        string      `json:"run_id,omitempty"`
+	GeneratedAt  time.Time   `json:"generated_at,omitzero"`
+	Queues       []TaskQueue `json:"task_queues"`
+}
+
+// TaskQueue is the staffing for one customer's task queue.
+type TaskQueue struct {
+	// FriendlyName is the queue's name in TaskRouter, the customer name
+	FriendlyName string `json:"friendly_name"`
+	// TargetWorkers is the TaskRouter expression selecting eligible workers,
+	// set when the customer requires a skill
+	TargetWorkers string          `json:"target_workers,omitempty"`
+	Location      string          `json:"location,omitempty"`
+	Priority      models.Priority `json:"priority"`
+	// ChannelCapacity is the worker channel capacity: how many tasks one
+	// worker handles at once on the task channel
+	ChannelCapacity int        `json:"channel_capacity"`
+	Staffing        []Staffing `json:"staffing"`
+}
+
+// Staffing is the required workers for a queue in one slot.
+type Staffing struct {
+	Hour    int    `json:"hour"`
+	Slot    string `json:"slot"`
+	Workers int    `json:"workers"`
+	// Unmet is the workers the queue needed but capacity could not cover
+	Unmet int `json:"unmet,omitempty"`
+}
+
+// Option configures NewStaffingPlan.
+type Option func(*StaffingPlan)
+
+// WithWorkspace sets the TaskRouter workspace SID the plan is for.
+func WithWorkspace(sid string) Option {
+	return func(p *StaffingPlan) {
+		p.WorkspaceSID = sid
+	}
+}
+
+// WithTaskChannel sets the task channel, e.g. "voice" or "chat". Defaults to
+// DefaultTaskChannel.
+func WithTaskChannel(channel string) Option {
+	return func(p *StaffingPlan) {
+		p.TaskChannel = channel
+	}
+}
+
+// NewStaffingPlan converts schedule into a staffing plan with one task queue
+// per customer, ordered by name. Slots where a customer needs no workers are
+// omitted from its staffing.
+func NewStaffingPlan(schedule *models.Schedule, opts ...Option) StaffingPlan {
+	plan := StaffingPlan{TaskChannel: DefaultTaskChannel, Queues: make([]TaskQueue, 0)}
+	for _, opt := range opts {
+		opt(&plan)
+	}
+	if md := schedule.Metadata; md != nil {
+		plan.RunID = md.RunID
+		plan.GeneratedAt = md.GeneratedAt
+	}
+
+	queues := make(map[string]*TaskQueue)
+	capacity := channelCapacities(schedule)
+	for e := range schedule.Entries() {
+		q, ok := queues[e.Customer]
+		if !ok {
+			q = &TaskQueue{
+				FriendlyName:    e.Customer,
+				Location:        e.Location.Name(),
+				Priority:        e.Priority,
+				ChannelCapacity: 1,
+				Staffing:        make([]Staffing, 0),
+			}
+			if c, ok := capacity[e.Customer]; ok {
+				q.TargetWorkers, q.ChannelCapacity = c.targetWorkers, c.channelCapacity
+			}
+			queues[e.Customer] = q
+		}
+		if n := len(q.Staffing); n > 0 && q.Staffing[n-1].Hour == e.Hour {
+			q.Staffing[n-1].Workers += e.Agents
+			q.Staffing[n-1].Unmet += e.Unmet
+			continue
+		}
+		q.Staffing = append(q.Staffing, Staffing{Hour: e.Hour, Slot: e.Slot.String(), Workers: e.Agents, Unmet: e.Unmet})
+	}
+
+	for _, q := range queues {
+		plan.Queues = append(plan.Queues, *q)
+	}
+	sort.Slice(plan.Queues, func(i, j int) bool {
+		return plan.Queues[i].FriendlyName < plan.Queues[j].FriendlyName
+	})
+	return plan
+}
+
+// Write writes the staffing plan for schedule as indented JSON.
+func Write(w io.Writer, schedule *models.Schedule, opts ...Option) error {
+	b, err := json.MarshalIndent(NewStaffingPlan(schedule, opts...), "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding staffing plan: %w", err)
+	}
+	_, err = w.Write(append(b, '\n'))
+	return err
+}
+
+type queueCapacity struct {
+	targetWorkers   string
+	channelCapacity int
+}
+
+// channelCapacities reads each customer's skill and concurrency from its
+// requirements, which Entries does not carry.
+func channelCapacities(schedule *models.Schedule) map[string]queueCapacity {
+	capacities := make(map[string]queueCapacity)
+	for _, reqs := range schedule.HourlyRequirements {
+		for _, req := range reqs {
+			if _, ok := capacities[req.Name]; ok {
+				continue
+			}
+			c := queueCapacity{channelCapacity: max(req.Concurrency, 1)}
+			if req.Skill != "" {
+				c.targetWorkers = fmt.Sprintf("skills HAS %q", req.Skill)
+			}
+			capacities[req.Name] = c
+		}
+	}
+	return capacities
+}
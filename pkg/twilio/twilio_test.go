@@ -0,0 +1,155 @@
+package twilio_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+	"github.com/karthikrao-23/agentscheduler/pkg/twilio"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newSchedule() *models.Schedule {
+	reqs := make([][]models.CustomerRequirement, 24)
+	reqs[9] = []models.CustomerRequirement{
+		{Name: "Globex", AgentsNeeded: 4, Priority: models.PriorityHigh, Location: models.NewLocation(time.UTC), Skill: "billing", Concurrency: 3},
+		{Name: "Acme", AgentsNeeded: 10, Priority: models.PriorityCritical, Location: models.NewLocation(time.UTC)},
+	}
+	reqs[10] = []models.CustomerRequirement{
+		{Name: "Acme", AgentsNeeded: 6, Priority: models.PriorityCritical, Location: models.NewLocation(time.UTC)},
+	}
+	return &models.Schedule{
+		HourlyRequirements: reqs,
+		UnmetDemands: []models.UnmetDemand{{
+			Hour:            10,
+			TotalDemand:     8,
+			AllocatedAgents: 6,
+			UnmetAgents:     2,
+			ImpactedClients: []models.ImpactedClient{
+				{Name: "Acme", RequestedAgents: 8, AllocatedAgents: 6, UnmetAgents: 2, Priority: models.PriorityCritical, Location: models.NewLocation(time.UTC)},
+			},
+		}},
+		Metadata: &models.Metadata{RunID: "run-1"},
+	}
+}
+
+func TestNewStaffingPlan(t *testing.T) {
+	plan := twilio.NewStaffingPlan(newSchedule(), twilio.WithWorkspace("WS123"))
+
+	assert.Equal(t, "WS123", plan.WorkspaceSID)
+	assert.Equal(t, twilio.DefaultTaskChannel, plan.TaskChannel)
+	assert.Equal(t, "run-1", plan.RunID)
+	require.Len(t, plan.Queues, 2)
+
+	acme := plan.Queues[0]
+	assert.Equal(t, "Acme", acme.FriendlyName)
+	assert.Equal(t, "UTC", acme.Location)
+	assert.Equal(t, models.PriorityCritical, acme.Priority)
+	assert.Equal(t, 1, acme.ChannelCapacity)
+	assert.Empty(t, acme.TargetWorkers)
+	assert.Equal(t, []twilio.Staffing{
+		{Hour: 9, Slot: "09:00", Workers: 10},
+		{Hour: 10, Slot: "10:00", Workers: 6, Unmet: 2},
+	}, acme.Staffing)
+
+	globex := plan.Queues[1]
+	assert.Equal(t, "Globex", globex.FriendlyName)
+	assert.Equal(t, `skills HAS "billing"`, globex.TargetWorkers)
+	assert.Equal(t, 3, globex.ChannelCapacity)
+	assert.Equal(t, []twilio.Staffing{{Hour: 9, Slot: "09:00", Workers: 4}}, globex.Staffing)
+}
+
+func TestWrite(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, twilio.Write(&buf, newSchedule(), twilio.WithTaskChannel("chat")))
+
+	var plan twilio.StaffingPlan
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &plan))
+	assert.Equal(t, "chat", plan.TaskChannel)
+	assert.Len(t, plan.Queues, 2)
+	assert.Contains(t, buf.String(), `"friendly_name": "Acme"`)
+}
+
+func TestClientPublish(t *testing.T) {
+	tests := map[string]struct {
+		exists   bool
+		status   int
+		wantErr  string
+		wantPath []string
+	}{
+		"UpdatesExistingDocument": {
+			exists:   true,
+			status:   http.StatusOK,
+			wantPath: []string{"/Services/IS1/Documents/staffing"},
+		},
+		"CreatesMissingDocument": {
+			status:   http.StatusCreated,
+			wantPath: []string{"/Services/IS1/Documents/staffing", "/Services/IS1/Documents"},
+		},
+		"ReportsFailure": {
+			exists:   true,
+			status:   http.StatusUnauthorized,
+			wantErr:  "updating twilio sync document: unexpected status 401 Unauthorized",
+			wantPath: []string{"/Services/IS1/Documents/staffing"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var paths []string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				paths = append(paths, r.URL.Path)
+				user, pass, ok := r.BasicAuth()
+				assert.True(t, ok)
+				assert.Equal(t, "AC1", user)
+				assert.Equal(t, "secret", pass)
+
+				require.NoError(t, r.ParseForm())
+				var plan twilio.StaffingPlan
+				assert.NoError(t, json.Unmarshal([]byte(r.PostForm.Get("Data")), &plan))
+				assert.Equal(t, "run-1", plan.RunID)
+
+				if r.URL.Path == "/Services/IS1/Documents" {
+					assert.Equal(t, "staffing", r.PostForm.Get("UniqueName"))
+					w.WriteHeader(tt.status)
+					return
+				}
+				if !tt.exists {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				w.WriteHeader(tt.status)
+			}))
+			defer srv.Close()
+
+			c := &twilio.Client{AccountSID: "AC1", AuthToken: "secret", BaseURL: srv.URL}
+			err := c.Publish(context.Background(), "IS1", "staffing", twilio.NewStaffingPlan(newSchedule()))
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantPath, paths)
+		})
+	}
+}
+
+func TestClientPublishEscapesNames(t *testing.T) {
+	var path string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.EscapedPath()
+	}))
+	defer srv.Close()
+
+	c := &twilio.Client{BaseURL: srv.URL + "/"}
+	require.NoError(t, c.Publish(context.Background(), "IS1", "plan/today", twilio.StaffingPlan{}))
+	assert.Equal(t, "/Services/IS1/Documents/"+url.PathEscape("plan/today"), path)
+}
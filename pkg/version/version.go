@@ -0,0 +1,20 @@
+// Package version reports the version of the agent scheduler build.
+package version
+
+import "runtime/debug"
+
+// Version is set at build time with
+// -ldflags "-X github.com/karthikrao-23/agentscheduler/pkg/version.Version=v1.2.3".
+// When unset, the module version recorded in the build info is used.
+var Version string
+
+// String returns the build's version, or "(devel)" for untagged builds.
+func String() string {
+	if Version != "" {
+		return Version
+	}
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" {
+		return info.Main.Version
+	}
+	return "(devel)"
+}
@@ -0,0 +1,219 @@
+// Package webhook notifies downstream systems when a schedule is generated,
+// so they can react to new schedules and shortfalls without polling.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+)
+
+// Events and severities carried in a Payload.
+const (
+	EventCompleted = "schedule.completed"
+	EventShortfall = "schedule.shortfall"
+
+	SeverityInfo    = "info"
+	SeverityWarning = "warning"
+)
+
+// Headers collects request headers from a repeatable command-line flag, one
+// header per use, written as "Name: value" as in an HTTP request, or as
+// "Name=value". Values are kept whole, commas and all.
+type Headers map[string]string
+
+// String implements flag.Value.
+func (h Headers) String() string {
+	lines := make([]string, 0, len(h))
+	for name, value := range h {
+		lines = append(lines, name+": "+value)
+	}
+	slices.Sort(lines)
+	return strings.Join(lines, ", ")
+}
+
+// Set implements flag.Value, adding one header.
+func (h Headers) Set(s string) error {
+	i := strings.IndexAny(s, ":=")
+	if i < 0 {
+		return fmt.Errorf("invalid webhook header %q: expected Name: value", s)
+	}
+	name, value := strings.TrimSpace(s[:i]), strings.TrimSpace(s[i+1:])
+	if name == "" || value == "" || strings.ContainsAny(name, " \t") {
+		return fmt.Errorf("invalid webhook header %q: expected Name: value", s)
+	}
+	h[name] = value
+	return nil
+}
+
+// Config configures where and what to send.
+type Config struct {
+	// URL receives the payload as a JSON POST
+	URL string
+	// Headers are added to every request, e.g. an authorization token
+	Headers map[string]string
+	// ShortfallPercent is the unmet percentage of total demand above which
+	// the run is reported as a shortfall with warning severity. 0 reports
+	// any unmet demand as a shortfall.
+	ShortfallPercent float64
+	// ScheduleURL links to where the full schedule can be fetched, if anywhere
+	ScheduleURL string
+	// AttachSchedule embeds the full schedule in the payload
+	AttachSchedule bool
+	// Retries is the number of additional attempts after a failed request
+	Retries int
+	// Backoff is the delay before the first retry; it doubles on each retry
+	Backoff time.Duration
+	// Timeout bounds each individual attempt (0 = no timeout)
+	Timeout time.Duration
+	// Client sends the requests; defaults to http.DefaultClient
+	Client *http.Client
+}
+
+// Payload is the JSON body of a webhook request.
+type Payload struct {
+	Event       string            `json:"event"`
+	Severity    string            `json:"severity"`
+	RunID       string            `json:"run_id,omitempty"`
+	GeneratedAt time.Time         `json:"generated_at,omitzero"`
+	Summary     models.Summary    `json:"summary"`
+	UnmetDemand []UnmetDemand     `json:"unmet_demand"`
+	ScheduleURL string            `json:"schedule_url,omitempty"`
+	Schedule    *models.Schedule  `json:"schedule,omitempty"`
+	Options     map[string]string `json:"options,omitempty"`
+}
+
+// UnmetDemand is one slot with a shortfall.
+type UnmetDemand struct {
+	Hour            int              `json:"hour"`
+	Slot            string           `json:"slot"`
+	TotalDemand     int              `json:"total_demand"`
+	AllocatedAgents int              `json:"allocated_agents"`
+	UnmetAgents     int              `json:"unmet_agents"`
+	ImpactedClients []ImpactedClient `json:"impacted_clients"`
+}
+
+// ImpactedClient is a customer that did not get all the agents it needed.
+type ImpactedClient struct {
+	Name        string          `json:"name"`
+	Priority    models.Priority `json:"priority"`
+	Location    string          `json:"location,omitempty"`
+	UnmetAgents int             `json:"unmet_agents"`
+}
+
+// NewPayload describes schedule for the webhook.
+func NewPayload(schedule *models.Schedule, cfg Config) Payload {
+	p := Payload{
+		Event:       EventCompleted,
+		Severity:    SeverityInfo,
+		Summary:     schedule.Summary(),
+		UnmetDemand: make([]UnmetDemand, 0, len(schedule.UnmetDemands)),
+		ScheduleURL: cfg.ScheduleURL,
+	}
+	if md := schedule.Metadata; md != nil {
+		p.RunID = md.RunID
+		p.GeneratedAt = md.GeneratedAt
+		p.Options = md.Options
+	}
+	if cfg.AttachSchedule {
+		p.Schedule = schedule
+	}
+	for _, unmet := range schedule.UnmetDemands {
+		u := UnmetDemand{
+			Hour:            unmet.Hour,
+			Slot:            unmet.Slot.String(),
+			TotalDemand:     unmet.TotalDemand,
+			AllocatedAgents: unmet.AllocatedAgents,
+			UnmetAgents:     unmet.UnmetAgents,
+			ImpactedClients: make([]ImpactedClient, len(unmet.ImpactedClients)),
+		}
+		for i, client := range unmet.ImpactedClients {
+			u.ImpactedClients[i] = ImpactedClient{
+				Name:        client.Name,
+				Priority:    client.Priority,
+				Location:    client.Location.Name(),
+				UnmetAgents: client.UnmetAgents,
+			}
+		}
+		p.UnmetDemand = append(p.UnmetDemand, u)
+	}
+
+	if p.Summary.TotalUnmet > 0 {
+		unmetPercent := float64(p.Summary.TotalUnmet) / float64(p.Summary.TotalDemand) * 100
+		if unmetPercent > cfg.ShortfallPercent {
+			p.Event = EventShortfall
+			p.Severity = SeverityWarning
+		}
+	}
+	return p
+}
+
+// Send posts the payload for schedule to cfg.URL, retrying with exponential
+// backoff on network errors and 5xx or 429 responses. Other 4xx responses
+// are not retried.
+func Send(ctx context.Context, cfg Config, schedule *models.Schedule) error {
+	body, err := json.Marshal(NewPayload(schedule, cfg))
+	if err != nil {
+		return fmt.Errorf("encoding webhook payload: %w", err)
+	}
+	client := cfg.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	backoff := cfg.Backoff
+	for attempt := 0; ; attempt++ {
+		retry, err := post(ctx, client, cfg, body)
+		if err == nil {
+			return nil
+		}
+		if !retry || attempt >= cfg.Retries {
+			return fmt.Errorf("webhook failed after %d attempts: %w", attempt+1, err)
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("webhook cancelled after %d attempts: %w", attempt+1, err)
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// post makes one attempt, reporting whether a failure is worth retrying.
+func post(ctx context.Context, client *http.Client, cfg Config, body []byte) (retry bool, err error) {
+	if cfg.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cfg.Timeout)
+		defer cancel()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, cfg.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range cfg.Headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+	// Drain so the connection can be reused
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	err = fmt.Errorf("unexpected status %s", resp.Status)
+	return resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests, err
+}
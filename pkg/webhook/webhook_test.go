@@ -0,0 +1,164 @@
+package webhook_test
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+	"github.com/karthikrao-23/agentscheduler/pkg/webhook"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newSchedule(unmet int) *models.Schedule {
+	reqs := make([][]models.CustomerRequirement, 24)
+	reqs[9] = []models.CustomerRequirement{{Name: "Acme", AgentsNeeded: 10, Priority: models.PriorityCritical}}
+	s := &models.Schedule{
+		HourlyRequirements: reqs,
+		Metadata:           &models.Metadata{RunID: "run-1", Options: map[string]string{"capacity": "10"}},
+	}
+	if unmet > 0 {
+		s.UnmetDemands = []models.UnmetDemand{{
+			Hour:            9,
+			Slot:            models.HourSlot(9),
+			TotalDemand:     10 + unmet,
+			AllocatedAgents: 10,
+			UnmetAgents:     unmet,
+			ImpactedClients: []models.ImpactedClient{
+				{Name: "Globex", RequestedAgents: unmet, UnmetAgents: unmet, Priority: models.PriorityLow, Location: models.NewLocation(time.UTC)},
+			},
+		}}
+	}
+	return s
+}
+
+func TestNewPayload(t *testing.T) {
+	tests := map[string]struct {
+		unmet            int
+		shortfallPercent float64
+		event            string
+		severity         string
+	}{
+		"NoShortfall":         {unmet: 0, event: webhook.EventCompleted, severity: webhook.SeverityInfo},
+		"AnyShortfall":        {unmet: 1, event: webhook.EventShortfall, severity: webhook.SeverityWarning},
+		"BelowThreshold":      {unmet: 1, shortfallPercent: 20, event: webhook.EventCompleted, severity: webhook.SeverityInfo},
+		"AboveThreshold":      {unmet: 5, shortfallPercent: 20, event: webhook.EventShortfall, severity: webhook.SeverityWarning},
+		"ThresholdIsExcluded": {unmet: 10, shortfallPercent: 50, event: webhook.EventCompleted, severity: webhook.SeverityInfo},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			p := webhook.NewPayload(newSchedule(tt.unmet), webhook.Config{ShortfallPercent: tt.shortfallPercent})
+			assert.Equal(t, tt.event, p.Event)
+			assert.Equal(t, tt.severity, p.Severity)
+			assert.Equal(t, "run-1", p.RunID)
+			assert.Equal(t, tt.unmet, p.Summary.TotalUnmet)
+			assert.Nil(t, p.Schedule)
+		})
+	}
+}
+
+func TestSend(t *testing.T) {
+	var calls atomic.Int32
+	var got webhook.Payload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	err := webhook.Send(context.Background(), webhook.Config{
+		URL:            server.URL,
+		Headers:        map[string]string{"Authorization": "Bearer secret"},
+		ScheduleURL:    "https://schedules.example.com/run-1",
+		AttachSchedule: true,
+		Retries:        2,
+		Backoff:        time.Millisecond,
+	}, newSchedule(3))
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), calls.Load())
+
+	assert.Equal(t, webhook.EventShortfall, got.Event)
+	assert.Equal(t, "https://schedules.example.com/run-1", got.ScheduleURL)
+	require.Len(t, got.UnmetDemand, 1)
+	assert.Equal(t, "09:00", got.UnmetDemand[0].Slot)
+	assert.Equal(t, []webhook.ImpactedClient{{Name: "Globex", Priority: models.PriorityLow, Location: "UTC", UnmetAgents: 3}}, got.UnmetDemand[0].ImpactedClients)
+	require.NotNil(t, got.Schedule)
+	assert.Equal(t, 10, got.Schedule.TotalAgents())
+}
+
+func TestSend_ClientErrorNotRetried(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	err := webhook.Send(context.Background(), webhook.Config{URL: server.URL, Retries: 3, Backoff: time.Millisecond}, newSchedule(0))
+	assert.ErrorContains(t, err, "401")
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestHeaders(t *testing.T) {
+	tests := map[string]struct {
+		args    []string
+		want    webhook.Headers
+		wantErr string
+	}{
+		"HTTPSyntax": {
+			args: []string{"-h", "Authorization: Bearer abc=="},
+			want: webhook.Headers{"Authorization": "Bearer abc=="},
+		},
+		"Equals": {
+			args: []string{"-h", "Authorization=vault://secret/webhook#authorization"},
+			want: webhook.Headers{"Authorization": "vault://secret/webhook#authorization"},
+		},
+		"CommaInValue": {
+			args: []string{"-h", "Accept: application/json, text/plain", "-h", "X-Team: ops"},
+			want: webhook.Headers{"Accept": "application/json, text/plain", "X-Team": "ops"},
+		},
+		"NoSeparator": {
+			args:    []string{"-h", "Authorization"},
+			wantErr: `invalid webhook header "Authorization"`,
+		},
+		"EmptyValue": {
+			args:    []string{"-h", "Authorization:"},
+			wantErr: "invalid webhook header",
+		},
+		"SpaceInName": {
+			args:    []string{"-h", "Bearer token: x"},
+			wantErr: "invalid webhook header",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			headers := webhook.Headers{}
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			fs.SetOutput(io.Discard)
+			fs.Var(headers, "h", "")
+			err := fs.Parse(tt.args)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, headers)
+		})
+	}
+}
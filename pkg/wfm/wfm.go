@@ -0,0 +1,134 @@
+// Package wfm exports schedules in the staffing-forecast import formats of
+// common workforce management suites, so one scheduler run can feed a WFM
+// system alongside, or instead of, this tool's own outputs.
+package wfm
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+)
+
+// Format identifies a WFM suite's import layout.
+type Format string
+
+// Supported formats.
+const (
+	// Verint is the Verint WFM staffing requirements import
+	Verint Format = "verint"
+	// NICE is the NICE IEX WFM required staff import
+	NICE Format = "nice"
+	// Calabrio is the Calabrio ONE forecasted agents import
+	Calabrio Format = "calabrio"
+)
+
+// Formats lists the supported formats.
+var Formats = []Format{Verint, NICE, Calabrio}
+
+// ParseFormat returns the format named s, case-insensitively.
+func ParseFormat(s string) (Format, error) {
+	f := Format(strings.ToLower(strings.TrimSpace(s)))
+	for _, known := range Formats {
+		if f == known {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("unknown WFM format %q: must be one of verint, nice, calabrio", s)
+}
+
+// Row is the staffing requirement for one queue in one slot. Required is the
+// agents the queue needs, including any the capacity could not cover, since
+// WFM suites plan their own schedules against the requirement.
+type Row struct {
+	Queue    string
+	Location models.Location
+	Start    time.Time
+	Interval time.Duration
+	Required int
+}
+
+// Rows flattens schedule into one row per customer and location per slot
+// with a non-zero requirement, in slot order. Slots of a generic day are
+// placed on date.
+func Rows(schedule *models.Schedule, date time.Time) []Row {
+	var rows []Row
+	index := make(map[string]int)
+	for e := range schedule.Entries() {
+		required := e.Agents + e.Unmet
+		if required == 0 {
+			continue
+		}
+		key := strconv.Itoa(e.Hour) + "\x00" + e.Customer + "\x00" + e.Location.Name()
+		if i, ok := index[key]; ok {
+			rows[i].Required += required
+			continue
+		}
+		index[key] = len(rows)
+		rows = append(rows, Row{
+			Queue:    e.Customer,
+			Location: e.Location,
+			Start:    slotStart(e.Slot, date),
+			Interval: e.Slot.Duration,
+			Required: required,
+		})
+	}
+	return rows
+}
+
+// Write writes schedule in format. Slots of a generic day are placed on date.
+func Write(w io.Writer, format Format, schedule *models.Schedule, date time.Time) error {
+	var (
+		header []string
+		record func(Row) []string
+	)
+	switch format {
+	case Verint:
+		header = []string{"Queue", "Date", "Start Time", "Interval (min)", "Required Staff"}
+		record = func(r Row) []string {
+			return []string{r.Queue, r.Start.Format("01/02/2006"), r.Start.Format("15:04"), minutes(r.Interval), strconv.Itoa(r.Required)}
+		}
+	case NICE:
+		header = []string{"Contact Type", "Date", "Interval Start", "Interval Length", "Req Staff"}
+		record = func(r Row) []string {
+			return []string{r.Queue, r.Start.Format("2006-01-02"), r.Start.Format("15:04"), minutes(r.Interval), strconv.Itoa(r.Required)}
+		}
+	case Calabrio:
+		header = []string{"Skill", "Time Zone", "Date", "Interval Start", "Forecasted Agents"}
+		record = func(r Row) []string {
+			return []string{r.Queue, r.Location.Name(), r.Start.Format("2006-01-02"), r.Start.Format("15:04"), strconv.Itoa(r.Required)}
+		}
+	default:
+		return fmt.Errorf("unknown WFM format %q", format)
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	for _, r := range Rows(schedule, date) {
+		if err := cw.Write(record(r)); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// slotStart returns the slot's start as local wall-clock time, on date for
+// slots of a generic day.
+func slotStart(slot models.TimeSlot, date time.Time) time.Time {
+	start := slot.Location.In(slot.Start)
+	if slot.HasDate() {
+		return start
+	}
+	return time.Date(date.Year(), date.Month(), date.Day(), start.Hour(), start.Minute(), 0, 0, time.UTC)
+}
+
+func minutes(d time.Duration) string {
+	return strconv.Itoa(int(d / time.Minute))
+}
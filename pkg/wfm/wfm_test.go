@@ -0,0 +1,134 @@
+package wfm_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/karthikrao-23/agentscheduler/pkg/models"
+	"github.com/karthikrao-23/agentscheduler/pkg/wfm"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newSchedule() *models.Schedule {
+	reqs := make([][]models.CustomerRequirement, 24)
+	reqs[9] = []models.CustomerRequirement{
+		{Name: "Acme", AgentsNeeded: 10, Priority: models.PriorityCritical, Location: models.NewLocation(time.UTC)},
+	}
+	return &models.Schedule{
+		HourlyRequirements: reqs,
+		UnmetDemands: []models.UnmetDemand{{
+			Hour:            9,
+			TotalDemand:     14,
+			AllocatedAgents: 10,
+			UnmetAgents:     4,
+			ImpactedClients: []models.ImpactedClient{
+				{Name: "Globex", RequestedAgents: 4, UnmetAgents: 4, Priority: models.PriorityLow, Location: models.NewLocation(time.UTC)},
+			},
+		}},
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	tests := map[string]struct {
+		input   string
+		want    wfm.Format
+		wantErr bool
+	}{
+		"Verint":   {input: "verint", want: wfm.Verint},
+		"NICE":     {input: "NICE", want: wfm.NICE},
+		"Calabrio": {input: " Calabrio ", want: wfm.Calabrio},
+		"Unknown":  {input: "genesys", wantErr: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := wfm.ParseFormat(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRows(t *testing.T) {
+	date := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	rows := wfm.Rows(newSchedule(), date)
+
+	require.Len(t, rows, 2)
+	assert.Equal(t, "Acme", rows[0].Queue)
+	assert.Equal(t, time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC), rows[0].Start)
+	assert.Equal(t, time.Hour, rows[0].Interval)
+	assert.Equal(t, 10, rows[0].Required)
+	assert.Equal(t, "Globex", rows[1].Queue)
+	assert.Equal(t, 4, rows[1].Required, "unmet demand is still required staffing")
+}
+
+func TestRows_CustomerInTwoLocations(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	require.NoError(t, err)
+	reqs := make([][]models.CustomerRequirement, 24)
+	reqs[9] = []models.CustomerRequirement{
+		{Name: "Acme", AgentsNeeded: 3, Priority: models.PriorityCritical, Location: models.NewLocation(ny)},
+		{Name: "Acme", AgentsNeeded: 5, Priority: models.PriorityCritical, Location: models.NewLocation(tokyo)},
+		{Name: "Acme", AgentsNeeded: 1, Priority: models.PriorityLow, Location: models.NewLocation(ny)},
+	}
+	schedule := &models.Schedule{HourlyRequirements: reqs}
+
+	rows := wfm.Rows(schedule, time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC))
+	require.Len(t, rows, 2)
+	assert.Equal(t, "America/New_York", rows[0].Location.Name())
+	assert.Equal(t, 4, rows[0].Required, "priorities in one location merge")
+	assert.Equal(t, "Asia/Tokyo", rows[1].Location.Name())
+	assert.Equal(t, 5, rows[1].Required)
+
+	var buf bytes.Buffer
+	require.NoError(t, wfm.Write(&buf, wfm.Calabrio, schedule, time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)))
+	assert.Contains(t, buf.String(), "Acme,America/New_York,2026-03-02,09:00,4\n")
+	assert.Contains(t, buf.String(), "Acme,Asia/Tokyo,2026-03-02,09:00,5\n")
+}
+
+func TestWrite(t *testing.T) {
+	date := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	tests := map[string]struct {
+		format wfm.Format
+		want   string
+	}{
+		"Verint": {
+			format: wfm.Verint,
+			want: "Queue,Date,Start Time,Interval (min),Required Staff\n" +
+				"Acme,03/02/2026,09:00,60,10\n" +
+				"Globex,03/02/2026,09:00,60,4\n",
+		},
+		"NICE": {
+			format: wfm.NICE,
+			want: "Contact Type,Date,Interval Start,Interval Length,Req Staff\n" +
+				"Acme,2026-03-02,09:00,60,10\n" +
+				"Globex,2026-03-02,09:00,60,4\n",
+		},
+		"Calabrio": {
+			format: wfm.Calabrio,
+			want: "Skill,Time Zone,Date,Interval Start,Forecasted Agents\n" +
+				"Acme,UTC,2026-03-02,09:00,10\n" +
+				"Globex,UTC,2026-03-02,09:00,4\n",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, wfm.Write(&buf, tt.format, newSchedule(), date))
+			assert.Equal(t, tt.want, buf.String())
+		})
+	}
+
+	err := wfm.Write(&bytes.Buffer{}, "genesys", newSchedule(), date)
+	assert.EqualError(t, err, `unknown WFM format "genesys"`)
+}